@@ -0,0 +1,105 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	errs "github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/backend/common/log"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+// apiTokenServicePrefix is the prefix of the methods managing the API tokens,
+// the API tokens are not allowed to mint or revoke the other tokens.
+const apiTokenServicePrefix = "/bytebase.v1.ApiTokenService/"
+
+// SCIMPathPrefix is the path prefix of the SCIM API, which only allows the API tokens with the SCIM scope.
+const SCIMPathPrefix = "/scim/v2"
+
+// apiTokenReadMethodPrefixes are the gRPC method name prefixes allowed by the read scope.
+var apiTokenReadMethodPrefixes = []string{"Get", "List", "Search"}
+
+// ValidateAPIToken validates the long-lived API token of the claims and records its last used time.
+// The API token is rejected if it's revoked, expired, or the principal is deactivated.
+func ValidateAPIToken(ctx context.Context, s *store.Store, claims jwt.RegisteredClaims) (*store.APITokenMessage, error) {
+	tokenID, err := strconv.Atoi(claims.ID)
+	if err != nil {
+		return nil, errs.Errorf("malformed API token ID %q", claims.ID)
+	}
+	principalID, err := strconv.Atoi(claims.Subject)
+	if err != nil {
+		return nil, errs.Errorf("malformed ID %q in the API token", claims.Subject)
+	}
+	token, err := s.GetAPIToken(ctx, tokenID)
+	if err != nil {
+		return nil, errs.Wrapf(err, "failed to find API token %d", tokenID)
+	}
+	if token == nil || token.PrincipalID != principalID {
+		return nil, errs.Errorf("API token %d not found", tokenID)
+	}
+	if token.Revoked {
+		return nil, errs.Errorf("API token %d has been revoked", tokenID)
+	}
+	if token.IsExpired(time.Now()) {
+		return nil, errs.Errorf("API token %d is expired", tokenID)
+	}
+	user, err := s.GetUserByID(ctx, principalID)
+	if err != nil {
+		return nil, errs.Wrapf(err, "failed to find user ID %d in the API token", principalID)
+	}
+	if user == nil {
+		return nil, errs.Errorf("user ID %d not exists in the API token", principalID)
+	}
+	if user.MemberDeleted {
+		return nil, errs.Errorf("user ID %d has been deactivated by administrators", principalID)
+	}
+
+	if err := s.TouchAPIToken(ctx, token); err != nil {
+		// Failing to record the last used time shouldn't block the request.
+		log.Warn("Failed to record the last used time of the API token", zap.Int("token", tokenID), zap.Error(err))
+	}
+	return token, nil
+}
+
+// IsAPITokenMethodAllowed returns whether the API token is granted the scope to call the gRPC method.
+func IsAPITokenMethodAllowed(fullMethod string, token *store.APITokenMessage) bool {
+	if strings.HasPrefix(fullMethod, apiTokenServicePrefix) {
+		return false
+	}
+	if token.HasScope(api.APITokenScopeRead) {
+		method := fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+		for _, prefix := range apiTokenReadMethodPrefixes {
+			if strings.HasPrefix(method, prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsAPITokenRequestAllowed returns whether the API token is granted the scope to send the HTTP request to the legacy API or OpenAPI path.
+func IsAPITokenRequestAllowed(method, path string, token *store.APITokenMessage) bool {
+	if strings.HasPrefix(path, SCIMPathPrefix+"/") {
+		return token.HasScope(api.APITokenScopeSCIM)
+	}
+	if method == http.MethodGet && token.HasScope(api.APITokenScopeRead) {
+		return true
+	}
+	if method != http.MethodPost {
+		return false
+	}
+	switch path {
+	case "/api/issue", "/v1/issues":
+		return token.HasScope(api.APITokenScopeIssueCreate)
+	case "/api/sql/execute":
+		return token.HasScope(api.APITokenScopeSQLQuery)
+	}
+	return false
+}
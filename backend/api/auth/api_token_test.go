@@ -0,0 +1,121 @@
+package auth
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+func TestIsAPITokenMethodAllowed(t *testing.T) {
+	tests := []struct {
+		fullMethod string
+		scopes     []string
+		want       bool
+	}{
+		{
+			fullMethod: "/bytebase.v1.ProjectService/ListProjects",
+			scopes:     []string{"read"},
+			want:       true,
+		},
+		{
+			fullMethod: "/bytebase.v1.ProjectService/GetIamPolicy",
+			scopes:     []string{"issue:create", "read"},
+			want:       true,
+		},
+		{
+			fullMethod: "/bytebase.v1.ProjectService/UpdateProject",
+			scopes:     []string{"read"},
+			want:       false,
+		},
+		{
+			fullMethod: "/bytebase.v1.ProjectService/ListProjects",
+			scopes:     []string{"sql:query"},
+			want:       false,
+		},
+		// The API tokens can't manage the other tokens.
+		{
+			fullMethod: "/bytebase.v1.ApiTokenService/ListApiTokens",
+			scopes:     []string{"read", "issue:create", "sql:query"},
+			want:       false,
+		},
+	}
+
+	a := require.New(t)
+	for _, tt := range tests {
+		got := IsAPITokenMethodAllowed(tt.fullMethod, &store.APITokenMessage{Scopes: tt.scopes})
+		a.Equal(tt.want, got, tt.fullMethod)
+	}
+}
+
+func TestIsAPITokenRequestAllowed(t *testing.T) {
+	tests := []struct {
+		method string
+		path   string
+		scopes []string
+		want   bool
+	}{
+		{
+			method: http.MethodGet,
+			path:   "/api/issue/101",
+			scopes: []string{"read"},
+			want:   true,
+		},
+		{
+			method: http.MethodGet,
+			path:   "/api/issue/101",
+			scopes: []string{"issue:create"},
+			want:   false,
+		},
+		{
+			method: http.MethodPost,
+			path:   "/v1/issues",
+			scopes: []string{"issue:create"},
+			want:   true,
+		},
+		{
+			method: http.MethodPost,
+			path:   "/api/issue",
+			scopes: []string{"read"},
+			want:   false,
+		},
+		{
+			method: http.MethodPost,
+			path:   "/api/sql/execute",
+			scopes: []string{"sql:query"},
+			want:   true,
+		},
+		{
+			method: http.MethodPost,
+			path:   "/api/sql/execute/admin",
+			scopes: []string{"sql:query"},
+			want:   false,
+		},
+		{
+			method: http.MethodPatch,
+			path:   "/v1/instances/mysql/databases/db",
+			scopes: []string{"read", "issue:create", "sql:query"},
+			want:   false,
+		},
+		{
+			method: http.MethodPatch,
+			path:   "/scim/v2/Users/101",
+			scopes: []string{"scim"},
+			want:   true,
+		},
+		{
+			method: http.MethodGet,
+			path:   "/scim/v2/Users",
+			scopes: []string{"read"},
+			want:   false,
+		},
+	}
+
+	a := require.New(t)
+	for _, tt := range tests {
+		got := IsAPITokenRequestAllowed(tt.method, tt.path, &store.APITokenMessage{Scopes: tt.scopes})
+		a.Equal(tt.want, got, "%s %s", tt.method, tt.path)
+	}
+}
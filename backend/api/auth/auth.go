@@ -32,9 +32,11 @@ const (
 	AccessTokenAudienceFmt = "bb.user.access.%s"
 	// RefreshTokenAudienceFmt is the format of the refresh token audience.
 	RefreshTokenAudienceFmt = "bb.user.refresh.%s"
-	apiTokenDuration        = 2 * time.Hour
-	accessTokenDuration     = 24 * time.Hour
-	refreshTokenDuration    = 7 * 24 * time.Hour
+	// APITokenAudienceFmt is the format of the long-lived API token audience, e.g. personal access tokens and service account keys.
+	APITokenAudienceFmt  = "bb.user.api-token.%s"
+	apiTokenDuration     = 2 * time.Hour
+	accessTokenDuration  = 24 * time.Hour
+	refreshTokenDuration = 7 * 24 * time.Hour
 	// RefreshThresholdDuration is the threshold duration for refreshing token.
 	RefreshThresholdDuration = 1 * time.Hour
 
@@ -88,22 +90,53 @@ func (in *APIAuthInterceptor) AuthenticationInterceptor(ctx context.Context, req
 		return nil, status.Errorf(codes.Unauthenticated, err.Error())
 	}
 
-	principalID, err := in.authenticate(ctx, accessTokenStr, refreshTokenStr)
+	principalID, apiToken, err := in.authenticate(ctx, accessTokenStr, refreshTokenStr)
 	if err != nil {
 		if IsAuthenticationAllowed(serverInfo.FullMethod) {
 			return handler(ctx, request)
 		}
 		return nil, err
 	}
+	if apiToken != nil && !IsAPITokenMethodAllowed(serverInfo.FullMethod, apiToken) {
+		return nil, status.Errorf(codes.PermissionDenied, "API token %d is not granted the scope to call %s", apiToken.UID, serverInfo.FullMethod)
+	}
+	if !IsMFAEnrollmentMethodAllowed(serverInfo.FullMethod) {
+		if err := in.checkMFAEnrollment(ctx, principalID); err != nil {
+			return nil, err
+		}
+	}
 
+	if actor, ok := ctx.Value(common.AuditActorContextKey).(*int); ok {
+		*actor = principalID
+	}
 	// Stores principalID into context.
 	childCtx := context.WithValue(ctx, common.PrincipalIDContextKey, principalID)
 	return handler(childCtx, request)
 }
 
-func (in *APIAuthInterceptor) authenticate(ctx context.Context, accessTokenStr, refreshTokenStr string) (int, error) {
+// checkMFAEnrollment denies the user who is required to enroll MFA but hasn't yet.
+func (in *APIAuthInterceptor) checkMFAEnrollment(ctx context.Context, principalID int) error {
+	user, err := in.store.GetUserByID(ctx, principalID)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to get user %d, error: %v", principalID, err)
+	}
+	if user == nil {
+		return status.Errorf(codes.Unauthenticated, "user %d not found", principalID)
+	}
+	pending, err := IsMFAEnrollmentPending(ctx, in.store, user)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to check MFA enrollment, error: %v", err)
+	}
+	if pending {
+		return status.Errorf(codes.PermissionDenied, "MFA is required by the workspace, please enable MFA for user %q first", user.Email)
+	}
+	return nil
+}
+
+// authenticate returns the principal ID of the token, and the API token if it's a long-lived API token.
+func (in *APIAuthInterceptor) authenticate(ctx context.Context, accessTokenStr, refreshTokenStr string) (int, *store.APITokenMessage, error) {
 	if accessTokenStr == "" {
-		return 0, status.Errorf(codes.Unauthenticated, "access token not found")
+		return 0, nil, status.Errorf(codes.Unauthenticated, "access token not found")
 	}
 	claims := &claimsMessage{}
 	generateToken := false
@@ -124,15 +157,25 @@ func (in *APIAuthInterceptor) authenticate(ctx context.Context, accessTokenStr,
 			// If expiration error is the only error, we will clear the err
 			// and generate new access token and refresh token
 			if refreshTokenStr == "" {
-				return 0, status.Errorf(codes.Unauthenticated, "access token is expired")
+				return 0, nil, status.Errorf(codes.Unauthenticated, "access token is expired")
 			}
 			generateToken = true
 		} else {
-			return 0, status.Errorf(codes.Unauthenticated, "failed to parse claim")
+			return 0, nil, status.Errorf(codes.Unauthenticated, "failed to parse claim")
+		}
+	}
+	if audienceContains(claims.Audience, fmt.Sprintf(APITokenAudienceFmt, in.mode)) {
+		if err != nil {
+			return 0, nil, status.Errorf(codes.Unauthenticated, "API token is expired")
+		}
+		apiToken, err := ValidateAPIToken(ctx, in.store, claims.RegisteredClaims)
+		if err != nil {
+			return 0, nil, status.Errorf(codes.Unauthenticated, err.Error())
 		}
+		return apiToken.PrincipalID, apiToken, nil
 	}
 	if !audienceContains(claims.Audience, fmt.Sprintf(AccessTokenAudienceFmt, in.mode)) {
-		return 0, status.Errorf(codes.Unauthenticated,
+		return 0, nil, status.Errorf(codes.Unauthenticated,
 			"invalid access token, audience mismatch, got %q, expected %q. you may send request to the wrong environment",
 			claims.Audience,
 			fmt.Sprintf(AccessTokenAudienceFmt, in.mode),
@@ -144,17 +187,17 @@ func (in *APIAuthInterceptor) authenticate(ctx context.Context, accessTokenStr,
 
 	principalID, err := strconv.Atoi(claims.Subject)
 	if err != nil {
-		return 0, status.Errorf(codes.Unauthenticated, "malformed ID %q in the access token", claims.Subject)
+		return 0, nil, status.Errorf(codes.Unauthenticated, "malformed ID %q in the access token", claims.Subject)
 	}
 	user, err := in.store.GetUserByID(ctx, principalID)
 	if err != nil {
-		return 0, status.Errorf(codes.Unauthenticated, "failed to find user ID %q in the access token", principalID)
+		return 0, nil, status.Errorf(codes.Unauthenticated, "failed to find user ID %q in the access token", principalID)
 	}
 	if user == nil {
-		return 0, status.Errorf(codes.Unauthenticated, "user ID %q not exists in the access token", principalID)
+		return 0, nil, status.Errorf(codes.Unauthenticated, "user ID %q not exists in the access token", principalID)
 	}
 	if user.MemberDeleted {
-		return 0, status.Errorf(codes.Unauthenticated, "user ID %q has been deactivated by administrators", principalID)
+		return 0, nil, status.Errorf(codes.Unauthenticated, "user ID %q has been deactivated by administrators", principalID)
 	}
 
 	if generateToken {
@@ -200,10 +243,10 @@ func (in *APIAuthInterceptor) authenticate(ctx context.Context, accessTokenStr,
 		// It may happen that we still have a valid access token, but we encounter issue when trying to generate new token
 		// In such case, we won't return the error.
 		if err := generateTokenFunc(); err != nil && !accessToken.Valid {
-			return 0, status.Errorf(codes.Unauthenticated, err.Error())
+			return 0, nil, status.Errorf(codes.Unauthenticated, err.Error())
 		}
 	}
-	return principalID, nil
+	return principalID, nil, nil
 }
 
 func getTokenFromMetadata(md metadata.MD) (string, string, error) {
@@ -288,6 +331,25 @@ func GenerateRefreshToken(userName string, userID int, mode common.ReleaseMode,
 	return generateToken(userName, userID, fmt.Sprintf(RefreshTokenAudienceFmt, mode), expirationTime, []byte(secret))
 }
 
+// GenerateLongLivedAPIToken generates a long-lived API token for the personal access token or the service account key.
+// The token ID is carried in the JWT ID claim so that the token can be revoked. The token never expires if expiresTs is 0.
+func GenerateLongLivedAPIToken(userName string, userID int, tokenID int, expiresTs int64, mode common.ReleaseMode, secret string) (string, error) {
+	claims := &claimsMessage{
+		Name: userName,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience: jwt.ClaimStrings{fmt.Sprintf(APITokenAudienceFmt, mode)},
+			IssuedAt: jwt.NewNumericDate(time.Now()),
+			Issuer:   issuer,
+			Subject:  strconv.Itoa(userID),
+			ID:       strconv.Itoa(tokenID),
+		},
+	}
+	if expiresTs > 0 {
+		claims.ExpiresAt = jwt.NewNumericDate(time.Unix(expiresTs, 0))
+	}
+	return signToken(claims, []byte(secret))
+}
+
 // Pay attention to this function. It holds the main JWT token generation logic.
 func generateToken(userName string, userID int, aud string, expirationTime time.Time, secret []byte) (string, error) {
 	// Create the JWT claims, which includes the username and expiry time.
@@ -302,7 +364,10 @@ func generateToken(userName string, userID int, aud string, expirationTime time.
 			Subject:   strconv.Itoa(userID),
 		},
 	}
+	return signToken(claims, secret)
+}
 
+func signToken(claims *claimsMessage, secret []byte) (string, error) {
 	// Declare the token with the HS256 algorithm used for signing, and the claims.
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	token.Header["kid"] = keyID
@@ -0,0 +1,242 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1" //nolint:gosec // TOTP uses HMAC-SHA1 as RFC 6238 specified, which authenticator apps support.
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	errs "github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/common"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/store"
+	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
+)
+
+const (
+	// MFATempTokenAudienceFmt is the format of the MFA temp token audience.
+	// The token is issued by the first login step of the users with MFA enabled, and can only be exchanged for the access token with the second factor.
+	MFATempTokenAudienceFmt = "bb.user.mfa-temp.%s"
+	mfaTempTokenDuration    = 5 * time.Minute
+
+	// otpPeriod is the time step of the TOTP codes in seconds.
+	otpPeriod = 30
+	otpDigits = 6
+	// otpSkew is the number of the time steps before and after the current one accepted for the clock drift.
+	otpSkew = 1
+	// otpSecretSize is the size of the OTP secret in bytes, which is the size of the HMAC-SHA1 output as RFC 4226 recommended.
+	otpSecretSize = 20
+
+	recoveryCodeCount = 10
+	recoveryCodeSize  = 5
+
+	// MaxLoginFailures is the number of the consecutive login failures locking the user out.
+	MaxLoginFailures = 5
+	// LoginLockoutDuration is how long the user is locked out after too many login failures.
+	LoginLockoutDuration = 15 * time.Minute
+)
+
+// mfaEnrollmentAllowlistMethods are the gRPC methods allowed for the users who are required to enroll MFA but haven't yet.
+var mfaEnrollmentAllowlistMethods = map[string]bool{
+	"/bytebase.v1.AuthService/GetUser":    true,
+	"/bytebase.v1.AuthService/UpdateUser": true,
+	"/bytebase.v1.AuthService/Logout":     true,
+}
+
+// IsMFAEnrollmentMethodAllowed returns whether the method is allowed before the required MFA enrollment.
+func IsMFAEnrollmentMethodAllowed(fullMethodName string) bool {
+	return mfaEnrollmentAllowlistMethods[fullMethodName]
+}
+
+// IsMFAEnabled returns whether the user has enrolled MFA.
+func IsMFAEnabled(user *store.UserMessage) bool {
+	return user.MFAConfig != nil && user.MFAConfig.OtpSecret != ""
+}
+
+// IsMFARequired returns whether the user is required to enroll MFA by the workspace setting, which applies to the Owners and DBAs.
+func IsMFARequired(ctx context.Context, s *store.Store, user *store.UserMessage) (bool, error) {
+	if user.Type != api.EndUser || (user.Role != api.Owner && user.Role != api.DBA) {
+		return false, nil
+	}
+	settingName := api.SettingWorkspaceRequireMFA
+	setting, err := s.GetSettingV2(ctx, &store.FindSettingMessage{Name: &settingName})
+	if err != nil {
+		return false, errs.Wrapf(err, "failed to get setting %q", settingName)
+	}
+	return setting != nil && setting.Value == "1", nil
+}
+
+// IsMFAEnrollmentPending returns whether the user is required to enroll MFA but hasn't yet.
+func IsMFAEnrollmentPending(ctx context.Context, s *store.Store, user *store.UserMessage) (bool, error) {
+	if IsMFAEnabled(user) {
+		return false, nil
+	}
+	return IsMFARequired(ctx, s, user)
+}
+
+// GenerateMFATempToken generates the MFA temp token of the user who has passed the first login step.
+func GenerateMFATempToken(userName string, userID int, mode common.ReleaseMode, secret string) (string, error) {
+	expirationTime := time.Now().Add(mfaTempTokenDuration)
+	return generateToken(userName, userID, fmt.Sprintf(MFATempTokenAudienceFmt, mode), expirationTime, []byte(secret))
+}
+
+// ParseMFATempToken returns the user ID of the MFA temp token.
+func ParseMFATempToken(tokenStr string, mode common.ReleaseMode, secret string) (int, error) {
+	claims := &claimsMessage{}
+	if _, err := jwt.ParseWithClaims(tokenStr, claims, func(t *jwt.Token) (interface{}, error) {
+		if t.Method.Alg() != jwt.SigningMethodHS256.Name {
+			return nil, errs.Errorf("unexpected MFA temp token signing method=%v, expect %v", t.Header["alg"], jwt.SigningMethodHS256)
+		}
+		if kid, ok := t.Header["kid"].(string); ok && kid == keyID {
+			return []byte(secret), nil
+		}
+		return nil, errs.Errorf("unexpected MFA temp token kid=%v", t.Header["kid"])
+	}); err != nil {
+		return 0, errs.Wrap(err, "invalid MFA temp token")
+	}
+	if !audienceContains(claims.Audience, fmt.Sprintf(MFATempTokenAudienceFmt, mode)) {
+		return 0, errs.Errorf("invalid MFA temp token, audience mismatch, got %q, expected %q", claims.Audience, fmt.Sprintf(MFATempTokenAudienceFmt, mode))
+	}
+	userID, err := strconv.Atoi(claims.Subject)
+	if err != nil {
+		return 0, errs.Errorf("malformed ID %q in the MFA temp token", claims.Subject)
+	}
+	return userID, nil
+}
+
+// GenerateOTPSecret generates a random base32 encoded OTP secret for the TOTP authenticator.
+func GenerateOTPSecret() (string, error) {
+	secret := make([]byte, otpSecretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(secret), nil
+}
+
+// ValidateOTPCode validates the TOTP code of the base32 encoded secret at the time as RFC 6238 specified.
+func ValidateOTPCode(secret string, code string, now time.Time) bool {
+	code = strings.TrimSpace(code)
+	if len(code) != otpDigits {
+		return false
+	}
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(strings.ToUpper(strings.TrimRight(secret, "=")))
+	if err != nil {
+		return false
+	}
+	counter := now.Unix() / otpPeriod
+	for i := counter - otpSkew; i <= counter+otpSkew; i++ {
+		if subtle.ConstantTimeCompare([]byte(generateOTPCode(key, uint64(i))), []byte(code)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// generateOTPCode generates the HOTP code of the counter as RFC 4226 specified.
+func generateOTPCode(key []byte, counter uint64) string {
+	message := make([]byte, 8)
+	binary.BigEndian.PutUint64(message, counter)
+	mac := hmac.New(sha1.New, key)
+	mac.Write(message)
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	modulo := uint32(1)
+	for i := 0; i < otpDigits; i++ {
+		modulo *= 10
+	}
+	return fmt.Sprintf("%0*d", otpDigits, value%modulo)
+}
+
+// GenerateRecoveryCodes generates the recovery codes and their hashes, only the hashes are stored.
+func GenerateRecoveryCodes() ([]string, []string, error) {
+	var codes, hashes []string
+	for i := 0; i < recoveryCodeCount; i++ {
+		b := make([]byte, recoveryCodeSize)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		code := hex.EncodeToString(b)
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
+
+func hashRecoveryCode(code string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(code))))
+	return hex.EncodeToString(sum[:])
+}
+
+// useRecoveryCode returns the MFA config without the recovery code if it's valid, otherwise nil.
+// Each recovery code can only be used once.
+func useRecoveryCode(config *storepb.MFAConfig, code string) *storepb.MFAConfig {
+	hash := hashRecoveryCode(code)
+	for i, h := range config.RecoveryCodeHashes {
+		if subtle.ConstantTimeCompare([]byte(h), []byte(hash)) != 1 {
+			continue
+		}
+		var hashes []string
+		hashes = append(hashes, config.RecoveryCodeHashes[:i]...)
+		hashes = append(hashes, config.RecoveryCodeHashes[i+1:]...)
+		return &storepb.MFAConfig{
+			OtpSecret:              config.OtpSecret,
+			TempOtpSecret:          config.TempOtpSecret,
+			RecoveryCodeHashes:     hashes,
+			TempRecoveryCodeHashes: config.TempRecoveryCodeHashes,
+		}
+	}
+	return nil
+}
+
+// VerifyMFA verifies the second factor of the user, which is either the OTP code or a recovery code.
+// The used recovery code is removed from the user.
+func VerifyMFA(ctx context.Context, s *store.Store, user *store.UserMessage, otpCode, recoveryCode string) (bool, error) {
+	if !IsMFAEnabled(user) {
+		return false, nil
+	}
+	if otpCode != "" {
+		return ValidateOTPCode(user.MFAConfig.OtpSecret, otpCode, time.Now()), nil
+	}
+	if recoveryCode == "" {
+		return false, nil
+	}
+	config := useRecoveryCode(user.MFAConfig, recoveryCode)
+	if config == nil {
+		return false, nil
+	}
+	if _, err := s.UpdateUser(ctx, user.ID, &store.UpdateUserMessage{MFAConfig: config}, user.ID); err != nil {
+		return false, errs.Wrap(err, "failed to remove the used recovery code")
+	}
+	return true, nil
+}
+
+// GetLoginLockout returns the time until which the user is locked out of login, it's zero if the user isn't locked out.
+func GetLoginLockout(ctx context.Context, s *store.Store, userID int) (time.Time, error) {
+	lockedUntilTs, err := s.GetUserLockedUntil(ctx, userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	lockedUntil := time.Unix(lockedUntilTs, 0)
+	if !lockedUntil.After(time.Now()) {
+		return time.Time{}, nil
+	}
+	return lockedUntil, nil
+}
+
+// RecordLoginFailure counts a failed login of the user with the wrong password or the wrong second factor,
+// and returns whether the user is locked out by it.
+func RecordLoginFailure(ctx context.Context, s *store.Store, userID int) (bool, error) {
+	return s.RecordFailedLogin(ctx, userID, MaxLoginFailures, time.Now().Add(LoginLockoutDuration).Unix())
+}
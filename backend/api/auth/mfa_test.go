@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"encoding/base32"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
+)
+
+func TestValidateOTPCode(t *testing.T) {
+	// The test vector of RFC 6238 for SHA1, with the code truncated to 6 digits.
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+	tests := []struct {
+		code string
+		now  time.Time
+		want bool
+	}{
+		{
+			code: "287082",
+			now:  time.Unix(59, 0),
+			want: true,
+		},
+		{
+			code: "081804",
+			now:  time.Unix(1111111109, 0),
+			want: true,
+		},
+		{
+			// The code of the previous time step is accepted for the clock drift.
+			code: "287082",
+			now:  time.Unix(89, 0),
+			want: true,
+		},
+		{
+			code: "287082",
+			now:  time.Unix(120, 0),
+			want: false,
+		},
+		{
+			code: "28708",
+			now:  time.Unix(59, 0),
+			want: false,
+		},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.want, ValidateOTPCode(secret, test.code, test.now), "code %s at %v", test.code, test.now.Unix())
+	}
+}
+
+func TestUseRecoveryCode(t *testing.T) {
+	codes, hashes, err := GenerateRecoveryCodes()
+	require.NoError(t, err)
+	require.Len(t, codes, recoveryCodeCount)
+	config := &storepb.MFAConfig{
+		OtpSecret:          "secret",
+		RecoveryCodeHashes: hashes,
+	}
+
+	got := useRecoveryCode(config, codes[3])
+	require.NotNil(t, got)
+	require.Equal(t, "secret", got.OtpSecret)
+	require.Len(t, got.RecoveryCodeHashes, recoveryCodeCount-1)
+	require.NotContains(t, got.RecoveryCodeHashes, hashes[3])
+
+	// The used recovery code cannot be used again.
+	require.Nil(t, useRecoveryCode(got, codes[3]))
+	require.Nil(t, useRecoveryCode(config, "invalid"))
+}
@@ -50,13 +50,23 @@ func (in *ACLInterceptor) ACLInterceptor(ctx context.Context, request interface{
 	}
 	// Store workspace role into context.
 	childCtx := context.WithValue(ctx, common.RoleContextKey, user.Role)
+	methodName := getShortMethodName(serverInfo.FullMethod)
+	if isWorkspaceOwnerMethod(methodName) && user.Role != api.Owner {
+		return nil, status.Errorf(codes.PermissionDenied, "only workspace owner can access method %q", methodName)
+	}
 	if isOwnerOrDBA(user.Role) {
 		return handler(childCtx, request)
 	}
 
-	methodName := getShortMethodName(serverInfo.FullMethod)
 	if isOwnerAndDBAMethod(methodName) {
-		return nil, status.Errorf(codes.PermissionDenied, "only workspace owner and DBA can access method %q", methodName)
+		// The custom roles bound in the workspace IAM policy can grant access to the owner and DBA methods.
+		permissions, err := in.store.GetWorkspacePermissions(ctx, user.ID)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to get workspace permissions: %v", err)
+		}
+		if !permissions[string(workspacePermissionMethods[methodName])] {
+			return nil, status.Errorf(codes.PermissionDenied, "only workspace owner and DBA can access method %q", methodName)
+		}
 	}
 
 	if isProjectOwnerMethod(methodName) {
@@ -69,7 +79,11 @@ func (in *ACLInterceptor) ACLInterceptor(ctx context.Context, request interface{
 			if err != nil {
 				return nil, status.Errorf(codes.PermissionDenied, err.Error())
 			}
-			if projectRole != api.Owner {
+			granted, err := in.hasProjectPermission(ctx, projectRole, projectOwnerMethods[methodName])
+			if err != nil {
+				return nil, status.Errorf(codes.Internal, "failed to check project permission: %v", err)
+			}
+			if !granted {
 				return nil, status.Errorf(codes.PermissionDenied, "only the owner of project %q can access method %q", projectID, methodName)
 			}
 		}
@@ -85,7 +99,11 @@ func (in *ACLInterceptor) ACLInterceptor(ctx context.Context, request interface{
 			if err != nil {
 				return nil, status.Errorf(codes.PermissionDenied, err.Error())
 			}
-			if projectRole != api.Owner {
+			granted, err := in.hasProjectPermission(ctx, projectRole, api.ProjectPermissionTransferDatabase)
+			if err != nil {
+				return nil, status.Errorf(codes.Internal, "failed to check project permission: %v", err)
+			}
+			if !granted {
 				return nil, status.Errorf(codes.PermissionDenied, "only project owner can transfer database to project %q", projectID)
 			}
 		}
@@ -111,13 +129,19 @@ func (in *ACLInterceptor) getUser(ctx context.Context) (*store.UserMessage, erro
 		return nil, status.Errorf(codes.PermissionDenied, "the user %v has been deactivated by the admin.", principalID)
 	}
 
-	// If RBAC feature is not enabled, all users are treated as OWNER.
-	if in.licenseService.IsFeatureEnabled(api.FeatureRBAC) {
-		user.Role = api.Owner
-	}
+	user.Role = in.getEffectiveRole(user.Role)
 	return user, nil
 }
 
+// getEffectiveRole returns the workspace role the user acts as.
+// If RBAC feature is not enabled, all users are treated as OWNER. Otherwise, the users keep their own roles.
+func (in *ACLInterceptor) getEffectiveRole(role api.Role) api.Role {
+	if !in.licenseService.IsFeatureEnabled(api.FeatureRBAC) {
+		return api.Owner
+	}
+	return role
+}
+
 func (in *ACLInterceptor) getProjectMember(ctx context.Context, user *store.UserMessage, projectID string) (api.Role, error) {
 	projectPolicy, err := in.store.GetProjectPolicy(ctx, &store.GetProjectPolicyMessage{ProjectID: &projectID})
 	if err != nil {
@@ -133,6 +157,15 @@ func (in *ACLInterceptor) getProjectMember(ctx context.Context, user *store.User
 	return api.UnknownRole, nil
 }
 
+// hasProjectPermission returns whether the project role can access the project owner method requiring the permission.
+// The built-in roles keep the owner-only rule, and the custom roles are granted the permissions they are made of.
+func (in *ACLInterceptor) hasProjectPermission(ctx context.Context, role api.Role, permission api.ProjectPermissionType) (bool, error) {
+	if api.IsBuiltinProjectRole(role) {
+		return role == api.Owner, nil
+	}
+	return in.store.HasProjectPermission(ctx, in.licenseService.GetEffectivePlan(), role, permission)
+}
+
 func getProjectIDs(req interface{}) ([]string, error) {
 	if request, ok := req.(*v1pb.UpdateProjectRequest); ok {
 		if request.Project == nil {
@@ -151,6 +184,13 @@ func getProjectIDs(req interface{}) ([]string, error) {
 		}
 		return []string{projectID}, nil
 	}
+	if request, ok := req.(*v1pb.SetIamPolicyRequest); ok {
+		projectID, err := getProjectID(request.Project)
+		if err != nil {
+			return nil, err
+		}
+		return []string{projectID}, nil
+	}
 	if request, ok := req.(*v1pb.UndeleteProjectRequest); ok {
 		projectID, err := getProjectID(request.Name)
 		if err != nil {
@@ -24,10 +24,37 @@ var ownerAndDBAMethods = map[string]bool{
 	"InstanceService/UpdateDataSource":       true,
 }
 
-var projectOwnerMethods = map[string]bool{
-	"ProjectService/UpdateProject":   true,
-	"ProjectService/DeleteProject":   true,
-	"ProjectService/UndeleteProject": true,
+// workspaceOwnerMethods can only be accessed by the workspace owner, because they grant permissions to the other users
+// or reveal the activities of the other users.
+var workspaceOwnerMethods = map[string]bool{
+	"CustomRoleService/CreateCustomRole":      true,
+	"CustomRoleService/UpdateCustomRole":      true,
+	"CustomRoleService/DeleteCustomRole":      true,
+	"CustomRoleService/SetWorkspaceIamPolicy": true,
+	"AuditLogService/ListAuditLogs":           true,
+}
+
+// workspacePermissionMethods maps the owner and DBA methods to the workspace permissions granting access to them through the custom roles.
+var workspacePermissionMethods = map[string]api.WorkspacePermissionType{
+	"EnvironmentService/CreateEnvironment":   api.WorkspacePermissionManageEnvironment,
+	"EnvironmentService/UpdateEnvironment":   api.WorkspacePermissionManageEnvironment,
+	"EnvironmentService/DeleteEnvironment":   api.WorkspacePermissionManageEnvironment,
+	"EnvironmentService/UndeleteEnvironment": api.WorkspacePermissionManageEnvironment,
+	"InstanceService/CreateInstance":         api.WorkspacePermissionManageInstance,
+	"InstanceService/UpdateInstance":         api.WorkspacePermissionManageInstance,
+	"InstanceService/DeleteInstance":         api.WorkspacePermissionManageInstance,
+	"InstanceService/UndeleteInstance":       api.WorkspacePermissionManageInstance,
+	"InstanceService/AddDataSource":          api.WorkspacePermissionManageInstance,
+	"InstanceService/RemoveDataSource":       api.WorkspacePermissionManageInstance,
+	"InstanceService/UpdateDataSource":       api.WorkspacePermissionManageInstance,
+}
+
+// projectOwnerMethods maps the project owner methods to the project permissions granting access to them through the custom roles.
+var projectOwnerMethods = map[string]api.ProjectPermissionType{
+	"ProjectService/UpdateProject":   api.ProjectPermissionManageGeneral,
+	"ProjectService/DeleteProject":   api.ProjectPermissionManageGeneral,
+	"ProjectService/UndeleteProject": api.ProjectPermissionManageGeneral,
+	"ProjectService/SetIamPolicy":    api.ProjectPermissionManageMember,
 }
 
 var transferDatabaseMethods = map[string]bool{
@@ -39,8 +66,13 @@ func isOwnerAndDBAMethod(methodName string) bool {
 	return ownerAndDBAMethods[methodName]
 }
 
+func isWorkspaceOwnerMethod(methodName string) bool {
+	return workspaceOwnerMethods[methodName]
+}
+
 func isProjectOwnerMethod(methodName string) bool {
-	return projectOwnerMethods[methodName]
+	_, ok := projectOwnerMethods[methodName]
+	return ok
 }
 
 func isTransferDatabaseMethods(methodName string) bool {
@@ -0,0 +1,54 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	enterpriseAPI "github.com/bytebase/bytebase/backend/enterprise/api"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+)
+
+type fakeLicenseService struct {
+	enterpriseAPI.LicenseService
+	features map[api.FeatureType]bool
+}
+
+func (s *fakeLicenseService) IsFeatureEnabled(feature api.FeatureType) bool {
+	return s.features[feature]
+}
+
+func TestGetEffectiveRole(t *testing.T) {
+	tests := []struct {
+		role        api.Role
+		rbacEnabled bool
+		want        api.Role
+	}{
+		{
+			role:        api.Developer,
+			rbacEnabled: true,
+			want:        api.Developer,
+		},
+		{
+			role:        api.DBA,
+			rbacEnabled: true,
+			want:        api.DBA,
+		},
+		{
+			role:        api.Developer,
+			rbacEnabled: false,
+			want:        api.Owner,
+		},
+		{
+			role:        api.Owner,
+			rbacEnabled: false,
+			want:        api.Owner,
+		},
+	}
+
+	a := require.New(t)
+	for _, tt := range tests {
+		in := &ACLInterceptor{licenseService: &fakeLicenseService{features: map[api.FeatureType]bool{api.FeatureRBAC: tt.rbacEnabled}}}
+		a.Equal(tt.want, in.getEffectiveRole(tt.role))
+	}
+}
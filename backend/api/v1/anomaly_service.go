@@ -0,0 +1,146 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/bytebase/bytebase/backend/common"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/store"
+	v1pb "github.com/bytebase/bytebase/proto/generated-go/v1"
+)
+
+// AnomalyService implements the anomaly service.
+type AnomalyService struct {
+	v1pb.UnimplementedAnomalyServiceServer
+	store *store.Store
+}
+
+// NewAnomalyService creates a new AnomalyService.
+func NewAnomalyService(store *store.Store) *AnomalyService {
+	return &AnomalyService{
+		store: store,
+	}
+}
+
+// ListAnomalies lists the active anomalies.
+func (s *AnomalyService) ListAnomalies(ctx context.Context, request *v1pb.ListAnomaliesRequest) (*v1pb.ListAnomaliesResponse, error) {
+	environmentID, instanceID, err := getEnvironmentInstanceID(request.Parent)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+	var severity *api.AnomalySeverity
+	if request.Filter != "" {
+		severityFilter, err := getFilter(request.Filter, "severity")
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, err.Error())
+		}
+		v, err := convertAnomalySeverity(severityFilter)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, err.Error())
+		}
+		severity = &v
+	}
+
+	findInstance := &store.FindInstanceMessage{}
+	if environmentID != "-" {
+		findInstance.EnvironmentID = &environmentID
+	}
+	if instanceID != "-" {
+		findInstance.ResourceID = &instanceID
+	}
+	instances, err := s.store.ListInstancesV2(ctx, findInstance)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	// The workspace owner and DBA can see all the anomalies, while the others can only see the database anomalies in their projects.
+	var projectMemberID *int
+	if role := ctx.Value(common.RoleContextKey).(api.Role); !isOwnerOrDBA(role) {
+		principalID := ctx.Value(common.PrincipalIDContextKey).(int)
+		projectMemberID = &principalID
+	}
+
+	response := &v1pb.ListAnomaliesResponse{}
+	normalRowStatus := api.Normal
+	for _, instance := range instances {
+		anomalies, err := s.store.FindAnomaly(ctx, &api.AnomalyFind{
+			RowStatus:       &normalRowStatus,
+			InstanceID:      &instance.UID,
+			ProjectMemberID: projectMemberID,
+		})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, err.Error())
+		}
+		for _, anomaly := range anomalies {
+			if severity != nil && anomaly.Severity != *severity {
+				continue
+			}
+			response.Anomalies = append(response.Anomalies, convertToAnomaly(instance, anomaly))
+		}
+	}
+	return response, nil
+}
+
+func convertToAnomaly(instance *store.InstanceMessage, anomaly *api.Anomaly) *v1pb.Anomaly {
+	resource := fmt.Sprintf("%s%s/%s%s", environmentNamePrefix, instance.EnvironmentID, instanceNamePrefix, instance.ResourceID)
+	if anomaly.Database != nil {
+		resource = fmt.Sprintf("%s/%s%s", resource, databaseIDPrefix, anomaly.Database.Name)
+	}
+	return &v1pb.Anomaly{
+		Resource:   resource,
+		Type:       convertToAnomalyType(anomaly.Type),
+		Severity:   convertToAnomalySeverity(anomaly.Severity),
+		Payload:    anomaly.Payload,
+		CreateTime: timestamppb.New(time.Unix(anomaly.CreatedTs, 0)),
+		UpdateTime: timestamppb.New(time.Unix(anomaly.UpdatedTs, 0)),
+	}
+}
+
+func convertToAnomalyType(anomalyType api.AnomalyType) v1pb.Anomaly_AnomalyType {
+	switch anomalyType {
+	case api.AnomalyInstanceConnection:
+		return v1pb.Anomaly_INSTANCE_CONNECTION
+	case api.AnomalyInstanceMigrationSchema:
+		return v1pb.Anomaly_INSTANCE_MIGRATION_SCHEMA
+	case api.AnomalyDatabaseBackupPolicyViolation:
+		return v1pb.Anomaly_DATABASE_BACKUP_POLICY_VIOLATION
+	case api.AnomalyDatabaseBackupMissing:
+		return v1pb.Anomaly_DATABASE_BACKUP_MISSING
+	case api.AnomalyDatabaseConnection:
+		return v1pb.Anomaly_DATABASE_CONNECTION
+	case api.AnomalyDatabaseSchemaDrift:
+		return v1pb.Anomaly_DATABASE_SCHEMA_DRIFT
+	}
+	return v1pb.Anomaly_ANOMALY_TYPE_UNSPECIFIED
+}
+
+func convertToAnomalySeverity(severity api.AnomalySeverity) v1pb.Anomaly_AnomalySeverity {
+	switch severity {
+	case api.AnomalySeverityMedium:
+		return v1pb.Anomaly_MEDIUM
+	case api.AnomalySeverityHigh:
+		return v1pb.Anomaly_HIGH
+	case api.AnomalySeverityCritical:
+		return v1pb.Anomaly_CRITICAL
+	}
+	return v1pb.Anomaly_ANOMALY_SEVERITY_UNSPECIFIED
+}
+
+func convertAnomalySeverity(severity string) (api.AnomalySeverity, error) {
+	switch v1pb.Anomaly_AnomalySeverity(v1pb.Anomaly_AnomalySeverity_value[severity]) {
+	case v1pb.Anomaly_MEDIUM:
+		return api.AnomalySeverityMedium, nil
+	case v1pb.Anomaly_HIGH:
+		return api.AnomalySeverityHigh, nil
+	case v1pb.Anomaly_CRITICAL:
+		return api.AnomalySeverityCritical, nil
+	}
+	return "", errors.Errorf("invalid anomaly severity %q", severity)
+}
@@ -0,0 +1,221 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/api/auth"
+	"github.com/bytebase/bytebase/backend/common"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/store"
+	v1pb "github.com/bytebase/bytebase/proto/generated-go/v1"
+)
+
+// APITokenService implements the API token service.
+type APITokenService struct {
+	v1pb.UnimplementedApiTokenServiceServer
+	store  *store.Store
+	secret string
+	mode   common.ReleaseMode
+}
+
+// NewAPITokenService creates a new APITokenService.
+func NewAPITokenService(store *store.Store, secret string, mode common.ReleaseMode) *APITokenService {
+	return &APITokenService{
+		store:  store,
+		secret: secret,
+		mode:   mode,
+	}
+}
+
+// ListApiTokens lists the API tokens of a user.
+func (s *APITokenService) ListApiTokens(ctx context.Context, request *v1pb.ListApiTokensRequest) (*v1pb.ListApiTokensResponse, error) {
+	user, err := s.getTokenUser(ctx, request.Parent, false /* create */)
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := s.store.ListAPITokens(ctx, &store.FindAPITokenMessage{
+		PrincipalID: &user.ID,
+		ShowRevoked: request.ShowRevoked,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	response := &v1pb.ListApiTokensResponse{}
+	for _, token := range tokens {
+		response.ApiTokens = append(response.ApiTokens, convertToAPIToken(token))
+	}
+	return response, nil
+}
+
+// CreateApiToken creates an API token for a user, the token is only returned in the response.
+func (s *APITokenService) CreateApiToken(ctx context.Context, request *v1pb.CreateApiTokenRequest) (*v1pb.CreateApiTokenResponse, error) {
+	if request.ApiToken == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "API token must be set")
+	}
+	if request.ApiToken.Title == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "API token title must be set")
+	}
+	if err := validateAPITokenScopes(request.ApiToken.Scopes); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+	var expiresTs int64
+	if request.ApiToken.ExpireTime != nil {
+		expireTime := request.ApiToken.ExpireTime.AsTime()
+		if !expireTime.After(time.Now()) {
+			return nil, status.Errorf(codes.InvalidArgument, "API token expire time %s must be in the future", expireTime.Format(time.RFC3339))
+		}
+		expiresTs = expireTime.Unix()
+	}
+	user, err := s.getTokenUser(ctx, request.Parent, true /* create */)
+	if err != nil {
+		return nil, err
+	}
+	creatorID, ok := ctx.Value(common.PrincipalIDContextKey).(int)
+	if !ok {
+		return nil, status.Errorf(codes.Internal, "cannot get principal ID from context")
+	}
+
+	token, err := s.store.CreateAPIToken(ctx, &store.APITokenMessage{
+		PrincipalID: user.ID,
+		Name:        request.ApiToken.Title,
+		Scopes:      request.ApiToken.Scopes,
+		ExpiresTs:   expiresTs,
+	}, creatorID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	tokenString, err := auth.GenerateLongLivedAPIToken(user.Name, user.ID, token.UID, token.ExpiresTs, s.mode, s.secret)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to generate API token, error: %v", err)
+	}
+	return &v1pb.CreateApiTokenResponse{
+		ApiToken: convertToAPIToken(token),
+		Token:    tokenString,
+	}, nil
+}
+
+// RevokeApiToken revokes an API token.
+func (s *APITokenService) RevokeApiToken(ctx context.Context, request *v1pb.RevokeApiTokenRequest) (*v1pb.ApiToken, error) {
+	userID, tokenID, err := getUserIDAndAPITokenID(request.Name)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+	if _, err := s.getTokenUser(ctx, fmt.Sprintf("%s%d", userNamePrefix, userID), false /* create */); err != nil {
+		return nil, err
+	}
+	token, err := s.store.GetAPIToken(ctx, tokenID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	if token == nil || token.PrincipalID != userID {
+		return nil, status.Errorf(codes.NotFound, "API token %q not found", request.Name)
+	}
+	if token.Revoked {
+		return nil, status.Errorf(codes.FailedPrecondition, "API token %q has already been revoked", request.Name)
+	}
+	updaterID, ok := ctx.Value(common.PrincipalIDContextKey).(int)
+	if !ok {
+		return nil, status.Errorf(codes.Internal, "cannot get principal ID from context")
+	}
+
+	if err := s.store.RevokeAPIToken(ctx, tokenID, updaterID); err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	token.Revoked = true
+	return convertToAPIToken(token), nil
+}
+
+// getTokenUser gets the user of the API tokens and checks the caller can manage them.
+// The users manage their own personal access tokens, and the workspace owners manage the service account keys.
+// The workspace owners can also list and revoke the tokens of the other users, but can't create tokens acting as them.
+func (s *APITokenService) getTokenUser(ctx context.Context, name string, create bool) (*store.UserMessage, error) {
+	userID, err := getUserID(name)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+	user, err := s.store.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	if user == nil {
+		return nil, status.Errorf(codes.NotFound, "user %q not found", name)
+	}
+	if user.Type == api.SystemBot {
+		return nil, status.Errorf(codes.InvalidArgument, "the system bot can't have API tokens")
+	}
+
+	principalID, ok := ctx.Value(common.PrincipalIDContextKey).(int)
+	if !ok {
+		return nil, status.Errorf(codes.Internal, "cannot get principal ID from context")
+	}
+	if principalID == user.ID {
+		return user, nil
+	}
+	role, ok := ctx.Value(common.RoleContextKey).(api.Role)
+	if !ok || role != api.Owner {
+		return nil, status.Errorf(codes.PermissionDenied, "only the user and the workspace owner can manage the API tokens of user %q", name)
+	}
+	if create && user.Type != api.ServiceAccount {
+		return nil, status.Errorf(codes.PermissionDenied, "workspace owner can only create API tokens for the service accounts")
+	}
+	return user, nil
+}
+
+func convertToAPIToken(token *store.APITokenMessage) *v1pb.ApiToken {
+	apiToken := &v1pb.ApiToken{
+		Name:       fmt.Sprintf("%s%d/%s%d", userNamePrefix, token.PrincipalID, apiTokenNamePrefix, token.UID),
+		Title:      token.Name,
+		Scopes:     token.Scopes,
+		CreateTime: timestamppb.New(time.Unix(token.CreatedTs, 0)),
+		Revoked:    token.Revoked,
+	}
+	if token.ExpiresTs > 0 {
+		apiToken.ExpireTime = timestamppb.New(time.Unix(token.ExpiresTs, 0))
+	}
+	if token.LastUsedTs > 0 {
+		apiToken.LastUsedTime = timestamppb.New(time.Unix(token.LastUsedTs, 0))
+	}
+	return apiToken
+}
+
+func validateAPITokenScopes(scopes []string) error {
+	if len(scopes) == 0 {
+		return errors.Errorf("API token must have at least one scope")
+	}
+	scopeMap := make(map[string]bool)
+	for _, scope := range scopes {
+		if !api.IsValidAPITokenScope(scope) {
+			return errors.Errorf("invalid API token scope %q", scope)
+		}
+		if scopeMap[scope] {
+			return errors.Errorf("duplicate API token scope %q", scope)
+		}
+		scopeMap[scope] = true
+	}
+	return nil
+}
+
+func getUserIDAndAPITokenID(name string) (int, int, error) {
+	tokens, err := getNameParentTokens(name, userNamePrefix, apiTokenNamePrefix)
+	if err != nil {
+		return 0, 0, err
+	}
+	userID, err := strconv.Atoi(tokens[0])
+	if err != nil {
+		return 0, 0, errors.Errorf("invalid user ID %q", tokens[0])
+	}
+	tokenID, err := strconv.Atoi(tokens[1])
+	if err != nil {
+		return 0, 0, errors.Errorf("invalid API token ID %q", tokens[1])
+	}
+	return userID, tokenID, nil
+}
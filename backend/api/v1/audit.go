@@ -0,0 +1,195 @@
+package v1
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	"github.com/bytebase/bytebase/backend/common"
+	"github.com/bytebase/bytebase/backend/component/audit"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+// readOnlyMethodPrefixes are the method name prefixes of the read-only methods, which are not audited.
+var readOnlyMethodPrefixes = []string{"Get", "List", "Search"}
+
+// AuditInterceptor is the v1 audit interceptor for gRPC server.
+type AuditInterceptor struct {
+	auditManager *audit.Manager
+}
+
+// NewAuditInterceptor returns a new v1 API audit interceptor.
+func NewAuditInterceptor(auditManager *audit.Manager) *AuditInterceptor {
+	return &AuditInterceptor{
+		auditManager: auditManager,
+	}
+}
+
+// AuditInterceptor is the unary interceptor for gRPC API.
+// It must be chained before the authentication interceptor to record the failed authentications.
+// The call is recorded after it has been handled by the authentication and ACL interceptors, and nothing is read on behalf of the caller.
+func (in *AuditInterceptor) AuditInterceptor(ctx context.Context, request interface{}, serverInfo *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	methodName := getShortMethodName(serverInfo.FullMethod)
+	readOnly := hasAnyPrefix(methodName, readOnlyMethodPrefixes)
+	actorID := 0
+	ctx = context.WithValue(ctx, common.AuditActorContextKey, &actorID)
+
+	response, err := handler(ctx, request)
+
+	code := status.Code(err)
+	if readOnly && code != codes.Unauthenticated {
+		return response, err
+	}
+	requestMessage, _ := request.(proto.Message)
+	var responseMessage proto.Message
+	if err == nil {
+		responseMessage, _ = response.(proto.Message)
+	}
+	auditLog := &store.AuditLogMessage{
+		ActorID:  actorID,
+		Method:   serverInfo.FullMethod,
+		Resource: getCallResourceName(requestMessage, responseMessage),
+		Status:   code.String(),
+	}
+	if err != nil {
+		auditLog.Error = err.Error()
+	}
+	if requestMessage != nil {
+		auditLog.Request = audit.Redact(marshalAuditMessage(requestMessage))
+	}
+	if err == nil && !readOnly && !strings.HasPrefix(methodName, "Delete") {
+		auditLog.Diff = audit.Diff(nil, marshalAuditMessage(getChangedResource(requestMessage, responseMessage)))
+	}
+	auditLog.ClientIP, auditLog.UserAgent = getClientInfo(ctx)
+	in.auditManager.Record(ctx, auditLog)
+	return response, err
+}
+
+// getCallResourceName returns the name of the resource of the call.
+// The name in the response takes precedence, since the name of the created resource is only known after the call.
+func getCallResourceName(request, response proto.Message) string {
+	if !isNilMessage(response) {
+		if name := getStringField(response.ProtoReflect(), "name"); name != "" {
+			return name
+		}
+	}
+	return getResourceName(request)
+}
+
+// getChangedResource returns the resource in the response with only the fields in the update mask of the request.
+// The whole resource is returned if the request has no update mask, e.g. the create requests.
+func getChangedResource(request, response proto.Message) proto.Message {
+	if isNilMessage(response) || isNilMessage(request) {
+		return response
+	}
+	maskField := request.ProtoReflect().Descriptor().Fields().ByName("update_mask")
+	if maskField == nil || maskField.Kind() != protoreflect.MessageKind || !request.ProtoReflect().Has(maskField) {
+		return response
+	}
+	updateMask, ok := request.ProtoReflect().Get(maskField).Message().Interface().(*fieldmaskpb.FieldMask)
+	if !ok || len(updateMask.Paths) == 0 {
+		return response
+	}
+	// The update mask paths are the fields of the resource, we only keep the top-level fields.
+	paths := make(map[protoreflect.Name]bool)
+	for _, path := range updateMask.Paths {
+		paths[protoreflect.Name(strings.Split(path, ".")[0])] = true
+	}
+	changed := proto.Clone(response).ProtoReflect()
+	fields := changed.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		if field := fields.Get(i); field.Name() != "name" && !paths[field.Name()] {
+			changed.Clear(field)
+		}
+	}
+	return changed.Interface()
+}
+
+// getResourceName returns the name of the resource in the request, which is the name, the parent,
+// or the name of the nested resource, e.g. UpdateInstanceRequest.instance.name.
+func getResourceName(request proto.Message) string {
+	if request == nil {
+		return ""
+	}
+	message := request.ProtoReflect()
+	for _, name := range []protoreflect.Name{"name", "parent", "project"} {
+		if value := getStringField(message, name); value != "" {
+			return value
+		}
+	}
+	fields := message.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		if field.Kind() != protoreflect.MessageKind || field.IsList() || field.IsMap() || !message.Has(field) {
+			continue
+		}
+		if value := getStringField(message.Get(field).Message(), "name"); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func getStringField(message protoreflect.Message, name protoreflect.Name) string {
+	field := message.Descriptor().Fields().ByName(name)
+	if field == nil || field.Kind() != protoreflect.StringKind || field.IsList() {
+		return ""
+	}
+	return message.Get(field).String()
+}
+
+func marshalAuditMessage(message proto.Message) []byte {
+	if isNilMessage(message) {
+		return nil
+	}
+	content, err := protojson.Marshal(message)
+	if err != nil {
+		return nil
+	}
+	return content
+}
+
+// getClientInfo returns the client IP and user agent of the call, the ones forwarded by the gRPC gateway take precedence.
+func getClientInfo(ctx context.Context) (string, string) {
+	var clientIP, userAgent string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("x-forwarded-for"); len(values) > 0 {
+			clientIP = strings.TrimSpace(strings.Split(values[0], ",")[0])
+		}
+		for _, key := range []string{"grpcgateway-user-agent", "user-agent"} {
+			if values := md.Get(key); len(values) > 0 {
+				userAgent = values[0]
+				break
+			}
+		}
+	}
+	if clientIP == "" {
+		if p, ok := peer.FromContext(ctx); ok {
+			clientIP = p.Addr.String()
+		}
+	}
+	return clientIP, userAgent
+}
+
+func isNilMessage(message proto.Message) bool {
+	return message == nil || reflect.ValueOf(message).IsNil()
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/bytebase/bytebase/backend/store"
+	v1pb "github.com/bytebase/bytebase/proto/generated-go/v1"
+)
+
+const (
+	auditLogNamePrefix      = "auditLogs/"
+	defaultAuditLogPageSize = 100
+	maxAuditLogPageSize     = 1000
+)
+
+// AuditLogService implements the audit log service.
+type AuditLogService struct {
+	v1pb.UnimplementedAuditLogServiceServer
+	store *store.Store
+}
+
+// NewAuditLogService creates a new AuditLogService.
+func NewAuditLogService(store *store.Store) *AuditLogService {
+	return &AuditLogService{
+		store: store,
+	}
+}
+
+// ListAuditLogs lists the audit logs.
+func (s *AuditLogService) ListAuditLogs(ctx context.Context, request *v1pb.ListAuditLogsRequest) (*v1pb.ListAuditLogsResponse, error) {
+	limit := int(request.PageSize)
+	if limit <= 0 {
+		limit = defaultAuditLogPageSize
+	}
+	if limit > maxAuditLogPageSize {
+		limit = maxAuditLogPageSize
+	}
+	// Fetch one more audit log to tell whether there is a next page.
+	limitPlusOne := limit + 1
+	find := &store.FindAuditLogMessage{Limit: &limitPlusOne}
+	if request.PageToken != "" {
+		afterID, err := strconv.ParseInt(request.PageToken, 10, 64)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid page token %q", request.PageToken)
+		}
+		find.AfterID = &afterID
+	}
+	if request.Actor != "" {
+		actorID, err := getUserID(request.Actor)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, err.Error())
+		}
+		find.ActorID = &actorID
+	}
+	if request.Method != "" {
+		find.Method = &request.Method
+	}
+	if request.ResourcePrefix != "" {
+		find.ResourcePrefix = &request.ResourcePrefix
+	}
+	if request.StartTime != nil {
+		startTs := request.StartTime.AsTime().Unix()
+		find.CreatedTsAfter = &startTs
+	}
+	if request.EndTime != nil {
+		endTs := request.EndTime.AsTime().Unix()
+		find.CreatedTsBefore = &endTs
+	}
+
+	auditLogs, err := s.store.ListAuditLogs(ctx, find)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	response := &v1pb.ListAuditLogsResponse{}
+	if len(auditLogs) > limit {
+		auditLogs = auditLogs[:limit]
+		response.NextPageToken = strconv.FormatInt(auditLogs[limit-1].ID, 10)
+	}
+	for _, auditLog := range auditLogs {
+		response.AuditLogs = append(response.AuditLogs, convertToAuditLog(auditLog))
+	}
+	return response, nil
+}
+
+func convertToAuditLog(auditLog *store.AuditLogMessage) *v1pb.AuditLog {
+	result := &v1pb.AuditLog{
+		Name:       fmt.Sprintf("%s%d", auditLogNamePrefix, auditLog.ID),
+		CreateTime: timestamppb.New(time.Unix(auditLog.CreatedTs, 0)),
+		Method:     auditLog.Method,
+		Resource:   auditLog.Resource,
+		Status:     auditLog.Status,
+		Error:      auditLog.Error,
+		Request:    auditLog.Request,
+		Diff:       auditLog.Diff,
+		ClientIp:   auditLog.ClientIP,
+		UserAgent:  auditLog.UserAgent,
+		PrevHash:   auditLog.PrevHash,
+		Hash:       auditLog.Hash,
+	}
+	if auditLog.ActorID > 0 {
+		result.Actor = fmt.Sprintf("%s%d", userNamePrefix, auditLog.ActorID)
+	}
+	return result
+}
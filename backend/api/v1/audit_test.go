@@ -0,0 +1,67 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	v1pb "github.com/bytebase/bytebase/proto/generated-go/v1"
+)
+
+func TestGetResourceName(t *testing.T) {
+	tests := []struct {
+		request proto.Message
+		want    string
+	}{
+		{
+			request: &v1pb.DeleteInstanceRequest{Name: "environments/prod/instances/mysql"},
+			want:    "environments/prod/instances/mysql",
+		},
+		{
+			request: &v1pb.UpdateInstanceRequest{Instance: &v1pb.Instance{Name: "environments/prod/instances/mysql"}},
+			want:    "environments/prod/instances/mysql",
+		},
+		{
+			request: &v1pb.SetIamPolicyRequest{Project: "projects/hr"},
+			want:    "projects/hr",
+		},
+		{
+			request: &v1pb.LoginRequest{Email: "dev@example.com"},
+			want:    "",
+		},
+	}
+
+	a := require.New(t)
+	for _, tt := range tests {
+		a.Equal(tt.want, getResourceName(tt.request))
+	}
+}
+
+func TestGetCallResourceName(t *testing.T) {
+	a := require.New(t)
+	// The name of the created resource is in the response.
+	a.Equal("environments/prod/instances/mysql", getCallResourceName(&v1pb.CreateInstanceRequest{Parent: "environments/prod"}, &v1pb.Instance{Name: "environments/prod/instances/mysql"}))
+	// The response is nil if the call fails.
+	a.Equal("environments/prod", getCallResourceName(&v1pb.CreateInstanceRequest{Parent: "environments/prod"}, nil))
+	var instance *v1pb.Instance
+	a.Equal("environments/prod", getCallResourceName(&v1pb.CreateInstanceRequest{Parent: "environments/prod"}, instance))
+}
+
+func TestGetChangedResource(t *testing.T) {
+	a := require.New(t)
+	instance := &v1pb.Instance{Name: "environments/prod/instances/mysql", Title: "MySQL", Engine: v1pb.Engine_MYSQL}
+
+	// Only the fields in the update mask are kept besides the name.
+	request := &v1pb.UpdateInstanceRequest{
+		Instance:   &v1pb.Instance{Name: "environments/prod/instances/mysql", Title: "MySQL"},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"title"}},
+	}
+	a.True(proto.Equal(&v1pb.Instance{Name: "environments/prod/instances/mysql", Title: "MySQL"}, getChangedResource(request, instance)))
+	// The response is not modified.
+	a.Equal(v1pb.Engine_MYSQL, instance.Engine)
+
+	// The whole resource is kept without the update mask.
+	a.True(proto.Equal(instance, getChangedResource(&v1pb.CreateInstanceRequest{Parent: "environments/prod"}, instance)))
+}
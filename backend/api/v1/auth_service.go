@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/mail"
+	"time"
 
 	"golang.org/x/crypto/bcrypt"
 	"google.golang.org/grpc"
@@ -237,6 +238,70 @@ func (s *AuthService) UpdateUser(ctx context.Context, request *v1pb.UpdateUserRe
 				return nil, status.Errorf(codes.InvalidArgument, "invalid user role %s", request.User.UserRole)
 			}
 			patch.Role = &userRole
+		case "user.mfa_enabled":
+			if request.User.MfaEnabled {
+				if user.MFAConfig.GetTempOtpSecret() == "" {
+					return nil, status.Errorf(codes.InvalidArgument, "MFA secret is not generated, regenerate_temp_mfa_secret first")
+				}
+				if !auth.ValidateOTPCode(user.MFAConfig.GetTempOtpSecret(), request.OtpCode, time.Now()) {
+					return nil, status.Errorf(codes.InvalidArgument, "invalid OTP code")
+				}
+				patch.MFAConfig = &storepb.MFAConfig{
+					OtpSecret:          user.MFAConfig.GetTempOtpSecret(),
+					RecoveryCodeHashes: user.MFAConfig.GetTempRecoveryCodeHashes(),
+				}
+			} else {
+				if principalID == userID {
+					required, err := auth.IsMFARequired(ctx, s.store, user)
+					if err != nil {
+						return nil, status.Errorf(codes.Internal, "failed to check MFA requirement, error: %v", err)
+					}
+					if required {
+						return nil, status.Errorf(codes.PermissionDenied, "MFA is required by the workspace and cannot be disabled")
+					}
+				}
+				patch.MFAConfig = &storepb.MFAConfig{}
+			}
+		}
+	}
+
+	var mfaSecret string
+	var recoveryCodes []string
+	if request.RegenerateTempMfaSecret {
+		if principalID != userID {
+			return nil, status.Errorf(codes.PermissionDenied, "only user itself can enroll MFA")
+		}
+		mfaSecret, err = auth.GenerateOTPSecret()
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to generate MFA secret, error: %v", err)
+		}
+		var recoveryCodeHashes []string
+		recoveryCodes, recoveryCodeHashes, err = auth.GenerateRecoveryCodes()
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to generate recovery codes, error: %v", err)
+		}
+		patch.MFAConfig = &storepb.MFAConfig{
+			OtpSecret:              user.MFAConfig.GetOtpSecret(),
+			RecoveryCodeHashes:     user.MFAConfig.GetRecoveryCodeHashes(),
+			TempOtpSecret:          mfaSecret,
+			TempRecoveryCodeHashes: recoveryCodeHashes,
+		}
+	}
+	if request.RegenerateRecoveryCodes {
+		if principalID != userID {
+			return nil, status.Errorf(codes.PermissionDenied, "only user itself can regenerate recovery codes")
+		}
+		if !auth.IsMFAEnabled(user) {
+			return nil, status.Errorf(codes.FailedPrecondition, "MFA is not enabled for user %q", user.Email)
+		}
+		var recoveryCodeHashes []string
+		recoveryCodes, recoveryCodeHashes, err = auth.GenerateRecoveryCodes()
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to generate recovery codes, error: %v", err)
+		}
+		patch.MFAConfig = &storepb.MFAConfig{
+			OtpSecret:          user.MFAConfig.GetOtpSecret(),
+			RecoveryCodeHashes: recoveryCodeHashes,
 		}
 	}
 
@@ -244,7 +309,11 @@ func (s *AuthService) UpdateUser(ctx context.Context, request *v1pb.UpdateUserRe
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to update user, error: %v", err)
 	}
-	return convertToUser(user), nil
+	userMessage := convertToUser(user)
+	// The MFA secret and recovery codes are only returned once after they're generated.
+	userMessage.MfaSecret = mfaSecret
+	userMessage.RecoveryCodes = recoveryCodes
+	return userMessage, nil
 }
 
 // DeleteUser deletes a user.
@@ -327,12 +396,13 @@ func convertToUser(user *store.UserMessage) *v1pb.User {
 	}
 
 	convertedUser := &v1pb.User{
-		Name:     fmt.Sprintf("%s%d", userNamePrefix, user.ID),
-		State:    convertDeletedToState(user.MemberDeleted),
-		Email:    user.Email,
-		Title:    user.Name,
-		UserType: userType,
-		UserRole: role,
+		Name:       fmt.Sprintf("%s%d", userNamePrefix, user.ID),
+		State:      convertDeletedToState(user.MemberDeleted),
+		Email:      user.Email,
+		Title:      user.Name,
+		UserType:   userType,
+		UserRole:   role,
+		MfaEnabled: user.MFAConfig.GetOtpSecret() != "",
 	}
 	if user.IdentityProviderResourceID != nil {
 		convertedUser.IdentityProvider = fmt.Sprintf("%s%s", identityProviderNamePrefix, *user.IdentityProviderResourceID)
@@ -356,7 +426,9 @@ func convertUserRole(userRole v1pb.UserRole) api.Role {
 func (s *AuthService) Login(ctx context.Context, request *v1pb.LoginRequest) (*v1pb.LoginResponse, error) {
 	var loginUser *store.UserMessage
 	var err error
-	if request.IdpName == "" {
+	if request.MfaTempToken != "" {
+		loginUser, err = s.getUserWithLoginRequestOfMFA(ctx, request)
+	} else if request.IdpName == "" {
 		loginUser, err = s.getUserWithLoginRequestOfBytebase(ctx, request)
 	} else {
 		loginUser, err = s.getUserWithLoginRequestOfIdentityProvider(ctx, request)
@@ -367,6 +439,19 @@ func (s *AuthService) Login(ctx context.Context, request *v1pb.LoginRequest) (*v
 	if loginUser == nil {
 		return nil, status.Errorf(codes.Unauthenticated, "login user not found")
 	}
+	// The users with MFA enabled get a temp token by the first factor, and exchange it for the access token with the second factor.
+	if request.MfaTempToken == "" && auth.IsMFAEnabled(loginUser) {
+		mfaTempToken, err := auth.GenerateMFATempToken(loginUser.Name, loginUser.ID, s.profile.Mode, s.secret)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to generate MFA temp token")
+		}
+		return &v1pb.LoginResponse{
+			MfaTempToken: mfaTempToken,
+		}, nil
+	}
+	if err := s.store.ResetFailedLogins(ctx, loginUser.ID); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to reset failed logins, error: %v", err)
+	}
 
 	var accessToken string
 	if request.Web {
@@ -426,14 +511,80 @@ func (s *AuthService) getUserWithLoginRequestOfBytebase(ctx context.Context, req
 	if user.IdentityProviderResourceID != nil {
 		return nil, status.Errorf(codes.InvalidArgument, "user %q only can login by SSO", request.Email)
 	}
+	if err := s.checkLoginLockout(ctx, user); err != nil {
+		return nil, err
+	}
 	// Compare the stored hashed password, with the hashed version of the password that was received.
 	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(request.Password)); err != nil {
+		if err := s.recordLoginFailure(ctx, user); err != nil {
+			return nil, err
+		}
 		// If the two passwords don't match, return a 401 status.
 		return nil, status.Errorf(codes.InvalidArgument, "incorrect password")
 	}
 	return user, nil
 }
 
+// getUserWithLoginRequestOfMFA returns the user of the MFA temp token if the second factor is valid.
+func (s *AuthService) getUserWithLoginRequestOfMFA(ctx context.Context, request *v1pb.LoginRequest) (*store.UserMessage, error) {
+	userID, err := auth.ParseMFATempToken(request.MfaTempToken, s.profile.Mode, s.secret)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "failed to verify MFA temp token, error: %v", err)
+	}
+	user, err := s.store.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get user, error: %v", err)
+	}
+	if user == nil {
+		return nil, status.Errorf(codes.Unauthenticated, "user %d not found", userID)
+	}
+	if user.MemberDeleted {
+		return nil, status.Errorf(codes.Unauthenticated, "user %q has been deactivated by administrators", user.Email)
+	}
+	if err := s.checkLoginLockout(ctx, user); err != nil {
+		return nil, err
+	}
+	if request.OtpCode == "" && request.RecoveryCode == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "OTP code or recovery code is required")
+	}
+	verified, err := auth.VerifyMFA(ctx, s.store, user, request.OtpCode, request.RecoveryCode)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to verify MFA, error: %v", err)
+	}
+	if !verified {
+		if err := s.recordLoginFailure(ctx, user); err != nil {
+			return nil, err
+		}
+		if request.OtpCode != "" {
+			return nil, status.Errorf(codes.Unauthenticated, "invalid OTP code")
+		}
+		return nil, status.Errorf(codes.Unauthenticated, "invalid recovery code")
+	}
+	return user, nil
+}
+
+func (s *AuthService) checkLoginLockout(ctx context.Context, user *store.UserMessage) error {
+	lockedUntil, err := auth.GetLoginLockout(ctx, s.store, user.ID)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to get login lockout of user %q, error: %v", user.Email, err)
+	}
+	if !lockedUntil.IsZero() {
+		return status.Errorf(codes.PermissionDenied, "user %q is locked out due to too many failed login attempts, please retry after %s", user.Email, lockedUntil.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
+func (s *AuthService) recordLoginFailure(ctx context.Context, user *store.UserMessage) error {
+	locked, err := auth.RecordLoginFailure(ctx, s.store, user.ID)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to record login failure of user %q, error: %v", user.Email, err)
+	}
+	if locked {
+		return status.Errorf(codes.PermissionDenied, "user %q is locked out for %v due to too many failed login attempts", user.Email, auth.LoginLockoutDuration)
+	}
+	return nil
+}
+
 func (s *AuthService) getUserWithLoginRequestOfIdentityProvider(ctx context.Context, request *v1pb.LoginRequest) (*store.UserMessage, error) {
 	identityProviderID, err := getIdentityProviderID(request.IdpName)
 	if err != nil {
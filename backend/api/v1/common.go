@@ -23,6 +23,8 @@ const (
 	userNamePrefix             = "users/"
 	identityProviderNamePrefix = "idps/"
 	settingNamePrefix          = "settings/"
+	roleNamePrefix             = "roles/"
+	apiTokenNamePrefix         = "tokens/"
 
 	deploymentConfigSuffix = "/deploymentConfig"
 	backupSettingSuffix    = "/backupSetting"
@@ -123,6 +125,14 @@ func getIdentityProviderID(name string) (string, error) {
 	return tokens[0], nil
 }
 
+func getRoleID(name string) (string, error) {
+	tokens, err := getNameParentTokens(name, roleNamePrefix)
+	if err != nil {
+		return "", err
+	}
+	return tokens[0], nil
+}
+
 func trimSuffix(name, suffix string) (string, error) {
 	if !strings.HasSuffix(name, suffix) {
 		return "", errors.Errorf("invalid request %q with suffix %q", name, suffix)
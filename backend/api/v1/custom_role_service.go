@@ -0,0 +1,271 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/common"
+	enterpriseAPI "github.com/bytebase/bytebase/backend/enterprise/api"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/store"
+	v1pb "github.com/bytebase/bytebase/proto/generated-go/v1"
+)
+
+// CustomRoleService implements the custom role service.
+type CustomRoleService struct {
+	v1pb.UnimplementedCustomRoleServiceServer
+	store          *store.Store
+	licenseService enterpriseAPI.LicenseService
+}
+
+// NewCustomRoleService creates a new CustomRoleService.
+func NewCustomRoleService(store *store.Store, licenseService enterpriseAPI.LicenseService) *CustomRoleService {
+	return &CustomRoleService{
+		store:          store,
+		licenseService: licenseService,
+	}
+}
+
+// ListCustomRoles lists the custom roles.
+func (s *CustomRoleService) ListCustomRoles(ctx context.Context, _ *v1pb.ListCustomRolesRequest) (*v1pb.ListCustomRolesResponse, error) {
+	roles, err := s.store.ListRoles(ctx, &store.FindRoleMessage{})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	response := &v1pb.ListCustomRolesResponse{}
+	for _, role := range roles {
+		response.CustomRoles = append(response.CustomRoles, convertToCustomRole(role))
+	}
+	return response, nil
+}
+
+// CreateCustomRole creates a custom role.
+func (s *CustomRoleService) CreateCustomRole(ctx context.Context, request *v1pb.CreateCustomRoleRequest) (*v1pb.CustomRole, error) {
+	if !s.licenseService.IsFeatureEnabled(api.FeatureRBAC) {
+		return nil, status.Errorf(codes.PermissionDenied, api.FeatureRBAC.AccessErrorMessage())
+	}
+	if request.CustomRole == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "custom role must be set")
+	}
+	if !isValidResourceID(request.CustomRoleId) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid custom role ID %v", request.CustomRoleId)
+	}
+	if request.CustomRole.Title == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "custom role title must be set")
+	}
+	if err := validatePermissions(request.CustomRole.Permissions); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+	creatorID, ok := ctx.Value(common.PrincipalIDContextKey).(int)
+	if !ok {
+		return nil, status.Errorf(codes.Internal, "cannot get principal ID from context")
+	}
+
+	existing, err := s.store.GetRole(ctx, request.CustomRoleId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	if existing != nil {
+		return nil, status.Errorf(codes.AlreadyExists, "custom role %q already exists", request.CustomRoleId)
+	}
+	role, err := s.store.CreateRole(ctx, &store.RoleMessage{
+		ResourceID:  request.CustomRoleId,
+		Name:        request.CustomRole.Title,
+		Description: request.CustomRole.Description,
+		Permissions: request.CustomRole.Permissions,
+	}, creatorID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	return convertToCustomRole(role), nil
+}
+
+// UpdateCustomRole updates a custom role.
+func (s *CustomRoleService) UpdateCustomRole(ctx context.Context, request *v1pb.UpdateCustomRoleRequest) (*v1pb.CustomRole, error) {
+	if request.CustomRole == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "custom role must be set")
+	}
+	if request.UpdateMask == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "update_mask must be set")
+	}
+	role, err := s.getRoleMessage(ctx, request.CustomRole.Name)
+	if err != nil {
+		return nil, err
+	}
+	updaterID, ok := ctx.Value(common.PrincipalIDContextKey).(int)
+	if !ok {
+		return nil, status.Errorf(codes.Internal, "cannot get principal ID from context")
+	}
+
+	patch := &store.UpdateRoleMessage{
+		UpdaterID:  updaterID,
+		ResourceID: role.ResourceID,
+	}
+	for _, path := range request.UpdateMask.Paths {
+		switch path {
+		case "custom_role.title":
+			if request.CustomRole.Title == "" {
+				return nil, status.Errorf(codes.InvalidArgument, "custom role title must be set")
+			}
+			patch.Name = &request.CustomRole.Title
+		case "custom_role.description":
+			patch.Description = &request.CustomRole.Description
+		case "custom_role.permissions":
+			if err := validatePermissions(request.CustomRole.Permissions); err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, err.Error())
+			}
+			patch.Permissions = &request.CustomRole.Permissions
+		}
+	}
+
+	role, err = s.store.UpdateRole(ctx, patch)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	return convertToCustomRole(role), nil
+}
+
+// DeleteCustomRole deletes a custom role.
+func (s *CustomRoleService) DeleteCustomRole(ctx context.Context, request *v1pb.DeleteCustomRoleRequest) (*emptypb.Empty, error) {
+	role, err := s.getRoleMessage(ctx, request.Name)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.store.DeleteRole(ctx, role.ResourceID); err != nil {
+		if common.ErrorCode(err) == common.Conflict {
+			return nil, status.Errorf(codes.FailedPrecondition, err.Error())
+		}
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// GetWorkspaceIamPolicy gets the custom role bindings of the workspace.
+func (s *CustomRoleService) GetWorkspaceIamPolicy(ctx context.Context, _ *v1pb.GetWorkspaceIamPolicyRequest) (*v1pb.IamPolicy, error) {
+	policy, err := s.store.GetWorkspaceIAMPolicy(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	return convertToIamPolicy(policy), nil
+}
+
+// SetWorkspaceIamPolicy sets the custom role bindings of the workspace.
+func (s *CustomRoleService) SetWorkspaceIamPolicy(ctx context.Context, request *v1pb.SetWorkspaceIamPolicyRequest) (*v1pb.IamPolicy, error) {
+	if !s.licenseService.IsFeatureEnabled(api.FeatureRBAC) {
+		return nil, status.Errorf(codes.PermissionDenied, api.FeatureRBAC.AccessErrorMessage())
+	}
+	if request.Policy == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "IAM Policy is required")
+	}
+	if err := validateWorkspaceBindings(request.Policy.Bindings); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+	updaterID, ok := ctx.Value(common.PrincipalIDContextKey).(int)
+	if !ok {
+		return nil, status.Errorf(codes.Internal, "cannot get principal ID from context")
+	}
+
+	policy := &store.IAMPolicyMessage{}
+	for _, binding := range request.Policy.Bindings {
+		role, err := s.getRoleMessage(ctx, binding.CustomRole)
+		if err != nil {
+			return nil, err
+		}
+		storeBinding := &store.PolicyBinding{Role: api.Role(role.ResourceID)}
+		for _, member := range binding.Members {
+			user, err := s.store.GetUserByEmail(ctx, getUserEmailFromIdentifier(member))
+			if err != nil {
+				return nil, status.Errorf(codes.Internal, err.Error())
+			}
+			if user == nil {
+				return nil, status.Errorf(codes.NotFound, "user %q not found", member)
+			}
+			storeBinding.Members = append(storeBinding.Members, user)
+		}
+		policy.Bindings = append(policy.Bindings, storeBinding)
+	}
+
+	policy, err := s.store.SetWorkspaceIAMPolicy(ctx, policy, updaterID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	return convertToIamPolicy(policy), nil
+}
+
+func (s *CustomRoleService) getRoleMessage(ctx context.Context, name string) (*store.RoleMessage, error) {
+	roleID, err := getRoleID(name)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+	role, err := s.store.GetRole(ctx, roleID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	if role == nil {
+		return nil, status.Errorf(codes.NotFound, "custom role %q not found", name)
+	}
+	return role, nil
+}
+
+func convertToCustomRole(role *store.RoleMessage) *v1pb.CustomRole {
+	return &v1pb.CustomRole{
+		Name:        fmt.Sprintf("%s%s", roleNamePrefix, role.ResourceID),
+		Title:       role.Name,
+		Description: role.Description,
+		Permissions: role.Permissions,
+	}
+}
+
+func validatePermissions(permissions []string) error {
+	if len(permissions) == 0 {
+		return errors.Errorf("custom role must have at least one permission")
+	}
+	permissionMap := make(map[string]bool)
+	for _, permission := range permissions {
+		if !api.IsValidPermission(permission) {
+			return errors.Errorf("invalid permission %q", permission)
+		}
+		if permissionMap[permission] {
+			return errors.Errorf("duplicate permission %q", permission)
+		}
+		permissionMap[permission] = true
+	}
+	return nil
+}
+
+// validateWorkspaceBindings validates the bindings of the workspace IAM policy, which can only use the custom roles.
+func validateWorkspaceBindings(bindings []*v1pb.Binding) error {
+	roleMap := make(map[string]bool)
+	for _, binding := range bindings {
+		if binding.Role != v1pb.ProjectRole_PROJECT_ROLE_UNSPECIFIED {
+			return errors.Errorf("workspace IAM binding can only use custom roles")
+		}
+		if binding.CustomRole == "" {
+			return errors.Errorf("IAM Binding custom role is required")
+		}
+		if roleMap[binding.CustomRole] {
+			return errors.Errorf("Each IAM binding must have a unique role")
+		}
+		roleMap[binding.CustomRole] = true
+		if len(binding.Members) == 0 {
+			return errors.Errorf("Each IAM binding must have at least one member")
+		}
+		userMap := make(map[string]bool)
+		for _, member := range binding.Members {
+			if userMap[member] {
+				return errors.Errorf("duplicate user %s", member)
+			}
+			userMap[member] = true
+			if err := validateMember(member); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	v1pb "github.com/bytebase/bytebase/proto/generated-go/v1"
+)
+
+func TestValidatePermissions(t *testing.T) {
+	tests := []struct {
+		permissions []string
+		wantErr     bool
+	}{
+		{
+			permissions: nil,
+			wantErr:     true,
+		},
+		{
+			permissions: []string{"bb.permission.workspace.manage-instance", "bb.permission.project.approve-issue"},
+			wantErr:     false,
+		},
+		{
+			permissions: []string{"bb.permission.workspace.unknown"},
+			wantErr:     true,
+		},
+		{
+			permissions: []string{"bb.permission.project.manage-backup", "bb.permission.project.manage-backup"},
+			wantErr:     true,
+		},
+	}
+
+	a := require.New(t)
+	for _, tt := range tests {
+		err := validatePermissions(tt.permissions)
+		if tt.wantErr {
+			a.Error(err)
+		} else {
+			a.NoError(err)
+		}
+	}
+}
+
+func TestValidateWorkspaceBindings(t *testing.T) {
+	tests := []struct {
+		bindings []*v1pb.Binding
+		wantErr  bool
+	}{
+		// Empty binding list clears the workspace policy.
+		{
+			bindings: nil,
+			wantErr:  false,
+		},
+		{
+			bindings: []*v1pb.Binding{
+				{
+					CustomRole: "roles/query-prod",
+					Members:    []string{"user:bytebase"},
+				},
+			},
+			wantErr: false,
+		},
+		// The built-in project roles cannot be bound in the workspace.
+		{
+			bindings: []*v1pb.Binding{
+				{
+					Role:    v1pb.ProjectRole_PROJECT_ROLE_OWNER,
+					Members: []string{"user:bytebase"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			bindings: []*v1pb.Binding{
+				{
+					CustomRole: "roles/query-prod",
+					Members:    []string{"user:bytebase"},
+				},
+				{
+					CustomRole: "roles/query-prod",
+					Members:    []string{"user:foo"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			bindings: []*v1pb.Binding{
+				{
+					CustomRole: "roles/query-prod",
+					Members:    []string{"bytebase"},
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	a := require.New(t)
+	for _, tt := range tests {
+		err := validateWorkspaceBindings(tt.bindings)
+		if tt.wantErr {
+			a.Error(err)
+		} else {
+			a.NoError(err)
+		}
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/bytebase/bytebase/backend/common"
 	enterpriseAPI "github.com/bytebase/bytebase/backend/enterprise/api"
 	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/secret"
 	"github.com/bytebase/bytebase/backend/store"
 	v1pb "github.com/bytebase/bytebase/proto/generated-go/v1"
 )
@@ -330,6 +331,39 @@ func (s *InstanceService) UpdateDataSource(ctx context.Context, request *v1pb.Up
 			patch.SRV = &request.DataSources.Srv
 		case "authentication_database":
 			patch.AuthenticationDatabase = &request.DataSources.AuthenticationDatabase
+		case "ssh_host":
+			patch.SSHHost = &request.DataSources.SshHost
+		case "ssh_port":
+			patch.SSHPort = &request.DataSources.SshPort
+		case "ssh_user":
+			patch.SSHUser = &request.DataSources.SshUser
+		case "ssh_password":
+			obfuscated := common.Obfuscate(request.DataSources.SshPassword, s.secret)
+			patch.SSHObfuscatedPassword = &obfuscated
+		case "ssh_private_key":
+			obfuscated := common.Obfuscate(request.DataSources.SshPrivateKey, s.secret)
+			patch.SSHObfuscatedPrivateKey = &obfuscated
+		case "ssh_host_key":
+			patch.SSHHostKey = &request.DataSources.SshHostKey
+		case "password_secret_ref":
+			if err := secret.ValidateReference(request.DataSources.PasswordSecretRef, request.DataSources.Password, "password"); err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, err.Error())
+			}
+			patch.PasswordSecretRef = &request.DataSources.PasswordSecretRef
+			if request.DataSources.PasswordSecretRef != "" {
+				// The stored password is dropped because it's resolved from the external secret provider.
+				obfuscated := common.Obfuscate("", s.secret)
+				patch.ObfuscatedPassword = &obfuscated
+			}
+		case "ssl_key_secret_ref":
+			if err := secret.ValidateReference(request.DataSources.SslKeySecretRef, request.DataSources.SslKey, "SSL key"); err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, err.Error())
+			}
+			patch.SslKeySecretRef = &request.DataSources.SslKeySecretRef
+			if request.DataSources.SslKeySecretRef != "" {
+				obfuscated := common.Obfuscate("", s.secret)
+				patch.ObfuscatedSslKey = &obfuscated
+			}
 		}
 	}
 
@@ -406,6 +440,12 @@ func convertToInstance(instance *store.InstanceMessage) *v1pb.Instance {
 			Database:               ds.Database,
 			Srv:                    ds.SRV,
 			AuthenticationDatabase: ds.AuthenticationDatabase,
+			SshHost:                ds.SSHHost,
+			SshPort:                ds.SSHPort,
+			SshUser:                ds.SSHUser,
+			SshHostKey:             ds.SSHHostKey,
+			PasswordSecretRef:      ds.PasswordSecretRef,
+			SslKeySecretRef:        ds.SslKeySecretRef,
 		})
 	}
 
@@ -453,6 +493,12 @@ func (s *InstanceService) convertToDataSourceMessage(dataSource *v1pb.DataSource
 	if err != nil {
 		return nil, err
 	}
+	if err := secret.ValidateReference(dataSource.PasswordSecretRef, dataSource.Password, "password"); err != nil {
+		return nil, err
+	}
+	if err := secret.ValidateReference(dataSource.SslKeySecretRef, dataSource.SslKey, "SSL key"); err != nil {
+		return nil, err
+	}
 
 	return &store.DataSourceMessage{
 		Title:                  dataSource.Title,
@@ -467,6 +513,15 @@ func (s *InstanceService) convertToDataSourceMessage(dataSource *v1pb.DataSource
 		Database:               dataSource.Database,
 		SRV:                    dataSource.Srv,
 		AuthenticationDatabase: dataSource.AuthenticationDatabase,
+
+		SSHHost:                 dataSource.SshHost,
+		SSHPort:                 dataSource.SshPort,
+		SSHUser:                 dataSource.SshUser,
+		SSHObfuscatedPassword:   common.Obfuscate(dataSource.SshPassword, s.secret),
+		SSHObfuscatedPrivateKey: common.Obfuscate(dataSource.SshPrivateKey, s.secret),
+		SSHHostKey:              dataSource.SshHostKey,
+		PasswordSecretRef:       dataSource.PasswordSecretRef,
+		SslKeySecretRef:         dataSource.SslKeySecretRef,
 	}, nil
 }
 
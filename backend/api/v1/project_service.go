@@ -4,10 +4,12 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/emptypb"
 
 	"github.com/bytebase/bytebase/backend/common"
@@ -338,6 +340,11 @@ func validateAndConvertToStoreDeploymentSchedule(deployment *v1pb.DeploymentConf
 	if deployment.Schedule == nil {
 		return nil, common.Errorf(common.Invalid, "schedule must not be empty")
 	}
+	if c := deployment.Schedule.Concurrency; c != nil {
+		if c.MaxRunningTasksPerStage < 0 || c.MaxRunningTasksPerInstance < 0 {
+			return nil, common.Errorf(common.Invalid, "concurrency limits must not be negative")
+		}
+	}
 	for _, d := range deployment.Schedule.Deployments {
 		if d == nil {
 			return nil, common.Errorf(common.Invalid, "deployment must not be empty")
@@ -351,11 +358,11 @@ func validateAndConvertToStoreDeploymentSchedule(deployment *v1pb.DeploymentConf
 				return nil, common.Errorf(common.Invalid, "label selector expression must not be empty")
 			}
 			switch e.Operator {
-			case v1pb.OperatorType_OPERATOR_TYPE_IN:
+			case v1pb.OperatorType_OPERATOR_TYPE_IN, v1pb.OperatorType_OPERATOR_TYPE_NOT_IN:
 				if len(e.Values) == 0 {
 					return nil, common.Errorf(common.Invalid, "expression key %q with %q operator should have at least one value", e.Key, e.Operator)
 				}
-			case v1pb.OperatorType_OPERATOR_TYPE_EXISTS:
+			case v1pb.OperatorType_OPERATOR_TYPE_EXISTS, v1pb.OperatorType_OPERATOR_TYPE_DOES_NOT_EXIST:
 				if len(e.Values) > 0 {
 					return nil, common.Errorf(common.Invalid, "expression key %q with %q operator shouldn't have values", e.Key, e.Operator)
 				}
@@ -372,6 +379,20 @@ func validateAndConvertToStoreDeploymentSchedule(deployment *v1pb.DeploymentConf
 		if !hasEnv {
 			return nil, common.Errorf(common.Invalid, "deployment should contain %q label", api.EnvironmentLabelKey)
 		}
+		if r := d.Spec.Rollout; r != nil {
+			if r.BakeDuration != nil && r.BakeDuration.AsDuration()%time.Minute != 0 {
+				return nil, common.Errorf(common.Invalid, "rollout bake duration must be in minutes")
+			}
+			rollout := convertToStoreRollout(r)
+			if err := (&api.DeploymentRollout{
+				Count:               rollout.Count,
+				Percent:             rollout.Percent,
+				PauseFailurePercent: rollout.PauseFailurePercent,
+				BakeMinutes:         rollout.BakeMinutes,
+			}).Validate(); err != nil {
+				return nil, err
+			}
+		}
 	}
 	return convertToStoreDeploymentConfig(deployment)
 }
@@ -404,10 +425,14 @@ func convertToIamPolicy(iamPolicy *store.IAMPolicyMessage) *v1pb.IamPolicy {
 		for _, member := range binding.Members {
 			members = append(members, getUserIdentifier(member.Email))
 		}
-		bindings = append(bindings, &v1pb.Binding{
+		v1Binding := &v1pb.Binding{
 			Role:    convertToProjectRole(binding.Role),
 			Members: members,
-		})
+		}
+		if !api.IsBuiltinProjectRole(binding.Role) {
+			v1Binding.CustomRole = fmt.Sprintf("%s%s", roleNamePrefix, binding.Role)
+		}
+		bindings = append(bindings, v1Binding)
 	}
 	return &v1pb.IamPolicy{
 		Bindings: bindings,
@@ -419,9 +444,9 @@ func (s *ProjectService) convertToIAMPolicyMessage(ctx context.Context, iamPolic
 	var bindings []*store.PolicyBinding
 	for _, binding := range iamPolicy.Bindings {
 		var users []*store.UserMessage
-		role, err := convertProjectRole(binding.Role)
+		role, err := s.convertBindingRole(ctx, binding)
 		if err != nil {
-			return nil, status.Errorf(codes.InvalidArgument, err.Error())
+			return nil, err
 		}
 		for _, member := range binding.Members {
 			user, err := s.store.GetUserByEmail(ctx, getUserEmailFromIdentifier(member))
@@ -444,6 +469,29 @@ func (s *ProjectService) convertToIAMPolicyMessage(ctx context.Context, iamPolic
 	}, nil
 }
 
+// convertBindingRole converts the role of the binding, which is either a built-in project role or a custom role.
+func (s *ProjectService) convertBindingRole(ctx context.Context, binding *v1pb.Binding) (api.Role, error) {
+	if binding.CustomRole == "" {
+		role, err := convertProjectRole(binding.Role)
+		if err != nil {
+			return "", status.Errorf(codes.InvalidArgument, err.Error())
+		}
+		return role, nil
+	}
+	roleID, err := getRoleID(binding.CustomRole)
+	if err != nil {
+		return "", status.Errorf(codes.InvalidArgument, err.Error())
+	}
+	customRole, err := s.store.GetRole(ctx, roleID)
+	if err != nil {
+		return "", status.Errorf(codes.Internal, err.Error())
+	}
+	if customRole == nil {
+		return "", status.Errorf(codes.NotFound, "custom role %q not found", binding.CustomRole)
+	}
+	return api.Role(customRole.ResourceID), nil
+}
+
 // getUserIdentifier returns the user identifier.
 // See more details in project_service.proto.
 func getUserIdentifier(email string) string {
@@ -670,6 +718,7 @@ func convertToSchedule(schedule *store.Schedule) *v1pb.Schedule {
 	}
 	return &v1pb.Schedule{
 		Deployments: ds,
+		Concurrency: convertToScheduleConcurrency(schedule.Concurrency),
 	}
 }
 
@@ -684,9 +733,30 @@ func convertToStoreSchedule(schedule *v1pb.Schedule) (*store.Schedule, error) {
 	}
 	return &store.Schedule{
 		Deployments: ds,
+		Concurrency: convertToStoreScheduleConcurrency(schedule.Concurrency),
 	}, nil
 }
 
+func convertToScheduleConcurrency(concurrency *store.Concurrency) *v1pb.ScheduleConcurrency {
+	if concurrency == nil {
+		return nil
+	}
+	return &v1pb.ScheduleConcurrency{
+		MaxRunningTasksPerStage:    int32(concurrency.MaxRunningTasksPerStage),
+		MaxRunningTasksPerInstance: int32(concurrency.MaxRunningTasksPerInstance),
+	}
+}
+
+func convertToStoreScheduleConcurrency(concurrency *v1pb.ScheduleConcurrency) *store.Concurrency {
+	if concurrency == nil {
+		return nil
+	}
+	return &store.Concurrency{
+		MaxRunningTasksPerStage:    int(concurrency.MaxRunningTasksPerStage),
+		MaxRunningTasksPerInstance: int(concurrency.MaxRunningTasksPerInstance),
+	}
+}
+
 func convertToDeployment(deployment *store.Deployment) *v1pb.ScheduleDeployment {
 	return &v1pb.ScheduleDeployment{
 		Title: deployment.Name,
@@ -709,6 +779,7 @@ func convertToStoreDeployment(deployment *v1pb.ScheduleDeployment) (*store.Deplo
 func convertToSpec(spec *store.DeploymentSpec) *v1pb.DeploymentSpec {
 	return &v1pb.DeploymentSpec{
 		LabelSelector: convertToLabelSelector(spec.Selector),
+		Rollout:       convertToRollout(spec.Rollout),
 	}
 }
 
@@ -719,9 +790,34 @@ func convertToStoreSpec(spec *v1pb.DeploymentSpec) (*store.DeploymentSpec, error
 	}
 	return &store.DeploymentSpec{
 		Selector: selector,
+		Rollout:  convertToStoreRollout(spec.Rollout),
 	}, nil
 }
 
+func convertToRollout(rollout *store.DeploymentRollout) *v1pb.DeploymentRollout {
+	if rollout == nil {
+		return nil
+	}
+	return &v1pb.DeploymentRollout{
+		Count:               int32(rollout.Count),
+		Percent:             int32(rollout.Percent),
+		PauseFailurePercent: int32(rollout.PauseFailurePercent),
+		BakeDuration:        durationpb.New(time.Duration(rollout.BakeMinutes) * time.Minute),
+	}
+}
+
+func convertToStoreRollout(rollout *v1pb.DeploymentRollout) *store.DeploymentRollout {
+	if rollout == nil {
+		return nil
+	}
+	return &store.DeploymentRollout{
+		Count:               int(rollout.Count),
+		Percent:             int(rollout.Percent),
+		PauseFailurePercent: int(rollout.PauseFailurePercent),
+		BakeMinutes:         int(rollout.BakeDuration.AsDuration() / time.Minute),
+	}
+}
+
 func convertToLabelSelector(selector *store.LabelSelector) *v1pb.LabelSelector {
 	var exprs []*v1pb.LabelSelectorRequirement
 	for _, expr := range selector.MatchExpressions {
@@ -771,8 +867,12 @@ func convertToLabelSelectorOperator(operator store.OperatorType) v1pb.OperatorTy
 	switch operator {
 	case store.InOperatorType:
 		return v1pb.OperatorType_OPERATOR_TYPE_IN
+	case store.NotInOperatorType:
+		return v1pb.OperatorType_OPERATOR_TYPE_NOT_IN
 	case store.ExistsOperatorType:
 		return v1pb.OperatorType_OPERATOR_TYPE_EXISTS
+	case store.DoesNotExistOperatorType:
+		return v1pb.OperatorType_OPERATOR_TYPE_DOES_NOT_EXIST
 	}
 	return v1pb.OperatorType_OPERATOR_TYPE_UNSPECIFIED
 }
@@ -781,8 +881,12 @@ func convertToStoreLabelSelectorOperator(operator v1pb.OperatorType) (store.Oper
 	switch operator {
 	case v1pb.OperatorType_OPERATOR_TYPE_IN:
 		return store.InOperatorType, nil
+	case v1pb.OperatorType_OPERATOR_TYPE_NOT_IN:
+		return store.NotInOperatorType, nil
 	case v1pb.OperatorType_OPERATOR_TYPE_EXISTS:
 		return store.ExistsOperatorType, nil
+	case v1pb.OperatorType_OPERATOR_TYPE_DOES_NOT_EXIST:
+		return store.DoesNotExistOperatorType, nil
 	}
 	return store.OperatorType(""), errors.Errorf("invalid operator type: %v", operator)
 }
@@ -814,17 +918,24 @@ func validateBindings(bindings []*v1pb.Binding) error {
 		return errors.Errorf("IAM Binding is required")
 	}
 	userMap := make(map[string]bool)
-	projectRoleMap := make(map[v1pb.ProjectRole]bool)
+	projectRoleMap := make(map[string]bool)
 	for _, binding := range bindings {
-		if binding.Role == v1pb.ProjectRole_PROJECT_ROLE_UNSPECIFIED {
+		if binding.Role == v1pb.ProjectRole_PROJECT_ROLE_UNSPECIFIED && binding.CustomRole == "" {
 			return errors.Errorf("IAM Binding role is required")
 		}
+		if binding.Role != v1pb.ProjectRole_PROJECT_ROLE_UNSPECIFIED && binding.CustomRole != "" {
+			return errors.Errorf("IAM Binding cannot set both role and custom role")
+		}
+		roleKey := binding.CustomRole
+		if roleKey == "" {
+			roleKey = binding.Role.String()
+		}
 		// Each of the bindings must contain at least one member.
 		if len(binding.Members) == 0 {
 			return errors.Errorf("Each IAM binding must have at least one member")
 		}
 		// We have not merge the binding by the same role yet, so the roles in each binding must be unique.
-		if _, ok := projectRoleMap[binding.Role]; ok {
+		if _, ok := projectRoleMap[roleKey]; ok {
 			return errors.Errorf("Each IAM binding must have a unique role")
 		}
 
@@ -837,10 +948,10 @@ func validateBindings(bindings []*v1pb.Binding) error {
 				return err
 			}
 		}
-		projectRoleMap[binding.Role] = true
+		projectRoleMap[roleKey] = true
 	}
 	// Must contain one owner binding.
-	if _, ok := projectRoleMap[v1pb.ProjectRole_PROJECT_ROLE_OWNER]; !ok {
+	if _, ok := projectRoleMap[v1pb.ProjectRole_PROJECT_ROLE_OWNER.String()]; !ok {
 		return errors.Errorf("IAM Policy must have at least one binding with role PROJECT_OWNER")
 	}
 	return nil
@@ -123,6 +123,31 @@ func TestValidateBindings(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		// Custom role binding.
+		{
+			bindings: []*v1pb.Binding{
+				{
+					Role:    v1pb.ProjectRole_PROJECT_ROLE_OWNER,
+					Members: []string{"user:bytebase"},
+				},
+				{
+					CustomRole: "roles/query-prod",
+					Members:    []string{"user:foo"},
+				},
+			},
+			wantErr: false,
+		},
+		// Cannot set both role and custom role.
+		{
+			bindings: []*v1pb.Binding{
+				{
+					Role:       v1pb.ProjectRole_PROJECT_ROLE_OWNER,
+					CustomRole: "roles/query-prod",
+					Members:    []string{"user:bytebase"},
+				},
+			},
+			wantErr: true,
+		},
 	}
 
 	a := require.New(t)
@@ -2,6 +2,7 @@ package v1
 
 import (
 	"context"
+	"strconv"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -28,6 +29,8 @@ var whitelistSettings = []api.SettingName{
 	api.SettingBrandingLogo,
 	api.SettingAppIM,
 	api.SettingWatermark,
+	api.SettingWorkspaceRequireMFA,
+	api.SettingWorkspaceMaxRunningTasks,
 }
 
 // GetSetting gets the setting by name.
@@ -68,6 +71,17 @@ func (s *SettingService) SetSetting(ctx context.Context, request *v1pb.SetSettin
 	if settingName == "" {
 		return nil, status.Errorf(codes.InvalidArgument, "setting name is empty")
 	}
+	if api.SettingName(settingName) == api.SettingWorkspaceRequireMFA {
+		if value := request.Setting.Value.GetStringValue(); value != "0" && value != "1" {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid setting value %q for %s, expect \"0\" or \"1\"", value, settingName)
+		}
+	}
+	if api.SettingName(settingName) == api.SettingWorkspaceMaxRunningTasks {
+		value := request.Setting.Value.GetStringValue()
+		if v, err := strconv.Atoi(value); err != nil || v < 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid setting value %q for %s, expect a non-negative integer", value, settingName)
+		}
+	}
 	setting, err := s.store.UpsertSettingV2(ctx, &store.SetSettingMessage{
 		Name:  api.SettingName(settingName),
 		Value: request.Setting.Value.GetStringValue(),
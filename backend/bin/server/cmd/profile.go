@@ -1,6 +1,11 @@
 package cmd
 
 import (
+	"fmt"
+	"os"
+
+	"github.com/google/uuid"
+
 	"github.com/bytebase/bytebase/backend/common"
 	"github.com/bytebase/bytebase/backend/component/config"
 	api "github.com/bytebase/bytebase/backend/legacyapi"
@@ -32,5 +37,21 @@ func getBaseProfile(dataDir string) config.Profile {
 		BackupBucket:         flags.backupBucket,
 		BackupCredentialFile: flags.backupCredential,
 		FeishuAPIURL:         feishu.APIPath,
+		SecretVaultAddress:   flags.secretVaultAddr,
+		SecretVaultToken:     os.Getenv("VAULT_TOKEN"),
+		SecretFileDir:        flags.secretFileDir,
+		HA:                   flags.ha,
+		ReplicaID:            getReplicaID(),
+		AuditLogFile:         flags.auditLogFile,
+		AuditLogSyslog:       flags.auditLogSyslog,
+	}
+}
+
+// getReplicaID returns a unique ID of the replica, the hostname is included for debugging.
+func getReplicaID() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
 	}
+	return fmt.Sprintf("%s-%s", hostname, uuid.New().String()[:8])
 }
@@ -86,6 +86,17 @@ var (
 		backupRegion     string
 		backupBucket     string
 		backupCredential string
+
+		// External secret provider configs.
+		secretVaultAddr string
+		secretFileDir   string
+
+		// ha is the flag to run multiple replicas sharing the same external PostgreSQL.
+		ha bool
+
+		// Audit log export configs.
+		auditLogFile   string
+		auditLogSyslog string
 	}
 
 	rootCmd = &cobra.Command{
@@ -133,6 +144,17 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&flags.backupBucket, "backup-bucket", "", "bucket where Bytebase stores backup data, e.g., s3://example-bucket. When provided, Bytebase will store data to the S3 bucket.")
 	rootCmd.PersistentFlags().StringVar(&flags.backupRegion, "backup-region", "", "region of the backup bucket, e.g., us-west-2 for AWS S3.")
 	rootCmd.PersistentFlags().StringVar(&flags.backupCredential, "backup-credential", "", "credentials file to use for the backup bucket. It should be the same format as the AWS/GCP credential files.")
+
+	// External secret provider related flags.
+	// The Vault token is only read from the VAULT_TOKEN environment variable so that it won't show up in the process list.
+	// AWS Secrets Manager uses the default AWS credential chain.
+	rootCmd.PersistentFlags().StringVar(&flags.secretVaultAddr, "secret-vault-addr", os.Getenv("VAULT_ADDR"), "address of the HashiCorp Vault server to resolve the data source secrets, e.g., https://vault.example.com:8200. The token is read from the VAULT_TOKEN environment variable.")
+	rootCmd.PersistentFlags().StringVar(&flags.secretFileDir, "secret-file-dir", "", "directory where the file secret provider reads the data source secrets from, e.g., /run/secrets. The file secret provider is disabled if it's empty.")
+
+	rootCmd.PersistentFlags().BoolVar(&flags.ha, "ha", false, "whether to run in high availability mode, in which multiple replicas share the same external PostgreSQL specified by --pg. Only the leader replica runs the background runners.")
+
+	rootCmd.PersistentFlags().StringVar(&flags.auditLogFile, "audit-log-file", "", "file the audit logs are streamed to as JSON lines besides the metadata database. The export is disabled if it's empty.")
+	rootCmd.PersistentFlags().StringVar(&flags.auditLogSyslog, "audit-log-syslog", "", "syslog server the audit logs are streamed to, \"local\" for the local syslog daemon, or an address like udp://syslog.example.com:514. The export is disabled if it's empty.")
 }
 
 // -----------------------------------Command Line Config END--------------------------------------
@@ -230,6 +252,10 @@ func start() {
 		log.Error("invalid flags for cloud backup", zap.Error(err))
 		return
 	}
+	if flags.ha && flags.pgURL == "" {
+		log.Error("--ha requires the external PostgreSQL specified by --pg")
+		return
+	}
 
 	profile := activeProfile(flags.dataDir)
 
@@ -8,4 +8,7 @@ const (
 	PrincipalIDContextKey ContextKey = iota
 	// RoleContextKey is the key name used to store principal role in the context.
 	RoleContextKey
+	// AuditActorContextKey is the key name used to store the pointer to the principal id of the audited call in the context.
+	// The audit interceptor runs before the authentication, and the authentication fills in the principal id.
+	AuditActorContextKey
 )
@@ -0,0 +1,77 @@
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCronScheduleNext(t *testing.T) {
+	shanghai, err := time.LoadLocation("Asia/Shanghai")
+	require.NoError(t, err)
+	tests := []struct {
+		spec string
+		from time.Time
+		want time.Time
+	}{
+		{
+			spec: "* * * * *",
+			from: time.Date(2023, 3, 1, 10, 0, 30, 0, time.UTC),
+			want: time.Date(2023, 3, 1, 10, 1, 0, 0, time.UTC),
+		},
+		{
+			spec: "0 22 * * 1-5",
+			// Friday.
+			from: time.Date(2023, 3, 3, 22, 0, 0, 0, time.UTC),
+			// Monday.
+			want: time.Date(2023, 3, 6, 22, 0, 0, 0, time.UTC),
+		},
+		{
+			spec: "*/15 2-3 * * *",
+			from: time.Date(2023, 3, 1, 2, 50, 0, 0, shanghai),
+			want: time.Date(2023, 3, 1, 3, 0, 0, 0, shanghai),
+		},
+		{
+			spec: "30 1 1,15 * *",
+			from: time.Date(2023, 3, 2, 0, 0, 0, 0, time.UTC),
+			want: time.Date(2023, 3, 15, 1, 30, 0, 0, time.UTC),
+		},
+		{
+			// Sunday is both 0 and 7.
+			spec: "0 0 * * 7",
+			from: time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC),
+			want: time.Date(2023, 3, 5, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			// The day matches either field if both day fields are restricted.
+			spec: "0 0 10 * 1",
+			from: time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC),
+			want: time.Date(2023, 3, 6, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			spec: "0 0 29 2 *",
+			from: time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC),
+			want: time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, test := range tests {
+		schedule, err := ParseCronSchedule(test.spec)
+		require.NoError(t, err, test.spec)
+		require.Equal(t, test.want, schedule.Next(test.from), test.spec)
+	}
+}
+
+func TestParseCronScheduleError(t *testing.T) {
+	for _, spec := range []string{
+		"* * * *",
+		"60 * * * *",
+		"* 5-1 * * *",
+		"*/0 * * * *",
+		"a * * * *",
+	} {
+		_, err := ParseCronSchedule(spec)
+		require.Error(t, err, spec)
+	}
+}
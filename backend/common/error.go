@@ -35,13 +35,17 @@ const (
 	MigrationFailed  Code = 206
 
 	// 301 task error.
-	TaskTimingNotAllowed Code = 301
+	TaskTimingNotAllowed   Code = 301
+	TaskVerificationFailed Code = 302
 
 	// 401 task sql type error.
 	TaskTypeNotDML         Code = 401
 	TaskTypeNotDDL         Code = 402
 	TaskTypeDropDatabase   Code = 403
 	TaskTypeCreateDatabase Code = 404
+	TaskTypeDisallowDCL    Code = 405
+	TaskTypeMixedDDLAndDML Code = 406
+	TaskTypeImplicitCommit Code = 407
 )
 
 // Int returns the int type of code.
@@ -69,6 +73,11 @@ func (e *Error) Error() string {
 	return e.Err.Error()
 }
 
+// Unwrap returns the embedded error, so that errors.Is and errors.As can inspect the underlying errors.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
 // ErrorCode unwraps an application error and returns its code.
 // Non-application errors always return EINTERNAL.
 func ErrorCode(err error) Code {
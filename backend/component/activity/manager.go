@@ -168,6 +168,170 @@ func (m *Manager) CreateActivity(ctx context.Context, create *api.ActivityCreate
 	return activity, nil
 }
 
+// CreateAnomalyActivity creates an activity for a detected or resolved anomaly, and posts it to the webhooks of related projects.
+// The related project of a database anomaly is the project of the database, and the related projects of an instance anomaly
+// are the projects of all databases on the instance.
+func (m *Manager) CreateAnomalyActivity(ctx context.Context, activityType api.ActivityType, anomaly *api.Anomaly) (*api.Activity, error) {
+	if activityType != api.ActivityAnomalyCreate && activityType != api.ActivityAnomalyResolve {
+		return nil, errors.Errorf("invalid anomaly activity type %q", activityType)
+	}
+	if anomaly.Instance == nil {
+		return nil, errors.Errorf("instance not found for anomaly %d", anomaly.ID)
+	}
+
+	activityPayload := api.ActivityAnomalyCreateResolvePayload{
+		AnomalyID:    anomaly.ID,
+		AnomalyType:  anomaly.Type,
+		Severity:     anomaly.Severity,
+		InstanceID:   anomaly.InstanceID,
+		InstanceName: anomaly.Instance.Name,
+	}
+	if anomaly.Database != nil {
+		activityPayload.DatabaseID = anomaly.Database.ID
+		activityPayload.DatabaseName = anomaly.Database.Name
+	}
+	payload, err := json.Marshal(activityPayload)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal activity payload for anomaly %d", anomaly.ID)
+	}
+	level := api.ActivityWarn
+	if activityType == api.ActivityAnomalyResolve {
+		level = api.ActivityInfo
+	}
+	activity, err := m.store.CreateActivity(ctx, &api.ActivityCreate{
+		CreatorID:   api.SystemBotID,
+		ContainerID: anomaly.InstanceID,
+		Type:        activityType,
+		Level:       level,
+		Payload:     string(payload),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	projectList, err := m.getAnomalyProjectList(ctx, anomaly)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find projects for anomaly %d", anomaly.ID)
+	}
+	webhookCtx := getAnomalyWebhookContext(activity, anomaly, m.profile.ExternalURL)
+	for _, project := range projectList {
+		webhookList, err := m.store.FindProjectWebhook(ctx, &api.ProjectWebhookFind{
+			ProjectID:    &project.UID,
+			ActivityType: &activityType,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to find project webhook for anomaly %d", anomaly.ID)
+		}
+		if len(webhookList) == 0 {
+			continue
+		}
+		projectWebhookCtx := webhookCtx
+		projectWebhookCtx.Project = &webhook.Project{
+			ID:   project.UID,
+			Name: project.Title,
+		}
+		// Call external webhook endpoint in Go routine to avoid blocking the anomaly scanner.
+		go postWebhookList(projectWebhookCtx, webhookList)
+	}
+
+	return activity, nil
+}
+
+func (m *Manager) getAnomalyProjectList(ctx context.Context, anomaly *api.Anomaly) ([]*store.ProjectMessage, error) {
+	find := &store.FindDatabaseMessage{}
+	if anomaly.DatabaseID != nil {
+		find.UID = anomaly.DatabaseID
+	} else {
+		find.InstanceID = &anomaly.Instance.ResourceID
+	}
+	databases, err := m.store.ListDatabases(ctx, find)
+	if err != nil {
+		return nil, err
+	}
+	var projectList []*store.ProjectMessage
+	projectIDMap := make(map[string]bool)
+	for _, database := range databases {
+		if projectIDMap[database.ProjectID] {
+			continue
+		}
+		projectIDMap[database.ProjectID] = true
+		project, err := m.store.GetProjectV2(ctx, &store.FindProjectMessage{ResourceID: &database.ProjectID})
+		if err != nil {
+			return nil, err
+		}
+		if project == nil {
+			continue
+		}
+		projectList = append(projectList, project)
+	}
+	return projectList, nil
+}
+
+func getAnomalyWebhookContext(activity *api.Activity, anomaly *api.Anomaly, externalURL string) webhook.Context {
+	webhookAnomaly := &webhook.Anomaly{
+		ID:           anomaly.ID,
+		Type:         string(anomaly.Type),
+		Severity:     string(anomaly.Severity),
+		InstanceName: anomaly.Instance.Name,
+	}
+	target := fmt.Sprintf("instance %q", anomaly.Instance.Name)
+	if anomaly.Database != nil {
+		webhookAnomaly.DatabaseName = anomaly.Database.Name
+		target = fmt.Sprintf("database %q", anomaly.Database.Name)
+	}
+
+	level := webhook.WebhookWarn
+	if anomaly.Severity == api.AnomalySeverityCritical {
+		level = webhook.WebhookError
+	}
+	title := fmt.Sprintf("Anomaly detected - %s", target)
+	if activity.Type == api.ActivityAnomalyResolve {
+		level = webhook.WebhookSuccess
+		title = fmt.Sprintf("Anomaly resolved - %s", target)
+	}
+	return webhook.Context{
+		Level:        level,
+		ActivityType: string(activity.Type),
+		Title:        title,
+		Description:  getAnomalyDescription(anomaly),
+		Link:         fmt.Sprintf("%s/anomaly-center", externalURL),
+		CreatorID:    activity.CreatorID,
+		CreatorName:  activity.Creator.Name,
+		CreatorEmail: activity.Creator.Email,
+		Anomaly:      webhookAnomaly,
+	}
+}
+
+func getAnomalyDescription(anomaly *api.Anomaly) string {
+	switch anomaly.Type {
+	case api.AnomalyInstanceConnection:
+		payload := &api.AnomalyInstanceConnectionPayload{}
+		if err := json.Unmarshal([]byte(anomaly.Payload), payload); err == nil && payload.Detail != "" {
+			return fmt.Sprintf("Failed to connect to the instance: %s", payload.Detail)
+		}
+		return "Failed to connect to the instance."
+	case api.AnomalyInstanceMigrationSchema:
+		return "The Bytebase migration schema is missing on the instance."
+	case api.AnomalyDatabaseBackupPolicyViolation:
+		return "The backup schedule of the database violates the backup policy of the environment."
+	case api.AnomalyDatabaseBackupMissing:
+		return "The database has missed its scheduled backup."
+	case api.AnomalyDatabaseConnection:
+		payload := &api.AnomalyDatabaseConnectionPayload{}
+		if err := json.Unmarshal([]byte(anomaly.Payload), payload); err == nil && payload.Detail != "" {
+			return fmt.Sprintf("Failed to connect to the database: %s", payload.Detail)
+		}
+		return "Failed to connect to the database."
+	case api.AnomalyDatabaseSchemaDrift:
+		payload := &api.AnomalyDatabaseSchemaDriftPayload{}
+		if err := json.Unmarshal([]byte(anomaly.Payload), payload); err == nil && payload.Version != "" {
+			return fmt.Sprintf("The database schema has drifted from the schema of version %s.", payload.Version)
+		}
+		return "The database schema has drifted from the recorded schema."
+	}
+	return ""
+}
+
 func postWebhookList(webhookCtx webhook.Context, webhookList []*api.ProjectWebhook) {
 	for _, hook := range webhookList {
 		webhookCtx.URL = hook.URL
@@ -307,6 +471,17 @@ func (m *Manager) getWebhookContext(ctx context.Context, activity *api.Activity,
 			title = fmt.Sprintf("Stage ends - %s", payload.StageName)
 		}
 
+	case api.ActivityPipelineTaskVerificationFail:
+		payload := &api.ActivityPipelineTaskVerificationFailPayload{}
+		if err := json.Unmarshal([]byte(activity.Payload), payload); err != nil {
+			log.Warn("Failed to post webhook event after failing the task verification, failed to unmarshal payload",
+				zap.String("issue_name", meta.Issue.Title),
+				zap.Error(err))
+			return webhookCtx, err
+		}
+		level = webhook.WebhookError
+		title = "Task verification failed - " + payload.TaskName
+
 	case api.ActivityPipelineTaskStatusUpdate:
 		update := &api.ActivityPipelineTaskStatusUpdatePayload{}
 		if err := json.Unmarshal([]byte(activity.Payload), update); err != nil {
@@ -334,8 +509,9 @@ func (m *Manager) getWebhookContext(ctx context.Context, activity *api.Activity,
 		}
 
 		webhookTaskResult = &webhook.TaskResult{
-			Name:   task.Name,
-			Status: string(task.Status),
+			Name:                  task.Name,
+			Status:                string(task.Status),
+			EstimatedAffectedRows: task.EstimatedAffectedRows,
 		}
 
 		title = "Task changed - " + task.Name
@@ -470,6 +646,8 @@ func shouldPostInbox(activity *api.Activity, createType api.ActivityType) (bool,
 		return true, nil
 	case api.ActivityPipelineStageStatusUpdate:
 		return false, nil
+	case api.ActivityPipelineTaskVerificationFail:
+		return true, nil
 	case api.ActivityPipelineTaskStatusUpdate:
 		update := new(api.ActivityPipelineTaskStatusUpdatePayload)
 		if err := json.Unmarshal([]byte(activity.Payload), update); err != nil {
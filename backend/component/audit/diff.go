@@ -0,0 +1,127 @@
+package audit
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const (
+	// maxPayloadSize is the max size of the request payload recorded in the audit log.
+	maxPayloadSize = 64 * 1024
+	redactedValue  = "******"
+)
+
+// sensitiveKeys are the normalized field names whose values are redacted from the audit logs.
+var sensitiveKeys = []string{"password", "secret", "token", "sslkey", "sslcert", "servicekey", "privatekey", "apikey"}
+
+// fieldChange is a changed field in the audit log diff.
+type fieldChange struct {
+	Path   string      `json:"path"`
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// Redact returns the JSON payload with the secrets redacted. The non-JSON and oversized payloads are dropped.
+func Redact(payload []byte) string {
+	if len(payload) == 0 || len(payload) > maxPayloadSize {
+		return ""
+	}
+	var value interface{}
+	if err := json.Unmarshal(payload, &value); err != nil {
+		return ""
+	}
+	redacted, err := json.Marshal(redactValue(value))
+	if err != nil {
+		return ""
+	}
+	return string(redacted)
+}
+
+// Diff returns the field-level diff of the JSON payloads of the resource before and after the call.
+// The nested fields are flattened into the dot-separated paths, and the lists are compared as a whole.
+// It returns an empty string if nothing is changed.
+func Diff(before, after []byte) string {
+	beforeFields, afterFields := map[string]interface{}{}, map[string]interface{}{}
+	flattenPayload(before, beforeFields)
+	flattenPayload(after, afterFields)
+
+	var changes []*fieldChange
+	for path, b := range beforeFields {
+		a, ok := afterFields[path]
+		if !ok || !reflect.DeepEqual(a, b) {
+			changes = append(changes, &fieldChange{Path: path, Before: b, After: a})
+		}
+	}
+	for path, a := range afterFields {
+		if _, ok := beforeFields[path]; !ok {
+			changes = append(changes, &fieldChange{Path: path, After: a})
+		}
+	}
+	if len(changes) == 0 {
+		return ""
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	content, err := json.Marshal(changes)
+	if err != nil {
+		return ""
+	}
+	return string(content)
+}
+
+func flattenPayload(payload []byte, fields map[string]interface{}) {
+	if len(payload) == 0 {
+		return
+	}
+	var value interface{}
+	if err := json.Unmarshal(payload, &value); err != nil {
+		return
+	}
+	flatten("", redactValue(value), fields)
+}
+
+func flatten(prefix string, value interface{}, fields map[string]interface{}) {
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		fields[prefix] = value
+		return
+	}
+	for key, v := range object {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+		flatten(path, v, fields)
+	}
+}
+
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if isSensitiveKey(key) {
+				v[key] = redactedValue
+				continue
+			}
+			v[key] = redactValue(field)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+	}
+	return value
+}
+
+func isSensitiveKey(key string) bool {
+	normalized := strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(key))
+	for _, sensitiveKey := range sensitiveKeys {
+		if strings.Contains(normalized, sensitiveKey) {
+			return true
+		}
+	}
+	return false
+}
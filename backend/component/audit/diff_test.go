@@ -0,0 +1,68 @@
+package audit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		payload string
+		want    string
+	}{
+		{
+			payload: `{"email":"dev@example.com","password":"123456"}`,
+			want:    `{"email":"dev@example.com","password":"******"}`,
+		},
+		{
+			payload: `{"dataSources":[{"username":"root","ssl_key":"key","dataSourceSecret":{"path":"a"}}]}`,
+			want:    `{"dataSources":[{"dataSourceSecret":"******","ssl_key":"******","username":"root"}]}`,
+		},
+		{
+			payload: `not json`,
+			want:    "",
+		},
+	}
+
+	a := require.New(t)
+	for _, tt := range tests {
+		a.Equal(tt.want, Redact([]byte(tt.payload)))
+	}
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		before string
+		after  string
+		want   string
+	}{
+		{
+			before: `{"name":"instances/mysql","title":"MySQL","options":{"port":"3306","password":"a"}}`,
+			after:  `{"name":"instances/mysql","title":"MySQL prod","options":{"port":"3307","password":"b"}}`,
+			want:   `[{"path":"options.port","before":"3306","after":"3307"},{"path":"title","before":"MySQL","after":"MySQL prod"}]`,
+		},
+		// Creation.
+		{
+			before: ``,
+			after:  `{"name":"roles/dba","labels":["a","b"]}`,
+			want:   `[{"path":"labels","before":null,"after":["a","b"]},{"path":"name","before":null,"after":"roles/dba"}]`,
+		},
+		// Deletion.
+		{
+			before: `{"name":"roles/dba"}`,
+			after:  `{}`,
+			want:   `[{"path":"name","before":"roles/dba","after":null}]`,
+		},
+		{
+			before: `{"name":"roles/dba"}`,
+			after:  `{"name":"roles/dba"}`,
+			want:   ``,
+		},
+	}
+
+	a := require.New(t)
+	for _, tt := range tests {
+		a.Equal(tt.want, Diff([]byte(tt.before), []byte(tt.after)))
+	}
+}
@@ -0,0 +1,124 @@
+package audit
+
+import (
+	"encoding/json"
+	"log/syslog"
+	"net/url"
+	"os"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+// syslogTag is the tag of the audit log syslog messages.
+const syslogTag = "bytebase-audit"
+
+// exporter streams the audit logs to an external sink.
+type exporter interface {
+	export(auditLog *store.AuditLogMessage) error
+	close() error
+}
+
+// exportedAuditLog is the JSON format of the exported audit log.
+type exportedAuditLog struct {
+	ID        int64  `json:"id"`
+	CreatedTs int64  `json:"createdTs"`
+	ActorID   int    `json:"actorId"`
+	Method    string `json:"method"`
+	Resource  string `json:"resource"`
+	Status    string `json:"status"`
+	Error     string `json:"error,omitempty"`
+	Request   string `json:"request,omitempty"`
+	Diff      string `json:"diff,omitempty"`
+	ClientIP  string `json:"clientIp"`
+	UserAgent string `json:"userAgent"`
+	PrevHash  string `json:"prevHash"`
+	Hash      string `json:"hash"`
+}
+
+func marshalAuditLog(auditLog *store.AuditLogMessage) ([]byte, error) {
+	return json.Marshal(&exportedAuditLog{
+		ID:        auditLog.ID,
+		CreatedTs: auditLog.CreatedTs,
+		ActorID:   auditLog.ActorID,
+		Method:    auditLog.Method,
+		Resource:  auditLog.Resource,
+		Status:    auditLog.Status,
+		Error:     auditLog.Error,
+		Request:   auditLog.Request,
+		Diff:      auditLog.Diff,
+		ClientIP:  auditLog.ClientIP,
+		UserAgent: auditLog.UserAgent,
+		PrevHash:  auditLog.PrevHash,
+		Hash:      auditLog.Hash,
+	})
+}
+
+// fileExporter appends the audit logs to a file as JSON lines.
+type fileExporter struct {
+	sync.Mutex
+	file *os.File
+}
+
+func newFileExporter(path string) (*fileExporter, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &fileExporter{file: file}, nil
+}
+
+func (e *fileExporter) export(auditLog *store.AuditLogMessage) error {
+	content, err := marshalAuditLog(auditLog)
+	if err != nil {
+		return err
+	}
+	e.Lock()
+	defer e.Unlock()
+	_, err = e.file.Write(append(content, '\n'))
+	return err
+}
+
+func (e *fileExporter) close() error {
+	return e.file.Close()
+}
+
+// syslogExporter sends the audit logs to a syslog server.
+type syslogExporter struct {
+	writer *syslog.Writer
+}
+
+// newSyslogExporter connects the syslog server at the address, which is "local" for the local syslog daemon,
+// or a URL like udp://syslog.example.com:514 and tcp://syslog.example.com:601.
+func newSyslogExporter(address string) (*syslogExporter, error) {
+	var network, raddr string
+	if address != "local" {
+		u, err := url.Parse(address)
+		if err != nil {
+			return nil, err
+		}
+		if u.Scheme != "udp" && u.Scheme != "tcp" {
+			return nil, errors.Errorf("unsupported syslog network %q, should be udp or tcp", u.Scheme)
+		}
+		network, raddr = u.Scheme, u.Host
+	}
+	writer, err := syslog.Dial(network, raddr, syslog.LOG_INFO|syslog.LOG_AUTH, syslogTag)
+	if err != nil {
+		return nil, err
+	}
+	return &syslogExporter{writer: writer}, nil
+}
+
+func (e *syslogExporter) export(auditLog *store.AuditLogMessage) error {
+	content, err := marshalAuditLog(auditLog)
+	if err != nil {
+		return err
+	}
+	return e.writer.Info(string(content))
+}
+
+func (e *syslogExporter) close() error {
+	return e.writer.Close()
+}
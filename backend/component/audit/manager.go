@@ -0,0 +1,69 @@
+// Package audit is a component for recording the audit logs of the mutating API calls.
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/backend/common/log"
+	"github.com/bytebase/bytebase/backend/component/config"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+// Manager is the audit log manager.
+type Manager struct {
+	store     *store.Store
+	exporters []exporter
+}
+
+// NewManager creates an audit log manager, which also streams the audit logs to the exporters configured in the profile.
+func NewManager(store *store.Store, profile config.Profile) (*Manager, error) {
+	m := &Manager{
+		store: store,
+	}
+	if profile.AuditLogFile != "" {
+		e, err := newFileExporter(profile.AuditLogFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to open audit log file %q", profile.AuditLogFile)
+		}
+		m.exporters = append(m.exporters, e)
+	}
+	if profile.AuditLogSyslog != "" {
+		e, err := newSyslogExporter(profile.AuditLogSyslog)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to connect audit log syslog %q", profile.AuditLogSyslog)
+		}
+		m.exporters = append(m.exporters, e)
+	}
+	return m, nil
+}
+
+// Record appends the audit log to the hash chain and streams it to the exporters.
+// The failures are logged instead of returned, so that auditing never fails the audited call.
+func (m *Manager) Record(ctx context.Context, auditLog *store.AuditLogMessage) {
+	if auditLog.CreatedTs == 0 {
+		auditLog.CreatedTs = time.Now().Unix()
+	}
+	created, err := m.store.CreateAuditLog(ctx, auditLog)
+	if err != nil {
+		log.Error("Failed to create audit log", zap.String("method", auditLog.Method), zap.Error(err))
+		return
+	}
+	for _, e := range m.exporters {
+		if err := e.export(created); err != nil {
+			log.Warn("Failed to export audit log", zap.Int64("id", created.ID), zap.Error(err))
+		}
+	}
+}
+
+// Close closes the exporters.
+func (m *Manager) Close() {
+	for _, e := range m.exporters {
+		if err := e.close(); err != nil {
+			log.Warn("Failed to close audit log exporter", zap.Error(err))
+		}
+	}
+}
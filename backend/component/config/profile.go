@@ -49,6 +49,26 @@ type Profile struct {
 	BackupBucket         string
 	BackupCredentialFile string
 
+	// External secret provider related fields
+	// SecretVaultAddress is the address of the Vault server to resolve the data source secrets.
+	SecretVaultAddress string
+	// SecretVaultToken is the token to access the Vault server.
+	SecretVaultToken string
+	// SecretFileDir is the directory where the file secret provider reads the data source secrets from.
+	SecretFileDir string
+
+	// High availability related fields
+	// HA is whether multiple replicas share the same metadata database.
+	HA bool
+	// ReplicaID is the unique ID of the replica.
+	ReplicaID string
+
+	// Audit log related fields
+	// AuditLogFile is the file the audit logs are exported to as JSON lines.
+	AuditLogFile string
+	// AuditLogSyslog is the syslog server the audit logs are exported to, e.g. "local", "udp://syslog.example.com:514".
+	AuditLogSyslog string
+
 	// IM integration related fields
 	// FeishuAPIURL is the URL of Feishu API server.
 	FeishuAPIURL string
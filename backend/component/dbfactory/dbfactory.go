@@ -3,10 +3,17 @@ package dbfactory
 
 import (
 	"context"
+	"crypto/sha256"
+	"fmt"
+	"net"
+	"sync"
+	"time"
 
 	"github.com/bytebase/bytebase/backend/common"
+	"github.com/bytebase/bytebase/backend/common/log"
 	api "github.com/bytebase/bytebase/backend/legacyapi"
 	"github.com/bytebase/bytebase/backend/plugin/db"
+	"github.com/bytebase/bytebase/backend/plugin/secret"
 	"github.com/bytebase/bytebase/backend/store"
 	"github.com/bytebase/bytebase/backend/utils"
 )
@@ -18,21 +25,42 @@ type DBFactory struct {
 	mongoBinDir string
 	dataDir     string
 	secret      string
+	// secretResolver resolves the credentials of the data sources with secret references.
+	secretResolver *secret.Resolver
+
+	// pool keeps the idle drivers for reuse.
+	pool *driverPool
+
+	// tunnelMu protects the tunnels map, it's not held while connecting to the SSH hosts.
+	tunnelMu sync.Mutex
+	// tunnels is the map from the SSH host and the target address to the SSH tunnel.
+	tunnels map[string]*tunnelEntry
+}
+
+type tunnelEntry struct {
+	// mu serializes creating the tunnel for the same key, and protects the fields below.
+	mu     sync.Mutex
+	tunnel *sshTunnel
+	// credentialHash is used to replace the tunnel after the SSH settings change.
+	credentialHash [sha256.Size]byte
 }
 
 // New creates a new database driver factory.
-func New(mysqlBinDir, mongoBinDir, pgBinDir, dataDir, secret string) *DBFactory {
+func New(mysqlBinDir, mongoBinDir, pgBinDir, dataDir, secret string, secretResolver *secret.Resolver) *DBFactory {
 	return &DBFactory{
-		mysqlBinDir: mysqlBinDir,
-		mongoBinDir: mongoBinDir,
-		pgBinDir:    pgBinDir,
-		dataDir:     dataDir,
-		secret:      secret,
+		mysqlBinDir:    mysqlBinDir,
+		mongoBinDir:    mongoBinDir,
+		pgBinDir:       pgBinDir,
+		dataDir:        dataDir,
+		secret:         secret,
+		secretResolver: secretResolver,
+		pool:           newDriverPool(),
+		tunnels:        make(map[string]*tunnelEntry),
 	}
 }
 
 // GetAdminDatabaseDriver gets the admin database driver using the instance's admin data source.
-// Upon successful return, caller must call driver.Close(). Otherwise, it will leak the database connection.
+// Upon successful return, caller must call driver.Close() to return the driver to the pool. Otherwise, it will leak the database connection.
 func (d *DBFactory) GetAdminDatabaseDriver(ctx context.Context, instance *store.InstanceMessage, databaseName string) (db.Driver, error) {
 	adminDataSource := utils.DataSourceFromInstanceWithType(instance, api.Admin)
 	if adminDataSource == nil {
@@ -52,7 +80,7 @@ func (d *DBFactory) GetAdminDatabaseDriver(ctx context.Context, instance *store.
 	if databaseName == "" {
 		databaseName = adminDataSource.Database
 	}
-	password, err := common.Unobfuscate(adminDataSource.ObfuscatedPassword, d.secret)
+	username, password, err := d.GetDataSourcePassword(ctx, adminDataSource)
 	if err != nil {
 		return nil, err
 	}
@@ -64,46 +92,39 @@ func (d *DBFactory) GetAdminDatabaseDriver(ctx context.Context, instance *store.
 	if err != nil {
 		return nil, err
 	}
-	sslKey, err := common.Unobfuscate(adminDataSource.ObfuscatedSslKey, d.secret)
+	sslKey, err := d.GetDataSourceSslKey(ctx, adminDataSource)
 	if err != nil {
 		return nil, err
 	}
-	driver, err := getDatabaseDriver(
-		ctx,
-		instance.Engine,
+	host, port, err := d.getDataSourceHostPort(adminDataSource, adminDataSource.Host, adminDataSource.Port)
+	if err != nil {
+		return nil, err
+	}
+	return d.getPooledDriver(ctx, instance, adminDataSource, fmt.Sprintf("%d/admin/%s", instance.UID, databaseName),
 		db.DriverConfig{
 			DbBinDir:  dbBinDir,
 			BinlogDir: common.GetBinlogAbsDir(d.dataDir, instance.UID),
 		},
 		db.ConnectionConfig{
-			Username: adminDataSource.Username,
+			Username: username,
 			Password: password,
 			TLSConfig: db.TLSConfig{
 				SslCA:   sslCA,
 				SslCert: sslCert,
 				SslKey:  sslKey,
 			},
-			Host:                   adminDataSource.Host,
-			Port:                   adminDataSource.Port,
+			Host:                   host,
+			Port:                   port,
 			Database:               databaseName,
 			SRV:                    adminDataSource.SRV,
 			AuthenticationDatabase: adminDataSource.AuthenticationDatabase,
 		},
-		db.ConnectionContext{
-			EnvironmentID: instance.EnvironmentID,
-			InstanceID:    instance.ResourceID,
-		},
 	)
-	if err != nil {
-		return nil, err
-	}
-
-	return driver, nil
 }
 
 // GetReadOnlyDatabaseDriver gets the read-only database driver using the instance's read-only data source.
 // If the read-only data source is not defined, we will fallback to admin data source.
-// Upon successful return, caller must call driver.Close(). Otherwise, it will leak the database connection.
+// Upon successful return, caller must call driver.Close() to return the driver to the pool. Otherwise, it will leak the database connection.
 func (d *DBFactory) GetReadOnlyDatabaseDriver(ctx context.Context, instance *store.InstanceMessage, databaseName string) (db.Driver, error) {
 	dataSource := utils.DataSourceFromInstanceWithType(instance, api.RO)
 	// If there are no read-only data source, fall back to admin data source.
@@ -115,9 +136,6 @@ func (d *DBFactory) GetReadOnlyDatabaseDriver(ctx context.Context, instance *sto
 	}
 
 	host, port := dataSource.Host, dataSource.Port
-	if dataSource.Host != "" || dataSource.Port != "" {
-		host, port = dataSource.Host, dataSource.Port
-	}
 
 	dbBinDir := ""
 	switch instance.Engine {
@@ -127,7 +145,7 @@ func (d *DBFactory) GetReadOnlyDatabaseDriver(ctx context.Context, instance *sto
 		dbBinDir = d.pgBinDir
 	}
 
-	password, err := common.Unobfuscate(dataSource.ObfuscatedPassword, d.secret)
+	username, password, err := d.GetDataSourcePassword(ctx, dataSource)
 	if err != nil {
 		return nil, err
 	}
@@ -139,19 +157,21 @@ func (d *DBFactory) GetReadOnlyDatabaseDriver(ctx context.Context, instance *sto
 	if err != nil {
 		return nil, err
 	}
-	sslKey, err := common.Unobfuscate(dataSource.ObfuscatedSslKey, d.secret)
+	sslKey, err := d.GetDataSourceSslKey(ctx, dataSource)
 	if err != nil {
 		return nil, err
 	}
-	driver, err := getDatabaseDriver(
-		ctx,
-		instance.Engine,
+	host, port, err = d.getDataSourceHostPort(dataSource, host, port)
+	if err != nil {
+		return nil, err
+	}
+	return d.getPooledDriver(ctx, instance, dataSource, fmt.Sprintf("%d/read-only/%s", instance.UID, databaseName),
 		db.DriverConfig{
 			DbBinDir:  dbBinDir,
 			BinlogDir: common.GetBinlogAbsDir(d.dataDir, instance.UID),
 		},
 		db.ConnectionConfig{
-			Username: dataSource.Username,
+			Username: username,
 			Password: password,
 			Host:     host,
 			Port:     port,
@@ -163,16 +183,181 @@ func (d *DBFactory) GetReadOnlyDatabaseDriver(ctx context.Context, instance *sto
 			},
 			ReadOnly: true,
 		},
-		db.ConnectionContext{
-			EnvironmentID: instance.EnvironmentID,
-			InstanceID:    instance.ResourceID,
-		},
 	)
+}
+
+// getPooledDriver gets an idle driver of the key from the pool, or opens a new one with the connection config.
+func (d *DBFactory) getPooledDriver(ctx context.Context, instance *store.InstanceMessage, dataSource *store.DataSourceMessage, key string, driverConfig db.DriverConfig, connectionConfig db.ConnectionConfig) (db.Driver, error) {
+	poolKey, err := newPoolKey(instance.ResourceID, key, connectionConfig)
 	if err != nil {
 		return nil, err
 	}
+	return d.pool.get(ctx, poolKey, func() (db.Driver, error) {
+		driver, err := getDatabaseDriver(
+			ctx,
+			instance.Engine,
+			driverConfig,
+			connectionConfig,
+			db.ConnectionContext{
+				EnvironmentID: instance.EnvironmentID,
+				InstanceID:    instance.ResourceID,
+			},
+		)
+		if err != nil {
+			d.invalidateDataSourceSecrets(dataSource)
+			return nil, err
+		}
+		return driver, nil
+	})
+}
+
+// Run evicts the expired idle drivers periodically, and closes all idle drivers on shutdown.
+func (d *DBFactory) Run(ctx context.Context, wg *sync.WaitGroup) {
+	ticker := time.NewTicker(evictInterval)
+	defer ticker.Stop()
+	defer wg.Done()
+	log.Debug(fmt.Sprintf("Database driver pool started and will evict idle drivers every %v", evictInterval))
+	for {
+		select {
+		case <-ticker.C:
+			d.pool.evict(ctx)
+		case <-ctx.Done():
+			// Use a new context because ctx is canceled.
+			d.pool.close(context.Background())
+			return
+		}
+	}
+}
 
-	return driver, nil
+// GetDataSourcePassword returns the username and the password of the data source.
+// If the password secret reference is set, the password is resolved from the external secret provider,
+// and the username is overridden by the dynamic credentials if any.
+func (d *DBFactory) GetDataSourcePassword(ctx context.Context, dataSource *store.DataSourceMessage) (string, string, error) {
+	if dataSource.PasswordSecretRef == "" {
+		password, err := common.Unobfuscate(dataSource.ObfuscatedPassword, d.secret)
+		if err != nil {
+			return "", "", err
+		}
+		return dataSource.Username, password, nil
+	}
+	credential, err := d.resolveSecret(ctx, dataSource.PasswordSecretRef)
+	if err != nil {
+		return "", "", err
+	}
+	username := dataSource.Username
+	if credential.Username != "" {
+		username = credential.Username
+	}
+	return username, credential.Value, nil
+}
+
+// GetDataSourceSslKey returns the SSL key of the data source, which is resolved from the external secret provider if the secret reference is set.
+func (d *DBFactory) GetDataSourceSslKey(ctx context.Context, dataSource *store.DataSourceMessage) (string, error) {
+	if dataSource.SslKeySecretRef == "" {
+		return common.Unobfuscate(dataSource.ObfuscatedSslKey, d.secret)
+	}
+	credential, err := d.resolveSecret(ctx, dataSource.SslKeySecretRef)
+	if err != nil {
+		return "", err
+	}
+	return credential.Value, nil
+}
+
+func (d *DBFactory) resolveSecret(ctx context.Context, reference string) (*secret.Secret, error) {
+	if d.secretResolver == nil {
+		return nil, common.Errorf(common.Internal, "external secret provider is not configured for secret %q", reference)
+	}
+	credential, err := d.secretResolver.Resolve(ctx, reference)
+	if err != nil {
+		return nil, common.Wrapf(err, common.DbConnectionFailure, "failed to resolve secret %q", reference)
+	}
+	return credential, nil
+}
+
+// invalidateDataSourceSecrets drops the cached credentials of the data source after failing to connect,
+// so that the rotated credentials are fetched again on the next connection.
+func (d *DBFactory) invalidateDataSourceSecrets(dataSource *store.DataSourceMessage) {
+	if d.secretResolver == nil {
+		return
+	}
+	if dataSource.PasswordSecretRef != "" {
+		d.secretResolver.Invalidate(dataSource.PasswordSecretRef)
+	}
+	if dataSource.SslKeySecretRef != "" {
+		d.secretResolver.Invalidate(dataSource.SslKeySecretRef)
+	}
+}
+
+// GetTunnelHostPort returns the local host and port of the SSH tunnel to the database at host:port.
+// The tunnel is kept open and shared by all connections to the same database through the same SSH host,
+// and it is closed after it has been idle for a while.
+func (d *DBFactory) GetTunnelHostPort(sshConfig SSHConfig, host, port string) (string, string, error) {
+	targetAddr := net.JoinHostPort(host, port)
+	key := fmt.Sprintf("%s@%s:%s/%s", sshConfig.User, sshConfig.Host, sshConfig.Port, targetAddr)
+	credentialHash := sha256.Sum256([]byte(sshConfig.Password + "\x00" + sshConfig.PrivateKey + "\x00" + sshConfig.HostKey))
+
+	entry := d.getTunnelEntry(key)
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	if entry.tunnel != nil {
+		if entry.credentialHash == credentialHash && entry.tunnel.touch() {
+			return "127.0.0.1", entry.tunnel.localPort(), nil
+		}
+		entry.tunnel.Close()
+		entry.tunnel = nil
+	}
+	tunnel, err := newSSHTunnel(sshConfig, targetAddr, sshTunnelIdleTimeout)
+	if err != nil {
+		return "", "", common.Wrapf(err, common.DbConnectionFailure, "failed to create SSH tunnel to %s through %s", targetAddr, sshConfig.Host)
+	}
+	entry.tunnel = tunnel
+	entry.credentialHash = credentialHash
+	return "127.0.0.1", tunnel.localPort(), nil
+}
+
+// getTunnelEntry returns the tunnel entry of the key, and drops the entries whose tunnels have been closed for being idle.
+func (d *DBFactory) getTunnelEntry(key string) *tunnelEntry {
+	d.tunnelMu.Lock()
+	defer d.tunnelMu.Unlock()
+	for k, entry := range d.tunnels {
+		if k == key || !entry.mu.TryLock() {
+			// The entry is in use.
+			continue
+		}
+		if entry.tunnel == nil || entry.tunnel.isClosed() {
+			delete(d.tunnels, k)
+		}
+		entry.mu.Unlock()
+	}
+	entry, ok := d.tunnels[key]
+	if !ok {
+		entry = &tunnelEntry{}
+		d.tunnels[key] = entry
+	}
+	return entry
+}
+
+// getDataSourceHostPort returns the host and port to connect to the database at host:port with the SSH settings of the data source.
+func (d *DBFactory) getDataSourceHostPort(dataSource *store.DataSourceMessage, host, port string) (string, string, error) {
+	if dataSource.SSHHost == "" {
+		return host, port, nil
+	}
+	sshPassword, err := common.Unobfuscate(dataSource.SSHObfuscatedPassword, d.secret)
+	if err != nil {
+		return "", "", err
+	}
+	sshPrivateKey, err := common.Unobfuscate(dataSource.SSHObfuscatedPrivateKey, d.secret)
+	if err != nil {
+		return "", "", err
+	}
+	return d.GetTunnelHostPort(SSHConfig{
+		Host:       dataSource.SSHHost,
+		Port:       dataSource.SSHPort,
+		User:       dataSource.SSHUser,
+		Password:   sshPassword,
+		PrivateKey: sshPrivateKey,
+		HostKey:    dataSource.SSHHostKey,
+	}, host, port)
 }
 
 // Retrieve db.Driver connection with standard parameters for all type data source.
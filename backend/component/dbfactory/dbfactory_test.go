@@ -0,0 +1,54 @@
+package dbfactory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/backend/common"
+	"github.com/bytebase/bytebase/backend/plugin/secret"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+func TestGetDataSourceCredential(t *testing.T) {
+	a := require.New(t)
+	ctx := context.Background()
+	t.Setenv("BB_SECRET_MYSQL_PASSWORD", "env-password")
+	t.Setenv("BB_SECRET_MYSQL_SSL_KEY", "env-ssl-key")
+	d := New("", "", "", "", "secret", secret.NewResolver(map[secret.ProviderType]secret.Provider{
+		secret.ProviderEnv: secret.NewEnvProvider(),
+	}, secret.DefaultCacheTTL))
+
+	// Fall back to the stored credentials if the secret references are not set.
+	dataSource := &store.DataSourceMessage{
+		Username:           "root",
+		ObfuscatedPassword: common.Obfuscate("stored-password", "secret"),
+		ObfuscatedSslKey:   common.Obfuscate("stored-ssl-key", "secret"),
+	}
+	username, password, err := d.GetDataSourcePassword(ctx, dataSource)
+	a.NoError(err)
+	a.Equal("root", username)
+	a.Equal("stored-password", password)
+	sslKey, err := d.GetDataSourceSslKey(ctx, dataSource)
+	a.NoError(err)
+	a.Equal("stored-ssl-key", sslKey)
+
+	dataSource = &store.DataSourceMessage{
+		Username:          "root",
+		PasswordSecretRef: "env://BB_SECRET_MYSQL_PASSWORD",
+		SslKeySecretRef:   "env://BB_SECRET_MYSQL_SSL_KEY",
+	}
+	username, password, err = d.GetDataSourcePassword(ctx, dataSource)
+	a.NoError(err)
+	a.Equal("root", username)
+	a.Equal("env-password", password)
+	sslKey, err = d.GetDataSourceSslKey(ctx, dataSource)
+	a.NoError(err)
+	a.Equal("env-ssl-key", sslKey)
+
+	// The provider is not configured.
+	_, _, err = d.GetDataSourcePassword(ctx, &store.DataSourceMessage{PasswordSecretRef: "vault://secret/data/mysql#password"})
+	a.Error(err)
+	a.Equal(common.DbConnectionFailure, common.ErrorCode(err))
+}
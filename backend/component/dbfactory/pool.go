@@ -0,0 +1,366 @@
+package dbfactory
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/backend/common/log"
+	"github.com/bytebase/bytebase/backend/plugin/db"
+)
+
+const (
+	// defaultMaxIdle is the maximum number of idle drivers kept for each instance, database and data source.
+	defaultMaxIdle = 2
+	// defaultMaxLifetime is the maximum duration a driver can be reused after it's opened.
+	defaultMaxLifetime = 30 * time.Minute
+	// defaultIdleTimeout is the maximum duration a driver can stay idle in the pool.
+	defaultIdleTimeout = 5 * time.Minute
+	// defaultHealthCheckInterval is the idle duration after which the driver is pinged before it's reused.
+	defaultHealthCheckInterval = 30 * time.Second
+	healthCheckTimeout         = 5 * time.Second
+	evictInterval              = time.Minute
+)
+
+// The reasons to close a driver instead of returning it to the pool.
+const (
+	closeReasonMaxIdle       = "max_idle"
+	closeReasonMaxLifetime   = "max_lifetime"
+	closeReasonIdleTimeout   = "idle_timeout"
+	closeReasonHealthCheck   = "health_check"
+	closeReasonStateful      = "stateful"
+	closeReasonConfigChanged = "config_changed"
+	closeReasonShutdown      = "shutdown"
+)
+
+var (
+	poolOpenDrivers = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "bytebase",
+		Subsystem: "driver_pool",
+		Name:      "open_drivers",
+		Help:      "The number of open database drivers, both in use and idle.",
+	}, []string{"instance"})
+	poolIdleDrivers = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "bytebase",
+		Subsystem: "driver_pool",
+		Name:      "idle_drivers",
+		Help:      "The number of idle database drivers in the pool.",
+	}, []string{"instance"})
+	poolAcquireTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "bytebase",
+		Subsystem: "driver_pool",
+		Name:      "acquire_total",
+		Help:      "The number of acquired database drivers, result is hit if the driver is reused from the pool.",
+	}, []string{"instance", "result"})
+	poolCloseTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "bytebase",
+		Subsystem: "driver_pool",
+		Name:      "close_total",
+		Help:      "The number of closed database drivers by the reason.",
+	}, []string{"instance", "reason"})
+)
+
+func init() {
+	prometheus.MustRegister(poolOpenDrivers, poolIdleDrivers, poolAcquireTotal, poolCloseTotal)
+}
+
+// driverPool keeps the idle database drivers for reuse.
+// A driver is used by one caller at a time, and it's returned to the pool when the caller closes it.
+type driverPool struct {
+	maxIdle             int
+	maxLifetime         time.Duration
+	idleTimeout         time.Duration
+	healthCheckInterval time.Duration
+	// now is overridden in tests.
+	now func() time.Time
+
+	mu sync.Mutex
+	// idle is the map from the pool key to the idle drivers, the most recently released driver is the last one.
+	idle   map[string][]*pooledDriver
+	closed bool
+}
+
+func newDriverPool() *driverPool {
+	return &driverPool{
+		maxIdle:             defaultMaxIdle,
+		maxLifetime:         defaultMaxLifetime,
+		idleTimeout:         defaultIdleTimeout,
+		healthCheckInterval: defaultHealthCheckInterval,
+		now:                 time.Now,
+		idle:                make(map[string][]*pooledDriver),
+	}
+}
+
+// poolKey identifies the drivers which can be shared.
+type poolKey struct {
+	// instanceID is the resource ID of the instance, which is used as the metric label.
+	instanceID string
+	// key is the instance, database and data source of the driver.
+	key string
+	// configHash is the hash of the connection config, the idle drivers are closed after the config changes.
+	configHash [sha256.Size]byte
+	// database is the database the driver connects to.
+	database string
+}
+
+func newPoolKey(instanceID, key string, connectionConfig db.ConnectionConfig) (poolKey, error) {
+	config, err := json.Marshal(connectionConfig)
+	if err != nil {
+		return poolKey{}, err
+	}
+	return poolKey{
+		instanceID: instanceID,
+		key:        key,
+		configHash: sha256.Sum256(config),
+		database:   connectionConfig.Database,
+	}, nil
+}
+
+// get returns an idle driver of the key from the pool, or opens a new one if there is no healthy idle driver.
+func (p *driverPool) get(ctx context.Context, key poolKey, open func() (db.Driver, error)) (db.Driver, error) {
+	for {
+		driver, reason := p.popIdle(key)
+		if reason != "" {
+			p.discard(ctx, driver, reason)
+			continue
+		}
+		if driver == nil {
+			break
+		}
+		if p.now().Sub(driver.releasedAt) >= p.healthCheckInterval {
+			pingCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+			err := driver.Driver.Ping(pingCtx)
+			cancel()
+			if err != nil {
+				log.Debug("Idle database driver failed the health check", zap.String("instance", key.instanceID), zap.Error(err))
+				p.discard(ctx, driver, closeReasonHealthCheck)
+				continue
+			}
+		}
+		driver.released = false
+		driver.stateful = false
+		poolAcquireTotal.WithLabelValues(key.instanceID, "hit").Inc()
+		return driver, nil
+	}
+
+	driver, err := open()
+	if err != nil {
+		return nil, err
+	}
+	poolAcquireTotal.WithLabelValues(key.instanceID, "miss").Inc()
+	poolOpenDrivers.WithLabelValues(key.instanceID).Inc()
+	return &pooledDriver{
+		Driver:   driver,
+		pool:     p,
+		key:      key,
+		openedAt: p.now(),
+	}, nil
+}
+
+// popIdle pops the most recently released driver of the key.
+// If the driver cannot be reused, the reason is returned and the caller should close it.
+func (p *driverPool) popIdle(key poolKey) (*pooledDriver, string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	drivers := p.idle[key.key]
+	if len(drivers) == 0 {
+		return nil, ""
+	}
+	driver := drivers[len(drivers)-1]
+	if len(drivers) == 1 {
+		delete(p.idle, key.key)
+	} else {
+		p.idle[key.key] = drivers[:len(drivers)-1]
+	}
+	poolIdleDrivers.WithLabelValues(key.instanceID).Dec()
+	return driver, p.checkReusable(driver, key.configHash)
+}
+
+// checkReusable returns the reason if the idle driver cannot be reused.
+func (p *driverPool) checkReusable(driver *pooledDriver, configHash [sha256.Size]byte) string {
+	now := p.now()
+	switch {
+	case driver.key.configHash != configHash:
+		return closeReasonConfigChanged
+	case now.Sub(driver.openedAt) >= p.maxLifetime:
+		return closeReasonMaxLifetime
+	case now.Sub(driver.releasedAt) >= p.idleTimeout:
+		return closeReasonIdleTimeout
+	}
+	return ""
+}
+
+// release returns the driver to the pool, or closes it if it cannot be reused.
+func (p *driverPool) release(ctx context.Context, driver *pooledDriver) error {
+	reason := func() string {
+		if driver.stateful {
+			return closeReasonStateful
+		}
+		if p.now().Sub(driver.openedAt) >= p.maxLifetime {
+			return closeReasonMaxLifetime
+		}
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		if p.closed {
+			return closeReasonShutdown
+		}
+		if len(p.idle[driver.key.key]) >= p.maxIdle {
+			return closeReasonMaxIdle
+		}
+		driver.releasedAt = p.now()
+		p.idle[driver.key.key] = append(p.idle[driver.key.key], driver)
+		poolIdleDrivers.WithLabelValues(driver.key.instanceID).Inc()
+		return ""
+	}()
+	if reason == "" {
+		return nil
+	}
+	return p.closeDriver(ctx, driver, reason)
+}
+
+func (*driverPool) closeDriver(ctx context.Context, driver *pooledDriver, reason string) error {
+	poolOpenDrivers.WithLabelValues(driver.key.instanceID).Dec()
+	poolCloseTotal.WithLabelValues(driver.key.instanceID, reason).Inc()
+	return driver.Driver.Close(ctx)
+}
+
+// discard closes the idle driver which cannot be reused, and the error is only logged since nobody is waiting for it.
+func (p *driverPool) discard(ctx context.Context, driver *pooledDriver, reason string) {
+	if err := p.closeDriver(ctx, driver, reason); err != nil {
+		log.Debug("Failed to close the idle database driver",
+			zap.String("instance", driver.key.instanceID),
+			zap.String("reason", reason),
+			zap.Error(err))
+	}
+}
+
+// evict closes the idle drivers exceeding the idle timeout or the max lifetime.
+func (p *driverPool) evict(ctx context.Context) {
+	type evicted struct {
+		driver *pooledDriver
+		reason string
+	}
+	var evictedList []evicted
+	p.mu.Lock()
+	for key, drivers := range p.idle {
+		var kept []*pooledDriver
+		for _, driver := range drivers {
+			if reason := p.checkReusable(driver, driver.key.configHash); reason != "" {
+				evictedList = append(evictedList, evicted{driver: driver, reason: reason})
+				poolIdleDrivers.WithLabelValues(driver.key.instanceID).Dec()
+				continue
+			}
+			kept = append(kept, driver)
+		}
+		if len(kept) == 0 {
+			delete(p.idle, key)
+		} else {
+			p.idle[key] = kept
+		}
+	}
+	p.mu.Unlock()
+
+	for _, e := range evictedList {
+		p.discard(ctx, e.driver, e.reason)
+	}
+}
+
+// close closes all idle drivers, and the drivers in use are closed once they are released.
+func (p *driverPool) close(ctx context.Context) {
+	p.mu.Lock()
+	idle := p.idle
+	p.idle = make(map[string][]*pooledDriver)
+	p.closed = true
+	p.mu.Unlock()
+
+	for _, drivers := range idle {
+		for _, driver := range drivers {
+			poolIdleDrivers.WithLabelValues(driver.key.instanceID).Dec()
+			p.discard(ctx, driver, closeReasonShutdown)
+		}
+	}
+}
+
+// pooledDriver is the database driver returned by the factory. Close returns the driver to the pool.
+// The methods which may change the connection state, e.g. switching the database or holding a session for migrations,
+// mark the driver as stateful so that it's closed instead of being reused.
+type pooledDriver struct {
+	db.Driver
+	pool       *driverPool
+	key        poolKey
+	openedAt   time.Time
+	releasedAt time.Time
+	stateful   bool
+	released   bool
+}
+
+// Unwrap returns the underlying database driver, e.g. to type assert the engine specific driver.
+// The driver won't be reused since the caller may change its state.
+func Unwrap(driver db.Driver) db.Driver {
+	if d, ok := driver.(*pooledDriver); ok {
+		d.stateful = true
+		return d.Driver
+	}
+	return driver
+}
+
+// Close returns the driver to the pool.
+func (d *pooledDriver) Close(ctx context.Context) error {
+	if d.released {
+		return nil
+	}
+	d.released = true
+	return d.pool.release(ctx, d)
+}
+
+// GetDBConnection returns the underlying database connection.
+// Some drivers reconnect to the other database, so the driver is only reused if the database is unchanged.
+func (d *pooledDriver) GetDBConnection(ctx context.Context, database string) (*sql.DB, error) {
+	if database != d.key.database {
+		d.stateful = true
+	}
+	return d.Driver.GetDBConnection(ctx, database)
+}
+
+// Execute executes the statement.
+func (d *pooledDriver) Execute(ctx context.Context, statement string, createDatabase bool) (int64, error) {
+	d.stateful = true
+	return d.Driver.Execute(ctx, statement, createDatabase)
+}
+
+// NeedsSetupMigration checks whether we need to setup migration.
+func (d *pooledDriver) NeedsSetupMigration(ctx context.Context) (bool, error) {
+	d.stateful = true
+	return d.Driver.NeedsSetupMigration(ctx)
+}
+
+// SetupMigrationIfNeeded creates or upgrades the migration related tables.
+func (d *pooledDriver) SetupMigrationIfNeeded(ctx context.Context) error {
+	d.stateful = true
+	return d.Driver.SetupMigrationIfNeeded(ctx)
+}
+
+// ExecuteMigration executes the migration.
+func (d *pooledDriver) ExecuteMigration(ctx context.Context, m *db.MigrationInfo, statement string) (string, string, error) {
+	d.stateful = true
+	return d.Driver.ExecuteMigration(ctx, m, statement)
+}
+
+// Dump dumps the database.
+func (d *pooledDriver) Dump(ctx context.Context, database string, out io.Writer, schemaOnly bool) (string, error) {
+	d.stateful = true
+	return d.Driver.Dump(ctx, database, out, schemaOnly)
+}
+
+// Restore restores the database.
+func (d *pooledDriver) Restore(ctx context.Context, src io.Reader) error {
+	d.stateful = true
+	return d.Driver.Restore(ctx, src)
+}
@@ -0,0 +1,154 @@
+package dbfactory
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/backend/plugin/db"
+)
+
+type fakeDriver struct {
+	db.Driver
+	id      int
+	pingErr error
+	closed  bool
+}
+
+func (d *fakeDriver) Ping(_ context.Context) error {
+	return d.pingErr
+}
+
+func (d *fakeDriver) Close(_ context.Context) error {
+	d.closed = true
+	return nil
+}
+
+func (*fakeDriver) GetDBConnection(context.Context, string) (*sql.DB, error) {
+	return nil, nil
+}
+
+func TestDriverPool(t *testing.T) {
+	a := require.New(t)
+	ctx := context.Background()
+	pool := newDriverPool()
+	now := time.Now()
+	pool.now = func() time.Time { return now }
+
+	var opened []*fakeDriver
+	open := func() (db.Driver, error) {
+		driver := &fakeDriver{id: len(opened)}
+		opened = append(opened, driver)
+		return driver, nil
+	}
+	get := func(key poolKey) *fakeDriver {
+		driver, err := pool.get(ctx, key, open)
+		a.NoError(err)
+		return driver.(*pooledDriver).Driver.(*fakeDriver)
+	}
+	key, err := newPoolKey("mysql", "101/admin/", db.ConnectionConfig{Username: "root", Password: "1"})
+	a.NoError(err)
+
+	// Reuse the released driver.
+	driver, err := pool.get(ctx, key, open)
+	a.NoError(err)
+	a.NoError(driver.Close(ctx))
+	// Closing twice doesn't release the driver twice.
+	a.NoError(driver.Close(ctx))
+	a.Equal(0, get(key).id)
+	a.Len(opened, 1)
+
+	// The drivers exceeding the max idle are closed.
+	var drivers []db.Driver
+	for i := 0; i < 3; i++ {
+		driver, err := pool.get(ctx, key, open)
+		a.NoError(err)
+		drivers = append(drivers, driver)
+	}
+	for _, driver := range drivers {
+		a.NoError(driver.Close(ctx))
+	}
+	a.Len(pool.idle[key.key], 2)
+	a.Equal([]bool{false, false, false, true}, []bool{opened[0].closed, opened[1].closed, opened[2].closed, opened[3].closed})
+
+	// The stateful driver is closed instead of being reused.
+	driver, err = pool.get(ctx, key, open)
+	a.NoError(err)
+	_ = Unwrap(driver)
+	a.NoError(driver.Close(ctx))
+	a.True(opened[2].closed)
+	a.Len(pool.idle[key.key], 1)
+
+	// The idle driver is closed after the connection config changes.
+	changedKey, err := newPoolKey("mysql", "101/admin/", db.ConnectionConfig{Username: "root", Password: "2"})
+	a.NoError(err)
+	a.Equal(4, get(changedKey).id)
+	a.True(opened[1].closed)
+	a.Empty(pool.idle)
+
+	// The idle driver failing the health check is closed.
+	driver, err = pool.get(ctx, key, open)
+	a.NoError(err)
+	a.NoError(driver.Close(ctx))
+	opened[5].pingErr = errors.New("connection reset")
+	now = now.Add(time.Minute)
+	a.Equal(6, get(key).id)
+	a.True(opened[5].closed)
+
+	// The idle drivers are evicted after the idle timeout or the max lifetime.
+	driver, err = pool.get(ctx, key, open)
+	a.NoError(err)
+	a.NoError(driver.Close(ctx))
+	now = now.Add(defaultIdleTimeout)
+	pool.evict(ctx)
+	a.True(opened[7].closed)
+	a.Empty(pool.idle)
+
+	driver, err = pool.get(ctx, key, open)
+	a.NoError(err)
+	now = now.Add(defaultMaxLifetime)
+	a.NoError(driver.Close(ctx))
+	a.True(opened[8].closed)
+
+	// The released drivers are closed after the pool is closed.
+	driver, err = pool.get(ctx, key, open)
+	a.NoError(err)
+	idle, err := pool.get(ctx, key, open)
+	a.NoError(err)
+	a.NoError(idle.Close(ctx))
+	pool.close(ctx)
+	a.True(opened[10].closed)
+	a.NoError(driver.Close(ctx))
+	a.True(opened[9].closed)
+}
+
+func TestUnwrap(t *testing.T) {
+	a := require.New(t)
+	driver := &fakeDriver{}
+	a.Equal(driver, Unwrap(driver))
+	pooled := &pooledDriver{Driver: driver}
+	a.Equal(driver, Unwrap(pooled))
+	a.True(pooled.stateful)
+}
+
+func TestGetDBConnection(t *testing.T) {
+	a := require.New(t)
+	ctx := context.Background()
+	key, err := newPoolKey("instance", "1/read-only/db", db.ConnectionConfig{Database: "db"})
+	a.NoError(err)
+
+	// The driver connecting to the same database is reused.
+	pooled := &pooledDriver{Driver: &fakeDriver{}, key: key}
+	_, err = pooled.GetDBConnection(ctx, "db")
+	a.NoError(err)
+	a.False(pooled.stateful)
+
+	// The driver switching to another database is not reused.
+	_, err = pooled.GetDBConnection(ctx, "other")
+	a.NoError(err)
+	a.True(pooled.stateful)
+}
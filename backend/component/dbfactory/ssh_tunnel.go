@@ -0,0 +1,322 @@
+package dbfactory
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/bytebase/bytebase/backend/common/log"
+)
+
+const (
+	defaultSSHPort = "22"
+	sshDialTimeout = 10 * time.Second
+	// sshTunnelIdleTimeout is the time after which the tunnel without any forwarded connection is closed.
+	sshTunnelIdleTimeout = 10 * time.Minute
+)
+
+// SSHConfig is the config of the SSH tunnel through a bastion host.
+// Either Password or PrivateKey should be set.
+type SSHConfig struct {
+	Host       string
+	Port       string
+	User       string
+	Password   string
+	PrivateKey string
+	// HostKey is the public key of the bastion host in the authorized_keys or known_hosts format, which is required to verify the host.
+	HostKey string
+}
+
+// sshTunnel forwards the connections accepted on a local port to the target address through the SSH bastion host.
+// The SSH connection is shared by all forwarded connections, and it is re-established once it is broken.
+// The tunnel closes itself after it has no forwarded connection for the idle timeout.
+type sshTunnel struct {
+	sshAddr      string
+	targetAddr   string
+	clientConfig *ssh.ClientConfig
+	listener     net.Listener
+	idleTimeout  time.Duration
+
+	mu     sync.Mutex
+	client *ssh.Client
+	closed bool
+	// activeConns is the number of the forwarded connections.
+	activeConns int
+	// idleTimer closes the tunnel if it's idle, it's nil if there are forwarded connections.
+	idleTimer *time.Timer
+}
+
+// newSSHTunnel connects to the bastion host and starts forwarding the connections on a random local port to the target address.
+func newSSHTunnel(config SSHConfig, targetAddr string, idleTimeout time.Duration) (*sshTunnel, error) {
+	clientConfig, err := getSSHClientConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	port := config.Port
+	if port == "" {
+		port = defaultSSHPort
+	}
+	t := &sshTunnel{
+		sshAddr:      net.JoinHostPort(config.Host, port),
+		targetAddr:   targetAddr,
+		clientConfig: clientConfig,
+		idleTimeout:  idleTimeout,
+	}
+	// Connect eagerly so that authentication errors are returned to the caller instead of showing up as broken connections.
+	if _, err := t.getClient(nil); err != nil {
+		return nil, err
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Close()
+		return nil, errors.Wrap(err, "failed to listen on local port for SSH tunnel")
+	}
+	t.listener = listener
+	t.mu.Lock()
+	t.idleTimer = time.AfterFunc(t.idleTimeout, t.closeIfIdle)
+	t.mu.Unlock()
+	go t.serve()
+	return t, nil
+}
+
+func getSSHClientConfig(config SSHConfig) (*ssh.ClientConfig, error) {
+	if config.User == "" {
+		return nil, errors.Errorf("SSH user is required")
+	}
+	var authMethods []ssh.AuthMethod
+	if config.PrivateKey != "" {
+		signer, err := ssh.ParsePrivateKey([]byte(config.PrivateKey))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse SSH private key")
+		}
+		authMethods = append(authMethods, ssh.PublicKeys(signer))
+	}
+	if config.Password != "" {
+		authMethods = append(authMethods, ssh.Password(config.Password))
+	}
+	if len(authMethods) == 0 {
+		return nil, errors.Errorf("either SSH password or private key is required")
+	}
+	hostKeys, err := parseSSHHostKeys(config.HostKey)
+	if err != nil {
+		return nil, err
+	}
+	return &ssh.ClientConfig{
+		User: config.User,
+		Auth: authMethods,
+		HostKeyCallback: func(_ string, _ net.Addr, key ssh.PublicKey) error {
+			for _, hostKey := range hostKeys {
+				if bytes.Equal(hostKey.Marshal(), key.Marshal()) {
+					return nil
+				}
+			}
+			return errors.Errorf("SSH host key %s %s doesn't match the configured host key", key.Type(), ssh.FingerprintSHA256(key))
+		},
+		HostKeyAlgorithms: getSSHHostKeyAlgorithms(hostKeys),
+		Timeout:           sshDialTimeout,
+	}, nil
+}
+
+// parseSSHHostKeys parses the host keys in the authorized_keys or known_hosts format, one key per line.
+// The host patterns of the known_hosts lines are ignored, since the keys are configured for the bastion host.
+func parseSSHHostKeys(hostKey string) ([]ssh.PublicKey, error) {
+	var keys []ssh.PublicKey
+	for _, line := range strings.Split(hostKey, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(line)); err == nil {
+			keys = append(keys, key)
+			continue
+		}
+		_, _, key, _, _, err := ssh.ParseKnownHosts([]byte(line))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse SSH host key %q", line)
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return nil, errors.Errorf("SSH host key is required to verify the bastion host")
+	}
+	return keys, nil
+}
+
+// getSSHHostKeyAlgorithms returns the algorithms of the host keys, so that the bastion host presents one of the configured keys.
+func getSSHHostKeyAlgorithms(hostKeys []ssh.PublicKey) []string {
+	var algorithms []string
+	seen := make(map[string]bool)
+	for _, key := range hostKeys {
+		keyAlgorithms := []string{key.Type()}
+		// The RSA keys are signed with the SHA-2 algorithms by the modern servers.
+		if key.Type() == ssh.KeyAlgoRSA {
+			keyAlgorithms = []string{ssh.KeyAlgoRSASHA512, ssh.KeyAlgoRSASHA256, ssh.KeyAlgoRSA}
+		}
+		for _, algorithm := range keyAlgorithms {
+			if !seen[algorithm] {
+				seen[algorithm] = true
+				algorithms = append(algorithms, algorithm)
+			}
+		}
+	}
+	return algorithms
+}
+
+// localPort returns the local port to connect to the target address.
+func (t *sshTunnel) localPort() string {
+	return strconv.Itoa(t.listener.Addr().(*net.TCPAddr).Port)
+}
+
+// Close stops the tunnel and closes all forwarded connections.
+func (t *sshTunnel) Close() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.closeLocked()
+}
+
+func (t *sshTunnel) closeLocked() {
+	t.closed = true
+	if t.idleTimer != nil {
+		t.idleTimer.Stop()
+		t.idleTimer = nil
+	}
+	if t.listener != nil {
+		t.listener.Close()
+	}
+	if t.client != nil {
+		t.client.Close()
+		t.client = nil
+	}
+}
+
+// closeIfIdle closes the tunnel if there is no forwarded connection.
+func (t *sshTunnel) closeIfIdle() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed || t.activeConns > 0 {
+		return
+	}
+	t.closeLocked()
+}
+
+// touch postpones closing the idle tunnel, since the caller is going to connect to it.
+// It returns false if the tunnel has been closed.
+func (t *sshTunnel) touch() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return false
+	}
+	if t.idleTimer != nil {
+		t.idleTimer.Reset(t.idleTimeout)
+	}
+	return true
+}
+
+func (t *sshTunnel) isClosed() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.closed
+}
+
+func (t *sshTunnel) serve() {
+	for {
+		conn, err := t.listener.Accept()
+		if err != nil {
+			// The listener is closed.
+			return
+		}
+		t.mu.Lock()
+		t.activeConns++
+		if t.idleTimer != nil {
+			t.idleTimer.Stop()
+			t.idleTimer = nil
+		}
+		t.mu.Unlock()
+		go t.forward(conn)
+	}
+}
+
+func (t *sshTunnel) forward(conn net.Conn) {
+	defer func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		t.activeConns--
+		if t.activeConns == 0 && !t.closed {
+			t.idleTimer = time.AfterFunc(t.idleTimeout, t.closeIfIdle)
+		}
+	}()
+	defer conn.Close()
+	remote, err := t.dial()
+	if err != nil {
+		log.Warn("Failed to dial through SSH tunnel",
+			zap.String("ssh", t.sshAddr),
+			zap.String("target", t.targetAddr),
+			zap.Error(err))
+		return
+	}
+	defer remote.Close()
+
+	done := make(chan struct{}, 2)
+	go func() {
+		_, _ = io.Copy(remote, conn)
+		done <- struct{}{}
+	}()
+	go func() {
+		_, _ = io.Copy(conn, remote)
+		done <- struct{}{}
+	}()
+	// Closing either side will unblock the other copy.
+	<-done
+}
+
+// dial dials the target address through the SSH connection, and re-establishes the SSH connection once if it is broken.
+func (t *sshTunnel) dial() (net.Conn, error) {
+	client, err := t.getClient(nil)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := client.Dial("tcp", t.targetAddr)
+	if err == nil {
+		return conn, nil
+	}
+	// The target may refuse the connection, so we only reconnect if the SSH connection itself is broken.
+	if _, _, keepAliveErr := client.SendRequest("keepalive@openssh.com", true /* wantReply */, nil); keepAliveErr == nil {
+		return nil, err
+	}
+	client, err = t.getClient(client)
+	if err != nil {
+		return nil, err
+	}
+	return client.Dial("tcp", t.targetAddr)
+}
+
+// getClient returns the shared SSH client. If broken is the current client, it reconnects to the bastion host.
+func (t *sshTunnel) getClient(broken *ssh.Client) (*ssh.Client, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return nil, errors.Errorf("SSH tunnel to %s is closed", t.sshAddr)
+	}
+	if t.client != nil && t.client != broken {
+		return t.client, nil
+	}
+	if t.client != nil {
+		t.client.Close()
+		t.client = nil
+	}
+	client, err := ssh.Dial("tcp", t.sshAddr, t.clientConfig)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to connect to SSH host %s", t.sshAddr)
+	}
+	t.client = client
+	return client, nil
+}
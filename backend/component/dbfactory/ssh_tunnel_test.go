@@ -0,0 +1,303 @@
+package dbfactory
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"io"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+const (
+	testSSHUser     = "bastion"
+	testSSHPassword = "bastion-password"
+)
+
+// startTestSSHServer starts an in-process SSH server which supports local port forwarding.
+// It accepts the test password and the given public key, and returns the address and the host key in the authorized_keys format.
+func startTestSSHServer(t *testing.T, authorizedKey ssh.PublicKey) (string, string) {
+	_, hostPrivateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	hostSigner, err := ssh.NewSignerFromKey(hostPrivateKey)
+	require.NoError(t, err)
+
+	config := &ssh.ServerConfig{
+		PasswordCallback: func(conn ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+			if conn.User() == testSSHUser && string(password) == testSSHPassword {
+				return nil, nil
+			}
+			return nil, io.EOF
+		},
+		PublicKeyCallback: func(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if conn.User() == testSSHUser && authorizedKey != nil && string(key.Marshal()) == string(authorizedKey.Marshal()) {
+				return nil, nil
+			}
+			return nil, io.EOF
+		},
+	}
+	config.AddHostKey(hostSigner)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveTestSSHConn(conn, config)
+		}
+	}()
+	return listener.Addr().String(), string(ssh.MarshalAuthorizedKey(hostSigner.PublicKey()))
+}
+
+func serveTestSSHConn(conn net.Conn, config *ssh.ServerConfig) {
+	_, chans, reqs, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		conn.Close()
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+	for newChannel := range chans {
+		if newChannel.ChannelType() != "direct-tcpip" {
+			_ = newChannel.Reject(ssh.UnknownChannelType, "unsupported channel type")
+			continue
+		}
+		// The payload of direct-tcpip is defined in RFC 4254 7.2.
+		var payload struct {
+			Host       string
+			Port       uint32
+			OriginHost string
+			OriginPort uint32
+		}
+		if err := ssh.Unmarshal(newChannel.ExtraData(), &payload); err != nil {
+			_ = newChannel.Reject(ssh.ConnectionFailed, err.Error())
+			continue
+		}
+		target, err := net.Dial("tcp", net.JoinHostPort(payload.Host, strconv.Itoa(int(payload.Port))))
+		if err != nil {
+			_ = newChannel.Reject(ssh.ConnectionFailed, err.Error())
+			continue
+		}
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			target.Close()
+			continue
+		}
+		go ssh.DiscardRequests(requests)
+		go func() {
+			defer channel.Close()
+			defer target.Close()
+			go func() {
+				_, _ = io.Copy(target, channel)
+			}()
+			_, _ = io.Copy(channel, target)
+		}()
+	}
+}
+
+// startTestEchoServer starts a TCP server which echoes the received data, which plays the database.
+func startTestEchoServer(t *testing.T) (string, string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_, _ = io.Copy(conn, conn)
+			}()
+		}
+	}()
+	host, port, err := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+	return host, port
+}
+
+func requireEcho(t *testing.T, host, port string) {
+	conn, err := net.Dial("tcp", net.JoinHostPort(host, port))
+	require.NoError(t, err)
+	defer conn.Close()
+	message := []byte("SELECT 1")
+	_, err = conn.Write(message)
+	require.NoError(t, err)
+	buf := make([]byte, len(message))
+	_, err = io.ReadFull(conn, buf)
+	require.NoError(t, err)
+	require.Equal(t, message, buf)
+}
+
+func generateTestPrivateKey(t *testing.T) (string, ssh.PublicKey) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	sshPublicKey, err := ssh.NewPublicKey(publicKey)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})), sshPublicKey
+}
+
+func TestSSHTunnel(t *testing.T) {
+	a := require.New(t)
+	privateKey, publicKey := generateTestPrivateKey(t)
+	addr, hostKey := startTestSSHServer(t, publicKey)
+	sshHost, sshPort, err := net.SplitHostPort(addr)
+	a.NoError(err)
+	targetHost, targetPort := startTestEchoServer(t)
+	_, otherHostKey := generateTestPrivateKey(t)
+
+	tests := []struct {
+		name    string
+		config  SSHConfig
+		wantErr bool
+	}{
+		{
+			name:   "password",
+			config: SSHConfig{Host: sshHost, Port: sshPort, User: testSSHUser, Password: testSSHPassword, HostKey: hostKey},
+		},
+		{
+			name:   "private key",
+			config: SSHConfig{Host: sshHost, Port: sshPort, User: testSSHUser, PrivateKey: privateKey, HostKey: hostKey},
+		},
+		{
+			name:   "known hosts",
+			config: SSHConfig{Host: sshHost, Port: sshPort, User: testSSHUser, Password: testSSHPassword, HostKey: "# bastion\n" + knownhostsLine(t, addr, hostKey)},
+		},
+		{
+			name:    "wrong password",
+			config:  SSHConfig{Host: sshHost, Port: sshPort, User: testSSHUser, Password: "wrong", HostKey: hostKey},
+			wantErr: true,
+		},
+		{
+			name:    "no credential",
+			config:  SSHConfig{Host: sshHost, Port: sshPort, User: testSSHUser, HostKey: hostKey},
+			wantErr: true,
+		},
+		{
+			name:    "no host key",
+			config:  SSHConfig{Host: sshHost, Port: sshPort, User: testSSHUser, Password: testSSHPassword},
+			wantErr: true,
+		},
+		{
+			name:    "wrong host key",
+			config:  SSHConfig{Host: sshHost, Port: sshPort, User: testSSHUser, Password: testSSHPassword, HostKey: string(ssh.MarshalAuthorizedKey(otherHostKey))},
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			a := require.New(t)
+			tunnel, err := newSSHTunnel(test.config, net.JoinHostPort(targetHost, targetPort), sshTunnelIdleTimeout)
+			if test.wantErr {
+				a.Error(err)
+				return
+			}
+			a.NoError(err)
+			defer tunnel.Close()
+			// Multiple connections share the same SSH connection.
+			requireEcho(t, "127.0.0.1", tunnel.localPort())
+			requireEcho(t, "127.0.0.1", tunnel.localPort())
+		})
+	}
+}
+
+func TestSSHTunnelReconnect(t *testing.T) {
+	a := require.New(t)
+	addr, hostKey := startTestSSHServer(t, nil)
+	sshHost, sshPort, err := net.SplitHostPort(addr)
+	a.NoError(err)
+	targetHost, targetPort := startTestEchoServer(t)
+
+	tunnel, err := newSSHTunnel(SSHConfig{Host: sshHost, Port: sshPort, User: testSSHUser, Password: testSSHPassword, HostKey: hostKey}, net.JoinHostPort(targetHost, targetPort), sshTunnelIdleTimeout)
+	a.NoError(err)
+	defer tunnel.Close()
+	requireEcho(t, "127.0.0.1", tunnel.localPort())
+
+	// Break the SSH connection, the tunnel should reconnect on the next connection.
+	client, err := tunnel.getClient(nil)
+	a.NoError(err)
+	a.NoError(client.Close())
+	requireEcho(t, "127.0.0.1", tunnel.localPort())
+}
+
+func TestGetTunnelHostPort(t *testing.T) {
+	a := require.New(t)
+	privateKey, publicKey := generateTestPrivateKey(t)
+	addr, hostKey := startTestSSHServer(t, publicKey)
+	sshHost, sshPort, err := net.SplitHostPort(addr)
+	a.NoError(err)
+	targetHost, targetPort := startTestEchoServer(t)
+
+	d := New("", "", "", "", "secret", nil)
+	config := SSHConfig{Host: sshHost, Port: sshPort, User: testSSHUser, Password: testSSHPassword, HostKey: hostKey}
+	host, port, err := d.GetTunnelHostPort(config, targetHost, targetPort)
+	a.NoError(err)
+	requireEcho(t, host, port)
+
+	// The tunnel is reused for the same SSH settings.
+	_, samePort, err := d.GetTunnelHostPort(config, targetHost, targetPort)
+	a.NoError(err)
+	a.Equal(port, samePort)
+
+	// The tunnel is replaced after the credentials change.
+	config.Password = ""
+	config.PrivateKey = privateKey
+	host, newPort, err := d.GetTunnelHostPort(config, targetHost, targetPort)
+	a.NoError(err)
+	a.NotEqual(port, newPort)
+	requireEcho(t, host, newPort)
+	a.Len(d.tunnels, 1)
+
+	// The closed tunnels are dropped from the map.
+	for _, entry := range d.tunnels {
+		entry.tunnel.Close()
+	}
+	config.Host = "localhost"
+	_, _, err = d.GetTunnelHostPort(config, targetHost, targetPort)
+	a.NoError(err)
+	a.Len(d.tunnels, 1)
+}
+
+func TestSSHTunnelIdle(t *testing.T) {
+	a := require.New(t)
+	addr, hostKey := startTestSSHServer(t, nil)
+	sshHost, sshPort, err := net.SplitHostPort(addr)
+	a.NoError(err)
+	targetHost, targetPort := startTestEchoServer(t)
+
+	idleTimeout := 100 * time.Millisecond
+	tunnel, err := newSSHTunnel(SSHConfig{Host: sshHost, Port: sshPort, User: testSSHUser, Password: testSSHPassword, HostKey: hostKey}, net.JoinHostPort(targetHost, targetPort), idleTimeout)
+	a.NoError(err)
+	defer tunnel.Close()
+
+	// The tunnel is kept open while there is a forwarded connection.
+	conn, err := net.Dial("tcp", net.JoinHostPort("127.0.0.1", tunnel.localPort()))
+	a.NoError(err)
+	time.Sleep(3 * idleTimeout)
+	a.False(tunnel.isClosed())
+	a.True(tunnel.touch())
+
+	// The tunnel is closed after the connection has been closed for the idle timeout.
+	a.NoError(conn.Close())
+	a.Eventually(tunnel.isClosed, 5*time.Second, 10*time.Millisecond)
+	a.False(tunnel.touch())
+}
+
+func knownhostsLine(t *testing.T, addr, hostKey string) string {
+	key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(hostKey))
+	require.NoError(t, err)
+	return knownhosts.Line([]string{addr}, key)
+}
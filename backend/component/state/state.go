@@ -18,8 +18,6 @@ type State struct {
 	// RollbackGenerateMap is the set of tasks for generating rollback statements.
 	RollbackGenerateMap sync.Map // map[task.ID]*store.TaskMessage
 
-	// TaskProgress is the map from task ID to task progress.
-	TaskProgress sync.Map // map[taskID]api.Progress
 	// GhostTaskState is the map from task ID to gh-ost state.
 	GhostTaskState sync.Map // map[taskID]sharedGhostState
 
@@ -29,6 +27,10 @@ type State struct {
 	RunningTaskChecks sync.Map // map[taskCheckID]bool
 	// RunningTasks is the set of running tasks.
 	RunningTasks sync.Map // map[taskID]bool
+	// RolloutPausedStages is the set of stages whose rollout is paused by the failed tasks.
+	RolloutPausedStages sync.Map // map[stageID]bool
+	// QueuedTasks is the map from task ID to the reason why the pending task waits for the concurrency limits.
+	QueuedTasks sync.Map // map[taskID]string
 	// RunningTasksCancel is the cancel's of running tasks.
 	RunningTasksCancel sync.Map // map[taskID]context.CancelFunc
 	// InstanceOutstandingConnections is the maximum number of connections per instance.
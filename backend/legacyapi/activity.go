@@ -31,6 +31,8 @@ const (
 	ActivityPipelineTaskStatementUpdate ActivityType = "bb.pipeline.task.statement.update"
 	// ActivityPipelineTaskEarliestAllowedTimeUpdate is the type for updating pipeline task the earliest allowed time.
 	ActivityPipelineTaskEarliestAllowedTimeUpdate ActivityType = "bb.pipeline.task.general.earliest-allowed-time.update"
+	// ActivityPipelineTaskVerificationFail is the type for failing the post-deployment verification of pipeline tasks.
+	ActivityPipelineTaskVerificationFail ActivityType = "bb.pipeline.task.verification.fail"
 
 	// Member related.
 
@@ -65,6 +67,13 @@ const (
 
 	// ActivityDatabaseRecoveryPITRDone is the type for performing PITR on the database successfully.
 	ActivityDatabaseRecoveryPITRDone ActivityType = "bb.database.recovery.pitr.done"
+
+	// Anomaly related.
+
+	// ActivityAnomalyCreate is the type for detecting a new anomaly.
+	ActivityAnomalyCreate ActivityType = "bb.anomaly.create"
+	// ActivityAnomalyResolve is the type for resolving an anomaly.
+	ActivityAnomalyResolve ActivityType = "bb.anomaly.resolve"
 )
 
 // ActivityLevel is the level of activities.
@@ -164,6 +173,19 @@ type ActivityPipelineTaskEarliestAllowedTimeUpdatePayload struct {
 	TaskName  string `json:"taskName"`
 }
 
+// ActivityPipelineTaskVerificationFailPayload is the API message payloads for failing the post-deployment verification of pipeline tasks.
+type ActivityPipelineTaskVerificationFailPayload struct {
+	TaskID         int    `json:"taskId"`
+	Statement      string `json:"statement,omitempty"`
+	ExpectedResult string `json:"expectedResult,omitempty"`
+	// ActualResult is empty if the verification statement fails to run.
+	ActualResult string `json:"actualResult,omitempty"`
+	Error        string `json:"error,omitempty"`
+	// Used by inbox to display info without paying the join cost
+	IssueName string `json:"issueName"`
+	TaskName  string `json:"taskName"`
+}
+
 // ActivityMemberCreatePayload is the API message payloads for creating members.
 type ActivityMemberCreatePayload struct {
 	PrincipalID    int          `json:"principalId"`
@@ -218,6 +240,22 @@ type ActivitySQLEditorQueryPayload struct {
 	DatabaseName           string           `json:"databaseName"`
 	Error                  string           `json:"error"`
 	AdviceList             []advisor.Advice `json:"adviceList"`
+	// AccessGrantID and AccessRequestIssueID link the query to the database access request it's allowed by.
+	AccessGrantID        int `json:"accessGrantId,omitempty"`
+	AccessRequestIssueID int `json:"accessRequestIssueId,omitempty"`
+}
+
+// ActivityAnomalyCreateResolvePayload is the API message payloads for creating or resolving anomalies.
+type ActivityAnomalyCreateResolvePayload struct {
+	AnomalyID   int             `json:"anomalyId"`
+	AnomalyType AnomalyType     `json:"anomalyType"`
+	Severity    AnomalySeverity `json:"severity"`
+	// Used by activity table to display info without paying the join cost
+	InstanceID   int    `json:"instanceId"`
+	InstanceName string `json:"instanceName"`
+	// DatabaseID/DatabaseName only exist for database anomalies.
+	DatabaseID   int    `json:"databaseId,omitempty"`
+	DatabaseName string `json:"databaseName,omitempty"`
 }
 
 // Activity is the API message for an activity.
@@ -2,6 +2,8 @@ package api
 
 import (
 	"encoding/json"
+
+	"github.com/bytebase/bytebase/backend/plugin/parser/differ"
 )
 
 // AnomalyType is the type of a task.
@@ -86,6 +88,34 @@ type AnomalyDatabaseSchemaDriftPayload struct {
 	Actual string `json:"actual,omitempty"`
 }
 
+// SchemaDriftReport is the API message for a database schema drift report.
+type SchemaDriftReport struct {
+	AnomalyID int `json:"anomalyId"`
+	// The schema version corresponds to the expected schema
+	Version string `json:"version"`
+	// ChangeList is the list of objects added, removed or changed out of band.
+	ChangeList []*differ.ObjectChange `json:"changeList"`
+	// DriftStatement is the DDL that turns the expected schema into the actual schema.
+	DriftStatement string `json:"driftStatement"`
+	// RevertStatement is the DDL that reverts the actual schema to the expected schema.
+	RevertStatement string `json:"revertStatement"`
+}
+
+// SchemaDriftRemediationAction is the action to remediate a database schema drift.
+type SchemaDriftRemediationAction string
+
+const (
+	// SchemaDriftRemediationBaseline accepts the drift by establishing a new baseline with the actual schema.
+	SchemaDriftRemediationBaseline SchemaDriftRemediationAction = "BASELINE"
+	// SchemaDriftRemediationRevert reverts the drift by applying the reverse DDL.
+	SchemaDriftRemediationRevert SchemaDriftRemediationAction = "REVERT"
+)
+
+// SchemaDriftRemediationCreate is the API message for creating an issue to remediate a database schema drift.
+type SchemaDriftRemediationCreate struct {
+	Action SchemaDriftRemediationAction `jsonapi:"attr,action"`
+}
+
 // Anomaly is the API message for an anomaly.
 type Anomaly struct {
 	ID int `jsonapi:"primary,anomaly"`
@@ -129,6 +159,7 @@ type AnomalyUpsert struct {
 // AnomalyFind is the API message for finding anomalies.
 type AnomalyFind struct {
 	// Standard fields
+	ID        *int
 	RowStatus *RowStatus
 
 	// Related fields
@@ -137,6 +168,8 @@ type AnomalyFind struct {
 	Type       *AnomalyType
 	// Only applicable if InstanceID is specified, if true, then we only return instance anomaly (database_id is NULL)
 	InstanceOnly bool
+	// ProjectMemberID is the principal ID, if specified, then we only return the database anomalies in the projects the principal is a member of.
+	ProjectMemberID *int
 }
 
 func (find *AnomalyFind) String() string {
@@ -0,0 +1,33 @@
+package api
+
+// APITokenScope is the scope of a personal access token or a service account key.
+type APITokenScope string
+
+const (
+	// APITokenScopeRead allows the token to call the read-only APIs.
+	APITokenScopeRead APITokenScope = "read"
+	// APITokenScopeIssueCreate allows the token to create issues.
+	APITokenScopeIssueCreate APITokenScope = "issue:create"
+	// APITokenScopeSQLQuery allows the token to run read-only queries against the databases.
+	APITokenScopeSQLQuery APITokenScope = "sql:query"
+	// APITokenScopeSCIM allows the token to provision the users and groups by the SCIM API.
+	APITokenScopeSCIM APITokenScope = "scim"
+)
+
+// APITokenScopeList is the list of the scopes which can be granted to the tokens.
+var APITokenScopeList = []APITokenScope{
+	APITokenScopeRead,
+	APITokenScopeIssueCreate,
+	APITokenScopeSQLQuery,
+	APITokenScopeSCIM,
+}
+
+// IsValidAPITokenScope returns whether the scope is a known token scope.
+func IsValidAPITokenScope(scope string) bool {
+	for _, s := range APITokenScopeList {
+		if string(s) == scope {
+			return true
+		}
+	}
+	return false
+}
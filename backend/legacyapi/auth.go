@@ -29,6 +29,9 @@ type Login struct {
 	// Domain specific fields
 	Email    string `jsonapi:"attr,email"`
 	Password string `jsonapi:"attr,password"`
+	// OTPCode or RecoveryCode is required for the users with MFA enabled.
+	OTPCode      string `jsonapi:"attr,otpCode"`
+	RecoveryCode string `jsonapi:"attr,recoveryCode"`
 }
 
 // SignUp is the API message for sign-ups.
@@ -41,6 +41,21 @@ type DataSourceOptions struct {
 	SRV bool `json:"srv" jsonapi:"attr,srv"`
 	// AuthenticationDatabase is used for MongoDB only.
 	AuthenticationDatabase string `json:"authenticationDatabase" jsonapi:"attr,authenticationDatabase"`
+	// SSHHost, SSHPort and SSHUser are the settings of the SSH tunnel through a bastion host.
+	// The connection is tunneled only if SSHHost is set.
+	SSHHost string `json:"sshHost,omitempty" jsonapi:"attr,sshHost"`
+	SSHPort string `json:"sshPort,omitempty" jsonapi:"attr,sshPort"`
+	SSHUser string `json:"sshUser,omitempty" jsonapi:"attr,sshUser"`
+	// SSHHostKey is the public key of the bastion host in the authorized_keys or known_hosts format.
+	SSHHostKey string `json:"sshHostKey,omitempty" jsonapi:"attr,sshHostKey"`
+	// SSHObfuscatedPassword and SSHObfuscatedPrivateKey are the obfuscated SSH credentials.
+	// They are only saved in the database and never returned to the client.
+	SSHObfuscatedPassword   string `json:"sshObfuscatedPassword,omitempty"`
+	SSHObfuscatedPrivateKey string `json:"sshObfuscatedPrivateKey,omitempty"`
+	// PasswordSecretRef and SslKeySecretRef are the references to the credentials in the external secret provider,
+	// e.g. vault://secret/data/mysql#password. The credentials are resolved when connecting to the database instead of being stored.
+	PasswordSecretRef string `json:"passwordSecretRef,omitempty" jsonapi:"attr,passwordSecretRef"`
+	SslKeySecretRef   string `json:"sslKeySecretRef,omitempty" jsonapi:"attr,sslKeySecretRef"`
 }
 
 // getDefaultDataSourceOptions returns the default data source options.
@@ -96,6 +111,16 @@ type DataSourceCreate struct {
 	Port     string            `jsonapi:"attr,port"`
 	Options  DataSourceOptions `jsonapi:"attr,options"`
 	Database string            `jsonapi:"attr,database"`
+	// SSH tunnel settings, the credentials are either the password or the private key.
+	SSHHost       string `jsonapi:"attr,sshHost"`
+	SSHPort       string `jsonapi:"attr,sshPort"`
+	SSHUser       string `jsonapi:"attr,sshUser"`
+	SSHPassword   string `jsonapi:"attr,sshPassword"`
+	SSHPrivateKey string `jsonapi:"attr,sshPrivateKey"`
+	SSHHostKey    string `jsonapi:"attr,sshHostKey"`
+	// References to the credentials in the external secret provider, which take precedence over the password and the SSL key.
+	PasswordSecretRef string `jsonapi:"attr,passwordSecretRef"`
+	SslKeySecretRef   string `jsonapi:"attr,sslKeySecretRef"`
 }
 
 // DataSourcePatch is the API message for data source.
@@ -110,4 +135,13 @@ type DataSourcePatch struct {
 	Port             *string            `jsonapi:"attr,port"`
 	Options          *DataSourceOptions `jsonapi:"attr,options"`
 	Database         *string            `jsonapi:"attr,database"`
+	SSHHost          *string            `jsonapi:"attr,sshHost"`
+	SSHPort          *string            `jsonapi:"attr,sshPort"`
+	SSHUser          *string            `jsonapi:"attr,sshUser"`
+	SSHPassword      *string            `jsonapi:"attr,sshPassword"`
+	SSHPrivateKey    *string            `jsonapi:"attr,sshPrivateKey"`
+	SSHHostKey       *string            `jsonapi:"attr,sshHostKey"`
+	// Set the secret references to empty to use the stored password and SSL key again.
+	PasswordSecretRef *string `jsonapi:"attr,passwordSecretRef"`
+	SslKeySecretRef   *string `jsonapi:"attr,sslKeySecretRef"`
 }
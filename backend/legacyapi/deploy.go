@@ -2,6 +2,7 @@ package api
 
 import (
 	"encoding/json"
+	"math"
 
 	"github.com/bytebase/bytebase/backend/common"
 )
@@ -23,6 +24,26 @@ type DeploymentConfig struct {
 // DeploymentSchedule is the API message for deployment schedule.
 type DeploymentSchedule struct {
 	Deployments []*Deployment `json:"deployments"`
+	// Concurrency limits the tasks running at the same time in the pipelines of the project.
+	Concurrency *DeploymentConcurrency `json:"concurrency,omitempty"`
+}
+
+// DeploymentConcurrency is the API message for the concurrency limits of the running tasks in a project.
+// The tasks over the limits are queued in the PENDING status. 0 means no limit.
+// The limit of the running tasks in the whole workspace is the SettingWorkspaceMaxRunningTasks setting.
+type DeploymentConcurrency struct {
+	// MaxRunningTasksPerStage is the maximum number of running tasks in a stage.
+	MaxRunningTasksPerStage int `json:"maxRunningTasksPerStage,omitempty"`
+	// MaxRunningTasksPerInstance is the maximum number of running tasks of the project on an instance.
+	MaxRunningTasksPerInstance int `json:"maxRunningTasksPerInstance,omitempty"`
+}
+
+// Validate validates the deployment concurrency.
+func (c *DeploymentConcurrency) Validate() error {
+	if c.MaxRunningTasksPerStage < 0 || c.MaxRunningTasksPerInstance < 0 {
+		return common.Errorf(common.Invalid, "concurrency limits must not be negative")
+	}
+	return nil
 }
 
 // Deployment is the API message for deployment.
@@ -34,6 +55,78 @@ type Deployment struct {
 // DeploymentSpec is the API message for deployment specification.
 type DeploymentSpec struct {
 	Selector *LabelSelector `json:"selector"`
+	// Rollout is the gradual rollout of the deployment, the deployment deploys to all matched databases at once if it's unset.
+	Rollout *DeploymentRollout `json:"rollout,omitempty"`
+	// Gate is the promotion gate of the deployment, it holds the deployment until the previous deployment meets the conditions.
+	Gate *DeploymentGate `json:"gate,omitempty"`
+}
+
+// DeploymentRollout is the API message for the gradual rollout of a deployment.
+// It's usually used by a canary deployment followed by a deployment with the same selector, which takes the remaining databases.
+type DeploymentRollout struct {
+	// Count limits the deployment to the first count databases matched by the selector in the order of database ID.
+	// The databases beyond the limit are left to the following deployments. 0 means no limit.
+	Count int `json:"count,omitempty"`
+	// Percent limits the deployment to the first percent of the databases matched by the selector, rounded up. 0 means no limit.
+	Percent int `json:"percent,omitempty"`
+	// PauseFailurePercent pauses the rollout of the stage once its failed tasks reach the percent of its tasks.
+	// The paused stage doesn't start new tasks until the failed tasks are retried or skipped. 0 means the rollout never pauses.
+	PauseFailurePercent int `json:"pauseFailurePercent,omitempty"`
+	// BakeMinutes is the time the stage must stay done before the following stage starts.
+	BakeMinutes int `json:"bakeMinutes,omitempty"`
+}
+
+// Validate validates the deployment rollout.
+func (r *DeploymentRollout) Validate() error {
+	if r.Count < 0 {
+		return common.Errorf(common.Invalid, "rollout count must not be negative")
+	}
+	if r.Percent < 0 || r.Percent > 100 {
+		return common.Errorf(common.Invalid, "rollout percent must be between 0 and 100")
+	}
+	if r.Count > 0 && r.Percent > 0 {
+		return common.Errorf(common.Invalid, "rollout count and percent cannot be set at the same time")
+	}
+	if r.PauseFailurePercent < 0 || r.PauseFailurePercent > 100 {
+		return common.Errorf(common.Invalid, "rollout pause failure percent must be between 0 and 100")
+	}
+	if r.BakeMinutes < 0 {
+		return common.Errorf(common.Invalid, "rollout bake minutes must not be negative")
+	}
+	return nil
+}
+
+// DeploymentGate is the API message for the promotion gate of a deployment.
+// The conditions are evaluated against the previous stage of the pipeline, and the stage doesn't start until all conditions pass.
+type DeploymentGate struct {
+	// SoakMinutes is the minimum time the previous stage must stay done.
+	SoakMinutes int `json:"soakMinutes,omitempty"`
+	// NoOpenAnomaly requires no open database connection or schema drift anomalies on the databases of the previous stage.
+	NoOpenAnomaly bool `json:"noOpenAnomaly,omitempty"`
+	// VerificationSuccess requires the post-deployment verification of the tasks in the previous stage to succeed.
+	// The tasks without verification are ignored.
+	VerificationSuccess bool `json:"verificationSuccess,omitempty"`
+}
+
+// Validate validates the deployment gate.
+func (g *DeploymentGate) Validate() error {
+	if g.SoakMinutes < 0 {
+		return common.Errorf(common.Invalid, "gate soak minutes must not be negative")
+	}
+	return nil
+}
+
+// GetDatabaseLimit returns the number of databases the deployment deploys to out of the matched databases.
+func (r *DeploymentRollout) GetDatabaseLimit(matched int) int {
+	if r.Count > 0 && r.Count < matched {
+		return r.Count
+	}
+	if r.Percent > 0 {
+		if limit := int(math.Ceil(float64(matched) * float64(r.Percent) / 100)); limit < matched {
+			return limit
+		}
+	}
+	return matched
 }
 
 // LabelSelector is the API message for label selector.
@@ -43,15 +136,18 @@ type LabelSelector struct {
 }
 
 // OperatorType is the type of label selector requirement operator.
-// Valid operators are In, Exists.
-// Note: NotIn and DoesNotExist are not supported initially.
+// Valid operators are In, NotIn, Exists and DoesNotExist.
 type OperatorType string
 
 const (
 	// InOperatorType is the operator type for In.
 	InOperatorType OperatorType = "In"
+	// NotInOperatorType is the operator type for NotIn.
+	NotInOperatorType OperatorType = "NotIn"
 	// ExistsOperatorType is the operator type for Exists.
 	ExistsOperatorType OperatorType = "Exists"
+	// DoesNotExistOperatorType is the operator type for DoesNotExist.
+	DoesNotExistOperatorType OperatorType = "DoesNotExist"
 )
 
 // LabelSelectorRequirement is the API message for label selector.
@@ -99,6 +195,11 @@ func ValidateAndGetDeploymentSchedule(payload string) (*DeploymentSchedule, erro
 		return nil, err
 	}
 
+	if schedule.Concurrency != nil {
+		if err := schedule.Concurrency.Validate(); err != nil {
+			return nil, err
+		}
+	}
 	for _, d := range schedule.Deployments {
 		if d.Name == "" {
 			return nil, common.Errorf(common.Invalid, "Deployment name must not be empty")
@@ -106,11 +207,11 @@ func ValidateAndGetDeploymentSchedule(payload string) (*DeploymentSchedule, erro
 		hasEnv := false
 		for _, e := range d.Spec.Selector.MatchExpressions {
 			switch e.Operator {
-			case InOperatorType:
+			case InOperatorType, NotInOperatorType:
 				if len(e.Values) == 0 {
 					return nil, common.Errorf(common.Invalid, "expression key %q with %q operator should have at least one value", e.Key, e.Operator)
 				}
-			case ExistsOperatorType:
+			case ExistsOperatorType, DoesNotExistOperatorType:
 				if len(e.Values) > 0 {
 					return nil, common.Errorf(common.Invalid, "expression key %q with %q operator shouldn't have values", e.Key, e.Operator)
 				}
@@ -127,6 +228,16 @@ func ValidateAndGetDeploymentSchedule(payload string) (*DeploymentSchedule, erro
 		if !hasEnv {
 			return nil, common.Errorf(common.Invalid, "deployment should contain %q label", EnvironmentLabelKey)
 		}
+		if d.Spec.Rollout != nil {
+			if err := d.Spec.Rollout.Validate(); err != nil {
+				return nil, err
+			}
+		}
+		if d.Spec.Gate != nil {
+			if err := d.Spec.Gate.Validate(); err != nil {
+				return nil, err
+			}
+		}
 	}
 	return schedule, nil
 }
@@ -97,6 +97,128 @@ func TestGetDeploymentSchedule(t *testing.T) {
 			`{"deployments":[{"name":"deployment1","spec":{"selector":{"matchExpressions":[{"key":"bb.environment","operator":"In","values":["prod", "dev"]},{"key":"location","operator":"In","values":["us-central1","europe-west1"]}]}}}]}`,
 			nil,
 			"should must use operator",
+		}, {
+			"canaryRollout",
+			`{"deployments":[{"name":"canary","spec":{"selector":{"matchExpressions":[{"key":"bb.environment","operator":"In","values":["prod"]},{"key":"bb.tenant","operator":"NotIn","values":["vip"]}]},"rollout":{"percent":5,"pauseFailurePercent":10,"bakeMinutes":60}}},{"name":"rest","spec":{"selector":{"matchExpressions":[{"key":"bb.environment","operator":"In","values":["prod"]},{"key":"bb.tenant","operator":"DoesNotExist"}]}}}]}`,
+			&DeploymentSchedule{
+				Deployments: []*Deployment{
+					{
+						Name: "canary",
+						Spec: &DeploymentSpec{
+							Selector: &LabelSelector{
+								MatchExpressions: []*LabelSelectorRequirement{
+									{
+										Key:      "bb.environment",
+										Operator: "In",
+										Values:   []string{"prod"},
+									}, {
+										Key:      "bb.tenant",
+										Operator: "NotIn",
+										Values:   []string{"vip"},
+									},
+								},
+							},
+							Rollout: &DeploymentRollout{
+								Percent:             5,
+								PauseFailurePercent: 10,
+								BakeMinutes:         60,
+							},
+						},
+					},
+					{
+						Name: "rest",
+						Spec: &DeploymentSpec{
+							Selector: &LabelSelector{
+								MatchExpressions: []*LabelSelectorRequirement{
+									{
+										Key:      "bb.environment",
+										Operator: "In",
+										Values:   []string{"prod"},
+									}, {
+										Key:      "bb.tenant",
+										Operator: "DoesNotExist",
+										Values:   nil,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"",
+		}, {
+			"notInOperatorWithNoValue",
+			`{"deployments":[{"name":"deployment1","spec":{"selector":{"matchExpressions":[{"key":"bb.environment","operator":"In","values":["prod"]},{"key":"location","operator":"NotIn"}]}}}]}`,
+			nil,
+			"operator should have at least one value",
+		}, {
+			"rolloutCountAndPercent",
+			`{"deployments":[{"name":"deployment1","spec":{"selector":{"matchExpressions":[{"key":"bb.environment","operator":"In","values":["prod"]}]},"rollout":{"count":10,"percent":5}}}]}`,
+			nil,
+			"cannot be set at the same time",
+		}, {
+			"concurrency",
+			`{"deployments":[{"name":"deployment1","spec":{"selector":{"matchExpressions":[{"key":"bb.environment","operator":"In","values":["prod"]}]}}}],"concurrency":{"maxRunningTasksPerStage":50,"maxRunningTasksPerInstance":5}}`,
+			&DeploymentSchedule{
+				Deployments: []*Deployment{
+					{
+						Name: "deployment1",
+						Spec: &DeploymentSpec{
+							Selector: &LabelSelector{
+								MatchExpressions: []*LabelSelectorRequirement{
+									{
+										Key:      "bb.environment",
+										Operator: "In",
+										Values:   []string{"prod"},
+									},
+								},
+							},
+						},
+					},
+				},
+				Concurrency: &DeploymentConcurrency{
+					MaxRunningTasksPerStage:    50,
+					MaxRunningTasksPerInstance: 5,
+				},
+			},
+			"",
+		}, {
+			"gate",
+			`{"deployments":[{"name":"prod","spec":{"selector":{"matchExpressions":[{"key":"bb.environment","operator":"In","values":["prod"]}]},"gate":{"soakMinutes":1440,"noOpenAnomaly":true,"verificationSuccess":true}}}]}`,
+			&DeploymentSchedule{
+				Deployments: []*Deployment{
+					{
+						Name: "prod",
+						Spec: &DeploymentSpec{
+							Selector: &LabelSelector{
+								MatchExpressions: []*LabelSelectorRequirement{
+									{
+										Key:      "bb.environment",
+										Operator: "In",
+										Values:   []string{"prod"},
+									},
+								},
+							},
+							Gate: &DeploymentGate{
+								SoakMinutes:         1440,
+								NoOpenAnomaly:       true,
+								VerificationSuccess: true,
+							},
+						},
+					},
+				},
+			},
+			"",
+		}, {
+			"negativeGateSoakMinutes",
+			`{"deployments":[{"name":"prod","spec":{"selector":{"matchExpressions":[{"key":"bb.environment","operator":"In","values":["prod"]}]},"gate":{"soakMinutes":-1}}}]}`,
+			nil,
+			"must not be negative",
+		}, {
+			"negativeConcurrency",
+			`{"deployments":[],"concurrency":{"maxRunningTasksPerStage":-1}}`,
+			nil,
+			"must not be negative",
 		},
 	}
 
@@ -110,3 +232,22 @@ func TestGetDeploymentSchedule(t *testing.T) {
 		require.Equal(t, cfg, test.wantCfg)
 	}
 }
+
+func TestDeploymentRolloutGetDatabaseLimit(t *testing.T) {
+	tests := []struct {
+		rollout *DeploymentRollout
+		matched int
+		want    int
+	}{
+		{&DeploymentRollout{}, 2000, 2000},
+		{&DeploymentRollout{Count: 10}, 2000, 10},
+		{&DeploymentRollout{Count: 10}, 3, 3},
+		{&DeploymentRollout{Percent: 5}, 2000, 100},
+		{&DeploymentRollout{Percent: 5}, 30, 2},
+		{&DeploymentRollout{Percent: 5}, 0, 0},
+		{&DeploymentRollout{Percent: 100}, 7, 7},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.want, test.rollout.GetDatabaseLimit(test.matched), "%+v of %d", test.rollout, test.matched)
+	}
+}
@@ -53,6 +53,18 @@ type InstanceCreate struct {
 	SRV bool `jsonapi:"attr,srv"`
 	// AuthenticationDatabase is used for MongoDB only.
 	AuthenticationDatabase string `jsonapi:"attr,authenticationDatabase"`
+
+	// SSH tunnel settings, the credentials are either the password or the private key.
+	SSHHost       string `jsonapi:"attr,sshHost"`
+	SSHPort       string `jsonapi:"attr,sshPort"`
+	SSHUser       string `jsonapi:"attr,sshUser"`
+	SSHPassword   string `jsonapi:"attr,sshPassword"`
+	SSHPrivateKey string `jsonapi:"attr,sshPrivateKey"`
+	SSHHostKey    string `jsonapi:"attr,sshHostKey"`
+
+	// Secret references of the admin data source, e.g. vault://secret/data/mysql#password.
+	PasswordSecretRef string `jsonapi:"attr,passwordSecretRef"`
+	SslKeySecretRef   string `jsonapi:"attr,sslKeySecretRef"`
 }
 
 // InstanceFind is the API message for finding instances.
@@ -35,6 +35,8 @@ const (
 	IssueDatabaseRestorePITR IssueType = "bb.issue.database.restore.pitr"
 	// IssueDatabaseRollback is the issue type for a generated rollback issue.
 	IssueDatabaseRollback IssueType = "bb.issue.database.rollback"
+	// IssueDatabaseAccessRequest is the issue type for requesting temporary query access to a database.
+	IssueDatabaseAccessRequest IssueType = "bb.issue.database.access.request"
 )
 
 // IssueFieldID is the field ID for an issue.
@@ -153,6 +155,13 @@ type MigrationDetail struct {
 	// SchemaVersion is parsed from VCS file name.
 	// It is automatically generated in the UI workflow.
 	SchemaVersion string `json:"schemaVersion"`
+	// ChunkConfig enables the chunked execution of the data update on MySQL and PostgreSQL.
+	ChunkConfig *DataUpdateChunkConfig `json:"chunkConfig"`
+	// Verification is the post-deployment verification of the schema or data update.
+	// It overrides the verification of the migration context.
+	Verification *TaskVerification `json:"verification"`
+	// ResumableExecution commits the statements applied before the failed statement, so that the failed schema or data update can be resumed from the failed statement.
+	ResumableExecution bool `json:"resumableExecution"`
 }
 
 // MigrationContext is the issue create context for database migration such as Migrate, Data.
@@ -162,6 +171,8 @@ type MigrationContext struct {
 	DetailList []*MigrationDetail `json:"detailList"`
 	// VCSPushEvent is the event information for VCS push.
 	VCSPushEvent *vcs.PushEvent `json:"vcsPushEvent"`
+	// Verification is the post-deployment verification of all schema and data updates in the issue.
+	Verification *TaskVerification `json:"verification"`
 }
 
 // MigrationFileYAMLDatabase contains the information of a database in a YAML
@@ -202,6 +213,18 @@ type RollbackContext struct {
 	TaskIDList []int `json:"taskIdList"`
 }
 
+// AccessRequestContext is the issue create context for requesting temporary query access to a database.
+type AccessRequestContext struct {
+	// DatabaseID is the ID of the database to query.
+	DatabaseID int `json:"databaseId"`
+	// TableList is the list of the tables the access is limited to, all tables are accessible if it's empty.
+	TableList []string `json:"tableList"`
+	// DurationSeconds is how long the access lasts after the request is approved.
+	DurationSeconds int64 `json:"durationSeconds"`
+	// Reason is why the access is needed.
+	Reason string `json:"reason"`
+}
+
 // IssuePatch is the API message for patching an issue.
 type IssuePatch struct {
 	Name                  *string `jsonapi:"attr,name"`
@@ -0,0 +1,179 @@
+package api
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/common"
+)
+
+// maintenanceWindowSearchLimit bounds the iterations looking for the next eligible time,
+// as each iteration skips either a blackout period or a gap between the windows.
+const maintenanceWindowSearchLimit = 1000
+
+// MaintenanceWindowPolicy is the policy configuration for the maintenance windows of an environment.
+// The tasks are only allowed to start in the maintenance windows and outside the blackout periods.
+// If there is no window applicable to a database, the tasks on it can start at any time except the blackout periods.
+type MaintenanceWindowPolicy struct {
+	// TimeZone is the IANA time zone name of the window schedules, e.g. "America/Los_Angeles". It's UTC if empty.
+	TimeZone     string                `json:"timeZone"`
+	WindowList   []MaintenanceWindow   `json:"windowList"`
+	BlackoutList []MaintenanceBlackout `json:"blackoutList"`
+}
+
+// MaintenanceWindow is a recurring maintenance window.
+type MaintenanceWindow struct {
+	// Schedule is the standard 5-field cron expression of the window start, e.g. "0 22 * * 1-5".
+	Schedule        string `json:"schedule"`
+	DurationMinutes int    `json:"durationMinutes"`
+	// DatabaseLabels limits the window to the databases having all the labels. The window applies to all databases if it's empty.
+	DatabaseLabels map[string]string `json:"databaseLabels"`
+}
+
+// MaintenanceBlackout is a period when no task can start, e.g. a holiday freeze.
+type MaintenanceBlackout struct {
+	Title   string `json:"title"`
+	StartTs int64  `json:"startTs"`
+	EndTs   int64  `json:"endTs"`
+	// DatabaseLabels limits the blackout to the databases having all the labels. The blackout applies to all databases if it's empty.
+	DatabaseLabels map[string]string `json:"databaseLabels"`
+}
+
+// UnmarshalMaintenanceWindowPolicy will unmarshal payload to maintenance window policy.
+func UnmarshalMaintenanceWindowPolicy(payload string) (*MaintenanceWindowPolicy, error) {
+	var p MaintenanceWindowPolicy
+	if err := json.Unmarshal([]byte(payload), &p); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal maintenance window policy %q", payload)
+	}
+	return &p, nil
+}
+
+func (p *MaintenanceWindowPolicy) String() (string, error) {
+	s, err := json.Marshal(p)
+	if err != nil {
+		return "", err
+	}
+	return string(s), nil
+}
+
+// Validate validates the time zone, the window schedules and the blackout periods.
+func (p *MaintenanceWindowPolicy) Validate() error {
+	if _, err := time.LoadLocation(p.TimeZone); err != nil {
+		return errors.Wrapf(err, "invalid maintenance window time zone %q", p.TimeZone)
+	}
+	for _, w := range p.WindowList {
+		if _, err := common.ParseCronSchedule(w.Schedule); err != nil {
+			return err
+		}
+		if w.DurationMinutes <= 0 {
+			return errors.Errorf("maintenance window %q must have a positive duration", w.Schedule)
+		}
+	}
+	for _, b := range p.BlackoutList {
+		if b.EndTs <= b.StartTs {
+			return errors.Errorf("blackout period %q must end after it starts", b.Title)
+		}
+	}
+	return nil
+}
+
+type activeMaintenanceWindow struct {
+	schedule *common.CronSchedule
+	duration time.Duration
+}
+
+// NextEligibleTime returns the start and the end of the first eligible period at or after now for the database with the labels.
+// The end is the zero time if the period is unbounded. It returns false if there is no eligible time, e.g. no window ever starts.
+func (p *MaintenanceWindowPolicy) NextEligibleTime(labels map[string]string, now time.Time) (time.Time, time.Time, bool, error) {
+	loc, err := time.LoadLocation(p.TimeZone)
+	if err != nil {
+		return time.Time{}, time.Time{}, false, errors.Wrapf(err, "invalid maintenance window time zone %q", p.TimeZone)
+	}
+	var windows []activeMaintenanceWindow
+	for _, w := range p.WindowList {
+		if !isMatchLabels(w.DatabaseLabels, labels) {
+			continue
+		}
+		schedule, err := common.ParseCronSchedule(w.Schedule)
+		if err != nil {
+			return time.Time{}, time.Time{}, false, err
+		}
+		windows = append(windows, activeMaintenanceWindow{schedule: schedule, duration: time.Duration(w.DurationMinutes) * time.Minute})
+	}
+	var blackouts []MaintenanceBlackout
+	for _, b := range p.BlackoutList {
+		if isMatchLabels(b.DatabaseLabels, labels) {
+			blackouts = append(blackouts, b)
+		}
+	}
+
+	t := now.In(loc)
+	for i := 0; i < maintenanceWindowSearchLimit; i++ {
+		if end, ok := inBlackout(blackouts, t); ok {
+			t = end.In(loc)
+			continue
+		}
+		end := nextBlackoutStart(blackouts, t)
+		if len(windows) == 0 {
+			return t, end, true, nil
+		}
+
+		var windowEnd, nextStart time.Time
+		for _, w := range windows {
+			// The window containing t starts in (t - duration, t].
+			if start := w.schedule.Next(t.Add(-w.duration)); !start.IsZero() && !start.After(t) {
+				if e := start.Add(w.duration); e.After(windowEnd) {
+					windowEnd = e
+				}
+			}
+			if start := w.schedule.Next(t); !start.IsZero() && (nextStart.IsZero() || start.Before(nextStart)) {
+				nextStart = start
+			}
+		}
+		if !windowEnd.IsZero() {
+			if end.IsZero() || windowEnd.Before(end) {
+				end = windowEnd
+			}
+			return t, end, true, nil
+		}
+		if nextStart.IsZero() {
+			return time.Time{}, time.Time{}, false, nil
+		}
+		t = nextStart
+	}
+	return time.Time{}, time.Time{}, false, nil
+}
+
+func inBlackout(blackouts []MaintenanceBlackout, t time.Time) (time.Time, bool) {
+	for _, b := range blackouts {
+		if t.Unix() >= b.StartTs && t.Unix() < b.EndTs {
+			return time.Unix(b.EndTs, 0), true
+		}
+	}
+	return time.Time{}, false
+}
+
+func nextBlackoutStart(blackouts []MaintenanceBlackout, t time.Time) time.Time {
+	var next time.Time
+	for _, b := range blackouts {
+		start := time.Unix(b.StartTs, 0)
+		if start.After(t) && (next.IsZero() || start.Before(next)) {
+			next = start
+		}
+	}
+	if next.IsZero() {
+		return next
+	}
+	return next.In(t.Location())
+}
+
+func isMatchLabels(selector, labels map[string]string) bool {
+	for k, v := range selector {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
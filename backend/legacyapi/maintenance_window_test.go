@@ -0,0 +1,102 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaintenanceWindowNextEligibleTime(t *testing.T) {
+	shanghai, err := time.LoadLocation("Asia/Shanghai")
+	require.NoError(t, err)
+	freezeStart := time.Date(2023, 3, 6, 23, 0, 0, 0, shanghai)
+	freezeEnd := time.Date(2023, 3, 8, 0, 0, 0, 0, shanghai)
+	policy := &MaintenanceWindowPolicy{
+		TimeZone: "Asia/Shanghai",
+		WindowList: []MaintenanceWindow{
+			{Schedule: "0 22 * * 1-5", DurationMinutes: 120, DatabaseLabels: map[string]string{"bb.location": "cn"}},
+		},
+		BlackoutList: []MaintenanceBlackout{
+			{Title: "Freeze", StartTs: freezeStart.Unix(), EndTs: freezeEnd.Unix()},
+		},
+	}
+	cn := map[string]string{"bb.location": "cn"}
+
+	tests := []struct {
+		name      string
+		labels    map[string]string
+		now       time.Time
+		wantStart time.Time
+		wantEnd   time.Time
+		wantFound bool
+	}{
+		{
+			name:      "in window",
+			labels:    cn,
+			now:       time.Date(2023, 3, 1, 23, 0, 0, 0, shanghai),
+			wantStart: time.Date(2023, 3, 1, 23, 0, 0, 0, shanghai),
+			wantEnd:   time.Date(2023, 3, 2, 0, 0, 0, 0, shanghai),
+			wantFound: true,
+		},
+		{
+			name:      "before window",
+			labels:    cn,
+			now:       time.Date(2023, 3, 1, 10, 0, 0, 0, shanghai),
+			wantStart: time.Date(2023, 3, 1, 22, 0, 0, 0, shanghai),
+			wantEnd:   time.Date(2023, 3, 2, 0, 0, 0, 0, shanghai),
+			wantFound: true,
+		},
+		{
+			name:      "skip weekend and window cut by blackout",
+			labels:    cn,
+			now:       time.Date(2023, 3, 4, 10, 0, 0, 0, shanghai),
+			wantStart: time.Date(2023, 3, 6, 22, 0, 0, 0, shanghai),
+			wantEnd:   freezeStart,
+			wantFound: true,
+		},
+		{
+			name:      "skip blackout",
+			labels:    cn,
+			now:       time.Date(2023, 3, 7, 10, 0, 0, 0, shanghai),
+			wantStart: time.Date(2023, 3, 8, 22, 0, 0, 0, shanghai),
+			wantEnd:   time.Date(2023, 3, 9, 0, 0, 0, 0, shanghai),
+			wantFound: true,
+		},
+		{
+			name:      "no window applicable",
+			labels:    map[string]string{"bb.location": "us"},
+			now:       time.Date(2023, 3, 1, 10, 0, 0, 0, shanghai),
+			wantStart: time.Date(2023, 3, 1, 10, 0, 0, 0, shanghai),
+			wantEnd:   freezeStart,
+			wantFound: true,
+		},
+		{
+			name:      "no window applicable in blackout",
+			labels:    nil,
+			now:       time.Date(2023, 3, 7, 10, 0, 0, 0, shanghai),
+			wantStart: freezeEnd,
+			wantEnd:   time.Time{},
+			wantFound: true,
+		},
+	}
+
+	for _, test := range tests {
+		start, end, found, err := policy.NextEligibleTime(test.labels, test.now)
+		require.NoError(t, err, test.name)
+		require.Equal(t, test.wantFound, found, test.name)
+		if !found {
+			continue
+		}
+		require.True(t, test.wantStart.Equal(start), "%s: start %v", test.name, start)
+		require.True(t, test.wantEnd.Equal(end), "%s: end %v", test.name, end)
+	}
+}
+
+func TestMaintenanceWindowPolicyValidate(t *testing.T) {
+	require.NoError(t, (&MaintenanceWindowPolicy{}).Validate())
+	require.Error(t, (&MaintenanceWindowPolicy{TimeZone: "Mars/Olympus"}).Validate())
+	require.Error(t, (&MaintenanceWindowPolicy{WindowList: []MaintenanceWindow{{Schedule: "0 22 * *", DurationMinutes: 60}}}).Validate())
+	require.Error(t, (&MaintenanceWindowPolicy{WindowList: []MaintenanceWindow{{Schedule: "0 22 * * *"}}}).Validate())
+	require.Error(t, (&MaintenanceWindowPolicy{BlackoutList: []MaintenanceBlackout{{Title: "Freeze", StartTs: 10, EndTs: 10}}}).Validate())
+}
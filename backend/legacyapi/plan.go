@@ -56,6 +56,7 @@ const (
 	//
 	// - Workspace level RBAC
 	// - Project level RBAC.
+	// - Custom roles made of fine-grained permissions.
 	FeatureRBAC FeatureType = "bb.feature.rbac"
 
 	// FeatureWatermark enables full-screen watermark.
@@ -42,6 +42,10 @@ const (
 	PolicyTypeSensitiveData PolicyType = "bb.policy.sensitive-data"
 	// PolicyTypeAccessControl is the access control policy type.
 	PolicyTypeAccessControl PolicyType = "bb.policy.access-control"
+	// PolicyTypeMaintenanceWindow is the maintenance window policy type.
+	PolicyTypeMaintenanceWindow PolicyType = "bb.policy.maintenance-window"
+	// PolicyTypeTaskRetry is the task retry policy type.
+	PolicyTypeTaskRetry PolicyType = "bb.policy.task-retry"
 
 	// PipelineApprovalValueManualNever means the pipeline will automatically be approved without user intervention.
 	PipelineApprovalValueManualNever PipelineApprovalValue = "MANUAL_APPROVAL_NEVER"
@@ -82,12 +86,14 @@ const (
 var (
 	// allowedResourceTypes includes allowed resource types for each policy type.
 	allowedResourceTypes = map[PolicyType][]PolicyResourceType{
-		PolicyTypePipelineApproval: {PolicyResourceTypeEnvironment},
-		PolicyTypeBackupPlan:       {PolicyResourceTypeEnvironment},
-		PolicyTypeSQLReview:        {PolicyResourceTypeEnvironment},
-		PolicyTypeEnvironmentTier:  {PolicyResourceTypeEnvironment},
-		PolicyTypeSensitiveData:    {PolicyResourceTypeDatabase},
-		PolicyTypeAccessControl:    {PolicyResourceTypeEnvironment, PolicyResourceTypeDatabase},
+		PolicyTypePipelineApproval:  {PolicyResourceTypeEnvironment},
+		PolicyTypeBackupPlan:        {PolicyResourceTypeEnvironment},
+		PolicyTypeSQLReview:         {PolicyResourceTypeEnvironment},
+		PolicyTypeEnvironmentTier:   {PolicyResourceTypeEnvironment},
+		PolicyTypeSensitiveData:     {PolicyResourceTypeDatabase},
+		PolicyTypeAccessControl:     {PolicyResourceTypeEnvironment, PolicyResourceTypeDatabase},
+		PolicyTypeMaintenanceWindow: {PolicyResourceTypeEnvironment},
+		PolicyTypeTaskRetry:         {PolicyResourceTypeEnvironment},
 	}
 )
 
@@ -387,6 +393,18 @@ func ValidatePolicy(resourceType PolicyResourceType, pType PolicyType, payload *
 			return err
 		}
 		return nil
+	case PolicyTypeMaintenanceWindow:
+		p, err := UnmarshalMaintenanceWindowPolicy(*payload)
+		if err != nil {
+			return err
+		}
+		return p.Validate()
+	case PolicyTypeTaskRetry:
+		p, err := UnmarshalTaskRetryPolicy(*payload)
+		if err != nil {
+			return err
+		}
+		return p.Validate()
 	}
 	return nil
 }
@@ -416,6 +434,12 @@ func GetDefaultPolicy(pType PolicyType) (string, error) {
 	case PolicyTypeSensitiveData:
 		policy := SensitiveDataPolicy{}
 		return policy.String()
+	case PolicyTypeMaintenanceWindow:
+		policy := MaintenanceWindowPolicy{}
+		return policy.String()
+	case PolicyTypeTaskRetry:
+		policy := TaskRetryPolicy{}
+		return policy.String()
 	}
 	return "", nil
 }
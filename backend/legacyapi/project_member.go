@@ -54,8 +54,31 @@ const (
 	ProjectPermissionCreateDatabase ProjectPermissionType = "bb.permission.project.create-database"
 	// ProjectPermissionTransferDatabase allows user to transfer database out of/into the project.
 	ProjectPermissionTransferDatabase ProjectPermissionType = "bb.permission.project.transfer-database"
+	// ProjectPermissionQueryDatabase allows user to run read-only queries against the databases in the project regardless of the access control policies.
+	ProjectPermissionQueryDatabase ProjectPermissionType = "bb.permission.project.query-database"
+	// ProjectPermissionApproveIssue allows user to approve the issues in the project.
+	ProjectPermissionApproveIssue ProjectPermissionType = "bb.permission.project.approve-issue"
+	// ProjectPermissionManageBackup allows user to take backups and update the backup settings of the databases in the project.
+	ProjectPermissionManageBackup ProjectPermissionType = "bb.permission.project.manage-backup"
 )
 
+// ProjectPermissionList is the list of the project permissions which can be granted by the custom roles.
+var ProjectPermissionList = []ProjectPermissionType{
+	ProjectPermissionManageGeneral,
+	ProjectPermissionManageMember,
+	ProjectPermissionCreateSheet,
+	ProjectPermissionAdminSheet,
+	ProjectPermissionOrganizeSheet,
+	ProjectPermissionSyncSheet,
+	ProjectPermissionChangeDatabase,
+	ProjectPermissionAdminDatabase,
+	ProjectPermissionCreateDatabase,
+	ProjectPermissionTransferDatabase,
+	ProjectPermissionQueryDatabase,
+	ProjectPermissionApproveIssue,
+	ProjectPermissionManageBackup,
+}
+
 // ProjectPermission returns whether a particular permission is granted to a particular project role in a particular plan.
 func ProjectPermission(permission ProjectPermissionType, plan PlanType, role common.ProjectRole) bool {
 	// a map from the a particular feature to the respective enablement of a project developer and owner.
@@ -72,6 +95,10 @@ func ProjectPermission(permission ProjectPermissionType, plan PlanType, role com
 		ProjectPermissionCreateDatabase: {!Feature(FeatureDBAWorkflow, plan), true},
 		// If dba-workflow is disabled, then project developer can also transfer database.
 		ProjectPermissionTransferDatabase: {!Feature(FeatureDBAWorkflow, plan), true},
+		// The built-in roles follow the access control policies and the approval policies instead.
+		ProjectPermissionQueryDatabase: {false, false},
+		ProjectPermissionApproveIssue:  {false, false},
+		ProjectPermissionManageBackup:  {true, true},
 	}
 
 	switch role {
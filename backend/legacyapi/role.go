@@ -0,0 +1,52 @@
+package api
+
+// WorkspacePermissionType is the type of a workspace permission.
+type WorkspacePermissionType string
+
+const (
+	// WorkspacePermissionManageEnvironment allows user to create, update and delete environments.
+	WorkspacePermissionManageEnvironment WorkspacePermissionType = "bb.permission.workspace.manage-environment"
+	// WorkspacePermissionManageInstance allows user to create, update and delete instances and the instance roles.
+	WorkspacePermissionManageInstance WorkspacePermissionType = "bb.permission.workspace.manage-instance"
+	// WorkspacePermissionManagePolicy allows user to update and delete environment and database policies.
+	WorkspacePermissionManagePolicy WorkspacePermissionType = "bb.permission.workspace.manage-policy"
+	// WorkspacePermissionManageBackup allows user to manage the environment backup settings.
+	WorkspacePermissionManageBackup WorkspacePermissionType = "bb.permission.workspace.manage-backup"
+	// WorkspacePermissionManageLabel allows user to update label keys and values.
+	WorkspacePermissionManageLabel WorkspacePermissionType = "bb.permission.workspace.manage-label"
+	// WorkspacePermissionAdminSQLEditor allows user to run statements in the admin mode of the SQL editor.
+	WorkspacePermissionAdminSQLEditor WorkspacePermissionType = "bb.permission.workspace.admin-sql-editor"
+	// WorkspacePermissionApproveIssue allows user to approve issues in all projects.
+	WorkspacePermissionApproveIssue WorkspacePermissionType = "bb.permission.workspace.approve-issue"
+)
+
+// WorkspacePermissionList is the list of the workspace permissions which can be granted by the custom roles.
+var WorkspacePermissionList = []WorkspacePermissionType{
+	WorkspacePermissionManageEnvironment,
+	WorkspacePermissionManageInstance,
+	WorkspacePermissionManagePolicy,
+	WorkspacePermissionManageBackup,
+	WorkspacePermissionManageLabel,
+	WorkspacePermissionAdminSQLEditor,
+	WorkspacePermissionApproveIssue,
+}
+
+// IsBuiltinProjectRole returns whether the project role is a built-in role instead of a custom role.
+func IsBuiltinProjectRole(role Role) bool {
+	return role == Owner || role == Developer
+}
+
+// IsValidPermission returns whether the permission is a known workspace or project permission.
+func IsValidPermission(permission string) bool {
+	for _, p := range WorkspacePermissionList {
+		if string(p) == permission {
+			return true
+		}
+	}
+	for _, p := range ProjectPermissionList {
+		if string(p) == permission {
+			return true
+		}
+	}
+	return false
+}
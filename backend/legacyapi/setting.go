@@ -22,6 +22,13 @@ const (
 	SettingAppIM SettingName = "bb.app.im"
 	// SettingWatermark is the setting name for watermark displaying.
 	SettingWatermark SettingName = "bb.workspace.watermark"
+	// SettingWorkspaceIAMPolicy is the setting name for the custom role bindings of the workspace.
+	SettingWorkspaceIAMPolicy SettingName = "bb.workspace.iam-policy"
+	// SettingWorkspaceRequireMFA is the setting name for requiring the Owners and DBAs to enable MFA, the value is "1" or "0".
+	SettingWorkspaceRequireMFA SettingName = "bb.workspace.require-mfa"
+	// SettingWorkspaceMaxRunningTasks is the setting name for the maximum number of running tasks in the workspace,
+	// the value is a non-negative integer and "0" means no limit.
+	SettingWorkspaceMaxRunningTasks SettingName = "bb.workspace.max-running-tasks"
 )
 
 // IMType is the type of IM.
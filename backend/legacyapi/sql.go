@@ -24,6 +24,17 @@ type ConnectionInfo struct {
 	SRV bool `jsonapi:"attr,srv"`
 	// AuthenticationDatabase is used for MongoDB only.
 	AuthenticationDatabase string `json:"authenticationDatabase" jsonapi:"attr,authenticationDatabase"`
+	// SSH tunnel settings. If both SSHPassword and SSHPrivateKey are empty and InstanceID is specified,
+	// we will use the SSH credentials of the instance admin data source, and so is SSHHostKey.
+	SSHHost       string `jsonapi:"attr,sshHost"`
+	SSHPort       string `jsonapi:"attr,sshPort"`
+	SSHUser       string `jsonapi:"attr,sshUser"`
+	SSHPassword   string `jsonapi:"attr,sshPassword"`
+	SSHPrivateKey string `jsonapi:"attr,sshPrivateKey"`
+	SSHHostKey    string `jsonapi:"attr,sshHostKey"`
+	// The secret references are rejected, since the credentials in the external secret provider are only resolved from the stored data source.
+	PasswordSecretRef string `jsonapi:"attr,passwordSecretRef"`
+	SslKeySecretRef   string `jsonapi:"attr,sslKeySecretRef"`
 }
 
 // SQLSyncSchema is the API message for sync schemas.
@@ -28,6 +28,11 @@ type Stage struct {
 
 	// Domain specific fields
 	Name string `jsonapi:"attr,name"`
+	// Unhealthy is set if the post-deployment verification of any task in the stage fails.
+	// It's not set in the stripped pipelines of the issue list.
+	Unhealthy bool `jsonapi:"attr,unhealthy"`
+	// GateStatus is the status of the promotion gate of the stage, it's nil if the stage has no gate or hasn't started.
+	GateStatus *StageGateStatus `jsonapi:"attr,gateStatus"`
 }
 
 // StageCreate is the API message for creating a stage.
@@ -43,7 +48,44 @@ type StageCreate struct {
 	TaskIndexDAGList []TaskIndexDAG
 
 	// Domain specific fields
-	Name string
+	Name    string
+	Payload *StagePayload
+}
+
+// StagePayload is the config of the stage copied from the deployment when the pipeline is created,
+// so that changing the deployment config doesn't affect the pipelines in progress.
+type StagePayload struct {
+	// Rollout is the gradual rollout of the stage.
+	Rollout *DeploymentRollout `json:"rollout,omitempty"`
+	// Gate is the promotion gate of the stage.
+	Gate *DeploymentGate `json:"gate,omitempty"`
+}
+
+// StageGateConditionType is the type of a promotion gate condition.
+type StageGateConditionType string
+
+const (
+	// StageGateConditionSoakTime is the condition that the previous stage has been done for the soak time.
+	StageGateConditionSoakTime StageGateConditionType = "SOAK_TIME"
+	// StageGateConditionNoOpenAnomaly is the condition that the databases of the previous stage have no open anomalies.
+	StageGateConditionNoOpenAnomaly StageGateConditionType = "NO_OPEN_ANOMALY"
+	// StageGateConditionVerificationSuccess is the condition that the verification of the previous stage succeeds.
+	StageGateConditionVerificationSuccess StageGateConditionType = "VERIFICATION_SUCCESS"
+)
+
+// StageGateStatus is the API message for the status of the promotion gate of a stage.
+type StageGateStatus struct {
+	// Passed is set if all conditions pass.
+	Passed        bool                  `json:"passed"`
+	ConditionList []*StageGateCondition `json:"conditionList"`
+}
+
+// StageGateCondition is the API message for the status of a promotion gate condition.
+type StageGateCondition struct {
+	Type   StageGateConditionType `json:"type"`
+	Passed bool                   `json:"passed"`
+	// Message explains why the condition doesn't pass.
+	Message string `json:"message,omitempty"`
 }
 
 // TaskIndexDAG describes task dependency relationship using array index to represent task.
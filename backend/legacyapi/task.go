@@ -51,6 +51,8 @@ const (
 	TaskDatabaseRestorePITRRestore TaskType = "bb.task.database.restore.pitr.restore"
 	// TaskDatabaseRestorePITRCutover is the task type for swapping the pitr and original database.
 	TaskDatabaseRestorePITRCutover TaskType = "bb.task.database.restore.pitr.cutover"
+	// TaskDatabaseAccessGrant is the task type for granting temporary query access to a database.
+	TaskDatabaseAccessGrant TaskType = "bb.task.database.access.grant"
 )
 
 // These payload types are only used when marshalling to the json format for saving into the database.
@@ -127,6 +129,34 @@ type TaskDatabaseSchemaUpdatePayload struct {
 	SheetID       int            `json:"sheetId,omitempty"`
 	SchemaVersion string         `json:"schemaVersion,omitempty"`
 	VCSPushEvent  *vcs.PushEvent `json:"pushEvent,omitempty"`
+	// ResumableExecution commits the statements applied before the failed statement, so that the failed task can be resumed from the failed statement.
+	// Otherwise, the statements executed in a transaction are rolled back on failure.
+	ResumableExecution bool `json:"resumableExecution,omitempty"`
+	// Resume is set if the failed task is resumed from the failed statement instead of being rerun from the start.
+	Resume bool `json:"resume,omitempty"`
+
+	// DownStatement is the down migration reverting the schema update, generated by diffing the schemas after and before the migration.
+	DownStatement string `json:"downStatement,omitempty"`
+	// DownStatementLossyList is the statements in the down migration which lose data, e.g. dropping the tables or columns added by the schema update.
+	DownStatementLossyList []string `json:"downStatementLossyList,omitempty"`
+	// DownStatementError is set if the down migration cannot be generated.
+	DownStatementError string `json:"downStatementError,omitempty"`
+	// RollbackFromIssueID is the issue ID containing the original task from which the down migration is generated for this task.
+	RollbackFromIssueID int `json:"rollbackFromIssueId,omitempty"`
+	// RollbackFromTaskID is the task ID from which the down migration is generated for this task.
+	RollbackFromTaskID int `json:"rollbackFromTaskId,omitempty"`
+
+	// Verification is the post-deployment verification running after the task is done.
+	Verification *TaskVerification `json:"verification,omitempty"`
+}
+
+// TaskVerification is the post-deployment verification of a task.
+// The verification fails if the first column of the first row returned by the statement doesn't equal the expected result.
+type TaskVerification struct {
+	// Statement is the read-only query to verify the outcome of the task, e.g. SELECT count(*) FROM t WHERE new_col IS NULL.
+	Statement string `json:"statement"`
+	// ExpectedResult is the expected result in text, or NULL for the null value.
+	ExpectedResult string `json:"expectedResult"`
 }
 
 // TaskDatabaseSchemaUpdateSDLPayload is the task payload for database schema update (SDL).
@@ -174,6 +204,11 @@ type TaskDatabaseDataUpdatePayload struct {
 	SheetID       int            `json:"sheetId,omitempty"`
 	SchemaVersion string         `json:"schemaVersion,omitempty"`
 	VCSPushEvent  *vcs.PushEvent `json:"pushEvent,omitempty"`
+	// ResumableExecution commits the statements applied before the failed statement, so that the failed task can be resumed from the failed statement.
+	// Otherwise, the statements executed in a transaction are rolled back on failure.
+	ResumableExecution bool `json:"resumableExecution,omitempty"`
+	// Resume is set if the failed task is resumed from the failed statement instead of being rerun from the start.
+	Resume bool `json:"resume,omitempty"`
 
 	// MySQL rollback SQL related.
 
@@ -196,6 +231,62 @@ type TaskDatabaseDataUpdatePayload struct {
 	RollbackFromIssueID int `json:"rollbackFromIssueId,omitempty"`
 	// RollbackFromTaskID is the task ID from which the rollback SQL statement is generated for this task.
 	RollbackFromTaskID int `json:"rollbackFromTaskId,omitempty"`
+
+	// ChunkConfig enables the chunked execution if it's set.
+	// The rollback SQL is not generated for the chunked execution.
+	ChunkConfig *DataUpdateChunkConfig `json:"chunkConfig,omitempty"`
+	// ChunkCheckpoint is the last completed chunk of the chunked execution.
+	ChunkCheckpoint *DataUpdateChunkCheckpoint `json:"chunkCheckpoint,omitempty"`
+
+	// Verification is the post-deployment verification running after the task is done.
+	Verification *TaskVerification `json:"verification,omitempty"`
+}
+
+const (
+	// DefaultDataUpdateChunkSize is the default number of rows in a chunk.
+	DefaultDataUpdateChunkSize = 1000
+	// MaxDataUpdateChunkSize is the max number of rows in a chunk.
+	MaxDataUpdateChunkSize = 100000
+)
+
+// DataUpdateChunkConfig is the configuration of the chunked execution for MySQL and PostgreSQL,
+// which splits the UPDATE and DELETE statements into chunks ranged by the primary key of the table.
+// Each chunk is committed on its own, and the chunk running at a server restart is executed again after the restart,
+// so the statements should be idempotent, e.g. "UPDATE t SET status = 'ARCHIVED' WHERE ...".
+type DataUpdateChunkConfig struct {
+	// ChunkSize is the number of rows in the primary key range of a chunk.
+	ChunkSize int `json:"chunkSize"`
+	// SleepMs is the time to sleep between the chunks.
+	SleepMs int `json:"sleepMs"`
+	// MaxReplicationLagSeconds holds the execution while the replication lag exceeds it. The check is disabled if it's 0.
+	// The lag is checked on the read-only data source for MySQL, and on the standby servers of the primary server for PostgreSQL.
+	MaxReplicationLagSeconds int `json:"maxReplicationLagSeconds"`
+	// Paused holds the execution after the running chunk until it's resumed.
+	Paused bool `json:"paused"`
+}
+
+// Validate validates the chunked execution configuration.
+func (c *DataUpdateChunkConfig) Validate() error {
+	if c.ChunkSize <= 0 || c.ChunkSize > MaxDataUpdateChunkSize {
+		return common.Errorf(common.Invalid, "chunk size must be in [1, %d]", MaxDataUpdateChunkSize)
+	}
+	if c.SleepMs < 0 {
+		return common.Errorf(common.Invalid, "sleep time between chunks must not be negative")
+	}
+	if c.MaxReplicationLagSeconds < 0 {
+		return common.Errorf(common.Invalid, "max replication lag must not be negative")
+	}
+	return nil
+}
+
+// DataUpdateChunkCheckpoint is the last completed chunk of the chunked execution, the execution resumes from it after restart.
+type DataUpdateChunkCheckpoint struct {
+	// StatementIndex is the index of the statement to execute.
+	StatementIndex int `json:"statementIndex"`
+	// LastKey is the primary key upper bound of the last completed chunk of the statement, it's nil if no chunk of the statement has completed.
+	LastKey         *string `json:"lastKey,omitempty"`
+	CompletedChunks int64   `json:"completedChunks"`
+	AffectedRows    int64   `json:"affectedRows"`
 }
 
 // TaskDatabaseBackupPayload is the task payload for database backup.
@@ -207,6 +298,19 @@ type TaskDatabaseBackupPayload struct {
 	BackupID int `json:"backupId,omitempty"`
 }
 
+// TaskDatabaseAccessGrantPayload is the task payload for granting temporary query access to a database.
+type TaskDatabaseAccessGrantPayload struct {
+	// Common fields
+	Skipped       bool   `json:"skipped,omitempty"`
+	SkippedReason string `json:"skippedReason,omitempty"`
+
+	TableList       []string `json:"tableList,omitempty"`
+	DurationSeconds int64    `json:"durationSeconds,omitempty"`
+	Reason          string   `json:"reason,omitempty"`
+	// AccessGrantID is the ID of the access grant created after the task is done.
+	AccessGrantID int `json:"accessGrantId,omitempty"`
+}
+
 // Task is the API message for a task.
 type Task struct {
 	ID int `jsonapi:"primary,task"`
@@ -240,8 +344,15 @@ type Task struct {
 	// BlockedBy is an array of Task ID.
 	// We use string here to workaround jsonapi limitations. https://github.com/google/jsonapi/issues/209
 	BlockedBy []string `jsonapi:"attr,blockedBy"`
-	// Progress is loaded from the task scheduler in memory, NOT from the database
+	// Progress is loaded from the running task run.
 	Progress Progress `jsonapi:"attr,progress"`
+	// EstimatedAffectedRows is loaded from the latest affected rows task check run.
+	EstimatedAffectedRows int64 `jsonapi:"attr,estimatedAffectedRows"`
+	// MaintenanceWindowStartTs is the next start time allowed by the maintenance window policy, it's 0 if the task can start now.
+	MaintenanceWindowStartTs int64 `jsonapi:"attr,maintenanceWindowStartTs"`
+	// QueuedReason is loaded from the task scheduler in memory, it's why the pending task waits for the concurrency limits.
+	// It's empty if the task isn't queued.
+	QueuedReason string `jsonapi:"attr,queuedReason"`
 }
 
 // Progress is a generalized struct which can track the progress of a task.
@@ -323,11 +434,30 @@ type TaskPatch struct {
 
 	// Domain specific fields
 	DatabaseID *int
-	// Statement/SchemaVersion and Payload cannot be set at the same time.
+	// Statement/SchemaVersion/ChunkConfig/ChunkCheckpoint and Payload cannot be set at the same time.
 	Statement         *string `jsonapi:"attr,statement"`
 	SchemaVersion     *string
 	Payload           *string
 	EarliestAllowedTs *int64 `jsonapi:"attr,earliestAllowedTs"`
+	// ChunkConfig and ChunkCheckpoint are the JSON of DataUpdateChunkConfig and DataUpdateChunkCheckpoint in the data update task payload.
+	// They are patched without overwriting the other payload fields, because the executor and the users patch them concurrently.
+	ChunkConfig     *string
+	ChunkCheckpoint *string
+}
+
+// TaskChunkConfigPatch is the API message for patching the chunked execution configuration of a data update task.
+type TaskChunkConfigPatch struct {
+	ID int
+
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	UpdaterID int
+
+	// Domain specific fields
+	ChunkSize                *int  `jsonapi:"attr,chunkSize"`
+	SleepMs                  *int  `jsonapi:"attr,sleepMs"`
+	MaxReplicationLagSeconds *int  `jsonapi:"attr,maxReplicationLagSeconds"`
+	Paused                   *bool `jsonapi:"attr,paused"`
 }
 
 // TaskStatusPatch is the API message for patching a task status.
@@ -347,4 +477,6 @@ type TaskStatusPatch struct {
 	// And SkippedReason is Comment.
 	Skipped       *bool
 	SkippedReason *string
+	// Resume is set to true if frontend retries the failed task from the failed statement.
+	Resume *bool `jsonapi:"attr,resume"`
 }
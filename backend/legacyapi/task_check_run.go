@@ -1,6 +1,8 @@
 package api
 
 import (
+	"encoding/json"
+
 	"github.com/bytebase/bytebase/backend/common"
 	"github.com/bytebase/bytebase/backend/plugin/advisor"
 	advisorDB "github.com/bytebase/bytebase/backend/plugin/advisor/db"
@@ -67,6 +69,8 @@ const (
 	TaskCheckDatabaseStatementAdvise TaskCheckType = "bb.task-check.database.statement.advise"
 	// TaskCheckDatabaseStatementType is the task check type for statement type.
 	TaskCheckDatabaseStatementType TaskCheckType = "bb.task-check.database.statement.type"
+	// TaskCheckDatabaseStatementAffectedRows is the task check type for estimating the affected rows of statements.
+	TaskCheckDatabaseStatementAffectedRows TaskCheckType = "bb.task-check.database.statement.affected-rows"
 	// TaskCheckDatabaseConnect is the task check type for database connection.
 	TaskCheckDatabaseConnect TaskCheckType = "bb.task-check.database.connect"
 	// TaskCheckInstanceMigrationSchema is the task check type for migrating schemas.
@@ -77,6 +81,8 @@ const (
 	TaskCheckIssueLGTM TaskCheckType = "bb.task-check.issue.lgtm"
 	// TaskCheckPITRMySQL is the task check type for MySQL PITR.
 	TaskCheckPITRMySQL TaskCheckType = "bb.task-check.pitr.mysql"
+	// TaskCheckDatabaseVerification is the task check type for the post-deployment verification.
+	TaskCheckDatabaseVerification TaskCheckType = "bb.task-check.database.verification"
 )
 
 // TaskCheckEarliestAllowedTimePayload is the task check payload for earliest allowed time.
@@ -102,6 +108,18 @@ type TaskCheckDatabaseStatementTypePayload struct {
 	Collation string `json:"collation,omitempty"`
 }
 
+// TaskCheckDatabaseStatementAffectedRowsPayload is the task check payload for estimating the affected rows.
+type TaskCheckDatabaseStatementAffectedRowsPayload struct {
+	Statement string  `json:"statement,omitempty"`
+	DbType    db.Type `json:"dbType,omitempty"`
+}
+
+// TaskCheckDatabaseVerificationPayload is the task check payload for the post-deployment verification.
+type TaskCheckDatabaseVerificationPayload struct {
+	Statement      string `json:"statement,omitempty"`
+	ExpectedResult string `json:"expectedResult,omitempty"`
+}
+
 // Namespace is the namespace for task check result.
 type Namespace string
 
@@ -120,6 +138,9 @@ type TaskCheckResult struct {
 	Title     string          `json:"title,omitempty"`
 	Content   string          `json:"content,omitempty"`
 	Line      int             `json:"line,omitempty"`
+	// AffectedRows is the estimated affected row count of the statement.
+	// It's only set by the affected rows task check.
+	AffectedRows int64 `json:"affectedRows,omitempty"`
 }
 
 // TaskCheckRunResultPayload is the result payload of a task check run.
@@ -152,6 +173,80 @@ type TaskCheckRun struct {
 	Payload string             `jsonapi:"attr,payload"`
 }
 
+// GetEstimatedAffectedRows returns the sum of the estimated affected rows in the latest done affected rows task check run.
+// The second return value is false if there is no such task check run.
+func GetEstimatedAffectedRows(taskCheckRunList []*TaskCheckRun) (int64, bool, error) {
+	var latest *TaskCheckRun
+	for _, run := range taskCheckRunList {
+		if run.Type != TaskCheckDatabaseStatementAffectedRows || run.Status != TaskCheckRunDone {
+			continue
+		}
+		if latest == nil || run.ID > latest.ID {
+			latest = run
+		}
+	}
+	if latest == nil {
+		return 0, false, nil
+	}
+
+	result := &TaskCheckRunResultPayload{}
+	if err := json.Unmarshal([]byte(latest.Result), result); err != nil {
+		return 0, false, err
+	}
+	var affectedRows int64
+	for _, r := range result.ResultList {
+		affectedRows += r.AffectedRows
+	}
+	return affectedRows, true, nil
+}
+
+// GetVerificationStatus returns the status of the latest done verification task check run.
+// The second return value is false if the verification hasn't been done.
+func GetVerificationStatus(taskCheckRunList []*TaskCheckRun) (TaskCheckStatus, bool, error) {
+	var latest *TaskCheckRun
+	for _, run := range taskCheckRunList {
+		if run.Type != TaskCheckDatabaseVerification {
+			continue
+		}
+		if latest == nil || run.ID > latest.ID {
+			latest = run
+		}
+	}
+	if latest == nil {
+		return "", false, nil
+	}
+	switch latest.Status {
+	case TaskCheckRunDone:
+	case TaskCheckRunFailed:
+		// The verification statement fails to run.
+		return TaskCheckStatusError, true, nil
+	default:
+		return "", false, nil
+	}
+
+	result := &TaskCheckRunResultPayload{}
+	if err := json.Unmarshal([]byte(latest.Result), result); err != nil {
+		return "", false, err
+	}
+	status := TaskCheckStatusSuccess
+	for _, r := range result.ResultList {
+		if r.Status.LessThan(status) {
+			status = r.Status
+		}
+	}
+	return status, true, nil
+}
+
+// IsStatementAffectedRowsCheckSupported checks the engine type if the affected rows estimation supports it.
+func IsStatementAffectedRowsCheckSupported(dbType db.Type) bool {
+	switch dbType {
+	case db.Postgres, db.TiDB, db.MySQL:
+		return true
+	default:
+		return false
+	}
+}
+
 // IsSyntaxCheckSupported checks the engine type if syntax check supports it.
 func IsSyntaxCheckSupported(dbType db.Type) bool {
 	if dbType == db.Postgres || dbType == db.MySQL || dbType == db.TiDB {
@@ -183,7 +278,7 @@ func IsSQLReviewSupported(dbType db.Type) bool {
 // IsStatementTypeCheckSupported checks the engine type if statement type check supports it.
 func IsStatementTypeCheckSupported(dbType db.Type) bool {
 	switch dbType {
-	case db.Postgres, db.TiDB, db.MySQL:
+	case db.Postgres, db.TiDB, db.MySQL, db.ClickHouse:
 		return true
 	default:
 		return false
@@ -0,0 +1,42 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetVerificationStatus(t *testing.T) {
+	a := require.New(t)
+	successResult := `{"resultList":[{"status":"SUCCESS"}]}`
+	errorResult := `{"resultList":[{"status":"ERROR"}]}`
+
+	_, found, err := GetVerificationStatus([]*TaskCheckRun{
+		{ID: 1, Type: TaskCheckDatabaseConnect, Status: TaskCheckRunDone, Result: errorResult},
+	})
+	a.NoError(err)
+	a.False(found)
+
+	_, found, err = GetVerificationStatus([]*TaskCheckRun{
+		{ID: 1, Type: TaskCheckDatabaseVerification, Status: TaskCheckRunDone, Result: errorResult},
+		{ID: 2, Type: TaskCheckDatabaseVerification, Status: TaskCheckRunRunning},
+	})
+	a.NoError(err)
+	a.False(found)
+
+	status, found, err := GetVerificationStatus([]*TaskCheckRun{
+		{ID: 2, Type: TaskCheckDatabaseVerification, Status: TaskCheckRunDone, Result: successResult},
+		{ID: 1, Type: TaskCheckDatabaseVerification, Status: TaskCheckRunDone, Result: errorResult},
+	})
+	a.NoError(err)
+	a.True(found)
+	a.Equal(TaskCheckStatusSuccess, status)
+
+	status, found, err = GetVerificationStatus([]*TaskCheckRun{
+		{ID: 1, Type: TaskCheckDatabaseVerification, Status: TaskCheckRunDone, Result: successResult},
+		{ID: 2, Type: TaskCheckDatabaseVerification, Status: TaskCheckRunFailed},
+	})
+	a.NoError(err)
+	a.True(found)
+	a.Equal(TaskCheckStatusError, status)
+}
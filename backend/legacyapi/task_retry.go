@@ -0,0 +1,98 @@
+package api
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// TaskRetryErrorClass is the class of the transient errors which can be retried.
+type TaskRetryErrorClass string
+
+const (
+	// TaskRetryErrorClassConnection is the class of the connection errors, e.g. connection refused or reset.
+	TaskRetryErrorClassConnection TaskRetryErrorClass = "CONNECTION"
+	// TaskRetryErrorClassLockTimeout is the class of the lock wait timeout errors, e.g. MySQL error 1205.
+	TaskRetryErrorClassLockTimeout TaskRetryErrorClass = "LOCK_TIMEOUT"
+	// TaskRetryErrorClassDeadlock is the class of the deadlock errors, e.g. MySQL error 1213 and Postgres 40P01.
+	TaskRetryErrorClassDeadlock TaskRetryErrorClass = "DEADLOCK"
+	// TaskRetryErrorClassSerialization is the class of the Postgres serialization failures, i.e. 40001.
+	TaskRetryErrorClassSerialization TaskRetryErrorClass = "SERIALIZATION"
+)
+
+// TaskRetryPolicy is the policy configuration for retrying the tasks failed by the transient errors automatically.
+// Each attempt is recorded as a separate task run.
+type TaskRetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts including the first one. The tasks aren't retried if it's less than 2.
+	MaxAttempts int `json:"maxAttempts"`
+	// BackoffSeconds is the wait before the first retry, and it doubles for each following retry.
+	BackoffSeconds int `json:"backoffSeconds"`
+	// MaxBackoffSeconds caps the wait between the retries. It's uncapped if 0.
+	MaxBackoffSeconds int `json:"maxBackoffSeconds"`
+	// ErrorClassList is the classes of the errors to retry.
+	ErrorClassList []TaskRetryErrorClass `json:"errorClassList"`
+	// RetryDataUpdate opts in retrying the data update tasks, which may not be idempotent.
+	RetryDataUpdate bool `json:"retryDataUpdate"`
+}
+
+// UnmarshalTaskRetryPolicy will unmarshal payload to task retry policy.
+func UnmarshalTaskRetryPolicy(payload string) (*TaskRetryPolicy, error) {
+	var p TaskRetryPolicy
+	if err := json.Unmarshal([]byte(payload), &p); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal task retry policy %q", payload)
+	}
+	return &p, nil
+}
+
+func (p *TaskRetryPolicy) String() (string, error) {
+	s, err := json.Marshal(p)
+	if err != nil {
+		return "", err
+	}
+	return string(s), nil
+}
+
+// Validate validates the attempts, the backoff and the error classes.
+func (p *TaskRetryPolicy) Validate() error {
+	if p.MaxAttempts < 0 {
+		return errors.Errorf("max attempts must not be negative")
+	}
+	if p.BackoffSeconds < 0 || p.MaxBackoffSeconds < 0 {
+		return errors.Errorf("backoff must not be negative")
+	}
+	for _, class := range p.ErrorClassList {
+		switch class {
+		case TaskRetryErrorClassConnection, TaskRetryErrorClassLockTimeout, TaskRetryErrorClassDeadlock, TaskRetryErrorClassSerialization:
+		default:
+			return errors.Errorf("invalid error class %q", class)
+		}
+	}
+	return nil
+}
+
+// HasErrorClass returns whether the errors of the class are retried.
+func (p *TaskRetryPolicy) HasErrorClass(class TaskRetryErrorClass) bool {
+	for _, c := range p.ErrorClassList {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+// GetBackoff returns the wait before retrying the failed attempt, the attempt starts from 1.
+func (p *TaskRetryPolicy) GetBackoff(attempt int) time.Duration {
+	backoff := time.Duration(p.BackoffSeconds) * time.Second
+	maxBackoff := time.Duration(p.MaxBackoffSeconds) * time.Second
+	for i := 1; i < attempt; i++ {
+		backoff *= 2
+		if maxBackoff > 0 && backoff >= maxBackoff {
+			break
+		}
+	}
+	if maxBackoff > 0 && backoff > maxBackoff {
+		return maxBackoff
+	}
+	return backoff
+}
@@ -0,0 +1,28 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskRetryPolicyGetBackoff(t *testing.T) {
+	policy := &TaskRetryPolicy{MaxAttempts: 5, BackoffSeconds: 10, MaxBackoffSeconds: 60}
+	require.Equal(t, 10*time.Second, policy.GetBackoff(1))
+	require.Equal(t, 20*time.Second, policy.GetBackoff(2))
+	require.Equal(t, 40*time.Second, policy.GetBackoff(3))
+	require.Equal(t, 60*time.Second, policy.GetBackoff(4))
+	require.Equal(t, 60*time.Second, policy.GetBackoff(100))
+
+	uncapped := &TaskRetryPolicy{MaxAttempts: 3, BackoffSeconds: 10}
+	require.Equal(t, 40*time.Second, uncapped.GetBackoff(3))
+}
+
+func TestTaskRetryPolicyValidate(t *testing.T) {
+	require.NoError(t, (&TaskRetryPolicy{}).Validate())
+	require.NoError(t, (&TaskRetryPolicy{MaxAttempts: 3, BackoffSeconds: 5, ErrorClassList: []TaskRetryErrorClass{TaskRetryErrorClassConnection, TaskRetryErrorClassDeadlock}}).Validate())
+	require.Error(t, (&TaskRetryPolicy{MaxAttempts: -1}).Validate())
+	require.Error(t, (&TaskRetryPolicy{BackoffSeconds: -1}).Validate())
+	require.Error(t, (&TaskRetryPolicy{ErrorClassList: []TaskRetryErrorClass{"TIMEOUT"}}).Validate())
+}
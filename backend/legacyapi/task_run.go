@@ -2,6 +2,7 @@ package api
 
 import (
 	"github.com/bytebase/bytebase/backend/common"
+	"github.com/bytebase/bytebase/backend/plugin/db"
 )
 
 // TaskRunStatus is the status of a task run.
@@ -25,6 +26,12 @@ type TaskRunResultPayload struct {
 	Detail      string `json:"detail,omitempty"`
 	MigrationID string `json:"migrationId,omitempty"`
 	Version     string `json:"version,omitempty"`
+	// RetryAttempt is the attempt number of the failed run which is retried automatically.
+	RetryAttempt int `json:"retryAttempt,omitempty"`
+	// AppliedStatementCount and FailedStatement are set if the run fails on a statement.
+	// The statements before the failed one have been applied, and the task can be resumed from the failed statement.
+	AppliedStatementCount int    `json:"appliedStatementCount,omitempty"`
+	FailedStatement       string `json:"failedStatement,omitempty"`
 }
 
 // TaskRun is the API message for a task run.
@@ -51,3 +58,11 @@ type TaskRun struct {
 	Result  string        `jsonapi:"attr,result"`
 	Payload string        `jsonapi:"attr,payload"`
 }
+
+// TaskRunLog is the API message for a structured log line of a task run.
+type TaskRunLog struct {
+	ID        int64          `json:"id"`
+	TaskRunID int            `json:"taskRunId"`
+	CreatedTs int64          `json:"createdTs"`
+	Payload   *db.ExecuteLog `json:"payload"`
+}
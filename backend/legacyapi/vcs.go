@@ -106,4 +106,7 @@ type VCSSQLReviewRequest struct {
 	// In GitHub, the URL should be "https://github.com". Docs: https://docs.github.com/en/actions/learn-github-actions/environment-variables
 	// In GitLab, the URL should be the base URL of the GitLab instance like "https://gitlab.bytebase.com". Docs: https://docs.gitlab.com/ee/ci/variables/predefined_variables.html
 	WebURL string `json:"webURL"`
+	// Format is the optional report format, could be "SARIF" or "JUNIT".
+	// If it's empty, the result content will be formatted for the CI of the VCS type.
+	Format advisor.ReportFormat `json:"format"`
 }
@@ -0,0 +1,294 @@
+package advisor
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// ReportFormat is the output format of the SQL review report.
+type ReportFormat string
+
+const (
+	// ReportFormatSARIF is the SARIF 2.1.0 report format, which can be uploaded to GitHub code scanning.
+	// Docs: https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+	ReportFormatSARIF ReportFormat = "SARIF"
+	// ReportFormatJUnit is the JUnit XML report format, which can be rendered by the GitLab test report.
+	// Docs: https://docs.gitlab.com/ee/ci/testing/unit_test_reports.html
+	ReportFormatJUnit ReportFormat = "JUNIT"
+
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+	// reportToolName is the tool name in the SQL review report.
+	reportToolName = "Bytebase SQL Review"
+)
+
+// Validate validates the report format.
+func (f ReportFormat) Validate() error {
+	switch f {
+	case ReportFormatSARIF, ReportFormatJUnit:
+		return nil
+	}
+	return errors.Errorf("unsupported report format %q", f)
+}
+
+// ReportContext is the context for generating the SQL review report.
+type ReportContext struct {
+	// DocURL is the URL for the SQL review error code docs. The advice code will be appended as the anchor.
+	DocURL string
+}
+
+// GenerateReport generates the SQL review report in the given format.
+// The adviceMap is keyed by the file path, and advices with the success status are omitted.
+func GenerateReport(format ReportFormat, adviceMap map[string][]Advice, reportContext ReportContext) ([]byte, error) {
+	switch format {
+	case ReportFormatSARIF:
+		return generateSARIFReport(adviceMap, reportContext)
+	case ReportFormatJUnit:
+		return generateJUnitReport(adviceMap, reportContext)
+	}
+	return nil, errors.Errorf("unsupported report format %q", format)
+}
+
+// GetReportStatus returns the most severe status in the advice map.
+func GetReportStatus(adviceMap map[string][]Advice) Status {
+	status := Success
+	for _, adviceList := range adviceMap {
+		for _, advice := range adviceList {
+			if advice.Code == Ok {
+				continue
+			}
+			switch advice.Status {
+			case Error:
+				return Error
+			case Warn:
+				status = Warn
+			}
+		}
+	}
+	return status
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	Name             string       `json:"name"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+	HelpURI          string       `json:"helpUri,omitempty"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+func generateSARIFReport(adviceMap map[string][]Advice, reportContext ReportContext) ([]byte, error) {
+	rules := []sarifRule{}
+	ruleIndex := map[string]bool{}
+	results := []sarifResult{}
+
+	for _, filePath := range sortedFileList(adviceMap) {
+		for _, advice := range adviceMap[filePath] {
+			if advice.Code == Ok || advice.Status == Success {
+				continue
+			}
+
+			// The rule ID is the advice code, which is stable across the releases.
+			ruleID := strconv.Itoa(int(advice.Code))
+			if !ruleIndex[ruleID] {
+				ruleIndex[ruleID] = true
+				rules = append(rules, sarifRule{
+					ID:               ruleID,
+					Name:             advice.Title,
+					ShortDescription: sarifMessage{Text: advice.Title},
+					HelpURI:          getAdviceDocURL(reportContext.DocURL, advice.Code),
+				})
+			}
+
+			level := "warning"
+			if advice.Status == Error {
+				level = "error"
+			}
+			results = append(results, sarifResult{
+				RuleID:  ruleID,
+				Level:   level,
+				Message: sarifMessage{Text: advice.Content},
+				Locations: []sarifLocation{
+					{
+						PhysicalLocation: sarifPhysicalLocation{
+							ArtifactLocation: sarifArtifactLocation{URI: filePath},
+							Region:           sarifRegion{StartLine: getAdviceLine(advice)},
+						},
+					},
+				},
+			})
+		}
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	// SQL statements in the advice content usually contain characters like "<" and "&".
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(&sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []sarifRun{
+			{
+				Tool: sarifTool{
+					Driver: sarifDriver{
+						Name:           reportToolName,
+						InformationURI: reportContext.DocURL,
+						Rules:          rules,
+					},
+				},
+				Results: results,
+			},
+		},
+	}); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+type junitTestSuites struct {
+	XMLName    xml.Name         `xml:"testsuites"`
+	Name       string           `xml:"name,attr"`
+	Tests      int              `xml:"tests,attr"`
+	Failures   int              `xml:"failures,attr"`
+	TestSuites []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string       `xml:"name,attr"`
+	ClassName string       `xml:"classname,attr"`
+	File      string       `xml:"file,attr"`
+	Line      int          `xml:"line,attr"`
+	Failure   junitFailure `xml:"failure"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Content string `xml:",chardata"`
+}
+
+func generateJUnitReport(adviceMap map[string][]Advice, reportContext ReportContext) ([]byte, error) {
+	testSuites := junitTestSuites{
+		Name: reportToolName,
+	}
+
+	for _, filePath := range sortedFileList(adviceMap) {
+		testSuite := junitTestSuite{
+			Name: filePath,
+		}
+		for _, advice := range adviceMap[filePath] {
+			if advice.Code == Ok || advice.Status == Success {
+				continue
+			}
+			testSuite.TestCases = append(testSuite.TestCases, junitTestCase{
+				Name:      advice.Title,
+				ClassName: filePath,
+				File:      filePath,
+				Line:      getAdviceLine(advice),
+				Failure: junitFailure{
+					Message: advice.Content,
+					Type:    string(advice.Status),
+					Content: fmt.Sprintf("%s: %s.\nYou can check the docs at %s", advice.Status, advice.Content, getAdviceDocURL(reportContext.DocURL, advice.Code)),
+				},
+			})
+		}
+		if len(testSuite.TestCases) == 0 {
+			continue
+		}
+		testSuite.Tests = len(testSuite.TestCases)
+		testSuite.Failures = len(testSuite.TestCases)
+		testSuites.Tests += testSuite.Tests
+		testSuites.Failures += testSuite.Failures
+		testSuites.TestSuites = append(testSuites.TestSuites, testSuite)
+	}
+
+	content, err := xml.MarshalIndent(&testSuites, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), content...), nil
+}
+
+func sortedFileList(adviceMap map[string][]Advice) []string {
+	var fileList []string
+	for filePath := range adviceMap {
+		fileList = append(fileList, filePath)
+	}
+	sort.Strings(fileList)
+	return fileList
+}
+
+func getAdviceLine(advice Advice) int {
+	if advice.Line <= 0 {
+		return 1
+	}
+	return advice.Line
+}
+
+func getAdviceDocURL(docURL string, code Code) string {
+	if docURL == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s#%d", docURL, code)
+}
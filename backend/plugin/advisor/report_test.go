@@ -0,0 +1,145 @@
+package advisor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var mockReportAdviceMap = map[string][]Advice{
+	"migration/b.sql": {
+		{
+			Status:  Warn,
+			Code:    NamingTableConventionMismatch,
+			Title:   "naming.table",
+			Content: `"techBook" mismatches table naming convention`,
+			Line:    0,
+		},
+	},
+	"migration/a.sql": {
+		{
+			Status:  Success,
+			Code:    Ok,
+			Title:   "OK",
+			Content: "",
+		},
+		{
+			Status:  Error,
+			Code:    StatementNoWhere,
+			Title:   "statement.where.require",
+			Content: `"DELETE FROM t" requires WHERE clause & <index>`,
+			Line:    3,
+		},
+	},
+}
+
+func TestGetReportStatus(t *testing.T) {
+	assert.Equal(t, Error, GetReportStatus(mockReportAdviceMap))
+	assert.Equal(t, Warn, GetReportStatus(map[string][]Advice{"a.sql": mockReportAdviceMap["migration/b.sql"]}))
+	assert.Equal(t, Success, GetReportStatus(map[string][]Advice{}))
+}
+
+func TestGenerateSARIFReport(t *testing.T) {
+	want := `{
+  "$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+  "version": "2.1.0",
+  "runs": [
+    {
+      "tool": {
+        "driver": {
+          "name": "Bytebase SQL Review",
+          "informationUri": "https://docs",
+          "rules": [
+            {
+              "id": "202",
+              "name": "statement.where.require",
+              "shortDescription": {
+                "text": "statement.where.require"
+              },
+              "helpUri": "https://docs#202"
+            },
+            {
+              "id": "301",
+              "name": "naming.table",
+              "shortDescription": {
+                "text": "naming.table"
+              },
+              "helpUri": "https://docs#301"
+            }
+          ]
+        }
+      },
+      "results": [
+        {
+          "ruleId": "202",
+          "level": "error",
+          "message": {
+            "text": "\"DELETE FROM t\" requires WHERE clause & <index>"
+          },
+          "locations": [
+            {
+              "physicalLocation": {
+                "artifactLocation": {
+                  "uri": "migration/a.sql"
+                },
+                "region": {
+                  "startLine": 3
+                }
+              }
+            }
+          ]
+        },
+        {
+          "ruleId": "301",
+          "level": "warning",
+          "message": {
+            "text": "\"techBook\" mismatches table naming convention"
+          },
+          "locations": [
+            {
+              "physicalLocation": {
+                "artifactLocation": {
+                  "uri": "migration/b.sql"
+                },
+                "region": {
+                  "startLine": 1
+                }
+              }
+            }
+          ]
+        }
+      ]
+    }
+  ]
+}`
+	got, err := GenerateReport(ReportFormatSARIF, mockReportAdviceMap, ReportContext{DocURL: "https://docs"})
+	require.NoError(t, err)
+	assert.Equal(t, want, string(got))
+}
+
+func TestGenerateJUnitReport(t *testing.T) {
+	want := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="Bytebase SQL Review" tests="2" failures="2">
+  <testsuite name="migration/a.sql" tests="1" failures="1">
+    <testcase name="statement.where.require" classname="migration/a.sql" file="migration/a.sql" line="3">
+      <failure message="&#34;DELETE FROM t&#34; requires WHERE clause &amp; &lt;index&gt;" type="ERROR">ERROR: &#34;DELETE FROM t&#34; requires WHERE clause &amp; &lt;index&gt;.&#xA;You can check the docs at https://docs#202</failure>
+    </testcase>
+  </testsuite>
+  <testsuite name="migration/b.sql" tests="1" failures="1">
+    <testcase name="naming.table" classname="migration/b.sql" file="migration/b.sql" line="1">
+      <failure message="&#34;techBook&#34; mismatches table naming convention" type="WARN">WARN: &#34;techBook&#34; mismatches table naming convention.&#xA;You can check the docs at https://docs#301</failure>
+    </testcase>
+  </testsuite>
+</testsuites>`
+	got, err := GenerateReport(ReportFormatJUnit, mockReportAdviceMap, ReportContext{DocURL: "https://docs"})
+	require.NoError(t, err)
+	assert.Equal(t, want, string(got))
+}
+
+func TestGenerateReportUnsupportedFormat(t *testing.T) {
+	_, err := GenerateReport(ReportFormat("HTML"), mockReportAdviceMap, ReportContext{})
+	require.Error(t, err)
+	require.Error(t, ReportFormat("HTML").Validate())
+	require.NoError(t, ReportFormatSARIF.Validate())
+}
@@ -132,6 +132,9 @@ const (
 // MigrationInfoPayload is the API message for migration info payload.
 type MigrationInfoPayload struct {
 	VCSPushEvent *vcs.PushEvent `json:"pushEvent,omitempty"`
+	// AppliedStatementCount is the number of the statements applied before the migration fails.
+	// Resuming the failed migration skips these statements.
+	AppliedStatementCount int `json:"appliedStatementCount,omitempty"`
 }
 
 // MigrationInfo is the API message for migration info.
@@ -162,6 +165,11 @@ type MigrationInfo struct {
 	// This applies to BASELINE and MIGRATE types of migrations because most of these migrations are retry-able.
 	// We don't use force option for DATA type of migrations yet till there's customer needs.
 	Force bool
+	// ResumableExecution commits the statements applied before the failed statement, so that the FAILED migration can be resumed.
+	ResumableExecution bool
+	// Resume is used to resume the FAILED migration of the same version from the failed statement.
+	// The statements applied by the failed migration are skipped if the statement is unchanged.
+	Resume bool
 }
 
 // placeholderRegexp is the regexp for placeholder.
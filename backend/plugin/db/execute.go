@@ -0,0 +1,64 @@
+package db
+
+import (
+	"context"
+	"fmt"
+)
+
+// ExecuteStatementError is the error returned by Execute() when the resumable execution fails on a statement.
+// The statements before the failed one have been applied, so the execution can be resumed from the failed statement.
+type ExecuteStatementError struct {
+	// Index is the 1-based index of the failed statement among the Count statements, including the skipped ones.
+	Index     int
+	Count     int
+	Statement string
+	Err       error
+}
+
+func (e *ExecuteStatementError) Error() string {
+	if e.Count <= 1 {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("failed to execute statement %d of %d: %v", e.Index, e.Count, e.Err)
+}
+
+// Unwrap returns the error of the failed statement.
+func (e *ExecuteStatementError) Unwrap() error {
+	return e.Err
+}
+
+// AppliedStatementCount returns the number of the statements applied before the failed one.
+func (e *ExecuteStatementError) AppliedStatementCount() int {
+	return e.Index - 1
+}
+
+type skippedStatementCountContextKey struct{}
+
+// WithSkippedStatementCount returns a copy of ctx telling the driver to skip the first count statements, which are applied by the previous execution.
+func WithSkippedStatementCount(ctx context.Context, count int) context.Context {
+	return context.WithValue(ctx, skippedStatementCountContextKey{}, count)
+}
+
+// GetSkippedStatementCount returns the number of the statements to skip carried by ctx.
+func GetSkippedStatementCount(ctx context.Context) int {
+	count, ok := ctx.Value(skippedStatementCountContextKey{}).(int)
+	if !ok {
+		return 0
+	}
+	return count
+}
+
+type resumableExecutionContextKey struct{}
+
+// WithResumableExecution returns a copy of ctx telling the driver to commit the statements applied before the failed statement,
+// so that the execution can be resumed from the failed statement.
+// Without it, the drivers executing the statements in a transaction roll back all the statements on failure.
+func WithResumableExecution(ctx context.Context) context.Context {
+	return context.WithValue(ctx, resumableExecutionContextKey{}, true)
+}
+
+// IsResumableExecution returns true if ctx opts into the resumable execution.
+func IsResumableExecution(ctx context.Context) bool {
+	resumable, ok := ctx.Value(resumableExecutionContextKey{}).(bool)
+	return ok && resumable
+}
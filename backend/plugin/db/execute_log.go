@@ -0,0 +1,64 @@
+package db
+
+import (
+	"context"
+)
+
+// ExecuteLogType is the type of an execute log.
+type ExecuteLogType string
+
+const (
+	// ExecuteLogStatementStart is the execute log type emitted before executing a statement.
+	ExecuteLogStatementStart ExecuteLogType = "STATEMENT_START"
+	// ExecuteLogStatementEnd is the execute log type emitted after executing a statement.
+	ExecuteLogStatementEnd ExecuteLogType = "STATEMENT_END"
+	// ExecuteLogNotice is the execute log type for the server notices, e.g. Postgres RAISE NOTICE and MySQL warnings.
+	ExecuteLogNotice ExecuteLogType = "NOTICE"
+)
+
+// maxExecuteLogStatementSize is the maximum size of the statement recorded in an execute log.
+const maxExecuteLogStatementSize = 1024
+
+// ExecuteLog is a structured log line emitted by the driver while executing statements.
+type ExecuteLog struct {
+	Type ExecuteLogType `json:"type"`
+	// StatementIndex is the 1-based index of the statement among the StatementCount statements.
+	StatementIndex int    `json:"statementIndex,omitempty"`
+	StatementCount int    `json:"statementCount,omitempty"`
+	Statement      string `json:"statement,omitempty"`
+	// AffectedRows, DurationMs and Error are only set for ExecuteLogStatementEnd.
+	AffectedRows int64  `json:"affectedRows,omitempty"`
+	DurationMs   int64  `json:"durationMs,omitempty"`
+	Error        string `json:"error,omitempty"`
+	// Level and Message are only set for ExecuteLogNotice.
+	Level   string `json:"level,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// ExecuteLogger receives the execute logs.
+type ExecuteLogger func(log *ExecuteLog)
+
+type executeLoggerContextKey struct{}
+
+// WithExecuteLogger returns a copy of ctx carrying the logger.
+// Drivers supporting execute logs will emit them to the logger in Execute().
+func WithExecuteLogger(ctx context.Context, logger ExecuteLogger) context.Context {
+	return context.WithValue(ctx, executeLoggerContextKey{}, logger)
+}
+
+// GetExecuteLogger returns the logger carried by ctx, or nil if there is none.
+func GetExecuteLogger(ctx context.Context) ExecuteLogger {
+	logger, ok := ctx.Value(executeLoggerContextKey{}).(ExecuteLogger)
+	if !ok {
+		return nil
+	}
+	return logger
+}
+
+// TruncateExecuteLogStatement truncates the statement to be recorded in an execute log.
+func TruncateExecuteLogStatement(statement string) string {
+	if len(statement) <= maxExecuteLogStatementSize {
+		return statement
+	}
+	return statement[:maxExecuteLogStatementSize] + "..."
+}
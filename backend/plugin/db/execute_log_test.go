@@ -0,0 +1,32 @@
+package db
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteLogger(t *testing.T) {
+	a := require.New(t)
+	ctx := context.Background()
+	a.Nil(GetExecuteLogger(ctx))
+
+	var logs []*ExecuteLog
+	ctx = WithExecuteLogger(ctx, func(log *ExecuteLog) {
+		logs = append(logs, log)
+	})
+	logger := GetExecuteLogger(ctx)
+	a.NotNil(logger)
+	logger(&ExecuteLog{Type: ExecuteLogNotice, Message: "hello"})
+	a.Equal([]*ExecuteLog{{Type: ExecuteLogNotice, Message: "hello"}}, logs)
+}
+
+func TestTruncateExecuteLogStatement(t *testing.T) {
+	a := require.New(t)
+	a.Equal("SELECT 1;", TruncateExecuteLogStatement("SELECT 1;"))
+
+	statement := strings.Repeat("a", maxExecuteLogStatementSize+1)
+	a.Equal(strings.Repeat("a", maxExecuteLogStatementSize)+"...", TruncateExecuteLogStatement(statement))
+}
@@ -0,0 +1,39 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteStatementError(t *testing.T) {
+	a := require.New(t)
+	cause := errors.New("table t1 already exists")
+
+	var err error = &ExecuteStatementError{Index: 37, Count: 50, Statement: "CREATE TABLE t1(id INT);", Err: cause}
+	a.Equal("failed to execute statement 37 of 50: table t1 already exists", err.Error())
+	a.ErrorIs(err, cause)
+	var statementErr *ExecuteStatementError
+	a.True(errors.As(errors.Wrap(err, "failed to migrate"), &statementErr))
+	a.Equal(36, statementErr.AppliedStatementCount())
+
+	// The single statement error keeps the original message.
+	err = &ExecuteStatementError{Index: 1, Count: 1, Statement: "CREATE TABLE t1(id INT);", Err: cause}
+	a.Equal("table t1 already exists", err.Error())
+}
+
+func TestSkippedStatementCount(t *testing.T) {
+	a := require.New(t)
+	ctx := context.Background()
+	a.Equal(0, GetSkippedStatementCount(ctx))
+	a.Equal(36, GetSkippedStatementCount(WithSkippedStatementCount(ctx, 36)))
+}
+
+func TestResumableExecution(t *testing.T) {
+	a := require.New(t)
+	ctx := context.Background()
+	a.False(IsResumableExecution(ctx))
+	a.True(IsResumableExecution(WithResumableExecution(ctx)))
+}
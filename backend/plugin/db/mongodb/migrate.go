@@ -402,6 +402,31 @@ func (driver *Driver) UpdateHistoryAsFailed(ctx context.Context, _ *sql.Tx, migr
 	return nil
 }
 
+// UpdateHistoryPayload will update the payload of the migration record.
+func (driver *Driver) UpdateHistoryPayload(ctx context.Context, _ *sql.Tx, payload string, insertedID string) error {
+	collection := driver.client.Database(migrationHistoryDefaultDatabase).Collection(migrationHistoryDefaultCollection)
+	longMigrationHistoryID, err := strconv.ParseInt(insertedID, 10, 64)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse inserted ID %s to int64", insertedID)
+	}
+	filter := bson.M{
+		"id": longMigrationHistoryID,
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"payload": payload,
+		},
+	}
+	updateResult, err := collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return errors.Wrapf(err, "failed to update the payload of a migration history")
+	}
+	if updateResult.MatchedCount == 0 {
+		return errors.Errorf("failed to find a migration history record to update the payload with ID %s", insertedID)
+	}
+	return nil
+}
+
 func getMongoTimestamp() primitive.Timestamp {
 	return primitive.Timestamp{T: uint32(time.Now().Unix()), I: 1}
 }
@@ -197,6 +197,19 @@ func (Driver) UpdateHistoryAsFailed(ctx context.Context, tx *sql.Tx, migrationDu
 	return err
 }
 
+// UpdateHistoryPayload will update the payload of the migration record.
+func (Driver) UpdateHistoryPayload(ctx context.Context, tx *sql.Tx, payload string, insertedID string) error {
+	const updateHistoryPayloadQuery = `
+		UPDATE
+			bytebase.migration_history
+		SET
+			payload = ?
+		WHERE id = ?
+		`
+	_, err := tx.ExecContext(ctx, updateHistoryPayloadQuery, payload, insertedID)
+	return err
+}
+
 // ExecuteMigration will execute the migration.
 func (driver *Driver) ExecuteMigration(ctx context.Context, m *db.MigrationInfo, statement string) (string, string, error) {
 	return util.ExecuteMigration(ctx, driver, m, statement, db.BytebaseDatabase)
@@ -7,6 +7,7 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/go-sql-driver/mysql"
 	"github.com/pkg/errors"
@@ -173,10 +174,23 @@ func (driver *Driver) getVersion(ctx context.Context) (string, error) {
 
 // Execute executes a SQL statement.
 func (driver *Driver) Execute(ctx context.Context, statement string, _ bool) (int64, error) {
-	trunks, err := splitAndTransformDelimiter(statement)
+	logger := db.GetExecuteLogger(ctx)
+	var trunks []string
+	var err error
+	if logger != nil {
+		// Execute the statements one by one so that each of them gets its own logs.
+		trunks, err = transformDelimiter(statement)
+	} else {
+		trunks, err = splitAndTransformDelimiter(statement)
+	}
 	if err != nil {
 		return 0, err
 	}
+	// The progress of the resumable execution is tracked by trunk, and the trunks are the statements for the execution with logs.
+	skippedCount := db.GetSkippedStatementCount(ctx)
+	if skippedCount > len(trunks) {
+		return 0, errors.Errorf("cannot skip %d statements as there are only %d statements", skippedCount, len(trunks))
+	}
 
 	tx, err := driver.migrationConn.BeginTx(ctx, nil)
 	if err != nil {
@@ -185,10 +199,42 @@ func (driver *Driver) Execute(ctx context.Context, statement string, _ bool) (in
 	defer tx.Rollback()
 
 	var totalRowsAffected int64
-	for _, trunk := range trunks {
+	for i := skippedCount; i < len(trunks); i++ {
+		trunk := trunks[i]
+		if logger != nil {
+			logger(&db.ExecuteLog{
+				Type:           db.ExecuteLogStatementStart,
+				StatementIndex: i + 1,
+				StatementCount: len(trunks),
+				Statement:      db.TruncateExecuteLogStatement(trunk),
+			})
+		}
+		startTime := time.Now()
 		sqlResult, err := tx.ExecContext(ctx, trunk)
 		if err != nil {
-			return 0, err
+			if logger != nil {
+				logger(&db.ExecuteLog{
+					Type:           db.ExecuteLogStatementEnd,
+					StatementIndex: i + 1,
+					StatementCount: len(trunks),
+					DurationMs:     time.Since(startTime).Milliseconds(),
+					Error:          err.Error(),
+				})
+			}
+			if !db.IsResumableExecution(ctx) {
+				return 0, err
+			}
+			// MySQL commits DDL implicitly, so the database may be partially migrated anyway.
+			// We commit the trunks before the failed one as well to make the applied trunks exactly the ones before the failed trunk.
+			if commitErr := tx.Commit(); commitErr != nil {
+				return 0, multierr.Append(err, commitErr)
+			}
+			return 0, &db.ExecuteStatementError{
+				Index:     i + 1,
+				Count:     len(trunks),
+				Statement: db.TruncateExecuteLogStatement(trunk),
+				Err:       err,
+			}
 		}
 		rowsAffected, err := sqlResult.RowsAffected()
 		if err != nil {
@@ -196,6 +242,18 @@ func (driver *Driver) Execute(ctx context.Context, statement string, _ bool) (in
 			log.Debug("rowsAffected returns error", zap.Error(err))
 		}
 		totalRowsAffected += rowsAffected
+		if logger != nil {
+			logger(&db.ExecuteLog{
+				Type:           db.ExecuteLogStatementEnd,
+				StatementIndex: i + 1,
+				StatementCount: len(trunks),
+				AffectedRows:   rowsAffected,
+				DurationMs:     time.Since(startTime).Milliseconds(),
+			})
+			if err := logWarnings(ctx, tx, logger); err != nil {
+				log.Debug("failed to log warnings", zap.Error(err))
+			}
+		}
 	}
 
 	if err := tx.Commit(); err != nil {
@@ -205,6 +263,28 @@ func (driver *Driver) Execute(ctx context.Context, statement string, _ bool) (in
 	return totalRowsAffected, nil
 }
 
+// logWarnings emits the warnings of the last statement executed in the transaction to the logger.
+func logWarnings(ctx context.Context, tx *sql.Tx, logger db.ExecuteLogger) error {
+	rows, err := tx.QueryContext(ctx, "SHOW WARNINGS")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var level, message string
+		var code int
+		if err := rows.Scan(&level, &code, &message); err != nil {
+			return err
+		}
+		logger(&db.ExecuteLog{
+			Type:    db.ExecuteLogNotice,
+			Level:   level,
+			Message: fmt.Sprintf("%d: %s", code, message),
+		})
+	}
+	return rows.Err()
+}
+
 // GetMigrationConnID gets the ID of the connection executing migrations.
 func (driver *Driver) GetMigrationConnID(ctx context.Context) (string, error) {
 	var id string
@@ -245,12 +325,35 @@ func splitAndTransformDelimiter(statement string) ([]string, error) {
 	var trunks []string
 
 	var out bytes.Buffer
-	statements, err := bbparser.SplitMultiSQL(bbparser.MySQL, statement)
+	statements, err := transformDelimiter(statement)
+	if err != nil {
+		return nil, err
+	}
+	for _, stmt := range statements {
+		if _, err = out.Write([]byte(stmt)); err != nil {
+			return nil, errors.Wrapf(err, "failed to write SQL statement")
+		}
+
+		if out.Len() > querySize {
+			trunks = append(trunks, out.String())
+			out.Reset()
+		}
+	}
+	if out.Len() > 0 {
+		trunks = append(trunks, out.String())
+	}
+	return trunks, nil
+}
+
+// transformDelimiter splits the statement and transforms the delimiter of each statement to the MySQL default delimiter.
+func transformDelimiter(statement string) ([]string, error) {
+	var statements []string
+	singleSQLs, err := bbparser.SplitMultiSQL(bbparser.MySQL, statement)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to split SQL statements")
 	}
 	delimiter := `;`
-	for _, singleSQL := range statements {
+	for _, singleSQL := range singleSQLs {
 		stmt := singleSQL.Text
 		if bbparser.IsDelimiter(stmt) {
 			delimiter, err = bbparser.ExtractDelimiter(stmt)
@@ -263,17 +366,7 @@ func splitAndTransformDelimiter(statement string) ([]string, error) {
 			// Trim delimiter
 			stmt = fmt.Sprintf("%s;", stmt[:len(stmt)-len(delimiter)])
 		}
-		if _, err = out.Write([]byte(stmt)); err != nil {
-			return nil, errors.Wrapf(err, "failed to write SQL statement")
-		}
-
-		if out.Len() > querySize {
-			trunks = append(trunks, out.String())
-			out.Reset()
-		}
-	}
-	if out.Len() > 0 {
-		trunks = append(trunks, out.String())
+		statements = append(statements, stmt)
 	}
-	return trunks, nil
+	return statements, nil
 }
@@ -105,3 +105,23 @@ func TestTransformDelimiter_Truncate(t *testing.T) {
 	// Make sure all trunks add up.
 	require.Equal(t, total, len(statements))
 }
+
+func TestTransformDelimiter_Split(t *testing.T) {
+	var out bytes.Buffer
+	for i := 0; i < 200000; i++ {
+		_, err := out.WriteString("INSERT INTO hello VALUES (555555555555555555555555555555555555555555555555555555555555555555555555555555555555555555555555);")
+		require.NoError(t, err)
+	}
+	statements := out.String()
+
+	got, err := transformDelimiter(statements)
+	require.NoError(t, err)
+
+	total := 0
+	for _, stmt := range got {
+		total += len(stmt)
+	}
+	require.Equal(t, 200000, len(got))
+	// Make sure all statements add up.
+	require.Equal(t, total, len(statements))
+}
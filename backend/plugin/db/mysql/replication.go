@@ -0,0 +1,56 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// GetReplicationLag returns the replication lag of the replica connected by db.
+// It returns 0 if the server isn't a replica, and false if the replication is stopped so that the lag is unknown.
+func GetReplicationLag(ctx context.Context, db *sql.DB) (time.Duration, bool, error) {
+	query := "SHOW SLAVE STATUS"
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return 0, false, errors.Wrapf(err, "cannot execute %q query", query)
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, false, errors.Wrapf(err, "cannot get columns from %q query", query)
+	}
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return 0, false, errors.Wrapf(err, "cannot scan row from %q query", query)
+		}
+		// SHOW SLAVE STATUS returns empty row if the server isn't a replica.
+		return 0, true, nil
+	}
+	var lag sql.NullInt64
+	var unused interface{}
+	cols := make([]interface{}, len(columns))
+	foundLag := false
+	// The columns vary in MySQL versions, so we dynamically scan the columns like SHOW MASTER STATUS.
+	for i := 0; i < len(columns); i++ {
+		if columns[i] == "Seconds_Behind_Master" {
+			cols[i] = &lag
+			foundLag = true
+		} else {
+			cols[i] = &unused
+		}
+	}
+	if !foundLag {
+		return 0, false, errors.Errorf("cannot find Seconds_Behind_Master column from %q query", query)
+	}
+	if err := rows.Scan(cols...); err != nil {
+		return 0, false, errors.Wrapf(err, "cannot scan row from %q query", query)
+	}
+	// Seconds_Behind_Master is NULL if the replication threads aren't running.
+	if !lag.Valid {
+		return 0, false, nil
+	}
+	return time.Duration(lag.Int64) * time.Second, true, nil
+}
@@ -241,6 +241,19 @@ func (*Driver) UpdateHistoryAsFailed(ctx context.Context, tx *sql.Tx, migrationD
 	return err
 }
 
+// UpdateHistoryPayload will update the payload of the migration record.
+func (*Driver) UpdateHistoryPayload(ctx context.Context, tx *sql.Tx, payload string, insertedID string) error {
+	const updateHistoryPayloadQuery = `
+	UPDATE
+		migration_history
+	SET
+		payload = $1
+	WHERE id = $2
+	`
+	_, err := tx.ExecContext(ctx, updateHistoryPayloadQuery, payload, insertedID)
+	return err
+}
+
 // ExecuteMigration will execute the migration.
 func (driver *Driver) ExecuteMigration(ctx context.Context, m *db.MigrationInfo, statement string) (string, string, error) {
 	if driver.strictUseDb() {
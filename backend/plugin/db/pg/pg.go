@@ -6,12 +6,16 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"sync"
+	"time"
 
 	// Import pg driver.
 	// init() in pgx/v5/stdlib will register it's pgx driver.
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/stdlib"
 	"github.com/pkg/errors"
+	"go.uber.org/multierr"
 	"go.uber.org/zap"
 
 	"github.com/bytebase/bytebase/backend/common"
@@ -65,6 +69,10 @@ type Driver struct {
 
 	// strictDatabase should be used only if the user gives only a database instead of a whole instance to access.
 	strictDatabase string
+
+	// executeLogger receives the notices raised by the server while executing statements.
+	executeLoggerMu sync.Mutex
+	executeLogger   db.ExecuteLogger
 }
 
 func newDriver(config db.DriverConfig) db.Driver {
@@ -110,7 +118,7 @@ func (driver *Driver) Open(_ context.Context, _ db.Type, config db.ConnectionCon
 		driver.strictDatabase = config.Database
 	}
 
-	connectionString, err := registerConnectionConfig(dsn, driver.config.TLSConfig)
+	connectionString, err := registerConnectionConfig(dsn, driver.config.TLSConfig, driver.onNotice)
 	if err != nil {
 		return nil, err
 	}
@@ -124,11 +132,12 @@ func (driver *Driver) Open(_ context.Context, _ db.Type, config db.ConnectionCon
 	return driver, nil
 }
 
-func registerConnectionConfig(dsn string, tlsConfig db.TLSConfig) (string, error) {
+func registerConnectionConfig(dsn string, tlsConfig db.TLSConfig, onNotice pgconn.NoticeHandler) (string, error) {
 	connConfig, err := pgx.ParseConfig(dsn)
 	if err != nil {
 		return "", err
 	}
+	connConfig.OnNotice = onNotice
 
 	if tlsConfig.SslCA != "" {
 		sslConfig, err := tlsConfig.GetSslConfig()
@@ -145,6 +154,26 @@ func unregisterConnectionConfig(connectionString string) {
 	stdlib.UnregisterConnConfig(connectionString)
 }
 
+// onNotice forwards the notices, e.g. RAISE NOTICE, to the execute logger of the running Execute().
+func (driver *Driver) onNotice(_ *pgconn.PgConn, notice *pgconn.Notice) {
+	driver.executeLoggerMu.Lock()
+	defer driver.executeLoggerMu.Unlock()
+	if driver.executeLogger == nil {
+		return
+	}
+	driver.executeLogger(&db.ExecuteLog{
+		Type:    db.ExecuteLogNotice,
+		Level:   notice.Severity,
+		Message: notice.Message,
+	})
+}
+
+func (driver *Driver) setExecuteLogger(logger db.ExecuteLogger) {
+	driver.executeLoggerMu.Lock()
+	defer driver.executeLoggerMu.Unlock()
+	driver.executeLogger = logger
+}
+
 // guessDSN will guess a valid DB connection and its database name.
 func guessDSN(username, password, hostname, port, database, sslCA, sslCert, sslKey string) (string, string, error) {
 	// dbname is guessed if not specified.
@@ -183,7 +212,7 @@ func guessDSN(username, password, hostname, port, database, sslCA, sslCert, sslK
 	for _, guessDatabase := range guesses {
 		guessDSN := fmt.Sprintf("%s dbname=%s", dsn, guessDatabase)
 		if err := func() error {
-			connectionString, err := registerConnectionConfig(guessDSN, tlsConfig)
+			connectionString, err := registerConnectionConfig(guessDSN, tlsConfig, nil)
 			if err != nil {
 				return err
 			}
@@ -264,86 +293,26 @@ func (driver *Driver) getVersion(ctx context.Context) (string, error) {
 
 // Execute executes a SQL statement.
 func (driver *Driver) Execute(ctx context.Context, statement string, createDatabase bool) (int64, error) {
-	owner, err := driver.GetCurrentDatabaseOwner()
-	if err != nil {
-		return 0, err
+	logger := db.GetExecuteLogger(ctx)
+	if logger != nil {
+		driver.setExecuteLogger(logger)
+		defer driver.setExecuteLogger(nil)
 	}
 
 	connected := false
+	var nonTransactionalStmts []string
 	var remainingStmts []string
-	totalRowsAffected := int64(0)
 	f := func(stmt string) error {
 		// We don't use transaction for creating / altering databases in Postgres.
 		// We will execute the statement directly before "\\connect" statement.
 		// https://github.com/bytebase/bytebase/issues/202
 		if createDatabase && !connected {
-			if strings.HasPrefix(stmt, "CREATE DATABASE ") {
-				databases, err := driver.getDatabases(ctx)
-				if err != nil {
-					return err
-				}
-				databaseName, err := getDatabaseInCreateDatabaseStatement(stmt)
-				if err != nil {
-					return err
-				}
-				exist := false
-				for _, database := range databases {
-					if database.Name == databaseName {
-						exist = true
-						break
-					}
-				}
-				if !exist {
-					if _, err := driver.db.ExecContext(ctx, stmt); err != nil {
-						return err
-					}
-				}
-			} else if strings.HasPrefix(stmt, "ALTER DATABASE") && strings.Contains(stmt, " OWNER TO ") {
-				if _, err := driver.db.ExecContext(ctx, stmt); err != nil {
-					return err
-				}
-			} else if strings.HasPrefix(stmt, "\\connect ") {
-				// For the case of `\connect "dbname";`, we need to use GetDBConnection() instead of executing the statement.
-				parts := strings.Split(stmt, `"`)
-				if len(parts) != 3 {
-					return errors.Errorf("invalid statement %q", stmt)
-				}
-				if _, err = driver.GetDBConnection(ctx, parts[1]); err != nil {
-					return err
-				}
-				// Update current owner
-				if owner, err = driver.GetCurrentDatabaseOwner(); err != nil {
-					return err
-				}
+			if strings.HasPrefix(stmt, "\\connect ") {
 				connected = true
-			} else {
-				sqlResult, err := driver.db.ExecContext(ctx, stmt)
-				if err != nil {
-					return err
-				}
-				rowsAffected, err := sqlResult.RowsAffected()
-				if err != nil {
-					// Since we cannot differentiate DDL and DML yet, we have to ignore the error.
-					log.Debug("rowsAffected returns error", zap.Error(err))
-				} else {
-					totalRowsAffected += rowsAffected
-				}
-			}
-		} else {
-			if isSuperuserStatement(stmt) {
-				// CREATE EVENT TRIGGER statement only supports EXECUTE PROCEDURE in version 10 and before, while newer version supports both EXECUTE { FUNCTION | PROCEDURE }.
-				// Since we use pg_dump version 14, the dump uses a new style even for an old version of PostgreSQL.
-				// We should convert EXECUTE FUNCTION to EXECUTE PROCEDURE to make the restoration work on old versions.
-				// https://www.postgresql.org/docs/14/sql-createeventtrigger.html
-				if strings.Contains(strings.ToUpper(stmt), "CREATE EVENT TRIGGER") {
-					stmt = strings.ReplaceAll(stmt, "EXECUTE FUNCTION", "EXECUTE PROCEDURE")
-				}
-				// Use superuser privilege to run privileged statements.
-				stmt = fmt.Sprintf("SET LOCAL ROLE NONE;%sSET LOCAL ROLE \"%s\";", stmt, owner)
-				remainingStmts = append(remainingStmts, stmt)
-			} else if !isIgnoredStatement(stmt) {
-				remainingStmts = append(remainingStmts, stmt)
 			}
+			nonTransactionalStmts = append(nonTransactionalStmts, stmt)
+		} else if !isIgnoredStatement(stmt) {
+			remainingStmts = append(remainingStmts, stmt)
 		}
 		return nil
 	}
@@ -352,8 +321,59 @@ func (driver *Driver) Execute(ctx context.Context, statement string, createDatab
 		return 0, err
 	}
 
-	if len(remainingStmts) == 0 {
-		return 0, nil
+	// The non-transactional statements are counted in the statements as well, so that they are skipped on resumption.
+	statementCount := len(nonTransactionalStmts) + len(remainingStmts)
+	skippedCount := db.GetSkippedStatementCount(ctx)
+	if skippedCount > statementCount {
+		return 0, errors.Errorf("cannot skip %d statements as there are only %d statements", skippedCount, statementCount)
+	}
+	resumable := db.IsResumableExecution(ctx)
+
+	totalRowsAffected := int64(0)
+	for i, stmt := range nonTransactionalStmts {
+		// The connection is switched again for the skipped statements after it.
+		if i < skippedCount && !strings.HasPrefix(stmt, "\\connect ") {
+			continue
+		}
+		rowsAffected, err := driver.executeNonTransactionalStatement(ctx, stmt)
+		if err != nil {
+			if resumable {
+				return 0, &db.ExecuteStatementError{
+					Index:     i + 1,
+					Count:     statementCount,
+					Statement: db.TruncateExecuteLogStatement(stmt),
+					Err:       err,
+				}
+			}
+			return 0, err
+		}
+		totalRowsAffected += rowsAffected
+	}
+
+	remainingSkippedCount := 0
+	if skippedCount > len(nonTransactionalStmts) {
+		remainingSkippedCount = skippedCount - len(nonTransactionalStmts)
+	}
+	if len(remainingStmts) == remainingSkippedCount {
+		return totalRowsAffected, nil
+	}
+
+	owner, err := driver.GetCurrentDatabaseOwner()
+	if err != nil {
+		return 0, err
+	}
+	for i, stmt := range remainingStmts {
+		if isSuperuserStatement(stmt) {
+			// CREATE EVENT TRIGGER statement only supports EXECUTE PROCEDURE in version 10 and before, while newer version supports both EXECUTE { FUNCTION | PROCEDURE }.
+			// Since we use pg_dump version 14, the dump uses a new style even for an old version of PostgreSQL.
+			// We should convert EXECUTE FUNCTION to EXECUTE PROCEDURE to make the restoration work on old versions.
+			// https://www.postgresql.org/docs/14/sql-createeventtrigger.html
+			if strings.Contains(strings.ToUpper(stmt), "CREATE EVENT TRIGGER") {
+				stmt = strings.ReplaceAll(stmt, "EXECUTE FUNCTION", "EXECUTE PROCEDURE")
+			}
+			// Use superuser privilege to run privileged statements.
+			remainingStmts[i] = fmt.Sprintf("SET LOCAL ROLE NONE;%sSET LOCAL ROLE \"%s\";", stmt, owner)
+		}
 	}
 
 	tx, err := driver.db.BeginTx(ctx, nil)
@@ -367,6 +387,37 @@ func (driver *Driver) Execute(ctx context.Context, statement string, createDatab
 		return 0, err
 	}
 
+	if resumable {
+		rowsAffected, err := executeStatementsWithSavepoints(ctx, tx, remainingStmts, len(nonTransactionalStmts), remainingSkippedCount, logger)
+		if err != nil {
+			var statementErr *db.ExecuteStatementError
+			if !errors.As(err, &statementErr) {
+				return 0, err
+			}
+			// The failed statement is rolled back to its savepoint, and we commit the statements before it so that the execution can be resumed from the failed statement.
+			if commitErr := tx.Commit(); commitErr != nil {
+				return 0, multierr.Append(err, commitErr)
+			}
+			return 0, err
+		}
+		if err := tx.Commit(); err != nil {
+			return 0, err
+		}
+		return totalRowsAffected + rowsAffected, nil
+	}
+
+	if logger != nil {
+		// Execute the statements one by one so that each of them gets its own logs.
+		rowsAffected, err := executeStatementsWithLogs(ctx, tx, remainingStmts, logger)
+		if err != nil {
+			return 0, err
+		}
+		if err := tx.Commit(); err != nil {
+			return 0, err
+		}
+		return totalRowsAffected + rowsAffected, nil
+	}
+
 	sqlResult, err := tx.ExecContext(ctx, strings.Join(remainingStmts, "\n"))
 	if err != nil {
 		return 0, err
@@ -384,6 +435,163 @@ func (driver *Driver) Execute(ctx context.Context, statement string, createDatab
 	return totalRowsAffected, nil
 }
 
+// executeNonTransactionalStatement executes the statement before the "\connect" statement out of the transaction.
+func (driver *Driver) executeNonTransactionalStatement(ctx context.Context, stmt string) (int64, error) {
+	if strings.HasPrefix(stmt, "CREATE DATABASE ") {
+		databases, err := driver.getDatabases(ctx)
+		if err != nil {
+			return 0, err
+		}
+		databaseName, err := getDatabaseInCreateDatabaseStatement(stmt)
+		if err != nil {
+			return 0, err
+		}
+		for _, database := range databases {
+			if database.Name == databaseName {
+				return 0, nil
+			}
+		}
+		if _, err := driver.db.ExecContext(ctx, stmt); err != nil {
+			return 0, err
+		}
+		return 0, nil
+	}
+	if strings.HasPrefix(stmt, "ALTER DATABASE") && strings.Contains(stmt, " OWNER TO ") {
+		if _, err := driver.db.ExecContext(ctx, stmt); err != nil {
+			return 0, err
+		}
+		return 0, nil
+	}
+	if strings.HasPrefix(stmt, "\\connect ") {
+		// For the case of `\connect "dbname";`, we need to use GetDBConnection() instead of executing the statement.
+		parts := strings.Split(stmt, `"`)
+		if len(parts) != 3 {
+			return 0, errors.Errorf("invalid statement %q", stmt)
+		}
+		if _, err := driver.GetDBConnection(ctx, parts[1]); err != nil {
+			return 0, err
+		}
+		return 0, nil
+	}
+	sqlResult, err := driver.db.ExecContext(ctx, stmt)
+	if err != nil {
+		return 0, err
+	}
+	rowsAffected, err := sqlResult.RowsAffected()
+	if err != nil {
+		// Since we cannot differentiate DDL and DML yet, we have to ignore the error.
+		log.Debug("rowsAffected returns error", zap.Error(err))
+		return 0, nil
+	}
+	return rowsAffected, nil
+}
+
+// executeStatementsWithLogs executes the statements one by one in the transaction, and emits the start and end logs of each statement.
+func executeStatementsWithLogs(ctx context.Context, tx *sql.Tx, statements []string, logger db.ExecuteLogger) (int64, error) {
+	var totalRowsAffected int64
+	for i, stmt := range statements {
+		logger(&db.ExecuteLog{
+			Type:           db.ExecuteLogStatementStart,
+			StatementIndex: i + 1,
+			StatementCount: len(statements),
+			Statement:      db.TruncateExecuteLogStatement(stmt),
+		})
+		startTime := time.Now()
+		sqlResult, err := tx.ExecContext(ctx, stmt)
+		if err != nil {
+			logger(&db.ExecuteLog{
+				Type:           db.ExecuteLogStatementEnd,
+				StatementIndex: i + 1,
+				StatementCount: len(statements),
+				DurationMs:     time.Since(startTime).Milliseconds(),
+				Error:          err.Error(),
+			})
+			return 0, err
+		}
+		rowsAffected, err := sqlResult.RowsAffected()
+		if err != nil {
+			// Since we cannot differentiate DDL and DML yet, we have to ignore the error.
+			log.Debug("rowsAffected returns error", zap.Error(err))
+		}
+		totalRowsAffected += rowsAffected
+		logger(&db.ExecuteLog{
+			Type:           db.ExecuteLogStatementEnd,
+			StatementIndex: i + 1,
+			StatementCount: len(statements),
+			AffectedRows:   rowsAffected,
+			DurationMs:     time.Since(startTime).Milliseconds(),
+		})
+	}
+	return totalRowsAffected, nil
+}
+
+// savepointName is the savepoint set before each statement executed in the transaction.
+const savepointName = "bytebase_statement"
+
+// executeStatementsWithSavepoints executes the statements after the skipped ones one by one in the transaction for the resumable execution.
+// The offset is the number of the statements executed out of the transaction before these statements, which are counted in the statement index.
+// Each statement runs after a savepoint, and the failed statement is rolled back to the savepoint with an ExecuteStatementError returned.
+func executeStatementsWithSavepoints(ctx context.Context, tx *sql.Tx, statements []string, offset int, skippedCount int, logger db.ExecuteLogger) (int64, error) {
+	var totalRowsAffected int64
+	statementCount := offset + len(statements)
+	for i := skippedCount; i < len(statements); i++ {
+		stmt := statements[i]
+		statementIndex := offset + i + 1
+		if logger != nil {
+			logger(&db.ExecuteLog{
+				Type:           db.ExecuteLogStatementStart,
+				StatementIndex: statementIndex,
+				StatementCount: statementCount,
+				Statement:      db.TruncateExecuteLogStatement(stmt),
+			})
+		}
+		if _, err := tx.ExecContext(ctx, "SAVEPOINT "+savepointName); err != nil {
+			return 0, err
+		}
+		startTime := time.Now()
+		sqlResult, err := tx.ExecContext(ctx, stmt)
+		if err != nil {
+			if logger != nil {
+				logger(&db.ExecuteLog{
+					Type:           db.ExecuteLogStatementEnd,
+					StatementIndex: statementIndex,
+					StatementCount: statementCount,
+					DurationMs:     time.Since(startTime).Milliseconds(),
+					Error:          err.Error(),
+				})
+			}
+			if _, rollbackErr := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+savepointName); rollbackErr != nil {
+				return 0, multierr.Append(err, rollbackErr)
+			}
+			return 0, &db.ExecuteStatementError{
+				Index:     statementIndex,
+				Count:     statementCount,
+				Statement: db.TruncateExecuteLogStatement(stmt),
+				Err:       err,
+			}
+		}
+		if _, err := tx.ExecContext(ctx, "RELEASE SAVEPOINT "+savepointName); err != nil {
+			return 0, err
+		}
+		rowsAffected, err := sqlResult.RowsAffected()
+		if err != nil {
+			// Since we cannot differentiate DDL and DML yet, we have to ignore the error.
+			log.Debug("rowsAffected returns error", zap.Error(err))
+		}
+		totalRowsAffected += rowsAffected
+		if logger != nil {
+			logger(&db.ExecuteLog{
+				Type:           db.ExecuteLogStatementEnd,
+				StatementIndex: statementIndex,
+				StatementCount: statementCount,
+				AffectedRows:   rowsAffected,
+				DurationMs:     time.Since(startTime).Milliseconds(),
+			})
+		}
+	}
+	return totalRowsAffected, nil
+}
+
 func isSuperuserStatement(stmt string) bool {
 	upperCaseStmt := strings.ToUpper(stmt)
 	if strings.HasPrefix(upperCaseStmt, "GRANT") || strings.HasPrefix(upperCaseStmt, "CREATE EXTENSION") || strings.HasPrefix(upperCaseStmt, "CREATE EVENT TRIGGER") || strings.HasPrefix(upperCaseStmt, "COMMENT ON EVENT TRIGGER") {
@@ -479,7 +687,7 @@ func (driver *Driver) switchDatabase(dbName string) error {
 
 	dsn := driver.baseDSN + " dbname=" + dbName
 
-	connectionString, err := registerConnectionConfig(dsn, driver.config.TLSConfig)
+	connectionString, err := registerConnectionConfig(dsn, driver.config.TLSConfig, driver.onNotice)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,21 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// GetReplicationLag returns the max replay lag of the standby servers connected to the primary server connected by db.
+// It returns 0 if there is no standby server.
+func GetReplicationLag(ctx context.Context, db *sql.DB) (time.Duration, error) {
+	// The replay_lag is NULL if the standby is idle and fully caught up.
+	query := "SELECT COALESCE(EXTRACT(EPOCH FROM MAX(replay_lag)), 0) FROM pg_stat_replication"
+	var lagSeconds float64
+	if err := db.QueryRowContext(ctx, query).Scan(&lagSeconds); err != nil {
+		return 0, errors.Wrapf(err, "cannot execute %q query", query)
+	}
+	return time.Duration(lagSeconds * float64(time.Second)), nil
+}
@@ -6,6 +6,7 @@ import (
 	"bytes"
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"io"
 	"math/big"
@@ -153,6 +154,8 @@ type MigrationExecutor interface {
 	UpdateHistoryAsDone(ctx context.Context, tx *sql.Tx, migrationDurationNs int64, updatedSchema string, insertedID string) error
 	// UpdateHistoryAsFailed will update the migration record as failed.
 	UpdateHistoryAsFailed(ctx context.Context, tx *sql.Tx, migrationDurationNs int64, insertedID string) error
+	// UpdateHistoryPayload will update the payload of the migration record.
+	UpdateHistoryPayload(ctx context.Context, tx *sql.Tx, payload string, insertedID string) error
 }
 
 // ExecuteMigration will execute the database migration.
@@ -181,6 +184,15 @@ func ExecuteMigration(ctx context.Context, executor MigrationExecutor, m *db.Mig
 	startedNs := time.Now().UnixNano()
 
 	defer func() {
+		var statementErr *db.ExecuteStatementError
+		if errors.As(resErr, &statementErr) {
+			if err := recordAppliedStatementCount(ctx, executor, m, insertedID, databaseName, statementErr.AppliedStatementCount()); err != nil {
+				log.Error("Failed to record the applied statements in migration history record",
+					zap.Error(err),
+					zap.String("migration_id", insertedID),
+				)
+			}
+		}
 		if err := EndMigration(ctx, executor, startedNs, insertedID, updatedSchema, databaseName, resErr == nil /*isDone*/); err != nil {
 			log.Error("Failed to update migration history record",
 				zap.Error(err),
@@ -208,7 +220,18 @@ func ExecuteMigration(ctx context.Context, executor MigrationExecutor, m *db.Mig
 				return "", "", err
 			}
 		}
-		if _, err := executor.Execute(ctx, statement, m.CreateDatabase); err != nil {
+		executeCtx := ctx
+		if m.ResumableExecution {
+			executeCtx = db.WithResumableExecution(executeCtx)
+		}
+		if m.Resume {
+			appliedCount, err := getAppliedStatementCount(ctx, executor, insertedID, statement)
+			if err != nil {
+				return "", "", err
+			}
+			executeCtx = db.WithSkippedStatementCount(executeCtx, appliedCount)
+		}
+		if _, err := executor.Execute(executeCtx, statement, m.CreateDatabase); err != nil {
 			return "", "", FormatError(err)
 		}
 	}
@@ -321,6 +344,66 @@ func BeginMigration(ctx context.Context, executor MigrationExecutor, m *db.Migra
 	return insertedID, nil
 }
 
+// getAppliedStatementCount returns the number of the statements applied by the FAILED migration history record.
+// It returns 0 if the record is not a FAILED one for the same statement, e.g. a new PENDING record.
+func getAppliedStatementCount(ctx context.Context, executor MigrationExecutor, migrationHistoryID string, statement string) (int, error) {
+	list, err := executor.FindMigrationHistoryList(ctx, &db.MigrationHistoryFind{ID: &migrationHistoryID})
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to find the migration history to resume")
+	}
+	if len(list) == 0 {
+		return 0, errors.Errorf("migration history %s not found", migrationHistoryID)
+	}
+	history := list[0]
+	if history.Status != db.Failed || history.Payload == "" {
+		return 0, nil
+	}
+	// The applied statements are meaningless if the statement has been changed since the failure.
+	if statementRecord, _ := common.TruncateString(statement, common.MaxSheetSize); history.Statement != statementRecord {
+		return 0, nil
+	}
+	payload := &db.MigrationInfoPayload{}
+	if err := json.Unmarshal([]byte(history.Payload), payload); err != nil {
+		return 0, errors.Wrapf(err, "failed to unmarshal the payload of migration history %s", migrationHistoryID)
+	}
+	return payload.AppliedStatementCount, nil
+}
+
+// recordAppliedStatementCount records the number of the statements applied by the failed migration in the migration history record.
+func recordAppliedStatementCount(ctx context.Context, executor MigrationExecutor, m *db.MigrationInfo, migrationHistoryID string, databaseName string, appliedCount int) error {
+	payload := &db.MigrationInfoPayload{}
+	if m.Payload != "" {
+		if err := json.Unmarshal([]byte(m.Payload), payload); err != nil {
+			return errors.Wrap(err, "failed to unmarshal the migration payload")
+		}
+	}
+	payload.AppliedStatementCount = appliedCount
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal the migration payload")
+	}
+
+	var tx *sql.Tx
+	if executor.GetType() != db.MongoDB {
+		sqldb, err := executor.GetDBConnection(ctx, databaseName)
+		if err != nil {
+			return err
+		}
+		tx, err = sqldb.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+	}
+	if err := executor.UpdateHistoryPayload(ctx, tx, string(payloadBytes), migrationHistoryID); err != nil {
+		return err
+	}
+	if executor.GetType() != db.MongoDB {
+		return tx.Commit()
+	}
+	return nil
+}
+
 // EndMigration updates the migration history record to DONE or FAILED depending on migration is done or not.
 func EndMigration(ctx context.Context, executor MigrationExecutor, startedNs int64, migrationHistoryID string, updatedSchema string, databaseName string, isDone bool) (err error) {
 	migrationDurationNs := time.Now().UnixNano() - startedNs
@@ -924,3 +1007,29 @@ func readRowsForClickhouse(rows *sql.Rows, columnTypes []*sql.ColumnType, column
 
 	return data, nil
 }
+
+// ValidateSQLSelectStatement returns true if the statement is a single SELECT, EXPLAIN or WITH query.
+func ValidateSQLSelectStatement(sqlStatement string) bool {
+	// Check if the query has only one statement.
+	count := 0
+	if err := ApplyMultiStatements(strings.NewReader(sqlStatement), func(_ string) error {
+		count++
+		return nil
+	}); err != nil {
+		return false
+	}
+	if count != 1 {
+		return false
+	}
+
+	// Allow SELECT and EXPLAIN queries only.
+	whiteListRegs := []string{`^SELECT\s+?`, `^EXPLAIN\s+?`, `^WITH\s+?`}
+	formattedStr := strings.ToUpper(strings.TrimSpace(sqlStatement))
+	for _, reg := range whiteListRegs {
+		matchResult, _ := regexp.MatchString(reg, formattedStr)
+		if matchResult {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,343 @@
+package parser
+
+import (
+	"strconv"
+	"strings"
+
+	pgquery "github.com/pganalyze/pg_query_go/v2"
+	tidbast "github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/format"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/opcode"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	// The parser driver is required to create the value expressions of the MySQL statements.
+	_ "github.com/pingcap/tidb/types/parser_driver"
+)
+
+// ChunkDML is a single-table UPDATE or DELETE statement which can be executed in chunks ranged by the primary key.
+type ChunkDML struct {
+	// Schema is the database name for MySQL or the schema name for Postgres, it's empty if the table isn't qualified.
+	Schema string
+	Table  string
+
+	engineType EngineType
+	// assignedColumns is the lower-case names of the columns assigned by the UPDATE statement.
+	assignedColumns map[string]bool
+	// mysqlNode is either *tidbast.UpdateStmt or *tidbast.DeleteStmt.
+	mysqlNode tidbast.DMLNode
+	pgTree    *pgquery.ParseResult
+}
+
+// ParseChunkDML parses the single-table UPDATE or DELETE statement for the chunked execution.
+// The statements with ORDER BY, LIMIT, joins or common table expressions are not supported.
+// The chunk interrupted before its checkpoint is saved is executed again on resumption, so the statement must be idempotent,
+// and the UPDATE statements assigning a column from the columns it assigns are not supported.
+func ParseChunkDML(engineType EngineType, statement string) (*ChunkDML, error) {
+	switch engineType {
+	case MySQL, TiDB:
+		return parseMySQLChunkDML(statement)
+	case Postgres:
+		return parsePostgresChunkDML(statement)
+	default:
+		return nil, errors.Errorf("engine type is not supported: %s", engineType)
+	}
+}
+
+func parseMySQLChunkDML(statement string) (*ChunkDML, error) {
+	nodeList, _, err := newMySQLParser().Parse(statement, "", "")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse statement %q", statement)
+	}
+	if len(nodeList) != 1 {
+		return nil, errors.Errorf("expect one statement but found %d", len(nodeList))
+	}
+	var tableRefs *tidbast.TableRefsClause
+	assignedColumns := make(map[string]bool)
+	switch node := nodeList[0].(type) {
+	case *tidbast.DeleteStmt:
+		if node.IsMultiTable || node.Order != nil || node.Limit != nil || node.With != nil {
+			return nil, errors.Errorf("only single-table DELETE without ORDER BY or LIMIT can be chunked: %q", statement)
+		}
+		tableRefs = node.TableRefs
+	case *tidbast.UpdateStmt:
+		if node.MultipleTable || node.Order != nil || node.Limit != nil || node.With != nil {
+			return nil, errors.Errorf("only single-table UPDATE without ORDER BY or LIMIT can be chunked: %q", statement)
+		}
+		tableRefs = node.TableRefs
+		for _, assignment := range node.List {
+			assignedColumns[assignment.Column.Name.L] = true
+		}
+		for _, assignment := range node.List {
+			collector := &columnNameCollector{columnNames: make(map[string]bool)}
+			assignment.Expr.Accept(collector)
+			for column := range collector.columnNames {
+				if assignedColumns[column] {
+					return nil, errors.Errorf("UPDATE assigning a column from the assigned column %q can't be chunked, since it's not idempotent: %q", column, statement)
+				}
+			}
+		}
+	default:
+		return nil, errors.Errorf("only UPDATE and DELETE statements can be chunked: %q", statement)
+	}
+	if tableRefs == nil || tableRefs.TableRefs == nil || tableRefs.TableRefs.Right != nil {
+		return nil, errors.Errorf("only single-table statements can be chunked: %q", statement)
+	}
+	source, ok := tableRefs.TableRefs.Left.(*tidbast.TableSource)
+	if !ok {
+		return nil, errors.Errorf("only single-table statements can be chunked: %q", statement)
+	}
+	table, ok := source.Source.(*tidbast.TableName)
+	if !ok {
+		return nil, errors.Errorf("only single-table statements can be chunked: %q", statement)
+	}
+	return &ChunkDML{
+		Schema:          table.Schema.O,
+		Table:           table.Name.O,
+		engineType:      MySQL,
+		assignedColumns: assignedColumns,
+		mysqlNode:       nodeList[0].(tidbast.DMLNode),
+	}, nil
+}
+
+// columnNameCollector collects the lower-case names of the columns referenced in the MySQL expression.
+type columnNameCollector struct {
+	columnNames map[string]bool
+}
+
+func (c *columnNameCollector) Enter(in tidbast.Node) (tidbast.Node, bool) {
+	if column, ok := in.(*tidbast.ColumnNameExpr); ok {
+		c.columnNames[column.Name.Name.L] = true
+	}
+	return in, false
+}
+
+func (*columnNameCollector) Leave(in tidbast.Node) (tidbast.Node, bool) {
+	return in, true
+}
+
+func parsePostgresChunkDML(statement string) (*ChunkDML, error) {
+	tree, err := pgquery.Parse(statement)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse statement %q", statement)
+	}
+	if len(tree.Stmts) != 1 {
+		return nil, errors.Errorf("expect one statement but found %d", len(tree.Stmts))
+	}
+	var relation *pgquery.RangeVar
+	assignedColumns := make(map[string]bool)
+	switch node := tree.Stmts[0].Stmt.Node.(type) {
+	case *pgquery.Node_DeleteStmt:
+		if len(node.DeleteStmt.UsingClause) > 0 || node.DeleteStmt.WithClause != nil {
+			return nil, errors.Errorf("only single-table DELETE without USING or WITH can be chunked: %q", statement)
+		}
+		relation = node.DeleteStmt.Relation
+	case *pgquery.Node_UpdateStmt:
+		if len(node.UpdateStmt.FromClause) > 0 || node.UpdateStmt.WithClause != nil {
+			return nil, errors.Errorf("only single-table UPDATE without FROM or WITH can be chunked: %q", statement)
+		}
+		relation = node.UpdateStmt.Relation
+		for _, target := range node.UpdateStmt.TargetList {
+			if resTarget, ok := target.Node.(*pgquery.Node_ResTarget); ok {
+				assignedColumns[strings.ToLower(resTarget.ResTarget.Name)] = true
+			}
+		}
+		for _, target := range node.UpdateStmt.TargetList {
+			resTarget, ok := target.Node.(*pgquery.Node_ResTarget)
+			if !ok {
+				continue
+			}
+			if resTarget.ResTarget.Val == nil {
+				continue
+			}
+			columnNames := make(map[string]bool)
+			collectPostgresColumnNames(resTarget.ResTarget.Val.ProtoReflect(), columnNames)
+			for column := range columnNames {
+				if assignedColumns[column] {
+					return nil, errors.Errorf("UPDATE assigning a column from the assigned column %q can't be chunked, since it's not idempotent: %q", column, statement)
+				}
+			}
+		}
+	default:
+		return nil, errors.Errorf("only UPDATE and DELETE statements can be chunked: %q", statement)
+	}
+	return &ChunkDML{
+		Schema:          relation.Schemaname,
+		Table:           relation.Relname,
+		engineType:      Postgres,
+		assignedColumns: assignedColumns,
+		pgTree:          tree,
+	}, nil
+}
+
+// collectPostgresColumnNames collects the lower-case names of the columns referenced in the Postgres expression.
+func collectPostgresColumnNames(message protoreflect.Message, columnNames map[string]bool) {
+	if columnRef, ok := message.Interface().(*pgquery.ColumnRef); ok {
+		if len(columnRef.Fields) > 0 {
+			if name, ok := columnRef.Fields[len(columnRef.Fields)-1].Node.(*pgquery.Node_String_); ok {
+				columnNames[strings.ToLower(name.String_.Str)] = true
+			}
+		}
+		return
+	}
+	// Walk through the child nodes of any expression type.
+	message.Range(func(field protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+		if field.Kind() != protoreflect.MessageKind || field.IsMap() {
+			return true
+		}
+		if field.IsList() {
+			list := value.List()
+			for i := 0; i < list.Len(); i++ {
+				collectPostgresColumnNames(list.Get(i).Message(), columnNames)
+			}
+			return true
+		}
+		collectPostgresColumnNames(value.Message(), columnNames)
+		return true
+	})
+}
+
+// CheckChunkColumn returns an error if the UPDATE statement assigns the column by which the statement is chunked,
+// because the rows moved across the chunks would be updated more than once or skipped.
+func (d *ChunkDML) CheckChunkColumn(column string) error {
+	if d.assignedColumns[strings.ToLower(column)] {
+		return errors.Errorf("UPDATE assigning the chunk key column %q can't be chunked", column)
+	}
+	return nil
+}
+
+// ChunkStatement returns the statement limited to the rows whose column value is in the range (lower, upper].
+// The range is unbounded on the side whose bound is nil. The bounds are integers if the column is an integer column, otherwise they're strings.
+func (d *ChunkDML) ChunkStatement(column string, isInteger bool, lower, upper *string) (string, error) {
+	if err := d.CheckChunkColumn(column); err != nil {
+		return "", err
+	}
+	if isInteger {
+		for _, bound := range []*string{lower, upper} {
+			if bound == nil {
+				continue
+			}
+			if _, err := strconv.ParseInt(*bound, 10, 64); err != nil {
+				return "", errors.Errorf("invalid integer bound %q of column %q", *bound, column)
+			}
+		}
+	}
+	switch d.engineType {
+	case MySQL:
+		return d.mysqlChunkStatement(column, isInteger, lower, upper)
+	case Postgres:
+		return d.postgresChunkStatement(column, isInteger, lower, upper)
+	default:
+		return "", errors.Errorf("engine type is not supported: %s", d.engineType)
+	}
+}
+
+func (d *ChunkDML) mysqlChunkStatement(column string, isInteger bool, lower, upper *string) (string, error) {
+	var where *tidbast.ExprNode
+	switch node := d.mysqlNode.(type) {
+	case *tidbast.DeleteStmt:
+		where = &node.Where
+	case *tidbast.UpdateStmt:
+		where = &node.Where
+	}
+	originalWhere := *where
+	defer func() {
+		*where = originalWhere
+	}()
+
+	var conditions []tidbast.ExprNode
+	if originalWhere != nil {
+		conditions = append(conditions, &tidbast.ParenthesesExpr{Expr: originalWhere})
+	}
+	newCondition := func(op opcode.Op, value string) tidbast.ExprNode {
+		return &tidbast.BinaryOperationExpr{
+			Op: op,
+			L:  &tidbast.ColumnNameExpr{Name: &tidbast.ColumnName{Name: model.NewCIStr(column)}},
+			R:  tidbast.NewValueExpr(chunkBoundValue(value, isInteger), "", ""),
+		}
+	}
+	if lower != nil {
+		conditions = append(conditions, newCondition(opcode.GT, *lower))
+	}
+	if upper != nil {
+		conditions = append(conditions, newCondition(opcode.LE, *upper))
+	}
+	var newWhere tidbast.ExprNode
+	for _, condition := range conditions {
+		if newWhere == nil {
+			newWhere = condition
+			continue
+		}
+		newWhere = &tidbast.BinaryOperationExpr{Op: opcode.LogicAnd, L: newWhere, R: condition}
+	}
+	*where = newWhere
+
+	var buf strings.Builder
+	if err := d.mysqlNode.Restore(format.NewRestoreCtx(format.DefaultRestoreFlags|format.RestoreStringWithoutDefaultCharset, &buf)); err != nil {
+		return "", errors.Wrap(err, "failed to restore the chunk statement")
+	}
+	return buf.String(), nil
+}
+
+func (d *ChunkDML) postgresChunkStatement(column string, isInteger bool, lower, upper *string) (string, error) {
+	var where **pgquery.Node
+	switch node := d.pgTree.Stmts[0].Stmt.Node.(type) {
+	case *pgquery.Node_DeleteStmt:
+		where = &node.DeleteStmt.WhereClause
+	case *pgquery.Node_UpdateStmt:
+		where = &node.UpdateStmt.WhereClause
+	}
+	originalWhere := *where
+	defer func() {
+		*where = originalWhere
+	}()
+
+	var conditions []*pgquery.Node
+	if originalWhere != nil {
+		conditions = append(conditions, originalWhere)
+	}
+	newCondition := func(op string, value string) *pgquery.Node {
+		constNode := pgquery.MakeAConstStrNode(value, -1)
+		if v, ok := chunkBoundValue(value, isInteger).(int64); ok {
+			constNode = pgquery.MakeAConstIntNode(v, -1)
+		}
+		return pgquery.MakeAExprNode(
+			pgquery.A_Expr_Kind_AEXPR_OP,
+			[]*pgquery.Node{pgquery.MakeStrNode(op)},
+			pgquery.MakeColumnRefNode([]*pgquery.Node{pgquery.MakeStrNode(column)}, -1),
+			constNode,
+			-1,
+		)
+	}
+	if lower != nil {
+		conditions = append(conditions, newCondition(">", *lower))
+	}
+	if upper != nil {
+		conditions = append(conditions, newCondition("<=", *upper))
+	}
+	switch len(conditions) {
+	case 0:
+		*where = nil
+	case 1:
+		*where = conditions[0]
+	default:
+		*where = pgquery.MakeBoolExprNode(pgquery.BoolExprType_AND_EXPR, conditions, -1)
+	}
+
+	statement, err := pgquery.Deparse(d.pgTree)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to deparse the chunk statement")
+	}
+	return statement, nil
+}
+
+// chunkBoundValue returns the bound as an integer for the integer column, so that the comparison doesn't convert the column values.
+func chunkBoundValue(value string, isInteger bool) interface{} {
+	if !isInteger {
+		return value
+	}
+	// The bound is validated by ChunkStatement.
+	v, _ := strconv.ParseInt(value, 10, 64)
+	return v
+}
@@ -0,0 +1,119 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChunkDML(t *testing.T) {
+	lower, upper := "100", "200"
+	tests := []struct {
+		engineType EngineType
+		statement  string
+		isInteger  bool
+		lower      *string
+		upper      *string
+		wantSchema string
+		wantTable  string
+		want       string
+	}{
+		{
+			engineType: MySQL,
+			statement:  "DELETE FROM t WHERE created_at < '2022-01-01' OR status = 'DELETED'",
+			isInteger:  true,
+			lower:      &lower,
+			upper:      &upper,
+			wantTable:  "t",
+			want:       "DELETE FROM `t` WHERE (`created_at`<'2022-01-01' OR `status`='DELETED') AND `id`>100 AND `id`<=200",
+		},
+		{
+			engineType: MySQL,
+			statement:  "UPDATE db.t SET a = b + 1",
+			lower:      &lower,
+			wantSchema: "db",
+			wantTable:  "t",
+			want:       "UPDATE `db`.`t` SET `a`=`b`+1 WHERE `id`>'100'",
+		},
+		{
+			engineType: Postgres,
+			statement:  "DELETE FROM public.t WHERE a = 1 OR b = 2",
+			isInteger:  true,
+			lower:      &lower,
+			upper:      &upper,
+			wantSchema: "public",
+			wantTable:  "t",
+			want:       "DELETE FROM public.t WHERE (a = 1 OR b = 2) AND id > 100 AND id <= 200",
+		},
+		{
+			engineType: Postgres,
+			statement:  "UPDATE t SET a = 'x'",
+			upper:      &upper,
+			wantTable:  "t",
+			want:       "UPDATE t SET a = 'x' WHERE id <= '200'",
+		},
+	}
+
+	for _, test := range tests {
+		dml, err := ParseChunkDML(test.engineType, test.statement)
+		require.NoError(t, err, test.statement)
+		require.Equal(t, test.wantSchema, dml.Schema)
+		require.Equal(t, test.wantTable, dml.Table)
+		got, err := dml.ChunkStatement("id", test.isInteger, test.lower, test.upper)
+		require.NoError(t, err, test.statement)
+		require.Equal(t, test.want, got)
+		// The parsed statement is not changed by the chunk statement.
+		got, err = dml.ChunkStatement("id", test.isInteger, nil, nil)
+		require.NoError(t, err, test.statement)
+		again, err := dml.ChunkStatement("id", test.isInteger, nil, nil)
+		require.NoError(t, err, test.statement)
+		require.Equal(t, got, again)
+	}
+}
+
+func TestParseChunkDMLError(t *testing.T) {
+	tests := []struct {
+		engineType EngineType
+		statement  string
+	}{
+		{engineType: MySQL, statement: "DELETE FROM t WHERE a = 1 LIMIT 10"},
+		{engineType: MySQL, statement: "UPDATE t1 JOIN t2 ON t1.id = t2.id SET t1.a = 1"},
+		{engineType: MySQL, statement: "INSERT INTO t VALUES (1)"},
+		{engineType: Postgres, statement: "DELETE FROM t USING t2 WHERE t.id = t2.id"},
+		{engineType: Postgres, statement: "UPDATE t SET a = t2.a FROM t2 WHERE t.id = t2.id"},
+		// The UPDATE statements which are not idempotent.
+		{engineType: MySQL, statement: "UPDATE t SET a = a + 1"},
+		{engineType: MySQL, statement: "UPDATE t SET a = b, b = a"},
+		{engineType: Postgres, statement: "UPDATE t SET a = COALESCE(t.a, 0) + 1"},
+		{engineType: Postgres, statement: "UPDATE t SET a = b, b = CASE WHEN a > 0 THEN 1 ELSE 2 END"},
+	}
+	for _, test := range tests {
+		_, err := ParseChunkDML(test.engineType, test.statement)
+		require.Error(t, err, test.statement)
+	}
+}
+
+func TestChunkDMLCheckChunkColumn(t *testing.T) {
+	tests := []struct {
+		engineType EngineType
+		statement  string
+		wantErr    bool
+	}{
+		{engineType: MySQL, statement: "UPDATE t SET id = 1000 WHERE a = 1", wantErr: true},
+		{engineType: MySQL, statement: "UPDATE t SET `ID` = 1000", wantErr: true},
+		{engineType: MySQL, statement: "UPDATE t SET a = id", wantErr: false},
+		{engineType: MySQL, statement: "DELETE FROM t WHERE id > 1", wantErr: false},
+		{engineType: Postgres, statement: "UPDATE t SET id = 1000", wantErr: true},
+		{engineType: Postgres, statement: "UPDATE t SET a = id * 2", wantErr: false},
+	}
+	for _, test := range tests {
+		dml, err := ParseChunkDML(test.engineType, test.statement)
+		require.NoError(t, err, test.statement)
+		err = dml.CheckChunkColumn("id")
+		if test.wantErr {
+			require.Error(t, err, test.statement)
+		} else {
+			require.NoError(t, err, test.statement)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package differ
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/bytebase/bytebase/backend/plugin/parser"
+)
+
+// ObjectChangeAction is the action of a schema object change.
+type ObjectChangeAction string
+
+const (
+	// ObjectChangeAdded means the object is added.
+	ObjectChangeAdded ObjectChangeAction = "ADDED"
+	// ObjectChangeRemoved means the object is removed.
+	ObjectChangeRemoved ObjectChangeAction = "REMOVED"
+	// ObjectChangeChanged means the object is changed.
+	ObjectChangeChanged ObjectChangeAction = "CHANGED"
+
+	unknownObjectType = "UNKNOWN"
+)
+
+// ObjectChange is the change of a single schema object.
+type ObjectChange struct {
+	Action ObjectChangeAction `json:"action"`
+	// ObjectType is the object type in upper case, e.g. TABLE, INDEX and MATERIALIZED VIEW.
+	ObjectType string `json:"objectType"`
+	// ObjectName is the object name as it appears in the statement, e.g. `t` or public.t.
+	ObjectName string `json:"objectName"`
+	// StatementList is the list of statements which change the object.
+	StatementList []string `json:"statementList"`
+}
+
+var ddlObjectRegexp = regexp.MustCompile("(?is)^(CREATE|DROP|ALTER)\\s+" +
+	`(?:OR\s+REPLACE\s+)?(?:UNIQUE\s+)?(?:TEMPORARY\s+|TEMP\s+)?` +
+	`((?:MATERIALIZED\s+)?(?:TABLE|VIEW|INDEX|FUNCTION|PROCEDURE|TRIGGER|SEQUENCE|SCHEMA|TYPE|EXTENSION|EVENT|DATABASE))\s+` +
+	`(?:IF\s+(?:NOT\s+)?EXISTS\s+)?(?:ONLY\s+)?(?:CONCURRENTLY\s+)?` +
+	"((?:`[^`]+`|\"[^\"]+\"|[\\w$]+)(?:\\.(?:`[^`]+`|\"[^\"]+\"|[\\w$]+))*)")
+
+var dropColumnRegexp = regexp.MustCompile(`(?is)\bDROP\s+COLUMN\b`)
+
+// SummarizeSchemaDiff summarizes the schema diff statements returned by SchemaDiff to object changes.
+// The object changes are in the order of their first appearance. An object both dropped and created
+// in the diff, e.g. a re-created view, is reported as changed.
+func SummarizeSchemaDiff(engineType parser.EngineType, diff string) ([]*ObjectChange, error) {
+	splitEngineType := engineType
+	if engineType != parser.Postgres {
+		splitEngineType = parser.MySQL
+	}
+	list, err := parser.SplitMultiSQL(splitEngineType, diff)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*ObjectChange
+	changeMap := make(map[string]*ObjectChange)
+	for _, singleSQL := range list {
+		if category, _ := parser.ClassifyStatement(engineType, singleSQL.Text); category != parser.StatementCategoryDDL {
+			continue
+		}
+		statement := strings.TrimSpace(singleSQL.Text)
+		action, objectType, objectName := parseDDLObject(statement)
+		key := objectType + "\x00" + objectName
+		if objectType == unknownObjectType {
+			// Do not merge the statements we cannot recognize.
+			key = statement
+		}
+		change, ok := changeMap[key]
+		if !ok {
+			change = &ObjectChange{
+				Action:     action,
+				ObjectType: objectType,
+				ObjectName: objectName,
+			}
+			changeMap[key] = change
+			result = append(result, change)
+		} else if change.Action != action {
+			change.Action = ObjectChangeChanged
+		}
+		change.StatementList = append(change.StatementList, statement)
+	}
+	return result, nil
+}
+
+// ListLossyStatements returns the statements in the schema diff returned by SchemaDiff which lose data,
+// i.e. the statements dropping tables or columns.
+func ListLossyStatements(engineType parser.EngineType, diff string) ([]string, error) {
+	changeList, err := SummarizeSchemaDiff(engineType, diff)
+	if err != nil {
+		return nil, err
+	}
+	var result []string
+	for _, change := range changeList {
+		if change.ObjectType != "TABLE" {
+			continue
+		}
+		for _, statement := range change.StatementList {
+			if action, _, _ := parseDDLObject(statement); action == ObjectChangeRemoved || dropColumnRegexp.MatchString(statement) {
+				result = append(result, statement)
+			}
+		}
+	}
+	return result, nil
+}
+
+func parseDDLObject(statement string) (ObjectChangeAction, string, string) {
+	text := strings.TrimSpace(statement)
+	matches := ddlObjectRegexp.FindStringSubmatch(text)
+	if matches == nil {
+		action := ObjectChangeChanged
+		fields := strings.Fields(strings.ToUpper(text))
+		if len(fields) > 0 {
+			switch fields[0] {
+			case "CREATE":
+				action = ObjectChangeAdded
+			case "DROP":
+				action = ObjectChangeRemoved
+			}
+		}
+		return action, unknownObjectType, ""
+	}
+
+	action := ObjectChangeChanged
+	switch strings.ToUpper(matches[1]) {
+	case "CREATE":
+		action = ObjectChangeAdded
+	case "DROP":
+		action = ObjectChangeRemoved
+	}
+	objectType := strings.Join(strings.Fields(strings.ToUpper(matches[2])), " ")
+	return action, objectType, matches[3]
+}
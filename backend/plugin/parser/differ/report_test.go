@@ -0,0 +1,89 @@
+package differ
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/backend/plugin/parser"
+)
+
+func TestSummarizeSchemaDiff(t *testing.T) {
+	tests := []struct {
+		engineType parser.EngineType
+		diff       string
+		want       []*ObjectChange
+	}{
+		{
+			engineType: parser.MySQL,
+			diff: "SET FOREIGN_KEY_CHECKS=0;\n" +
+				"DROP TABLE IF EXISTS `book`;\n" +
+				"ALTER TABLE `author` ADD COLUMN `age` INT;\n" +
+				"ALTER TABLE `author` DROP COLUMN `email`;\n" +
+				"CREATE TABLE `t` (\n  `id` INT\n);\n" +
+				"SET FOREIGN_KEY_CHECKS=1;\n",
+			want: []*ObjectChange{
+				{Action: ObjectChangeRemoved, ObjectType: "TABLE", ObjectName: "`book`", StatementList: []string{"DROP TABLE IF EXISTS `book`;"}},
+				{Action: ObjectChangeChanged, ObjectType: "TABLE", ObjectName: "`author`", StatementList: []string{"ALTER TABLE `author` ADD COLUMN `age` INT;", "ALTER TABLE `author` DROP COLUMN `email`;"}},
+				{Action: ObjectChangeAdded, ObjectType: "TABLE", ObjectName: "`t`", StatementList: []string{"CREATE TABLE `t` (\n  `id` INT\n);"}},
+			},
+		},
+		{
+			engineType: parser.Postgres,
+			diff: "DROP VIEW public.v;\n" +
+				"CREATE UNIQUE INDEX CONCURRENTLY idx ON public.t (a);\n" +
+				"CREATE VIEW public.v AS SELECT 1;\n" +
+				"COMMENT ON TABLE public.t IS 'x';\n",
+			want: []*ObjectChange{
+				{Action: ObjectChangeChanged, ObjectType: "VIEW", ObjectName: "public.v", StatementList: []string{"DROP VIEW public.v;", "CREATE VIEW public.v AS SELECT 1;"}},
+				{Action: ObjectChangeAdded, ObjectType: "INDEX", ObjectName: "idx", StatementList: []string{"CREATE UNIQUE INDEX CONCURRENTLY idx ON public.t (a);"}},
+				{Action: ObjectChangeChanged, ObjectType: "UNKNOWN", ObjectName: "", StatementList: []string{"COMMENT ON TABLE public.t IS 'x';"}},
+			},
+		},
+	}
+
+	a := require.New(t)
+	for _, test := range tests {
+		got, err := SummarizeSchemaDiff(test.engineType, test.diff)
+		a.NoError(err)
+		a.Equal(test.want, got)
+	}
+}
+
+func TestListLossyStatements(t *testing.T) {
+	tests := []struct {
+		engineType parser.EngineType
+		diff       string
+		want       []string
+	}{
+		{
+			engineType: parser.MySQL,
+			diff: "SET FOREIGN_KEY_CHECKS=0;\n" +
+				"DROP TABLE IF EXISTS `book`;\n" +
+				"ALTER TABLE `author` ADD COLUMN `age` INT;\n" +
+				"ALTER TABLE `author` DROP COLUMN `email`;\n" +
+				"ALTER TABLE `author` DROP INDEX `idx_name`;\n" +
+				"SET FOREIGN_KEY_CHECKS=1;\n",
+			want: []string{"DROP TABLE IF EXISTS `book`;", "ALTER TABLE `author` DROP COLUMN `email`;"},
+		},
+		{
+			engineType: parser.Postgres,
+			diff: "DROP VIEW public.v;\n" +
+				"DROP INDEX public.idx;\n" +
+				"ALTER TABLE public.t DROP COLUMN a;\n" +
+				"CREATE TABLE public.t2 (id INT);\n",
+			want: []string{"ALTER TABLE public.t DROP COLUMN a;"},
+		},
+		{
+			engineType: parser.Postgres,
+			diff:       "CREATE TABLE public.t2 (id INT);\n",
+			want:       nil,
+		},
+	}
+	a := require.New(t)
+	for _, test := range tests {
+		got, err := ListLossyStatements(test.engineType, test.diff)
+		a.NoError(err)
+		a.Equal(test.want, got)
+	}
+}
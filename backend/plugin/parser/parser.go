@@ -20,6 +20,16 @@ const (
 	Postgres EngineType = "POSTGRES"
 	// TiDB is the engine type for TiDB.
 	TiDB EngineType = "TIDB"
+	// ClickHouse is the engine type for CLICKHOUSE.
+	ClickHouse EngineType = "CLICKHOUSE"
+	// Snowflake is the engine type for SNOWFLAKE.
+	Snowflake EngineType = "SNOWFLAKE"
+	// SQLite is the engine type for SQLITE.
+	SQLite EngineType = "SQLITE"
+	// MongoDB is the engine type for MONGODB.
+	MongoDB EngineType = "MONGODB"
+	// Spanner is the engine type for SPANNER.
+	Spanner EngineType = "SPANNER"
 
 	// DeparseIndentString is the string for each indent level.
 	DeparseIndentString = "    "
@@ -0,0 +1,341 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+// StatementCategory is the category of a single statement.
+type StatementCategory string
+
+const (
+	// StatementCategoryDDL is the category for data definition statements, e.g. CREATE TABLE.
+	StatementCategoryDDL StatementCategory = "DDL"
+	// StatementCategoryDML is the category for data manipulation and query statements, e.g. UPDATE and SELECT.
+	StatementCategoryDML StatementCategory = "DML"
+	// StatementCategoryDCL is the category for data control statements, e.g. GRANT and CREATE USER.
+	StatementCategoryDCL StatementCategory = "DCL"
+	// StatementCategoryTransactionControl is the category for transaction control statements, e.g. COMMIT.
+	StatementCategoryTransactionControl StatementCategory = "TRANSACTION_CONTROL"
+	// StatementCategorySession is the category for session setting statements, e.g. SET and USE.
+	StatementCategorySession StatementCategory = "SESSION"
+	// StatementCategoryUnknown is the category for statements we cannot classify.
+	StatementCategoryUnknown StatementCategory = "UNKNOWN"
+)
+
+// ClassifiedSQL is a single statement with its category.
+type ClassifiedSQL struct {
+	SingleSQL
+	Category StatementCategory
+	// ImplicitCommit is true if the statement commits the enclosing transaction implicitly,
+	// or if it cannot be executed inside a transaction block at all.
+	ImplicitCommit bool
+}
+
+var (
+	leadingCommentRegexp = regexp.MustCompile(`^(\s+|--[^\n]*(\n|$)|#[^\n]*(\n|$)|/\*(.|\n)*?\*/)`)
+
+	ddlKeywords = map[string]bool{
+		"CREATE":   true,
+		"ALTER":    true,
+		"DROP":     true,
+		"TRUNCATE": true,
+		"RENAME":   true,
+		"COMMENT":  true,
+		"VACUUM":   true,
+		"ANALYZE":  true,
+		"ANALYSE":  true,
+		"REINDEX":  true,
+		"CLUSTER":  true,
+		"OPTIMIZE": true,
+		"REPAIR":   true,
+		"UNDROP":   true,
+		"ATTACH":   true,
+		"DETACH":   true,
+	}
+	dmlKeywords = map[string]bool{
+		"SELECT":   true,
+		"INSERT":   true,
+		"UPDATE":   true,
+		"DELETE":   true,
+		"REPLACE":  true,
+		"MERGE":    true,
+		"UPSERT":   true,
+		"WITH":     true,
+		"VALUES":   true,
+		"TABLE":    true,
+		"COPY":     true,
+		"LOAD":     true,
+		"CALL":     true,
+		"EXPLAIN":  true,
+		"DESC":     true,
+		"DESCRIBE": true,
+	}
+	dclKeywords = map[string]bool{
+		"GRANT":  true,
+		"REVOKE": true,
+		"DENY":   true,
+	}
+	// dclObjects are the objects which make CREATE, ALTER and DROP statements DCL statements.
+	dclObjects = map[string]bool{
+		"USER":  true,
+		"ROLE":  true,
+		"GROUP": true,
+	}
+	transactionControlKeywords = map[string]bool{
+		"BEGIN":     true,
+		"START":     true,
+		"COMMIT":    true,
+		"ROLLBACK":  true,
+		"SAVEPOINT": true,
+		"RELEASE":   true,
+		"END":       true,
+		"ABORT":     true,
+		"XA":        true,
+		"LOCK":      true,
+		"UNLOCK":    true,
+	}
+	sessionKeywords = map[string]bool{
+		"SET":       true,
+		"RESET":     true,
+		"USE":       true,
+		"DELIMITER": true,
+		"PRAGMA":    true,
+		"\\CONNECT": true,
+		"\\C":       true,
+	}
+
+	// mysqlImplicitCommitTransactionControlKeywords are the transaction control statements causing an implicit commit in MySQL.
+	// Docs: https://dev.mysql.com/doc/refman/8.0/en/implicit-commit.html
+	mysqlImplicitCommitTransactionControlKeywords = map[string]bool{
+		"BEGIN":  true,
+		"START":  true,
+		"LOCK":   true,
+		"UNLOCK": true,
+	}
+	// pgNonTransactionalRegexp matches the statements which cannot be executed inside a transaction block in PostgreSQL.
+	pgNonTransactionalRegexp = regexp.MustCompile(`(?is)^(CREATE\s+DATABASE|DROP\s+DATABASE|CREATE\s+TABLESPACE|DROP\s+TABLESPACE|ALTER\s+SYSTEM|VACUUM|REINDEX\s+(SYSTEM|DATABASE)|(CREATE|CREATE\s+UNIQUE|DROP)\s+INDEX\s+CONCURRENTLY|REINDEX\s+.*CONCURRENTLY)\b`)
+
+	// clickHouseMutationRegexp matches the ClickHouse mutations which change the table data, e.g. ALTER TABLE t DELETE WHERE a = 1.
+	// Docs: https://clickhouse.com/docs/en/sql-reference/statements/alter#mutations
+	clickHouseMutationRegexp = regexp.MustCompile(`(?is)^ALTER\s+TABLE\s+(` + "`[^`]*`" + `|"[^"]*"|[^\s"` + "`" + `])+(\s+ON\s+CLUSTER\s+\S+)?\s+(DELETE|UPDATE)\b`)
+
+	mongoDBMethodRegexp = regexp.MustCompile(`\.\s*([A-Za-z_]\w*)\s*\(`)
+	mongoDBDDLMethods   = map[string]bool{
+		"createCollection": true,
+		"createView":       true,
+		"drop":             true,
+		"dropDatabase":     true,
+		"createIndex":      true,
+		"createIndexes":    true,
+		"dropIndex":        true,
+		"dropIndexes":      true,
+		"renameCollection": true,
+	}
+	mongoDBDMLMethods = map[string]bool{
+		"insert":            true,
+		"insertOne":         true,
+		"insertMany":        true,
+		"update":            true,
+		"updateOne":         true,
+		"updateMany":        true,
+		"replaceOne":        true,
+		"remove":            true,
+		"deleteOne":         true,
+		"deleteMany":        true,
+		"bulkWrite":         true,
+		"find":              true,
+		"findOne":           true,
+		"findOneAndUpdate":  true,
+		"findOneAndDelete":  true,
+		"findOneAndReplace": true,
+		"aggregate":         true,
+		"count":             true,
+		"countDocuments":    true,
+		"distinct":          true,
+	}
+	mongoDBDCLMethods = map[string]bool{
+		"createUser":               true,
+		"updateUser":               true,
+		"dropUser":                 true,
+		"grantRolesToUser":         true,
+		"revokeRolesFromUser":      true,
+		"createRole":               true,
+		"updateRole":               true,
+		"dropRole":                 true,
+		"grantPrivilegesToRole":    true,
+		"revokePrivilegesFromRole": true,
+	}
+	mongoDBTransactionControlMethods = map[string]bool{
+		"startSession":      true,
+		"startTransaction":  true,
+		"commitTransaction": true,
+		"abortTransaction":  true,
+		"endSession":        true,
+	}
+)
+
+// ClassifyStatements splits the statement and classifies every single statement.
+// PostgreSQL statements are split by the PostgreSQL tokenizer, and the MySQL, TiDB and ClickHouse statements
+// are split by the MySQL tokenizer, since ClickHouse shares the quote and comment styles of MySQL.
+// The other engines are not supported, because the MySQL tokenizer mis-splits their statements,
+// e.g. the MongoDB scripts and the Snowflake $$ bodies.
+func ClassifyStatements(engineType EngineType, statement string) ([]ClassifiedSQL, error) {
+	var list []SingleSQL
+	var err error
+	switch engineType {
+	case Postgres:
+		list, err = SplitMultiSQL(Postgres, statement)
+	case MySQL, TiDB, ClickHouse:
+		list, err = SplitMultiSQL(MySQL, statement)
+	default:
+		return nil, errors.Errorf("engine type is not supported: %s", engineType)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var result []ClassifiedSQL
+	for _, sql := range list {
+		category, implicitCommit := ClassifyStatement(engineType, sql.Text)
+		result = append(result, ClassifiedSQL{
+			SingleSQL:      sql,
+			Category:       category,
+			ImplicitCommit: implicitCommit,
+		})
+	}
+	return result, nil
+}
+
+// ClassifyStatement returns the category of a single statement,
+// and whether the statement commits the enclosing transaction implicitly.
+func ClassifyStatement(engineType EngineType, statement string) (StatementCategory, bool) {
+	text := trimLeadingComments(statement)
+	if engineType == MongoDB {
+		return classifyMongoDBStatement(text), false
+	}
+
+	words := strings.FieldsFunc(strings.ToUpper(text), func(r rune) bool {
+		return unicode.IsSpace(r) || r == ';' || r == '(' || r == ','
+	})
+	if len(words) == 0 {
+		return StatementCategoryUnknown, false
+	}
+	category := classifyByKeywords(words)
+	if engineType == ClickHouse && clickHouseMutationRegexp.MatchString(text) {
+		category = StatementCategoryDML
+	}
+	return category, isImplicitCommit(engineType, category, words, text)
+}
+
+// IsDataChangeStatement returns true if the statement changes the table data, e.g. INSERT, UPDATE and DELETE.
+func IsDataChangeStatement(engineType EngineType, statement string) bool {
+	category, _ := ClassifyStatement(engineType, statement)
+	if category != StatementCategoryDML {
+		return false
+	}
+	text := strings.ToUpper(trimLeadingComments(statement))
+	if engineType == ClickHouse && clickHouseMutationRegexp.MatchString(text) {
+		return true
+	}
+	for _, keyword := range []string{"INSERT", "UPDATE", "DELETE", "REPLACE", "MERGE", "UPSERT"} {
+		if strings.HasPrefix(text, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+func classifyByKeywords(words []string) StatementCategory {
+	first := words[0]
+	switch {
+	case dclKeywords[first]:
+		return StatementCategoryDCL
+	case first == "CREATE" || first == "ALTER" || first == "DROP":
+		// CREATE [OR REPLACE] USER, DROP ROLE IF EXISTS, ALTER DEFAULT PRIVILEGES, etc.
+		for _, word := range words[1:] {
+			if word == "OR" || word == "REPLACE" || word == "IF" || word == "NOT" || word == "EXISTS" {
+				continue
+			}
+			if dclObjects[word] || (first == "ALTER" && word == "DEFAULT") {
+				return StatementCategoryDCL
+			}
+			break
+		}
+		return StatementCategoryDDL
+	case ddlKeywords[first]:
+		return StatementCategoryDDL
+	case dmlKeywords[first]:
+		return StatementCategoryDML
+	case first == "SET":
+		// SET TRANSACTION and SET PASSWORD are not session settings.
+		if len(words) > 1 {
+			switch words[1] {
+			case "TRANSACTION", "CONSTRAINTS":
+				return StatementCategoryTransactionControl
+			case "PASSWORD":
+				return StatementCategoryDCL
+			}
+		}
+		return StatementCategorySession
+	case transactionControlKeywords[first]:
+		return StatementCategoryTransactionControl
+	case sessionKeywords[first]:
+		return StatementCategorySession
+	}
+	return StatementCategoryUnknown
+}
+
+func isImplicitCommit(engineType EngineType, category StatementCategory, words []string, text string) bool {
+	switch engineType {
+	case MySQL, TiDB:
+		switch category {
+		case StatementCategoryDDL, StatementCategoryDCL:
+			return true
+		case StatementCategoryTransactionControl:
+			return mysqlImplicitCommitTransactionControlKeywords[words[0]]
+		}
+	case Postgres:
+		return pgNonTransactionalRegexp.MatchString(text)
+	case Snowflake, Spanner:
+		// DDL statements are not transactional in Snowflake and Spanner.
+		return category == StatementCategoryDDL || category == StatementCategoryDCL
+	case SQLite:
+		return words[0] == "VACUUM"
+	}
+	return false
+}
+
+func classifyMongoDBStatement(text string) StatementCategory {
+	if strings.HasPrefix(text, "use ") {
+		return StatementCategorySession
+	}
+	for _, match := range mongoDBMethodRegexp.FindAllStringSubmatch(text, -1) {
+		method := match[1]
+		switch {
+		case mongoDBDDLMethods[method]:
+			return StatementCategoryDDL
+		case mongoDBDMLMethods[method]:
+			return StatementCategoryDML
+		case mongoDBDCLMethods[method]:
+			return StatementCategoryDCL
+		case mongoDBTransactionControlMethods[method]:
+			return StatementCategoryTransactionControl
+		}
+	}
+	return StatementCategoryUnknown
+}
+
+func trimLeadingComments(statement string) string {
+	text := statement
+	for {
+		loc := leadingCommentRegexp.FindStringIndex(text)
+		if loc == nil || loc[1] == 0 {
+			return text
+		}
+		text = text[loc[1]:]
+	}
+}
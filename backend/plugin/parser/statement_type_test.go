@@ -0,0 +1,99 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyStatement(t *testing.T) {
+	tests := []struct {
+		engineType         EngineType
+		stmt               string
+		wantCategory       StatementCategory
+		wantImplicitCommit bool
+	}{
+		{MySQL, "CREATE TABLE t(a int)", StatementCategoryDDL, true},
+		{MySQL, "/* comment */\n-- another comment\nALTER TABLE t ADD COLUMN b int;", StatementCategoryDDL, true},
+		{MySQL, "INSERT INTO t VALUES (1)", StatementCategoryDML, false},
+		{MySQL, "with cte AS (SELECT 1) SELECT * FROM cte", StatementCategoryDML, false},
+		{MySQL, "EXPLAIN SELECT 1", StatementCategoryDML, false},
+		{MySQL, "GRANT SELECT ON db.* TO 'u'@'%'", StatementCategoryDCL, true},
+		{MySQL, "CREATE USER IF NOT EXISTS 'u'@'%'", StatementCategoryDCL, true},
+		{MySQL, "SET PASSWORD FOR 'u'@'%' = 'p'", StatementCategoryDCL, true},
+		{MySQL, "BEGIN", StatementCategoryTransactionControl, true},
+		{MySQL, "COMMIT", StatementCategoryTransactionControl, false},
+		{MySQL, "LOCK TABLES t WRITE", StatementCategoryTransactionControl, true},
+		{MySQL, "SET NAMES utf8mb4", StatementCategorySession, false},
+		{MySQL, "SET TRANSACTION ISOLATION LEVEL READ COMMITTED", StatementCategoryTransactionControl, false},
+		{TiDB, "USE db", StatementCategorySession, false},
+		{MySQL, "SHOW TABLES", StatementCategoryUnknown, false},
+		{Postgres, "CREATE TABLE t(a int)", StatementCategoryDDL, false},
+		{Postgres, "CREATE INDEX CONCURRENTLY idx ON t(a)", StatementCategoryDDL, true},
+		{Postgres, "create unique index concurrently idx ON t(a)", StatementCategoryDDL, true},
+		{Postgres, "VACUUM t", StatementCategoryDDL, true},
+		{Postgres, "ALTER SYSTEM SET work_mem = '64MB'", StatementCategoryDDL, true},
+		{Postgres, "ALTER DEFAULT PRIVILEGES GRANT SELECT ON TABLES TO r", StatementCategoryDCL, false},
+		{Postgres, "DROP ROLE IF EXISTS r", StatementCategoryDCL, false},
+		{Postgres, "SET search_path TO public", StatementCategorySession, false},
+		{Postgres, "COPY t FROM STDIN", StatementCategoryDML, false},
+		{Snowflake, "CREATE OR REPLACE TABLE t(a int)", StatementCategoryDDL, true},
+		{Snowflake, "CREATE OR REPLACE ROLE r", StatementCategoryDCL, true},
+		{Spanner, "CREATE TABLE t (a INT64) PRIMARY KEY (a)", StatementCategoryDDL, true},
+		{SQLite, "VACUUM", StatementCategoryDDL, true},
+		{SQLite, "PRAGMA foreign_keys = ON", StatementCategorySession, false},
+		{ClickHouse, "OPTIMIZE TABLE t FINAL", StatementCategoryDDL, false},
+		{ClickHouse, "ALTER TABLE t DELETE WHERE a = 1", StatementCategoryDML, false},
+		{ClickHouse, "alter table db.`my t` on cluster c update a = 2 where a = 1", StatementCategoryDML, false},
+		{ClickHouse, "ALTER TABLE t DROP COLUMN a", StatementCategoryDDL, false},
+		{MySQL, "ALTER TABLE t DELETE WHERE a = 1", StatementCategoryDDL, true},
+		{MongoDB, `db.getCollection("t").insertOne({a: 1})`, StatementCategoryDML, false},
+		{MongoDB, `db.t.createIndex({a: 1})`, StatementCategoryDDL, false},
+		{MongoDB, `db.grantRolesToUser("u", ["read"])`, StatementCategoryDCL, false},
+		{MongoDB, `use test`, StatementCategorySession, false},
+		{MongoDB, `db.version()`, StatementCategoryUnknown, false},
+	}
+
+	a := require.New(t)
+	for _, test := range tests {
+		category, implicitCommit := ClassifyStatement(test.engineType, test.stmt)
+		a.Equal(test.wantCategory, category, "%s: %s", test.engineType, test.stmt)
+		a.Equal(test.wantImplicitCommit, implicitCommit, "%s: %s", test.engineType, test.stmt)
+	}
+}
+
+func TestClassifyStatements(t *testing.T) {
+	a := require.New(t)
+	got, err := ClassifyStatements(MySQL, "CREATE TABLE t(a int);\nINSERT INTO t VALUES (';');\n-- comment\nCOMMIT;")
+	a.NoError(err)
+	var categories []StatementCategory
+	for _, sql := range got {
+		categories = append(categories, sql.Category)
+	}
+	a.Equal([]StatementCategory{StatementCategoryDDL, StatementCategoryDML, StatementCategoryTransactionControl}, categories)
+
+	got, err = ClassifyStatements(ClickHouse, "ALTER TABLE t DELETE WHERE a = ';';\nCREATE TABLE t2(a Int32) ENGINE = Memory;")
+	a.NoError(err)
+	categories = nil
+	for _, sql := range got {
+		categories = append(categories, sql.Category)
+	}
+	a.Equal([]StatementCategory{StatementCategoryDML, StatementCategoryDDL}, categories)
+
+	// The engines whose statements can't be split by the MySQL tokenizer are not supported.
+	for _, engineType := range []EngineType{MongoDB, Snowflake, SQLite, Spanner, EngineType("UNKNOWN")} {
+		_, err = ClassifyStatements(engineType, "SELECT 1")
+		a.Error(err, engineType)
+	}
+}
+
+func TestIsDataChangeStatement(t *testing.T) {
+	a := require.New(t)
+	a.True(IsDataChangeStatement(MySQL, "-- comment\nUPDATE t SET a = 1"))
+	a.True(IsDataChangeStatement(Postgres, "delete from t"))
+	a.True(IsDataChangeStatement(MySQL, "INSERT INTO t VALUES (1)"))
+	a.False(IsDataChangeStatement(MySQL, "SELECT * FROM t"))
+	a.False(IsDataChangeStatement(Postgres, "CREATE TABLE t(a int)"))
+	a.True(IsDataChangeStatement(ClickHouse, "ALTER TABLE t DELETE WHERE a = 1"))
+	a.False(IsDataChangeStatement(ClickHouse, "ALTER TABLE t ADD COLUMN a int"))
+}
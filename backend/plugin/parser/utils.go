@@ -2,12 +2,14 @@ package parser
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"regexp"
 	"sort"
 	"strings"
 
+	pgquery "github.com/pganalyze/pg_query_go/v2"
 	tidbparser "github.com/pingcap/tidb/parser"
 	tidbast "github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/model"
@@ -198,6 +200,126 @@ func ExtractDatabaseList(engineType EngineType, statement string) ([]string, err
 	}
 }
 
+// ExtractTableList extracts all tables accessed by the statement, the tables are
+// qualified by the database or schema name if they are in the statement, e.g. "db1.t".
+// The common table expressions are not tables, so they're excluded.
+func ExtractTableList(engineType EngineType, statement string) ([]string, error) {
+	switch engineType {
+	case MySQL, TiDB:
+		return extractMySQLTableNameList(statement)
+	case Postgres:
+		return extractPostgresTableNameList(statement)
+	default:
+		return nil, errors.Errorf("engine type is not supported: %s", engineType)
+	}
+}
+
+// cteNameCollector collects the names of the common table expressions in the MySQL statement.
+type cteNameCollector struct {
+	cteNames map[string]bool
+}
+
+func (c *cteNameCollector) Enter(in tidbast.Node) (tidbast.Node, bool) {
+	if cte, ok := in.(*tidbast.CommonTableExpression); ok {
+		c.cteNames[cte.Name.L] = true
+	}
+	return in, false
+}
+
+func (*cteNameCollector) Leave(in tidbast.Node) (tidbast.Node, bool) {
+	return in, true
+}
+
+func extractMySQLTableNameList(statement string) ([]string, error) {
+	p := newMySQLParser()
+	nodeList, _, err := p.Parse(statement, "", "")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parser statement %q", statement)
+	}
+
+	tableMap := make(map[string]bool)
+	for _, node := range nodeList {
+		collector := &cteNameCollector{cteNames: make(map[string]bool)}
+		node.Accept(collector)
+		for _, table := range ExtractMySQLTableList(node, false /* asName */) {
+			if table.Schema.O == "" && collector.cteNames[table.Name.L] {
+				continue
+			}
+			name := table.Name.O
+			if table.Schema.O != "" {
+				name = fmt.Sprintf("%s.%s", table.Schema.O, table.Name.O)
+			}
+			tableMap[name] = true
+		}
+	}
+	return sortedKeys(tableMap), nil
+}
+
+func extractPostgresTableNameList(statement string) ([]string, error) {
+	jsonText, err := pgquery.ParseToJSON(statement)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parser statement %q", statement)
+	}
+	var tree interface{}
+	if err := json.Unmarshal([]byte(jsonText), &tree); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal parse tree")
+	}
+
+	var relations [][2]string
+	cteNames := make(map[string]bool)
+	var walk func(node interface{})
+	walk = func(node interface{}) {
+		switch v := node.(type) {
+		case map[string]interface{}:
+			for key, value := range v {
+				switch key {
+				case "RangeVar":
+					if rangeVar, ok := value.(map[string]interface{}); ok {
+						schemaName, _ := rangeVar["schemaname"].(string)
+						relName, _ := rangeVar["relname"].(string)
+						relations = append(relations, [2]string{schemaName, relName})
+					}
+				case "CommonTableExpr":
+					if cte, ok := value.(map[string]interface{}); ok {
+						if cteName, ok := cte["ctename"].(string); ok {
+							cteNames[cteName] = true
+						}
+					}
+				}
+				walk(value)
+			}
+		case []interface{}:
+			for _, item := range v {
+				walk(item)
+			}
+		}
+	}
+	walk(tree)
+
+	tableMap := make(map[string]bool)
+	for _, relation := range relations {
+		schemaName, relName := relation[0], relation[1]
+		if schemaName == "" && cteNames[relName] {
+			continue
+		}
+		name := relName
+		if schemaName != "" {
+			name = fmt.Sprintf("%s.%s", schemaName, relName)
+		}
+		tableMap[name] = true
+	}
+	return sortedKeys(tableMap), nil
+}
+
+func sortedKeys(m map[string]bool) []string {
+	var keys []string
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 func newMySQLParser() *tidbparser.Parser {
 	p := tidbparser.New()
 
@@ -209,3 +209,43 @@ func TestExtractDatabaseList(t *testing.T) {
 		require.Equal(t, test.want, res)
 	}
 }
+
+func TestExtractTableList(t *testing.T) {
+	tests := []struct {
+		engine EngineType
+		stmt   string
+		want   []string
+	}{
+		{
+			engine: MySQL,
+			stmt:   `SELECT * FROM t1 JOIN db1.t2 ON t1.a = t2.a WHERE t1.b IN (SELECT b FROM t3);`,
+			want:   []string{"db1.t2", "t1", "t3"},
+		},
+		{
+			engine: MySQL,
+			stmt:   `WITH c AS (SELECT * FROM t1) SELECT * FROM c;`,
+			want:   []string{"t1"},
+		},
+		{
+			engine: MySQL,
+			stmt:   `SELECT 1;`,
+			want:   nil,
+		},
+		{
+			engine: Postgres,
+			stmt:   `SELECT * FROM t1 JOIN public.t2 ON t1.a = t2.a WHERE t1.b IN (SELECT b FROM s1.t3);`,
+			want:   []string{"public.t2", "s1.t3", "t1"},
+		},
+		{
+			engine: Postgres,
+			stmt:   `WITH c AS (SELECT * FROM t1) SELECT * FROM c;`,
+			want:   []string{"t1"},
+		},
+	}
+
+	for _, test := range tests {
+		res, err := ExtractTableList(test.engine, test.stmt)
+		require.NoError(t, err)
+		require.Equal(t, test.want, res, test.stmt)
+	}
+}
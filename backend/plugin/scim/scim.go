@@ -0,0 +1,286 @@
+// Package scim is the plugin for the SCIM 2.0 protocol defined by RFC 7643 and RFC 7644,
+// which lets the identity providers like Okta and Azure AD provision the users and groups.
+package scim
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// ContentType is the media type of the SCIM requests and responses.
+	ContentType = "application/scim+json"
+
+	// UserSchema is the schema URN of the user resource.
+	UserSchema = "urn:ietf:params:scim:schemas:core:2.0:User"
+	// GroupSchema is the schema URN of the group resource.
+	GroupSchema = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	// ListResponseSchema is the schema URN of the list response.
+	ListResponseSchema = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	// PatchOpSchema is the schema URN of the patch request.
+	PatchOpSchema = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	// ErrorSchema is the schema URN of the error response.
+	ErrorSchema = "urn:ietf:params:scim:api:messages:2.0:Error"
+	// ServiceProviderConfigSchema is the schema URN of the service provider configuration.
+	ServiceProviderConfigSchema = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+
+	// ErrorTypeUniqueness is the SCIM error type for the conflicting resources.
+	ErrorTypeUniqueness = "uniqueness"
+	// ErrorTypeInvalidFilter is the SCIM error type for the unsupported filters.
+	ErrorTypeInvalidFilter = "invalidFilter"
+	// ErrorTypeInvalidValue is the SCIM error type for the invalid attribute values.
+	ErrorTypeInvalidValue = "invalidValue"
+	// ErrorTypeMutability is the SCIM error type for modifying the immutable resources.
+	ErrorTypeMutability = "mutability"
+
+	// MaxResults is the max number of the resources returned by a list request.
+	MaxResults = 200
+)
+
+// Meta is the metadata of a resource.
+type Meta struct {
+	ResourceType string `json:"resourceType"`
+	Location     string `json:"location,omitempty"`
+}
+
+// Name is the name of a user.
+type Name struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+// Email is an email address of a user.
+type Email struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+// User is the SCIM user resource.
+type User struct {
+	Schemas     []string `json:"schemas"`
+	ID          string   `json:"id,omitempty"`
+	ExternalID  string   `json:"externalId,omitempty"`
+	UserName    string   `json:"userName"`
+	Name        *Name    `json:"name,omitempty"`
+	DisplayName string   `json:"displayName,omitempty"`
+	Emails      []Email  `json:"emails,omitempty"`
+	// Active is nil if it's not set in the request, and the user is active by default.
+	Active *bool `json:"active,omitempty"`
+	Meta   *Meta `json:"meta,omitempty"`
+}
+
+// GetDisplayName returns the display name of the user, falling back to the name.
+func (u *User) GetDisplayName() string {
+	if u.DisplayName != "" {
+		return u.DisplayName
+	}
+	if u.Name != nil {
+		if u.Name.Formatted != "" {
+			return u.Name.Formatted
+		}
+		if name := strings.TrimSpace(u.Name.GivenName + " " + u.Name.FamilyName); name != "" {
+			return name
+		}
+	}
+	return u.UserName
+}
+
+// GetEmail returns the email of the user, which is the primary email or the user name.
+func (u *User) GetEmail() string {
+	for _, email := range u.Emails {
+		if email.Primary {
+			return email.Value
+		}
+	}
+	if strings.Contains(u.UserName, "@") || len(u.Emails) == 0 {
+		return u.UserName
+	}
+	return u.Emails[0].Value
+}
+
+// Member is a member of a group.
+type Member struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+}
+
+// Group is the SCIM group resource.
+type Group struct {
+	Schemas     []string `json:"schemas"`
+	ID          string   `json:"id,omitempty"`
+	DisplayName string   `json:"displayName"`
+	Members     []Member `json:"members"`
+	Meta        *Meta    `json:"meta,omitempty"`
+}
+
+// ListResponse is the response of a list request.
+type ListResponse struct {
+	Schemas      []string      `json:"schemas"`
+	TotalResults int           `json:"totalResults"`
+	StartIndex   int           `json:"startIndex"`
+	ItemsPerPage int           `json:"itemsPerPage"`
+	Resources    []interface{} `json:"Resources"`
+}
+
+// NewListResponse returns the list response of the page of the resources.
+// The startIndex is 1-based, and count limits the number of the resources on the page.
+func NewListResponse(resources []interface{}, startIndex, count int) *ListResponse {
+	if startIndex < 1 {
+		startIndex = 1
+	}
+	if count < 0 || count > MaxResults {
+		count = MaxResults
+	}
+	page := []interface{}{}
+	if startIndex <= len(resources) {
+		end := startIndex - 1 + count
+		if end > len(resources) {
+			end = len(resources)
+		}
+		page = resources[startIndex-1 : end]
+	}
+	return &ListResponse{
+		Schemas:      []string{ListResponseSchema},
+		TotalResults: len(resources),
+		StartIndex:   startIndex,
+		ItemsPerPage: len(page),
+		Resources:    page,
+	}
+}
+
+// PatchOperation is an operation of the patch request.
+type PatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// PatchRequest is the patch request modifying a resource.
+type PatchRequest struct {
+	Schemas    []string         `json:"schemas"`
+	Operations []PatchOperation `json:"Operations"`
+}
+
+// GetOp returns the lower case operation, which is "add", "remove" or "replace".
+func (op *PatchOperation) GetOp() string {
+	return strings.ToLower(op.Op)
+}
+
+// Attributes returns the attributes set by the operation keyed by the lower case attribute path.
+// The attributes are either set by the path and the value, or by the value object without the path.
+func (op *PatchOperation) Attributes() (map[string]json.RawMessage, error) {
+	if op.Path != "" {
+		return map[string]json.RawMessage{strings.ToLower(op.Path): op.Value}, nil
+	}
+	values := map[string]json.RawMessage{}
+	if err := json.Unmarshal(op.Value, &values); err != nil {
+		return nil, errors.Wrap(err, "patch value without path must be an object")
+	}
+	attributes := map[string]json.RawMessage{}
+	for k, v := range values {
+		attributes[strings.ToLower(k)] = v
+	}
+	return attributes, nil
+}
+
+// Error is the SCIM error response.
+type Error struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail,omitempty"`
+}
+
+// NewError returns the SCIM error response of the HTTP status.
+func NewError(status int, scimType, detail string) *Error {
+	return &Error{
+		Schemas:  []string{ErrorSchema},
+		Status:   strconv.Itoa(status),
+		ScimType: scimType,
+		Detail:   detail,
+	}
+}
+
+// Filter is an equality filter of the list request, e.g. `userName eq "alice@example.com"`.
+// The other filter expressions are not supported because the identity providers only look up the resources by the equality filters.
+type Filter struct {
+	// Attribute is the lower case attribute path.
+	Attribute string
+	Value     string
+}
+
+var filterRegexp = regexp.MustCompile(`^\s*([A-Za-z][\w.:]*)\s+(?i:eq)\s+"((?:[^"\\]|\\.)*)"\s*$`)
+
+// ParseFilter parses the equality filter, it returns nil if the filter is empty.
+func ParseFilter(filter string) (*Filter, error) {
+	if strings.TrimSpace(filter) == "" {
+		return nil, nil
+	}
+	matches := filterRegexp.FindStringSubmatch(filter)
+	if matches == nil {
+		return nil, errors.Errorf("unsupported filter %q, only the eq operator is supported", filter)
+	}
+	value, err := strconv.Unquote(`"` + matches[2] + `"`)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid filter value in %q", filter)
+	}
+	return &Filter{
+		Attribute: strings.ToLower(matches[1]),
+		Value:     value,
+	}, nil
+}
+
+var memberPathRegexp = regexp.MustCompile(`^(?i:members)\[\s*(?i:value)\s+(?i:eq)\s+"([^"]*)"\s*\]$`)
+
+// ParseMemberPath returns the member ID of the path selecting a group member, e.g. `members[value eq "101"]`.
+func ParseMemberPath(path string) (string, bool) {
+	matches := memberPathRegexp.FindStringSubmatch(strings.TrimSpace(path))
+	if matches == nil {
+		return "", false
+	}
+	return matches[1], true
+}
+
+// ParseBool parses the boolean attribute value, some identity providers like Azure AD send the booleans as strings.
+func ParseBool(value json.RawMessage) (bool, error) {
+	var b bool
+	if err := json.Unmarshal(value, &b); err == nil {
+		return b, nil
+	}
+	var s string
+	if err := json.Unmarshal(value, &s); err != nil {
+		return false, errors.Errorf("invalid boolean value %s", string(value))
+	}
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		return false, errors.Errorf("invalid boolean value %s", string(value))
+	}
+	return b, nil
+}
+
+// ParseString parses the string attribute value.
+func ParseString(value json.RawMessage) (string, error) {
+	var s string
+	if err := json.Unmarshal(value, &s); err != nil {
+		return "", errors.Errorf("invalid string value %s", string(value))
+	}
+	return s, nil
+}
+
+// ParseMembers parses the members attribute value.
+func ParseMembers(value json.RawMessage) ([]Member, error) {
+	if len(value) == 0 {
+		return nil, nil
+	}
+	var members []Member
+	if err := json.Unmarshal(value, &members); err != nil {
+		return nil, errors.Errorf("invalid members value %s", string(value))
+	}
+	return members, nil
+}
@@ -0,0 +1,92 @@
+package scim
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		filter  string
+		want    *Filter
+		wantErr bool
+	}{
+		{
+			filter: "",
+			want:   nil,
+		},
+		{
+			filter: `userName eq "alice@example.com"`,
+			want:   &Filter{Attribute: "username", Value: "alice@example.com"},
+		},
+		{
+			filter: `displayName EQ "DBA \"team\""`,
+			want:   &Filter{Attribute: "displayname", Value: `DBA "team"`},
+		},
+		{
+			filter: `emails.value eq "bob@example.com"`,
+			want:   &Filter{Attribute: "emails.value", Value: "bob@example.com"},
+		},
+		{
+			filter:  `userName sw "alice"`,
+			wantErr: true,
+		},
+		{
+			filter:  `userName eq "alice" and active eq true`,
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		got, err := ParseFilter(test.filter)
+		if test.wantErr {
+			require.Error(t, err, test.filter)
+			continue
+		}
+		require.NoError(t, err, test.filter)
+		require.Equal(t, test.want, got, test.filter)
+	}
+}
+
+func TestParseMemberPath(t *testing.T) {
+	id, ok := ParseMemberPath(`members[value eq "101"]`)
+	require.True(t, ok)
+	require.Equal(t, "101", id)
+
+	_, ok = ParseMemberPath("members")
+	require.False(t, ok)
+}
+
+func TestPatchOperationAttributes(t *testing.T) {
+	op := &PatchOperation{Op: "Replace", Value: json.RawMessage(`{"active": "False", "displayName": "Alice"}`)}
+	require.Equal(t, "replace", op.GetOp())
+	attributes, err := op.Attributes()
+	require.NoError(t, err)
+	active, err := ParseBool(attributes["active"])
+	require.NoError(t, err)
+	require.False(t, active)
+	displayName, err := ParseString(attributes["displayname"])
+	require.NoError(t, err)
+	require.Equal(t, "Alice", displayName)
+
+	op = &PatchOperation{Op: "replace", Path: "active", Value: json.RawMessage(`true`)}
+	attributes, err = op.Attributes()
+	require.NoError(t, err)
+	active, err = ParseBool(attributes["active"])
+	require.NoError(t, err)
+	require.True(t, active)
+}
+
+func TestNewListResponse(t *testing.T) {
+	resources := []interface{}{1, 2, 3, 4, 5}
+	resp := NewListResponse(resources, 2, 2)
+	require.Equal(t, 5, resp.TotalResults)
+	require.Equal(t, 2, resp.ItemsPerPage)
+	require.Equal(t, []interface{}{2, 3}, resp.Resources)
+
+	resp = NewListResponse(resources, 10, 2)
+	require.Equal(t, 0, resp.ItemsPerPage)
+	require.Equal(t, []interface{}{}, resp.Resources)
+}
@@ -0,0 +1,120 @@
+package secret
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/pkg/errors"
+)
+
+const (
+	awsRequestTimeout = 10 * time.Second
+	awsServiceName    = "secretsmanager"
+)
+
+// AWSSecretsManagerProvider reads the secrets from the AWS Secrets Manager, e.g. aws-secrets-manager://prod/mysql#password.
+// The path is the name or the ARN of the secret. If the key is set, the secret string is parsed as a JSON object,
+// otherwise the whole secret string is used.
+// The credentials and the region are loaded from the default AWS credential chain, and the region in the ARN takes precedence.
+type AWSSecretsManagerProvider struct {
+	// endpoint overrides the regional endpoint of AWS Secrets Manager, it's used in tests.
+	endpoint string
+	client   *http.Client
+	signer   *v4.Signer
+}
+
+// NewAWSSecretsManagerProvider creates a new AWS Secrets Manager provider.
+// If endpoint is empty, the regional endpoint is used.
+func NewAWSSecretsManagerProvider(endpoint string) *AWSSecretsManagerProvider {
+	return &AWSSecretsManagerProvider{
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: awsRequestTimeout},
+		signer:   v4.NewSigner(),
+	}
+}
+
+type getSecretValueResponse struct {
+	SecretString string `json:"SecretString"`
+	// Type and Message are set if the request fails.
+	Type    string `json:"__type"`
+	Message string `json:"message"`
+}
+
+// GetSecret gets the secret from the AWS Secrets Manager.
+func (p *AWSSecretsManagerProvider) GetSecret(ctx context.Context, reference *Reference) (*Secret, error) {
+	cfg, err := awsconfig.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load AWS config")
+	}
+	region := cfg.Region
+	// The ARN is in the format of arn:aws:secretsmanager:<region>:<account-id>:secret:<name>.
+	if parts := strings.Split(reference.Path, ":"); len(parts) >= 7 && parts[0] == "arn" {
+		region = parts[3]
+	}
+	if region == "" {
+		return nil, errors.Errorf("AWS region is required for secret %q", reference.Path)
+	}
+	credentials, err := cfg.Credentials.Retrieve(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to retrieve AWS credentials")
+	}
+
+	endpoint := p.endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://%s.%s.amazonaws.com", awsServiceName, region)
+	}
+	payload, err := json.Marshal(map[string]string{"SecretId": reference.Path})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal GetSecretValue request")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to construct POST %s", endpoint)
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	payloadHash := sha256.Sum256(payload)
+	if err := p.signer.SignHTTP(ctx, credentials, req, hex.EncodeToString(payloadHash[:]), awsServiceName, region, time.Now()); err != nil {
+		return nil, errors.Wrap(err, "failed to sign GetSecretValue request")
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to POST %s", endpoint)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read response body of POST %s", endpoint)
+	}
+	response := &getSecretValueResponse{}
+	if err := json.Unmarshal(body, response); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal response body of POST %s, status code %d", endpoint, resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("failed to get AWS secret %q, status code %d, %s: %s", reference.Path, resp.StatusCode, response.Type, response.Message)
+	}
+
+	if reference.Key == "" {
+		return &Secret{Value: response.SecretString}, nil
+	}
+	data := make(map[string]any)
+	if err := json.Unmarshal([]byte(response.SecretString), &data); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal AWS secret %q as key-value pairs", reference.Path)
+	}
+	value, err := getValue(data, reference.Key)
+	if err != nil {
+		return nil, err
+	}
+	return &Secret{Value: value}, nil
+}
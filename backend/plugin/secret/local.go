@@ -0,0 +1,76 @@
+package secret
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// EnvPrefix is the required prefix of the environment variables read by the env provider.
+// Otherwise, anyone who can edit the data source is able to send the other environment variables of the server to the database host.
+const EnvPrefix = "BB_SECRET_"
+
+// FileProvider reads the secrets from the files under a directory, e.g. file://mysql/password.
+// If the key is set, the file content is parsed as a JSON object.
+type FileProvider struct {
+	dir string
+}
+
+// NewFileProvider creates a new file provider reading the secrets under dir.
+func NewFileProvider(dir string) *FileProvider {
+	return &FileProvider{dir: dir}
+}
+
+// GetSecret reads the secret from the file.
+func (p *FileProvider) GetSecret(_ context.Context, reference *Reference) (*Secret, error) {
+	// Join cleans the path, so we check the prefix afterward to prevent reading the files outside of the directory.
+	path := filepath.Join(p.dir, reference.Path)
+	if rel, err := filepath.Rel(p.dir, path); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil, errors.Errorf("secret file %q is outside of the secret directory", reference.Path)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read secret file %q", reference.Path)
+	}
+	return parseLocalSecret(strings.TrimRight(string(content), "\r\n"), reference)
+}
+
+// EnvProvider reads the secrets from the environment variables with EnvPrefix, e.g. env://BB_SECRET_MYSQL_PASSWORD.
+// If the key is set, the value is parsed as a JSON object.
+type EnvProvider struct{}
+
+// NewEnvProvider creates a new env provider.
+func NewEnvProvider() *EnvProvider {
+	return &EnvProvider{}
+}
+
+// GetSecret reads the secret from the environment variable.
+func (*EnvProvider) GetSecret(_ context.Context, reference *Reference) (*Secret, error) {
+	if !strings.HasPrefix(reference.Path, EnvPrefix) {
+		return nil, errors.Errorf("secret environment variable %q must start with %q", reference.Path, EnvPrefix)
+	}
+	value, ok := os.LookupEnv(reference.Path)
+	if !ok {
+		return nil, errors.Errorf("secret environment variable %q is not set", reference.Path)
+	}
+	return parseLocalSecret(value, reference)
+}
+
+func parseLocalSecret(value string, reference *Reference) (*Secret, error) {
+	if reference.Key == "" {
+		return &Secret{Value: value}, nil
+	}
+	data := make(map[string]any)
+	if err := json.Unmarshal([]byte(value), &data); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal secret %q as key-value pairs", reference.Path)
+	}
+	v, err := getValue(data, reference.Key)
+	if err != nil {
+		return nil, err
+	}
+	return &Secret{Value: v}, nil
+}
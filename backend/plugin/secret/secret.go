@@ -0,0 +1,212 @@
+// Package secret provides the external secret providers to resolve the data source credentials.
+package secret
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ProviderType is the type of the external secret provider.
+type ProviderType string
+
+const (
+	// ProviderVault is the HashiCorp Vault KV secrets engine, both version 1 and version 2 are supported.
+	ProviderVault ProviderType = "vault"
+	// ProviderVaultDatabase is the HashiCorp Vault database secrets engine which issues short-lived dynamic credentials.
+	ProviderVaultDatabase ProviderType = "vault-database"
+	// ProviderAWSSecretsManager is the AWS Secrets Manager.
+	ProviderAWSSecretsManager ProviderType = "aws-secrets-manager"
+	// ProviderFile reads the secret from a file, e.g. the secrets mounted by Kubernetes or Docker.
+	ProviderFile ProviderType = "file"
+	// ProviderEnv reads the secret from an environment variable.
+	ProviderEnv ProviderType = "env"
+)
+
+// DefaultCacheTTL is the default duration to cache the resolved secrets.
+const DefaultCacheTTL = 5 * time.Minute
+
+// Reference is the reference to a secret in the external secret provider.
+// It is stored on the data source instead of the secret itself, in the format of "<provider>://<path>[#<key>]", e.g.
+//   - vault://secret/data/mysql#password
+//   - vault-database://database/creds/readonly
+//   - aws-secrets-manager://prod/mysql#password
+//   - file://mysql/password
+//   - env://BB_SECRET_MYSQL_PASSWORD
+type Reference struct {
+	Provider ProviderType
+	// Path is the path of the secret in the provider.
+	Path string
+	// Key is the key of the value in a secret with multiple key-value pairs.
+	Key string
+}
+
+// ParseReference parses the secret reference.
+func ParseReference(reference string) (*Reference, error) {
+	provider, rest, ok := strings.Cut(reference, "://")
+	if !ok {
+		return nil, errors.Errorf("invalid secret reference %q, it should be in the format of <provider>://<path>[#<key>]", reference)
+	}
+	path, key, _ := strings.Cut(rest, "#")
+	if path == "" {
+		return nil, errors.Errorf("invalid secret reference %q, path is required", reference)
+	}
+	switch ProviderType(provider) {
+	case ProviderVault, ProviderVaultDatabase, ProviderAWSSecretsManager, ProviderFile, ProviderEnv:
+	default:
+		return nil, errors.Errorf("invalid secret reference %q, unsupported secret provider %q", reference, provider)
+	}
+	return &Reference{
+		Provider: ProviderType(provider),
+		Path:     path,
+		Key:      key,
+	}, nil
+}
+
+// ValidateReference validates the secret reference of the credential.
+// The credential must be empty if the reference is set, because we store the reference instead of the secret itself.
+func ValidateReference(reference, credential, credentialName string) error {
+	if reference == "" {
+		return nil
+	}
+	if credential != "" {
+		return errors.Errorf("%s and its secret reference cannot be both set", credentialName)
+	}
+	_, err := ParseReference(reference)
+	return err
+}
+
+// String returns the secret reference in the format of "<provider>://<path>[#<key>]".
+func (r *Reference) String() string {
+	s := string(r.Provider) + "://" + r.Path
+	if r.Key != "" {
+		s += "#" + r.Key
+	}
+	return s
+}
+
+// Secret is the secret resolved from the external secret provider.
+type Secret struct {
+	// Value is the secret value, e.g. the password.
+	Value string
+	// Username is only set by the providers issuing dynamic credentials, and it overrides the username of the data source.
+	Username string
+	// TTL is the lease duration of the secret. Zero means the provider doesn't specify it.
+	TTL time.Duration
+}
+
+// Provider is the interface of the external secret provider.
+type Provider interface {
+	// GetSecret gets the secret of the reference from the provider.
+	GetSecret(ctx context.Context, reference *Reference) (*Secret, error)
+}
+
+type cacheEntry struct {
+	secret   *Secret
+	expireAt time.Time
+}
+
+// Resolver resolves the secret references through the registered providers, and caches the resolved secrets.
+type Resolver struct {
+	providers map[ProviderType]Provider
+	cacheTTL  time.Duration
+	// now is overridden in tests.
+	now func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]*cacheEntry
+}
+
+// NewResolver creates a new secret resolver.
+// The resolved secrets are cached for cacheTTL, or shorter if the lease duration of the secret is shorter.
+func NewResolver(providers map[ProviderType]Provider, cacheTTL time.Duration) *Resolver {
+	return &Resolver{
+		providers: providers,
+		cacheTTL:  cacheTTL,
+		now:       time.Now,
+		cache:     make(map[string]*cacheEntry),
+	}
+}
+
+// Config is the config of the default secret providers.
+type Config struct {
+	// VaultAddress is the address of the Vault server. The Vault providers are disabled if it's empty.
+	VaultAddress string
+	// VaultToken is the token to access the Vault server.
+	VaultToken string
+	// FileDir is the directory where the file provider reads the secrets from. The file provider is disabled if it's empty.
+	FileDir string
+}
+
+// NewDefaultResolver creates a new secret resolver with all the supported providers.
+func NewDefaultResolver(config Config) *Resolver {
+	providers := map[ProviderType]Provider{
+		ProviderAWSSecretsManager: NewAWSSecretsManagerProvider(""),
+		ProviderEnv:               NewEnvProvider(),
+	}
+	if config.VaultAddress != "" {
+		vault := newVaultClient(config.VaultAddress, config.VaultToken)
+		providers[ProviderVault] = &VaultKVProvider{client: vault}
+		providers[ProviderVaultDatabase] = &VaultDatabaseProvider{client: vault}
+	}
+	if config.FileDir != "" {
+		providers[ProviderFile] = NewFileProvider(config.FileDir)
+	}
+	return NewResolver(providers, DefaultCacheTTL)
+}
+
+// Resolve parses the secret reference and resolves it.
+func (r *Resolver) Resolve(ctx context.Context, reference string) (*Secret, error) {
+	ref, err := ParseReference(reference)
+	if err != nil {
+		return nil, err
+	}
+	// Use the normalized reference as the cache key.
+	key := ref.String()
+	r.mu.Lock()
+	if entry, ok := r.cache[key]; ok {
+		if r.now().Before(entry.expireAt) {
+			r.mu.Unlock()
+			return entry.secret, nil
+		}
+		delete(r.cache, key)
+	}
+	r.mu.Unlock()
+
+	provider, ok := r.providers[ref.Provider]
+	if !ok {
+		return nil, errors.Errorf("secret provider %q is not configured", ref.Provider)
+	}
+	secret, err := provider.GetSecret(ctx, ref)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get secret from %s", ref.Provider)
+	}
+
+	ttl := r.cacheTTL
+	if secret.TTL > 0 {
+		// Refresh the leased secret before it expires, so that the new connections won't use expired credentials.
+		if leaseTTL := secret.TTL * 2 / 3; leaseTTL < ttl {
+			ttl = leaseTTL
+		}
+	}
+	if ttl > 0 {
+		r.mu.Lock()
+		r.cache[key] = &cacheEntry{secret: secret, expireAt: r.now().Add(ttl)}
+		r.mu.Unlock()
+	}
+	return secret, nil
+}
+
+// Invalidate removes the cached secret of the reference, e.g. after the secret is rotated and the connection fails.
+func (r *Resolver) Invalidate(reference string) {
+	ref, err := ParseReference(reference)
+	if err != nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.cache, ref.String())
+}
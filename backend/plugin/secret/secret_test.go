@@ -0,0 +1,246 @@
+package secret
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReference(t *testing.T) {
+	tests := []struct {
+		reference string
+		want      *Reference
+		wantErr   bool
+	}{
+		{
+			reference: "vault://secret/data/mysql#password",
+			want:      &Reference{Provider: ProviderVault, Path: "secret/data/mysql", Key: "password"},
+		},
+		{
+			reference: "vault-database://database/creds/readonly",
+			want:      &Reference{Provider: ProviderVaultDatabase, Path: "database/creds/readonly"},
+		},
+		{
+			reference: "aws-secrets-manager://arn:aws:secretsmanager:us-west-2:123456789012:secret:prod/mysql#password",
+			want:      &Reference{Provider: ProviderAWSSecretsManager, Path: "arn:aws:secretsmanager:us-west-2:123456789012:secret:prod/mysql", Key: "password"},
+		},
+		{
+			reference: "env://BB_SECRET_MYSQL_PASSWORD",
+			want:      &Reference{Provider: ProviderEnv, Path: "BB_SECRET_MYSQL_PASSWORD"},
+		},
+		{
+			reference: "secret/data/mysql",
+			wantErr:   true,
+		},
+		{
+			reference: "gcp://secret",
+			wantErr:   true,
+		},
+		{
+			reference: "file://#key",
+			wantErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		got, err := ParseReference(test.reference)
+		if test.wantErr {
+			require.Error(t, err, test.reference)
+			continue
+		}
+		require.NoError(t, err, test.reference)
+		require.Equal(t, test.want, got, test.reference)
+		require.Equal(t, test.reference, got.String())
+	}
+}
+
+type fakeProvider struct {
+	secrets []*Secret
+	calls   int
+}
+
+func (p *fakeProvider) GetSecret(_ context.Context, _ *Reference) (*Secret, error) {
+	secret := p.secrets[p.calls]
+	p.calls++
+	return secret, nil
+}
+
+func TestResolverCache(t *testing.T) {
+	a := require.New(t)
+	ctx := context.Background()
+	provider := &fakeProvider{
+		secrets: []*Secret{
+			{Value: "static-1"},
+			{Value: "static-2"},
+			{Value: "dynamic-1", Username: "v-user-1", TTL: 30 * time.Second},
+			{Value: "dynamic-2", Username: "v-user-2", TTL: 30 * time.Second},
+			{Value: "static-3"},
+		},
+	}
+	resolver := NewResolver(map[ProviderType]Provider{ProviderVault: provider, ProviderVaultDatabase: provider}, time.Minute)
+	now := time.Now()
+	resolver.now = func() time.Time { return now }
+
+	secret, err := resolver.Resolve(ctx, "vault://secret/data/mysql#password")
+	a.NoError(err)
+	a.Equal("static-1", secret.Value)
+	// Cached within the cache TTL.
+	now = now.Add(50 * time.Second)
+	secret, err = resolver.Resolve(ctx, "vault://secret/data/mysql#password")
+	a.NoError(err)
+	a.Equal("static-1", secret.Value)
+	// Refreshed after the cache TTL.
+	now = now.Add(20 * time.Second)
+	secret, err = resolver.Resolve(ctx, "vault://secret/data/mysql#password")
+	a.NoError(err)
+	a.Equal("static-2", secret.Value)
+
+	// The dynamic credentials are refreshed before the lease expires.
+	secret, err = resolver.Resolve(ctx, "vault-database://database/creds/readonly")
+	a.NoError(err)
+	a.Equal("v-user-1", secret.Username)
+	now = now.Add(15 * time.Second)
+	secret, err = resolver.Resolve(ctx, "vault-database://database/creds/readonly")
+	a.NoError(err)
+	a.Equal("v-user-1", secret.Username)
+	now = now.Add(10 * time.Second)
+	secret, err = resolver.Resolve(ctx, "vault-database://database/creds/readonly")
+	a.NoError(err)
+	a.Equal("v-user-2", secret.Username)
+
+	// Invalidate drops the cached secret.
+	resolver.Invalidate("vault://secret/data/mysql#password")
+	secret, err = resolver.Resolve(ctx, "vault://secret/data/mysql#password")
+	a.NoError(err)
+	a.Equal("static-3", secret.Value)
+
+	_, err = resolver.Resolve(ctx, "env://BB_SECRET_MYSQL_PASSWORD")
+	a.Error(err)
+}
+
+func TestVaultProvider(t *testing.T) {
+	a := require.New(t)
+	ctx := context.Background()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "root" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/kv/mysql":
+			_, _ = w.Write([]byte(`{"lease_duration":2764800,"data":{"password":"kv1-password"}}`))
+		case "/v1/secret/data/mysql":
+			_, _ = w.Write([]byte(`{"data":{"data":{"password":"kv2-password"},"metadata":{"version":3}}}`))
+		case "/v1/database/creds/readonly":
+			_, _ = w.Write([]byte(`{"lease_id":"database/creds/readonly/abc","lease_duration":3600,"renewable":true,"data":{"username":"v-token-readonly","password":"dynamic-password"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors":[]}`))
+		}
+	}))
+	defer server.Close()
+
+	kv := NewVaultKVProvider(server.URL, "root")
+	secret, err := kv.GetSecret(ctx, &Reference{Provider: ProviderVault, Path: "kv/mysql", Key: "password"})
+	a.NoError(err)
+	a.Equal(&Secret{Value: "kv1-password"}, secret)
+	secret, err = kv.GetSecret(ctx, &Reference{Provider: ProviderVault, Path: "secret/data/mysql", Key: "password"})
+	a.NoError(err)
+	a.Equal(&Secret{Value: "kv2-password"}, secret)
+	_, err = kv.GetSecret(ctx, &Reference{Provider: ProviderVault, Path: "secret/data/mysql", Key: "user"})
+	a.Error(err)
+	_, err = kv.GetSecret(ctx, &Reference{Provider: ProviderVault, Path: "secret/data/mysql"})
+	a.Error(err)
+	_, err = kv.GetSecret(ctx, &Reference{Provider: ProviderVault, Path: "secret/data/postgres", Key: "password"})
+	a.Error(err)
+	_, err = NewVaultKVProvider(server.URL, "wrong").GetSecret(ctx, &Reference{Provider: ProviderVault, Path: "kv/mysql", Key: "password"})
+	a.ErrorContains(err, "permission denied")
+
+	secret, err = NewVaultDatabaseProvider(server.URL, "root").GetSecret(ctx, &Reference{Provider: ProviderVaultDatabase, Path: "database/creds/readonly"})
+	a.NoError(err)
+	a.Equal(&Secret{Value: "dynamic-password", Username: "v-token-readonly", TTL: time.Hour}, secret)
+}
+
+func TestAWSSecretsManagerProvider(t *testing.T) {
+	a := require.New(t)
+	ctx := context.Background()
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(t.TempDir(), "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(t.TempDir(), "credentials"))
+
+	var regions []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Authorization: AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20060102/<region>/secretsmanager/aws4_request, ...
+		credential := strings.Split(strings.TrimPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential="), "/")
+		a.Len(credential, 5)
+		regions = append(regions, credential[2])
+		a.Equal("secretsmanager.GetSecretValue", r.Header.Get("X-Amz-Target"))
+		body, err := io.ReadAll(r.Body)
+		a.NoError(err)
+		request := map[string]string{}
+		a.NoError(json.Unmarshal(body, &request))
+		switch request["SecretId"] {
+		case "prod/mysql", "arn:aws:secretsmanager:us-west-2:123456789012:secret:prod/mysql":
+			_, _ = w.Write([]byte(`{"Name":"prod/mysql","SecretString":"{\"username\":\"admin\",\"password\":\"aws-password\"}"}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"__type":"ResourceNotFoundException","message":"Secrets Manager can't find the specified secret."}`))
+		}
+	}))
+	defer server.Close()
+
+	provider := NewAWSSecretsManagerProvider(server.URL)
+	secret, err := provider.GetSecret(ctx, &Reference{Provider: ProviderAWSSecretsManager, Path: "prod/mysql", Key: "password"})
+	a.NoError(err)
+	a.Equal(&Secret{Value: "aws-password"}, secret)
+	secret, err = provider.GetSecret(ctx, &Reference{Provider: ProviderAWSSecretsManager, Path: "arn:aws:secretsmanager:us-west-2:123456789012:secret:prod/mysql"})
+	a.NoError(err)
+	a.Equal(&Secret{Value: `{"username":"admin","password":"aws-password"}`}, secret)
+	_, err = provider.GetSecret(ctx, &Reference{Provider: ProviderAWSSecretsManager, Path: "prod/postgres", Key: "password"})
+	a.ErrorContains(err, "ResourceNotFoundException")
+	a.Equal([]string{"us-east-1", "us-west-2", "us-east-1"}, regions)
+}
+
+func TestLocalProvider(t *testing.T) {
+	a := require.New(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	a.NoError(os.MkdirAll(filepath.Join(dir, "mysql"), 0700))
+	a.NoError(os.WriteFile(filepath.Join(dir, "mysql", "password"), []byte("file-password\n"), 0600))
+	a.NoError(os.WriteFile(filepath.Join(dir, "mysql", "credentials.json"), []byte(`{"password":"json-password"}`), 0600))
+
+	file := NewFileProvider(filepath.Join(dir, "mysql"))
+	secret, err := file.GetSecret(ctx, &Reference{Provider: ProviderFile, Path: "password"})
+	a.NoError(err)
+	a.Equal("file-password", secret.Value)
+	secret, err = file.GetSecret(ctx, &Reference{Provider: ProviderFile, Path: "credentials.json", Key: "password"})
+	a.NoError(err)
+	a.Equal("json-password", secret.Value)
+	_, err = file.GetSecret(ctx, &Reference{Provider: ProviderFile, Path: "../mysql/password"})
+	a.NoError(err)
+	_, err = file.GetSecret(ctx, &Reference{Provider: ProviderFile, Path: "../../etc/passwd"})
+	a.ErrorContains(err, "outside of the secret directory")
+
+	t.Setenv("BB_SECRET_MYSQL_PASSWORD", "env-password")
+	t.Setenv("MYSQL_PASSWORD", "env-password")
+	env := NewEnvProvider()
+	secret, err = env.GetSecret(ctx, &Reference{Provider: ProviderEnv, Path: "BB_SECRET_MYSQL_PASSWORD"})
+	a.NoError(err)
+	a.Equal("env-password", secret.Value)
+	_, err = env.GetSecret(ctx, &Reference{Provider: ProviderEnv, Path: "MYSQL_PASSWORD"})
+	a.Error(err)
+	_, err = env.GetSecret(ctx, &Reference{Provider: ProviderEnv, Path: "BB_SECRET_NOT_EXIST"})
+	a.Error(err)
+}
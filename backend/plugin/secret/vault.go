@@ -0,0 +1,147 @@
+package secret
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const vaultRequestTimeout = 10 * time.Second
+
+// vaultClient is the client of the Vault HTTP API.
+type vaultClient struct {
+	address string
+	token   string
+	client  *http.Client
+}
+
+// vaultResponse is the response of reading a secret from Vault.
+// https://developer.hashicorp.com/vault/api-docs#reading-writing-and-listing-secrets
+type vaultResponse struct {
+	LeaseID       string         `json:"lease_id"`
+	LeaseDuration int            `json:"lease_duration"`
+	Renewable     bool           `json:"renewable"`
+	Data          map[string]any `json:"data"`
+	Errors        []string       `json:"errors"`
+}
+
+func newVaultClient(address, token string) *vaultClient {
+	return &vaultClient{
+		address: strings.TrimSuffix(address, "/"),
+		token:   token,
+		client:  &http.Client{Timeout: vaultRequestTimeout},
+	}
+}
+
+func (c *vaultClient) read(ctx context.Context, path string) (*vaultResponse, error) {
+	url := fmt.Sprintf("%s/v1/%s", c.address, strings.TrimPrefix(path, "/"))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to construct GET %s", url)
+	}
+	req.Header.Set("X-Vault-Token", c.token)
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to GET %s", url)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read response body of GET %s", url)
+	}
+
+	response := &vaultResponse{}
+	if err := json.Unmarshal(body, response); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal response body of GET %s, status code %d", url, resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("failed to read Vault secret %q, status code %d, errors %v", path, resp.StatusCode, response.Errors)
+	}
+	return response, nil
+}
+
+// VaultKVProvider reads the static secrets from the Vault KV secrets engine.
+// The reference key is required, e.g. vault://secret/data/mysql#password.
+type VaultKVProvider struct {
+	client *vaultClient
+}
+
+// NewVaultKVProvider creates a new Vault KV provider.
+func NewVaultKVProvider(address, token string) *VaultKVProvider {
+	return &VaultKVProvider{client: newVaultClient(address, token)}
+}
+
+// GetSecret gets the secret from the Vault KV secrets engine.
+func (p *VaultKVProvider) GetSecret(ctx context.Context, reference *Reference) (*Secret, error) {
+	if reference.Key == "" {
+		return nil, errors.Errorf("key is required for Vault KV secret %q", reference.Path)
+	}
+	response, err := p.client.read(ctx, reference.Path)
+	if err != nil {
+		return nil, err
+	}
+	data := response.Data
+	// KV version 2 wraps the key-value pairs in data.data along with data.metadata.
+	if nested, ok := data["data"].(map[string]any); ok {
+		if _, ok := data["metadata"]; ok {
+			data = nested
+		}
+	}
+	value, err := getValue(data, reference.Key)
+	if err != nil {
+		return nil, err
+	}
+	// The lease duration of KV version 1 is only a hint to refresh the secret, so we don't treat it as the secret TTL.
+	return &Secret{Value: value}, nil
+}
+
+// VaultDatabaseProvider issues the short-lived dynamic credentials from the Vault database secrets engine,
+// e.g. vault-database://database/creds/readonly.
+type VaultDatabaseProvider struct {
+	client *vaultClient
+}
+
+// NewVaultDatabaseProvider creates a new Vault database provider.
+func NewVaultDatabaseProvider(address, token string) *VaultDatabaseProvider {
+	return &VaultDatabaseProvider{client: newVaultClient(address, token)}
+}
+
+// GetSecret generates a new pair of username and password from the Vault database secrets engine.
+func (p *VaultDatabaseProvider) GetSecret(ctx context.Context, reference *Reference) (*Secret, error) {
+	response, err := p.client.read(ctx, reference.Path)
+	if err != nil {
+		return nil, err
+	}
+	username, err := getValue(response.Data, "username")
+	if err != nil {
+		return nil, err
+	}
+	password, err := getValue(response.Data, "password")
+	if err != nil {
+		return nil, err
+	}
+	return &Secret{
+		Value:    password,
+		Username: username,
+		TTL:      time.Duration(response.LeaseDuration) * time.Second,
+	}, nil
+}
+
+// getValue gets the string value of the key in the secret with multiple key-value pairs.
+func getValue(data map[string]any, key string) (string, error) {
+	v, ok := data[key]
+	if !ok {
+		return "", errors.Errorf("key %q not found in the secret", key)
+	}
+	value, ok := v.(string)
+	if !ok {
+		return "", errors.Errorf("value of key %q in the secret is not a string", key)
+	}
+	return value, nil
+}
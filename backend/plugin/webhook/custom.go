@@ -27,6 +27,7 @@ type CustomWebhookRequest struct {
 	CreatedTS    int64    `json:"created_ts"`
 	Issue        *Issue   `json:"issue"`
 	Project      *Project `json:"project"`
+	Anomaly      *Anomaly `json:"anomaly,omitempty"`
 }
 
 func init() {
@@ -49,6 +50,7 @@ func (*CustomReceiver) post(context Context) error {
 		CreatedTS:    context.CreatedTs,
 		Issue:        context.Issue,
 		Project:      context.Project,
+		Anomaly:      context.Anomaly,
 	}
 
 	body, err := json.Marshal(&payload)
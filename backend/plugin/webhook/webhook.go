@@ -2,6 +2,7 @@
 package webhook
 
 import (
+	"strconv"
 	"sync"
 	"time"
 
@@ -49,11 +50,13 @@ type Issue struct {
 // TaskResult is the latest result of a task.
 // The `detail` field is only present if the status is TaskFailed.
 // The `SkippedReason` field is only present if the task is skipped.
+// The `EstimatedAffectedRows` field is only present if the affected rows have been estimated.
 type TaskResult struct {
-	Name          string `json:"name"`
-	Status        string `json:"status"`
-	Detail        string `json:"detail"`
-	SkippedReason string `json:"skippedReason"`
+	Name                  string `json:"name"`
+	Status                string `json:"status"`
+	Detail                string `json:"detail"`
+	SkippedReason         string `json:"skippedReason"`
+	EstimatedAffectedRows int64  `json:"estimatedAffectedRows,omitempty"`
 }
 
 // Project object of project.
@@ -62,6 +65,16 @@ type Project struct {
 	Name string `json:"name"`
 }
 
+// Anomaly object of anomaly.
+// The `DatabaseName` field is only present for database anomalies.
+type Anomaly struct {
+	ID           int    `json:"id"`
+	Type         string `json:"type"`
+	Severity     string `json:"severity"`
+	InstanceName string `json:"instanceName"`
+	DatabaseName string `json:"databaseName,omitempty"`
+}
+
 // Context is the context of webhook.
 type Context struct {
 	URL          string
@@ -77,6 +90,7 @@ type Context struct {
 	Issue        *Issue
 	Project      *Project
 	TaskResult   *TaskResult
+	Anomaly      *Anomaly
 }
 
 // Receiver is the webhook receiver.
@@ -130,6 +144,33 @@ func (c *Context) getMetaList() []meta {
 				Value: c.TaskResult.SkippedReason,
 			})
 		}
+		if c.TaskResult.EstimatedAffectedRows > 0 {
+			m = append(m, meta{
+				Name:  "Estimated Affected Rows",
+				Value: strconv.FormatInt(c.TaskResult.EstimatedAffectedRows, 10),
+			})
+		}
+	}
+
+	if c.Anomaly != nil {
+		m = append(m, meta{
+			Name:  "Instance",
+			Value: c.Anomaly.InstanceName,
+		})
+		if c.Anomaly.DatabaseName != "" {
+			m = append(m, meta{
+				Name:  "Database",
+				Value: c.Anomaly.DatabaseName,
+			})
+		}
+		m = append(m, meta{
+			Name:  "Anomaly Type",
+			Value: c.Anomaly.Type,
+		})
+		m = append(m, meta{
+			Name:  "Severity",
+			Value: c.Anomaly.Severity,
+		})
 	}
 
 	return m
@@ -121,4 +121,35 @@ func TestContext_getMetaList(t *testing.T) {
 		}
 		a.Equal(want, context.getMetaList())
 	})
+	t.Run("anomaly", func(t *testing.T) {
+		a := require.New(t)
+		context := Context{
+			Anomaly: &Anomaly{
+				ID:           1,
+				Type:         "bb.anomaly.database.schema.drift",
+				Severity:     "CRITICAL",
+				InstanceName: "prod-mysql",
+				DatabaseName: "employee",
+			},
+		}
+		want := []meta{
+			{
+				Name:  "Instance",
+				Value: "prod-mysql",
+			},
+			{
+				Name:  "Database",
+				Value: "employee",
+			},
+			{
+				Name:  "Anomaly Type",
+				Value: "bb.anomaly.database.schema.drift",
+			},
+			{
+				Name:  "Severity",
+				Value: "CRITICAL",
+			},
+		}
+		a.Equal(want, context.getMetaList())
+	})
 }
@@ -0,0 +1,103 @@
+// Package accessrun is the runner for revoking the expired database access grants.
+package accessrun
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/backend/common/log"
+	"github.com/bytebase/bytebase/backend/component/activity"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+const (
+	accessRunnerInterval = 30 * time.Second
+)
+
+// NewRunner creates a new access grant runner.
+func NewRunner(store *store.Store, activityManager *activity.Manager) *Runner {
+	return &Runner{
+		store:           store,
+		activityManager: activityManager,
+	}
+}
+
+// Runner is the access grant runner revoking the expired access grants.
+type Runner struct {
+	store           *store.Store
+	activityManager *activity.Manager
+}
+
+// Run starts the access grant runner.
+func (r *Runner) Run(ctx context.Context, wg *sync.WaitGroup) {
+	ticker := time.NewTicker(accessRunnerInterval)
+	defer ticker.Stop()
+	defer wg.Done()
+	log.Debug(fmt.Sprintf("Access grant runner started and will run every %v", accessRunnerInterval))
+	for {
+		select {
+		case <-ticker.C:
+			r.revokeExpiredAccessGrants(ctx)
+		case <-ctx.Done(): // if cancel() execute
+			return
+		}
+	}
+}
+
+// revokeExpiredAccessGrants revokes the expired access grants, and comments on the access request issues.
+// The expired grants no longer allow queries even before they're revoked here.
+func (r *Runner) revokeExpiredAccessGrants(ctx context.Context) {
+	now := time.Now().Unix()
+	grants, err := r.store.ListAccessGrants(ctx, &store.FindAccessGrantMessage{ExpiresBefore: &now})
+	if err != nil {
+		log.Error("Failed to list expired access grants", zap.Error(err))
+		return
+	}
+	for _, grant := range grants {
+		if err := r.revokeAccessGrant(ctx, grant); err != nil {
+			log.Error("Failed to revoke expired access grant", zap.Int("id", grant.UID), zap.Error(err))
+		}
+	}
+}
+
+func (r *Runner) revokeAccessGrant(ctx context.Context, grant *store.AccessGrantMessage) error {
+	revoked, err := r.store.RevokeAccessGrant(ctx, grant.UID, api.SystemBotID)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return nil
+	}
+
+	issue, err := r.store.GetIssueV2(ctx, &store.FindIssueMessage{UID: &grant.IssueID})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get issue %d", grant.IssueID)
+	}
+	if issue == nil {
+		return errors.Errorf("issue %d not found", grant.IssueID)
+	}
+	payload, err := json.Marshal(api.ActivityIssueCommentCreatePayload{
+		IssueName: issue.Title,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal ActivityIssueCommentCreatePayload")
+	}
+	if _, err := r.activityManager.CreateActivity(ctx, &api.ActivityCreate{
+		CreatorID:   api.SystemBotID,
+		ContainerID: issue.UID,
+		Type:        api.ActivityIssueCommentCreate,
+		Level:       api.ActivityInfo,
+		Comment:     fmt.Sprintf("The database access granted by this issue expired at %s and has been revoked.", time.Unix(grant.ExpiresTs, 0).UTC().Format(time.RFC3339)),
+		Payload:     string(payload),
+	}, &activity.Metadata{Issue: issue}); err != nil {
+		return errors.Wrap(err, "failed to create activity after revoking the access grant")
+	}
+	return nil
+}
@@ -14,6 +14,7 @@ import (
 
 	"github.com/bytebase/bytebase/backend/common"
 	"github.com/bytebase/bytebase/backend/common/log"
+	"github.com/bytebase/bytebase/backend/component/activity"
 	"github.com/bytebase/bytebase/backend/component/dbfactory"
 	enterpriseAPI "github.com/bytebase/bytebase/backend/enterprise/api"
 	api "github.com/bytebase/bytebase/backend/legacyapi"
@@ -27,19 +28,21 @@ const (
 )
 
 // NewScanner creates a anomaly scanner.
-func NewScanner(store *store.Store, dbFactory *dbfactory.DBFactory, licenseService enterpriseAPI.LicenseService) *Scanner {
+func NewScanner(store *store.Store, dbFactory *dbfactory.DBFactory, activityManager *activity.Manager, licenseService enterpriseAPI.LicenseService) *Scanner {
 	return &Scanner{
-		store:          store,
-		dbFactory:      dbFactory,
-		licenseService: licenseService,
+		store:           store,
+		dbFactory:       dbFactory,
+		activityManager: activityManager,
+		licenseService:  licenseService,
 	}
 }
 
 // Scanner is the anomaly scanner.
 type Scanner struct {
-	store          *store.Store
-	dbFactory      *dbfactory.DBFactory
-	licenseService enterpriseAPI.LicenseService
+	store           *store.Store
+	dbFactory       *dbfactory.DBFactory
+	activityManager *activity.Manager
+	licenseService  enterpriseAPI.LicenseService
 }
 
 // Run will run the anomaly scanner once.
@@ -164,7 +167,7 @@ func (s *Scanner) checkInstanceAnomaly(ctx context.Context, instance *store.Inst
 				zap.String("type", string(api.AnomalyInstanceConnection)),
 				zap.Error(err))
 		} else {
-			if _, err = s.store.UpsertActiveAnomaly(ctx, &api.AnomalyUpsert{
+			if _, err = s.upsertActiveAnomaly(ctx, &api.AnomalyUpsert{
 				CreatorID:  api.SystemBotID,
 				InstanceID: instance.UID,
 				Type:       api.AnomalyInstanceConnection,
@@ -180,7 +183,7 @@ func (s *Scanner) checkInstanceAnomaly(ctx context.Context, instance *store.Inst
 	}
 
 	defer driver.Close(ctx)
-	err = s.store.ArchiveAnomaly(ctx, &api.AnomalyArchive{
+	err = s.archiveAnomaly(ctx, &api.AnomalyArchive{
 		InstanceID: &instance.UID,
 		Type:       api.AnomalyInstanceConnection,
 	})
@@ -201,7 +204,7 @@ func (s *Scanner) checkInstanceAnomaly(ctx context.Context, instance *store.Inst
 				zap.Error(err))
 		} else {
 			if setup {
-				if _, err = s.store.UpsertActiveAnomaly(ctx, &api.AnomalyUpsert{
+				if _, err = s.upsertActiveAnomaly(ctx, &api.AnomalyUpsert{
 					CreatorID:  api.SystemBotID,
 					InstanceID: instance.UID,
 					Type:       api.AnomalyInstanceMigrationSchema,
@@ -212,7 +215,7 @@ func (s *Scanner) checkInstanceAnomaly(ctx context.Context, instance *store.Inst
 						zap.Error(err))
 				}
 			} else {
-				err := s.store.ArchiveAnomaly(ctx, &api.AnomalyArchive{
+				err := s.archiveAnomaly(ctx, &api.AnomalyArchive{
 					InstanceID: &instance.UID,
 					Type:       api.AnomalyInstanceMigrationSchema,
 				})
@@ -243,7 +246,7 @@ func (s *Scanner) checkDatabaseAnomaly(ctx context.Context, instance *store.Inst
 				zap.String("type", string(api.AnomalyDatabaseConnection)),
 				zap.Error(err))
 		} else {
-			if _, err = s.store.UpsertActiveAnomaly(ctx, &api.AnomalyUpsert{
+			if _, err = s.upsertActiveAnomaly(ctx, &api.AnomalyUpsert{
 				CreatorID:  api.SystemBotID,
 				InstanceID: instance.UID,
 				DatabaseID: &database.UID,
@@ -260,7 +263,7 @@ func (s *Scanner) checkDatabaseAnomaly(ctx context.Context, instance *store.Inst
 		return
 	}
 	defer driver.Close(ctx)
-	err = s.store.ArchiveAnomaly(ctx, &api.AnomalyArchive{
+	err = s.archiveAnomaly(ctx, &api.AnomalyArchive{
 		DatabaseID: &database.UID,
 		Type:       api.AnomalyDatabaseConnection,
 	})
@@ -332,7 +335,7 @@ func (s *Scanner) checkDatabaseAnomaly(ctx context.Context, instance *store.Inst
 						zap.String("type", string(api.AnomalyDatabaseSchemaDrift)),
 						zap.Error(err))
 				} else {
-					if _, err = s.store.UpsertActiveAnomaly(ctx, &api.AnomalyUpsert{
+					if _, err = s.upsertActiveAnomaly(ctx, &api.AnomalyUpsert{
 						CreatorID:  api.SystemBotID,
 						InstanceID: instance.UID,
 						DatabaseID: &database.UID,
@@ -347,7 +350,7 @@ func (s *Scanner) checkDatabaseAnomaly(ctx context.Context, instance *store.Inst
 					}
 				}
 			} else {
-				err := s.store.ArchiveAnomaly(ctx, &api.AnomalyArchive{
+				err := s.archiveAnomaly(ctx, &api.AnomalyArchive{
 					DatabaseID: &database.UID,
 					Type:       api.AnomalyDatabaseSchemaDrift,
 				})
@@ -412,7 +415,7 @@ func (s *Scanner) checkBackupAnomaly(ctx context.Context, environment *store.Env
 					zap.String("type", string(api.AnomalyDatabaseBackupPolicyViolation)),
 					zap.Error(err))
 			} else {
-				if _, err = s.store.UpsertActiveAnomaly(ctx, &api.AnomalyUpsert{
+				if _, err = s.upsertActiveAnomaly(ctx, &api.AnomalyUpsert{
 					CreatorID:  api.SystemBotID,
 					InstanceID: instance.UID,
 					DatabaseID: &database.UID,
@@ -427,7 +430,7 @@ func (s *Scanner) checkBackupAnomaly(ctx context.Context, environment *store.Env
 				}
 			}
 		} else {
-			err := s.store.ArchiveAnomaly(ctx, &api.AnomalyArchive{
+			err := s.archiveAnomaly(ctx, &api.AnomalyArchive{
 				DatabaseID: &database.UID,
 				Type:       api.AnomalyDatabaseBackupPolicyViolation,
 			})
@@ -495,7 +498,7 @@ func (s *Scanner) checkBackupAnomaly(ctx context.Context, environment *store.Env
 					zap.String("type", string(api.AnomalyDatabaseBackupMissing)),
 					zap.Error(err))
 			} else {
-				if _, err = s.store.UpsertActiveAnomaly(ctx, &api.AnomalyUpsert{
+				if _, err = s.upsertActiveAnomaly(ctx, &api.AnomalyUpsert{
 					CreatorID:  api.SystemBotID,
 					InstanceID: instance.UID,
 					DatabaseID: &database.UID,
@@ -510,7 +513,7 @@ func (s *Scanner) checkBackupAnomaly(ctx context.Context, environment *store.Env
 				}
 			}
 		} else {
-			err := s.store.ArchiveAnomaly(ctx, &api.AnomalyArchive{
+			err := s.archiveAnomaly(ctx, &api.AnomalyArchive{
 				DatabaseID: &database.UID,
 				Type:       api.AnomalyDatabaseBackupMissing,
 			})
@@ -524,3 +527,72 @@ func (s *Scanner) checkBackupAnomaly(ctx context.Context, environment *store.Env
 		}
 	}
 }
+
+// upsertActiveAnomaly upserts the active anomaly, and creates an anomaly create activity if the anomaly is newly detected.
+func (s *Scanner) upsertActiveAnomaly(ctx context.Context, upsert *api.AnomalyUpsert) (*api.Anomaly, error) {
+	status := api.Normal
+	find := &api.AnomalyFind{
+		RowStatus:  &status,
+		InstanceID: &upsert.InstanceID,
+		DatabaseID: upsert.DatabaseID,
+		Type:       &upsert.Type,
+		// Only look for the instance anomaly if the database is not specified.
+		InstanceOnly: upsert.DatabaseID == nil,
+	}
+	activeList, err := s.store.FindAnomaly(ctx, find)
+	if err != nil {
+		return nil, err
+	}
+	anomaly, err := s.store.UpsertActiveAnomaly(ctx, upsert)
+	if err != nil {
+		return nil, err
+	}
+	if len(activeList) == 0 {
+		if _, err := s.activityManager.CreateAnomalyActivity(ctx, api.ActivityAnomalyCreate, anomaly); err != nil {
+			log.Error("Failed to create anomaly activity",
+				zap.Int("anomaly_id", anomaly.ID),
+				zap.String("type", string(anomaly.Type)),
+				zap.Error(err))
+		}
+	}
+	return anomaly, nil
+}
+
+func (s *Scanner) archiveAnomaly(ctx context.Context, archive *api.AnomalyArchive) error {
+	return ArchiveAnomaly(ctx, s.store, s.activityManager, archive)
+}
+
+// ArchiveAnomaly archives the active anomaly, and creates an anomaly resolve activity for it.
+// Returns ENOTFOUND if there is no active anomaly.
+func ArchiveAnomaly(ctx context.Context, stores *store.Store, activityManager *activity.Manager, archive *api.AnomalyArchive) error {
+	status := api.Normal
+	find := &api.AnomalyFind{
+		RowStatus: &status,
+		Type:      &archive.Type,
+	}
+	if archive.DatabaseID != nil {
+		find.DatabaseID = archive.DatabaseID
+	} else if archive.InstanceID != nil {
+		find.InstanceID = archive.InstanceID
+		find.InstanceOnly = true
+	}
+	activeList, err := stores.FindAnomaly(ctx, find)
+	if err != nil {
+		return err
+	}
+	if len(activeList) == 0 {
+		return &common.Error{Code: common.NotFound, Err: errors.Errorf("active anomaly not found with filter %+v", find)}
+	}
+	if err := stores.ArchiveAnomaly(ctx, archive); err != nil {
+		return err
+	}
+	for _, anomaly := range activeList {
+		if _, err := activityManager.CreateAnomalyActivity(ctx, api.ActivityAnomalyResolve, anomaly); err != nil {
+			log.Error("Failed to create anomaly activity",
+				zap.Int("anomaly_id", anomaly.ID),
+				zap.String("type", string(anomaly.Type)),
+				zap.Error(err))
+		}
+	}
+	return nil
+}
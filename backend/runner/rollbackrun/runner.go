@@ -159,7 +159,7 @@ func (r *Runner) generateRollbackSQLImpl(ctx context.Context, task *store.TaskMe
 		return "", errors.WithMessage(err, "failed to parse the schema")
 	}
 
-	mysqlDriver, ok := driver.(*mysql.Driver)
+	mysqlDriver, ok := dbfactory.Unwrap(driver).(*mysql.Driver)
 	if !ok {
 		return "", errors.Errorf("failed to cast driver to mysql.Driver")
 	}
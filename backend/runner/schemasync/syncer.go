@@ -67,6 +67,21 @@ func (s *Syncer) Run(ctx context.Context, wg *sync.WaitGroup) {
 	}
 }
 
+// RunSyncRequests only syncs the instances requested by the API.
+// It's used in the HA mode, where the replica serving the request may not be the leader running the syncer.
+func (s *Syncer) RunSyncRequests(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+	for {
+		select {
+		case instance := <-s.stateCfg.InstanceDatabaseSyncChan:
+			// Sync all databases for instance.
+			s.syncAllDatabases(ctx, instance)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
 func (s *Syncer) syncAllInstances(ctx context.Context) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -0,0 +1,186 @@
+package taskcheck
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/backend/common"
+	"github.com/bytebase/bytebase/backend/common/log"
+	"github.com/bytebase/bytebase/backend/component/activity"
+	"github.com/bytebase/bytebase/backend/component/dbfactory"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/db"
+	"github.com/bytebase/bytebase/backend/plugin/db/util"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+const (
+	// verificationNullResult is the result text of the NULL value.
+	verificationNullResult = "NULL"
+	// verificationTimeout is the timeout of running the verification statement.
+	verificationTimeout = 1 * time.Minute
+)
+
+// NewDatabaseVerificationExecutor creates a task check database verification executor.
+func NewDatabaseVerificationExecutor(store *store.Store, dbFactory *dbfactory.DBFactory, activityManager *activity.Manager) Executor {
+	return &DatabaseVerificationExecutor{
+		store:           store,
+		dbFactory:       dbFactory,
+		activityManager: activityManager,
+	}
+}
+
+// DatabaseVerificationExecutor is the task check database verification executor.
+// It runs the post-deployment verification statement after the task is done, and compares the result with the expected result.
+type DatabaseVerificationExecutor struct {
+	store           *store.Store
+	dbFactory       *dbfactory.DBFactory
+	activityManager *activity.Manager
+}
+
+// Run will run the task check database verification executor once.
+func (e *DatabaseVerificationExecutor) Run(ctx context.Context, taskCheckRun *api.TaskCheckRun, task *api.Task) (result []api.TaskCheckResult, err error) {
+	payload := &api.TaskCheckDatabaseVerificationPayload{}
+	if err := json.Unmarshal([]byte(taskCheckRun.Payload), payload); err != nil {
+		return nil, common.Wrapf(err, common.Invalid, "invalid database verification payload")
+	}
+	if task.Database == nil {
+		return nil, common.Errorf(common.Invalid, "task %d has no database to verify", task.ID)
+	}
+	instance, err := e.store.GetInstanceV2(ctx, &store.FindInstanceMessage{UID: &task.InstanceID})
+	if err != nil {
+		return nil, err
+	}
+	if instance == nil {
+		return nil, common.Errorf(common.Internal, "instance %d not found", task.InstanceID)
+	}
+
+	expectedResult := strings.TrimSpace(payload.ExpectedResult)
+	actualResult, err := e.runVerification(ctx, instance, task.Database.Name, payload.Statement)
+	if err == nil && actualResult == expectedResult {
+		return []api.TaskCheckResult{
+			{
+				Status:    api.TaskCheckStatusSuccess,
+				Namespace: api.BBNamespace,
+				Code:      common.Ok.Int(),
+				Title:     "OK",
+				Content:   fmt.Sprintf("The verification result %q equals the expected result", actualResult),
+			},
+		}, nil
+	}
+
+	activityPayload := api.ActivityPipelineTaskVerificationFailPayload{
+		TaskID:         task.ID,
+		Statement:      payload.Statement,
+		ExpectedResult: expectedResult,
+		TaskName:       task.Name,
+	}
+	checkResult := api.TaskCheckResult{
+		Status:    api.TaskCheckStatusError,
+		Namespace: api.BBNamespace,
+		Code:      common.TaskVerificationFailed.Int(),
+		Title:     "Verification failed",
+	}
+	if err != nil {
+		activityPayload.Error = err.Error()
+		checkResult.Content = fmt.Sprintf("Failed to run the verification statement: %s", err.Error())
+	} else {
+		activityPayload.ActualResult = actualResult
+		checkResult.Content = fmt.Sprintf("The verification result %q doesn't equal the expected result %q", actualResult, expectedResult)
+	}
+	if err := e.createVerificationFailActivity(ctx, task, activityPayload, checkResult.Content); err != nil {
+		log.Error("Failed to create the verification failure activity", zap.Int("task_id", task.ID), zap.Error(err))
+	}
+	return []api.TaskCheckResult{checkResult}, nil
+}
+
+func (e *DatabaseVerificationExecutor) runVerification(ctx context.Context, instance *store.InstanceMessage, databaseName, statement string) (string, error) {
+	if !util.ValidateSQLSelectStatement(statement) {
+		return "", errors.Errorf("the verification statement must be a single SELECT statement")
+	}
+	ctx, cancel := context.WithTimeout(ctx, verificationTimeout)
+	defer cancel()
+
+	driver, err := e.dbFactory.GetReadOnlyDatabaseDriver(ctx, instance, databaseName)
+	if err != nil {
+		return "", err
+	}
+	defer driver.Close(ctx)
+	connection, err := driver.GetDBConnection(ctx, databaseName)
+	if err != nil {
+		return "", err
+	}
+	// TiDB, ClickHouse and Snowflake don't support READ ONLY transactions, see util.Query().
+	readOnly := instance.Engine != db.TiDB && instance.Engine != db.ClickHouse && instance.Engine != db.Snowflake
+	tx, err := connection.BeginTx(ctx, &sql.TxOptions{ReadOnly: readOnly})
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+	rows, err := tx.QueryContext(ctx, statement)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+	return readVerificationResult(rows)
+}
+
+// readVerificationResult returns the first column of the first row in text.
+// It returns NULL for the null value, and an empty string if there is no row.
+func readVerificationResult(rows *sql.Rows) (string, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return "", err
+	}
+	if len(columns) == 0 {
+		return "", errors.Errorf("the verification statement returns no column")
+	}
+	if !rows.Next() {
+		return "", rows.Err()
+	}
+	values := make([]sql.NullString, len(columns))
+	scanArgs := make([]interface{}, len(columns))
+	for i := range values {
+		scanArgs[i] = &values[i]
+	}
+	if err := rows.Scan(scanArgs...); err != nil {
+		return "", err
+	}
+	if !values[0].Valid {
+		return verificationNullResult, nil
+	}
+	return strings.TrimSpace(values[0].String), nil
+}
+
+func (e *DatabaseVerificationExecutor) createVerificationFailActivity(ctx context.Context, task *api.Task, activityPayload api.ActivityPipelineTaskVerificationFailPayload, comment string) error {
+	issue, err := e.store.GetIssueV2(ctx, &store.FindIssueMessage{PipelineID: &task.PipelineID})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get issue of pipeline %d", task.PipelineID)
+	}
+	if issue == nil {
+		return nil
+	}
+	activityPayload.IssueName = issue.Title
+	payload, err := json.Marshal(activityPayload)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal ActivityPipelineTaskVerificationFailPayload")
+	}
+	if _, err := e.activityManager.CreateActivity(ctx, &api.ActivityCreate{
+		CreatorID:   api.SystemBotID,
+		ContainerID: issue.UID,
+		Type:        api.ActivityPipelineTaskVerificationFail,
+		Level:       api.ActivityError,
+		Comment:     comment,
+		Payload:     string(payload),
+	}, &activity.Metadata{Issue: issue}); err != nil {
+		return errors.Wrap(err, "failed to create activity")
+	}
+	return nil
+}
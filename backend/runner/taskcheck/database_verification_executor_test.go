@@ -0,0 +1,49 @@
+package taskcheck
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	// Import sqlite3 driver.
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadVerificationResult(t *testing.T) {
+	tests := []struct {
+		statement string
+		want      string
+	}{
+		{
+			statement: "SELECT 42, 'ignored'",
+			want:      "42",
+		},
+		{
+			statement: "SELECT ' ok '",
+			want:      "ok",
+		},
+		{
+			statement: "SELECT NULL",
+			want:      "NULL",
+		},
+		{
+			statement: "SELECT 1 WHERE 1 = 0",
+			want:      "",
+		},
+	}
+
+	a := require.New(t)
+	ctx := context.Background()
+	connection, err := sql.Open("sqlite3", ":memory:")
+	a.NoError(err)
+	defer connection.Close()
+	for _, test := range tests {
+		rows, err := connection.QueryContext(ctx, test.statement)
+		a.NoError(err)
+		got, err := readVerificationResult(rows)
+		a.NoError(err)
+		a.NoError(rows.Close())
+		a.Equal(test.want, got, test.statement)
+	}
+}
@@ -8,23 +8,24 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/bytebase/bytebase/backend/common"
+	"github.com/bytebase/bytebase/backend/component/dbfactory"
 	api "github.com/bytebase/bytebase/backend/legacyapi"
 	"github.com/bytebase/bytebase/backend/store"
 	"github.com/bytebase/bytebase/backend/utils"
 )
 
 // NewGhostSyncExecutor creates a task check gh-ost sync executor.
-func NewGhostSyncExecutor(store *store.Store, secret string) Executor {
+func NewGhostSyncExecutor(store *store.Store, dbFactory *dbfactory.DBFactory) Executor {
 	return &GhostSyncExecutor{
-		store:  store,
-		secret: secret,
+		store:     store,
+		dbFactory: dbFactory,
 	}
 }
 
 // GhostSyncExecutor is the task check gh-ost sync executor.
 type GhostSyncExecutor struct {
-	store  *store.Store
-	secret string
+	store     *store.Store
+	dbFactory *dbfactory.DBFactory
 }
 
 // Run will run the task check database connector executor once.
@@ -88,10 +89,11 @@ func (e *GhostSyncExecutor) Run(ctx context.Context, _ *api.TaskCheckRun, task *
 		return nil, common.Wrapf(err, common.Internal, "failed to parse table name from statement, statement: %v", payload.Statement)
 	}
 
-	config, err := utils.GetGhostConfig(task.ID, database, adminDataSource, e.secret, instanceUsers, tableName, payload.Statement, true, 20000000)
+	username, password, err := e.dbFactory.GetDataSourcePassword(ctx, adminDataSource)
 	if err != nil {
 		return nil, err
 	}
+	config := utils.GetGhostConfig(task.ID, database, adminDataSource, username, password, instanceUsers, tableName, payload.Statement, true, 20000000)
 
 	migrationContext, err := utils.NewMigrationContext(config)
 	if err != nil {
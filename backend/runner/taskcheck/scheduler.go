@@ -240,6 +240,18 @@ func (s *Scheduler) getTaskCheck(ctx context.Context, project *store.ProjectMess
 		return createList, nil
 	}
 
+	// The done task only reruns the post-deployment verification, the pre-deployment checks are meaningless after the deployment.
+	if task.Status == api.TaskDone {
+		verificationCreate, err := utils.GetVerificationTaskCheckRunCreate(task, creatorID)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to schedule verification task check")
+		}
+		if verificationCreate != nil {
+			createList = append(createList, verificationCreate)
+		}
+		return createList, nil
+	}
+
 	create, err = s.getGeneralTaskCheck(task, creatorID)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to schedule general task check")
@@ -306,6 +318,14 @@ func (s *Scheduler) getTaskCheck(ctx context.Context, project *store.ProjectMess
 		createList = append(createList, create...)
 	}
 
+	create, err = getStatementAffectedRowsTaskCheck(task, instance, statement)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to schedule statement affected rows task check")
+	}
+	if create != nil {
+		createList = append(createList, create...)
+	}
+
 	return createList, nil
 }
 
@@ -341,6 +361,27 @@ func getStmtTypeTaskCheck(task *store.TaskMessage, instance *store.InstanceMessa
 	}, nil
 }
 
+func getStatementAffectedRowsTaskCheck(task *store.TaskMessage, instance *store.InstanceMessage, statement string) ([]*store.TaskCheckRunCreate, error) {
+	if task.Type != api.TaskDatabaseDataUpdate || !api.IsStatementAffectedRowsCheckSupported(instance.Engine) {
+		return nil, nil
+	}
+	payload, err := json.Marshal(api.TaskCheckDatabaseStatementAffectedRowsPayload{
+		Statement: statement,
+		DbType:    instance.Engine,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal statement affected rows payload: %v", task.Name)
+	}
+	return []*store.TaskCheckRunCreate{
+		{
+			CreatorID: api.SystemBotID,
+			TaskID:    task.ID,
+			Type:      api.TaskCheckDatabaseStatementAffectedRows,
+			Payload:   string(payload),
+		},
+	}, nil
+}
+
 func (*Scheduler) getSQLReviewTaskCheck(task *store.TaskMessage, instance *store.InstanceMessage, dbSchema *store.DBSchema, statement string) ([]*store.TaskCheckRunCreate, error) {
 	if !api.IsSQLReviewSupported(instance.Engine) {
 		return nil, nil
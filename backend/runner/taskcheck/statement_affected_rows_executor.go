@@ -0,0 +1,323 @@
+package taskcheck
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tidbparser "github.com/pingcap/tidb/parser"
+	tidbast "github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/format"
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/common"
+	"github.com/bytebase/bytebase/backend/component/dbfactory"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/advisor"
+	"github.com/bytebase/bytebase/backend/plugin/db"
+	"github.com/bytebase/bytebase/backend/plugin/parser"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+const (
+	// affectedRowsSampleLimit is the max number of rows counted by the sampling query.
+	// The sampling query only runs if the EXPLAIN estimate of the scanned rows is within the limit,
+	// otherwise we use the EXPLAIN estimate.
+	affectedRowsSampleLimit = 10000
+	// affectedRowsStatementDisplayLimit is the max length of the statement shown in the check result.
+	affectedRowsStatementDisplayLimit = 200
+)
+
+// NewStatementAffectedRowsExecutor creates a task check statement affected rows executor.
+func NewStatementAffectedRowsExecutor(store *store.Store, dbFactory *dbfactory.DBFactory) Executor {
+	return &StatementAffectedRowsExecutor{
+		store:     store,
+		dbFactory: dbFactory,
+	}
+}
+
+// StatementAffectedRowsExecutor is the task check statement affected rows executor.
+// It estimates the affected rows of every data change statement.
+type StatementAffectedRowsExecutor struct {
+	store     *store.Store
+	dbFactory *dbfactory.DBFactory
+}
+
+// Run will run the task check statement affected rows executor once.
+func (e *StatementAffectedRowsExecutor) Run(ctx context.Context, taskCheckRun *api.TaskCheckRun, task *api.Task) (result []api.TaskCheckResult, err error) {
+	payload := &api.TaskCheckDatabaseStatementAffectedRowsPayload{}
+	if err := json.Unmarshal([]byte(taskCheckRun.Payload), payload); err != nil {
+		return nil, common.Wrapf(err, common.Invalid, "invalid check statement affected rows payload")
+	}
+	if !api.IsStatementAffectedRowsCheckSupported(payload.DbType) {
+		return nil, common.Errorf(common.Invalid, "invalid check statement affected rows database type: %s", payload.DbType)
+	}
+
+	engineType := parser.EngineType(payload.DbType)
+	splitEngineType := engineType
+	if engineType == parser.TiDB {
+		splitEngineType = parser.MySQL
+	}
+	singleSQLs, err := parser.SplitMultiSQL(splitEngineType, payload.Statement)
+	if err != nil {
+		//nolint:nilerr
+		return []api.TaskCheckResult{
+			{
+				Status:    api.TaskCheckStatusError,
+				Namespace: api.AdvisorNamespace,
+				Code:      advisor.StatementSyntaxError.Int(),
+				Title:     "Syntax error",
+				Content:   err.Error(),
+			},
+		}, nil
+	}
+	var dataChangeSQLs []parser.SingleSQL
+	for _, singleSQL := range singleSQLs {
+		if parser.IsDataChangeStatement(engineType, singleSQL.Text) {
+			dataChangeSQLs = append(dataChangeSQLs, singleSQL)
+		}
+	}
+	if len(dataChangeSQLs) == 0 {
+		return []api.TaskCheckResult{
+			{
+				Status:    api.TaskCheckStatusSuccess,
+				Namespace: api.BBNamespace,
+				Code:      common.Ok.Int(),
+				Title:     "OK",
+				Content:   "No data change statement found",
+			},
+		}, nil
+	}
+
+	instance, err := e.store.GetInstanceV2(ctx, &store.FindInstanceMessage{UID: &task.InstanceID})
+	if err != nil {
+		return nil, err
+	}
+	if instance == nil {
+		return nil, common.Errorf(common.Internal, "instance %d not found", task.InstanceID)
+	}
+	driver, err := e.dbFactory.GetReadOnlyDatabaseDriver(ctx, instance, task.Database.Name)
+	if err != nil {
+		return nil, err
+	}
+	defer driver.Close(ctx)
+	connection, err := driver.GetDBConnection(ctx, task.Database.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, singleSQL := range dataChangeSQLs {
+		var affectedRows int64
+		var err error
+		switch payload.DbType {
+		case db.MySQL, db.TiDB:
+			affectedRows, err = estimateMySQLAffectedRows(ctx, connection, payload.DbType, singleSQL.Text)
+		case db.Postgres:
+			affectedRows, err = estimatePostgresAffectedRows(ctx, connection, singleSQL.Text)
+		}
+		displayStatement, _ := common.TruncateString(strings.TrimSpace(singleSQL.Text), affectedRowsStatementDisplayLimit)
+		if err != nil {
+			result = append(result, api.TaskCheckResult{
+				Status:    api.TaskCheckStatusWarn,
+				Namespace: api.BBNamespace,
+				Code:      common.DbExecutionError.Int(),
+				Title:     "Failed to estimate affected rows",
+				Content:   fmt.Sprintf("Failed to estimate the affected rows of %q: %s", displayStatement, err.Error()),
+				Line:      singleSQL.LastLine,
+			})
+			continue
+		}
+		result = append(result, api.TaskCheckResult{
+			Status:       api.TaskCheckStatusSuccess,
+			Namespace:    api.BBNamespace,
+			Code:         common.Ok.Int(),
+			Title:        "Estimated affected rows",
+			Content:      fmt.Sprintf("The statement %q affects about %d rows", displayStatement, affectedRows),
+			Line:         singleSQL.LastLine,
+			AffectedRows: affectedRows,
+		})
+	}
+	return result, nil
+}
+
+// estimateMySQLAffectedRows estimates the affected rows for MySQL and TiDB.
+// For INSERT ... VALUES statements, we count the value lists. Otherwise, we use the EXPLAIN estimate.
+// For single table UPDATE and DELETE statements scanning few rows, we count the matched rows with a bounded sampling query.
+// The sampling query doesn't run for the large scans, because it may scan the whole table if the WHERE clause isn't covered by any index.
+func estimateMySQLAffectedRows(ctx context.Context, connection *sql.DB, dbType db.Type, statement string) (int64, error) {
+	sampleQuery := ""
+	p := tidbparser.New()
+	p.EnableWindowFunc(true)
+	if node, err := p.ParseOneStmt(statement, "", ""); err == nil {
+		if insert, ok := node.(*tidbast.InsertStmt); ok && insert.Select == nil {
+			return int64(len(insert.Lists)), nil
+		}
+		if sampleQuery, err = getMySQLSampleCountQuery(node); err != nil {
+			return 0, err
+		}
+	}
+
+	estimatedRows, err := explainMySQLAffectedRows(ctx, connection, dbType, statement)
+	if err != nil {
+		return 0, err
+	}
+	if sampleQuery == "" || estimatedRows > affectedRowsSampleLimit {
+		return estimatedRows, nil
+	}
+	var count int64
+	if err := connection.QueryRowContext(ctx, sampleQuery).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// explainMySQLAffectedRows returns the EXPLAIN estimate of the rows scanned by the statement.
+func explainMySQLAffectedRows(ctx context.Context, connection *sql.DB, dbType db.Type, statement string) (int64, error) {
+	rows, err := connection.QueryContext(ctx, fmt.Sprintf("EXPLAIN %s", statement))
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, err
+	}
+	var rowList [][]sql.NullString
+	for rows.Next() {
+		row := make([]sql.NullString, len(columns))
+		scanArgs := make([]any, len(columns))
+		for i := range row {
+			scanArgs[i] = &row[i]
+		}
+		if err := rows.Scan(scanArgs...); err != nil {
+			return 0, err
+		}
+		rowList = append(rowList, row)
+	}
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	return getAffectedRowsFromMySQLExplain(dbType, columns, rowList)
+}
+
+// getMySQLSampleCountQuery returns the bounded count query for single table UPDATE and DELETE statements.
+// It returns an empty string if the statement is not supported.
+func getMySQLSampleCountQuery(node tidbast.StmtNode) (string, error) {
+	var tableRefs *tidbast.TableRefsClause
+	var where tidbast.ExprNode
+	switch stmt := node.(type) {
+	case *tidbast.UpdateStmt:
+		if stmt.MultipleTable {
+			return "", nil
+		}
+		tableRefs, where = stmt.TableRefs, stmt.Where
+	case *tidbast.DeleteStmt:
+		if stmt.IsMultiTable {
+			return "", nil
+		}
+		tableRefs, where = stmt.TableRefs, stmt.Where
+	default:
+		return "", nil
+	}
+	if tableRefs == nil {
+		return "", nil
+	}
+
+	var buf strings.Builder
+	restoreCtx := format.NewRestoreCtx(format.DefaultRestoreFlags, &buf)
+	if _, err := buf.WriteString("SELECT COUNT(*) FROM (SELECT 1 FROM "); err != nil {
+		return "", err
+	}
+	if err := tableRefs.Restore(restoreCtx); err != nil {
+		return "", errors.Wrap(err, "failed to restore table references")
+	}
+	if where != nil {
+		if _, err := buf.WriteString(" WHERE "); err != nil {
+			return "", err
+		}
+		if err := where.Restore(restoreCtx); err != nil {
+			return "", errors.Wrap(err, "failed to restore where clause")
+		}
+	}
+	if _, err := fmt.Fprintf(&buf, " LIMIT %d) AS bb_affected_rows_sample", affectedRowsSampleLimit+1); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// getAffectedRowsFromMySQLExplain returns the estimated rows of the first row in the EXPLAIN result,
+// which is the table to be changed.
+//
+// mysql> explain delete from td;
+// +----+-------------+-------+------------+------+---------------+------+---------+------+------+----------+-------+
+// | id | select_type | table | partitions | type | possible_keys | key  | key_len | ref  | rows | filtered | Extra |
+// +----+-------------+-------+------------+------+---------------+------+---------+------+------+----------+-------+
+// |  1 | DELETE      | td    | NULL       | ALL  | NULL          | NULL | NULL    | NULL |    1 |   100.00 | NULL  |
+// +----+-------------+-------+------------+------+---------------+------+---------+------+------+----------+-------+
+//
+// TiDB uses the column estRows instead.
+func getAffectedRowsFromMySQLExplain(dbType db.Type, columns []string, rowList [][]sql.NullString) (int64, error) {
+	rowsColumn := "rows"
+	if dbType == db.TiDB {
+		rowsColumn = "estRows"
+	}
+	index := -1
+	for i, column := range columns {
+		if strings.EqualFold(column, rowsColumn) {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return 0, errors.Errorf("column %q not found in the EXPLAIN result", rowsColumn)
+	}
+	for _, row := range rowList {
+		if index >= len(row) || !row[index].Valid {
+			continue
+		}
+		rows, err := strconv.ParseFloat(row[index].String, 64)
+		if err != nil {
+			return 0, errors.Wrapf(err, "failed to parse rows %q", row[index].String)
+		}
+		return int64(rows), nil
+	}
+	return 0, errors.Errorf("rows not found in the EXPLAIN result")
+}
+
+// estimatePostgresAffectedRows estimates the affected rows for PostgreSQL with the EXPLAIN estimate.
+// EXPLAIN without ANALYZE doesn't execute the statement.
+func estimatePostgresAffectedRows(ctx context.Context, connection *sql.DB, statement string) (int64, error) {
+	var plan string
+	if err := connection.QueryRowContext(ctx, fmt.Sprintf("EXPLAIN (FORMAT JSON) %s", statement)).Scan(&plan); err != nil {
+		return 0, err
+	}
+	return getAffectedRowsFromPostgresExplain(plan)
+}
+
+type postgresExplainPlan struct {
+	NodeType string                `json:"Node Type"`
+	PlanRows float64               `json:"Plan Rows"`
+	Plans    []postgresExplainPlan `json:"Plans"`
+}
+
+// getAffectedRowsFromPostgresExplain returns the estimated rows from the JSON format EXPLAIN result.
+// The estimated rows of the ModifyTable node is always 0, so we use its first sub-plan, which produces the rows to be changed.
+func getAffectedRowsFromPostgresExplain(plan string) (int64, error) {
+	var explain []struct {
+		Plan postgresExplainPlan `json:"Plan"`
+	}
+	if err := json.Unmarshal([]byte(plan), &explain); err != nil {
+		return 0, errors.Wrap(err, "failed to unmarshal the EXPLAIN result")
+	}
+	if len(explain) == 0 {
+		return 0, errors.Errorf("empty EXPLAIN result")
+	}
+	node := explain[0].Plan
+	if node.NodeType == "ModifyTable" && len(node.Plans) > 0 {
+		node = node.Plans[0]
+	}
+	return int64(node.PlanRows), nil
+}
@@ -0,0 +1,79 @@
+package taskcheck
+
+import (
+	"database/sql"
+	"testing"
+
+	tidbparser "github.com/pingcap/tidb/parser"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/backend/plugin/db"
+)
+
+func TestGetMySQLSampleCountQuery(t *testing.T) {
+	tests := []struct {
+		statement string
+		want      string
+	}{
+		{
+			statement: "UPDATE t SET a = 1 WHERE b > 10",
+			want:      "SELECT COUNT(*) FROM (SELECT 1 FROM `t` WHERE `b`>10 LIMIT 10001) AS bb_affected_rows_sample",
+		},
+		{
+			statement: "DELETE FROM t",
+			want:      "SELECT COUNT(*) FROM (SELECT 1 FROM `t` LIMIT 10001) AS bb_affected_rows_sample",
+		},
+		{
+			statement: "DELETE t1 FROM t1 JOIN t2 ON t1.a = t2.a",
+			want:      "",
+		},
+		{
+			statement: "INSERT INTO t SELECT * FROM t2",
+			want:      "",
+		},
+	}
+
+	a := require.New(t)
+	p := tidbparser.New()
+	for _, test := range tests {
+		node, err := p.ParseOneStmt(test.statement, "", "")
+		a.NoError(err)
+		got, err := getMySQLSampleCountQuery(node)
+		a.NoError(err)
+		a.Equal(test.want, got, test.statement)
+	}
+}
+
+func TestGetAffectedRowsFromMySQLExplain(t *testing.T) {
+	a := require.New(t)
+	columns := []string{"id", "select_type", "table", "partitions", "type", "possible_keys", "key", "key_len", "ref", "rows", "filtered", "Extra"}
+	rowList := [][]sql.NullString{
+		{{String: "1", Valid: true}, {String: "DELETE", Valid: true}, {String: "td", Valid: true}, {}, {String: "ALL", Valid: true}, {}, {}, {}, {}, {String: "42", Valid: true}, {String: "100.00", Valid: true}, {}},
+	}
+	rows, err := getAffectedRowsFromMySQLExplain(db.MySQL, columns, rowList)
+	a.NoError(err)
+	a.Equal(int64(42), rows)
+
+	columns = []string{"id", "estRows", "task", "access object", "operator info"}
+	rowList = [][]sql.NullString{
+		{{String: "Delete_4", Valid: true}, {String: "N/A", Valid: false}, {String: "root", Valid: true}, {}, {}},
+		{{String: "TableReader_8", Valid: true}, {String: "10000.00", Valid: true}, {String: "root", Valid: true}, {}, {}},
+	}
+	rows, err = getAffectedRowsFromMySQLExplain(db.TiDB, columns, rowList)
+	a.NoError(err)
+	a.Equal(int64(10000), rows)
+
+	_, err = getAffectedRowsFromMySQLExplain(db.TiDB, []string{"id"}, nil)
+	a.Error(err)
+}
+
+func TestGetAffectedRowsFromPostgresExplain(t *testing.T) {
+	a := require.New(t)
+	plan := `[{"Plan": {"Node Type": "ModifyTable", "Operation": "Delete", "Plan Rows": 0, "Plans": [{"Node Type": "Seq Scan", "Plan Rows": 1234}]}}]`
+	rows, err := getAffectedRowsFromPostgresExplain(plan)
+	a.NoError(err)
+	a.Equal(int64(1234), rows)
+
+	_, err = getAffectedRowsFromPostgresExplain(`[]`)
+	a.Error(err)
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"regexp"
+	"strings"
 
 	tidbparser "github.com/pingcap/tidb/parser"
 	tidbast "github.com/pingcap/tidb/parser/ast"
@@ -17,6 +19,8 @@ import (
 	"github.com/bytebase/bytebase/backend/store"
 )
 
+var createOrDropDatabaseRegexp = regexp.MustCompile(`(?i)^\s*(CREATE|DROP)\s+(DATABASE|SCHEMA)\b`)
+
 // NewStatementTypeExecutor creates a task check DML executor.
 func NewStatementTypeExecutor(store *store.Store) Executor {
 	return &StatementTypeExecutor{
@@ -47,6 +51,11 @@ func (*StatementTypeExecutor) Run(_ context.Context, taskCheckRun *api.TaskCheck
 		if err != nil {
 			return nil, err
 		}
+	case db.ClickHouse:
+		result, err = statementCategoryCheck(parser.EngineType(payload.DbType), payload.Statement, task.Type)
+		if err != nil {
+			return nil, err
+		}
 	default:
 		return nil, common.Errorf(common.Invalid, "invalid check statement type database type: %s", payload.DbType)
 	}
@@ -106,9 +115,6 @@ func mysqlStatementTypeCheck(statement string, charset string, collation string,
 			},
 		}, nil
 	}
-	// TODO(zp): We regard the DELIMITER statement as a DDL statement here.
-	// But we should ban the DELIMITER statement because go-sql-driver doesn't support it.
-	hasUnsupportDDL := len(unsupportStmt) > 0
 
 	p := tidbparser.New()
 	// To support MySQL8 window function syntax.
@@ -129,54 +135,31 @@ func mysqlStatementTypeCheck(statement string, charset string, collation string,
 		}, nil
 	}
 
-	if err != nil {
-		//nolint: nilerr
-		return []api.TaskCheckResult{
-			{
-				Status:    api.TaskCheckStatusError,
-				Namespace: api.AdvisorNamespace,
-				Code:      advisor.StatementSyntaxError.Int(),
-				Title:     "Syntax error",
-				Content:   err.Error(),
-			},
-		}, nil
-	}
-
 	// Disallow CREATE/DROP DATABASE statements.
 	result = append(result, mysqlCreateAndDropDatabaseCheck(stmts)...)
 
-	switch taskType {
-	case api.TaskDatabaseDataUpdate:
-		for _, node := range stmts {
-			_, isDDL := node.(tidbast.DDLNode)
-			// We only want to disallow DDL statements in CHANGE DATA.
-			// We need to run some common statements, e.g. COMMIT.
-			if isDDL || hasUnsupportDDL {
-				result = append(result, api.TaskCheckResult{
-					Status:    api.TaskCheckStatusWarn,
-					Namespace: api.BBNamespace,
-					Code:      common.TaskTypeNotDML.Int(),
-					Title:     "Data change can only run DML",
-					Content:   fmt.Sprintf("\"%s\" is not DML", node.Text()),
-				})
-			}
-		}
-	case api.TaskDatabaseSchemaUpdate, api.TaskDatabaseSchemaUpdateSDL, api.TaskDatabaseSchemaUpdateGhostSync:
-		for _, node := range stmts {
-			_, isDML := node.(tidbast.DMLNode)
-			_, isExplain := node.(*tidbast.ExplainStmt)
-			if isDML || isExplain {
-				result = append(result, api.TaskCheckResult{
-					Status:    api.TaskCheckStatusWarn,
-					Namespace: api.BBNamespace,
-					Code:      common.TaskTypeNotDDL.Int(),
-					Title:     "Alter schema can only run DDL",
-					Content:   fmt.Sprintf("\"%s\" is not DDL", node.Text()),
-				})
+	categoryResult, err := statementCategoryCheck(parser.MySQL, statement, taskType)
+	if err != nil {
+		return nil, err
+	}
+	result = append(result, categoryResult...)
+
+	// TODO(zp): We regard the DELIMITER statement as a DDL statement here.
+	// But we should ban the DELIMITER statement because go-sql-driver doesn't support it.
+	if taskType == api.TaskDatabaseDataUpdate {
+		for _, stmt := range unsupportStmt {
+			// The TiDB unsupported DDL statements, e.g. CREATE PROCEDURE, are reported by the statement category check already.
+			if category, _ := parser.ClassifyStatement(parser.MySQL, stmt); category == parser.StatementCategoryDDL {
+				continue
 			}
+			result = append(result, api.TaskCheckResult{
+				Status:    api.TaskCheckStatusWarn,
+				Namespace: api.BBNamespace,
+				Code:      common.TaskTypeNotDML.Int(),
+				Title:     "Data change can only run DML",
+				Content:   fmt.Sprintf("\"%s\" is not DML", strings.TrimSpace(stmt)),
+			})
 		}
-	default:
-		return nil, common.Errorf(common.Invalid, "invalid check statement type task type: %s", taskType)
 	}
 
 	return result, nil
@@ -225,40 +208,124 @@ func postgresqlStatementTypeCheck(statement string, taskType api.TaskType) (resu
 	// Disallow CREATE/DROP DATABASE statements.
 	result = append(result, postgresqlCreateAndDropDatabaseCheck(stmts)...)
 
+	categoryResult, err := statementCategoryCheck(parser.Postgres, statement, taskType)
+	if err != nil {
+		return nil, err
+	}
+	result = append(result, categoryResult...)
+
+	return result, nil
+}
+
+// statementCategoryCheck checks the category of every statement against the task type.
+// It disallows DDL in data changes, DML in schema changes, GRANT/REVOKE in any migration,
+// mixing DDL and DML in one task, and implicit-commit statements in transactional tasks.
+func statementCategoryCheck(engineType parser.EngineType, statement string, taskType api.TaskType) ([]api.TaskCheckResult, error) {
 	switch taskType {
-	case api.TaskDatabaseDataUpdate:
-		for _, node := range stmts {
-			_, isDDL := node.(ast.DDLNode)
+	case api.TaskDatabaseDataUpdate, api.TaskDatabaseSchemaUpdate, api.TaskDatabaseSchemaUpdateSDL, api.TaskDatabaseSchemaUpdateGhostSync:
+	default:
+		return nil, common.Errorf(common.Invalid, "invalid check statement type task type: %s", taskType)
+	}
+
+	sqlList, err := parser.ClassifyStatements(engineType, statement)
+	if err != nil {
+		//nolint:nilerr
+		return []api.TaskCheckResult{
+			{
+				Status:    api.TaskCheckStatusError,
+				Namespace: api.AdvisorNamespace,
+				Code:      advisor.StatementSyntaxError.Int(),
+				Title:     "Syntax error",
+				Content:   err.Error(),
+			},
+		}, nil
+	}
+
+	var result []api.TaskCheckResult
+	var hasDDL, hasDML bool
+	transactional := isTransactionalTask(engineType, taskType)
+	for _, sql := range sqlList {
+		text := strings.TrimSpace(sql.Text)
+		flagged := false
+		switch sql.Category {
+		case parser.StatementCategoryDDL:
+			hasDDL = true
 			// We only want to disallow DDL statements in CHANGE DATA.
 			// We need to run some common statements, e.g. COMMIT.
-			if isDDL {
+			if taskType == api.TaskDatabaseDataUpdate {
+				flagged = true
 				result = append(result, api.TaskCheckResult{
 					Status:    api.TaskCheckStatusWarn,
 					Namespace: api.BBNamespace,
 					Code:      common.TaskTypeNotDML.Int(),
 					Title:     "Data change can only run DML",
-					Content:   fmt.Sprintf("\"%s\" is not DML", node.Text()),
+					Content:   fmt.Sprintf("\"%s\" is not DML", text),
 				})
 			}
-		}
-	case api.TaskDatabaseSchemaUpdate, api.TaskDatabaseSchemaUpdateSDL, api.TaskDatabaseSchemaUpdateGhostSync:
-		for _, node := range stmts {
-			_, isDML := node.(ast.DMLNode)
-			_, isSelect := node.(*ast.SelectStmt)
-			_, isExplain := node.(*ast.ExplainStmt)
-			if isDML || isSelect || isExplain {
+		case parser.StatementCategoryDML:
+			hasDML = true
+			if taskType != api.TaskDatabaseDataUpdate {
+				flagged = true
 				result = append(result, api.TaskCheckResult{
 					Status:    api.TaskCheckStatusWarn,
 					Namespace: api.BBNamespace,
 					Code:      common.TaskTypeNotDDL.Int(),
 					Title:     "Alter schema can only run DDL",
-					Content:   fmt.Sprintf("\"%s\" is not DDL", node.Text()),
+					Content:   fmt.Sprintf("\"%s\" is not DDL", text),
 				})
 			}
+		case parser.StatementCategoryDCL:
+			flagged = true
+			result = append(result, api.TaskCheckResult{
+				Status:    api.TaskCheckStatusWarn,
+				Namespace: api.BBNamespace,
+				Code:      common.TaskTypeDisallowDCL.Int(),
+				Title:     "Migration should not change privileges",
+				Content:   fmt.Sprintf("\"%s\" changes users or privileges", text),
+			})
+		}
+		// Avoid reporting the same statement twice.
+		if transactional && sql.ImplicitCommit && !flagged && !isCreateOrDropDatabaseCheckedStatement(engineType, text) {
+			result = append(result, api.TaskCheckResult{
+				Status:    api.TaskCheckStatusWarn,
+				Namespace: api.BBNamespace,
+				Code:      common.TaskTypeImplicitCommit.Int(),
+				Title:     "Statement breaks the transaction",
+				Content:   fmt.Sprintf("\"%s\" commits the transaction implicitly or cannot run in a transaction", text),
+			})
 		}
-	default:
-		return nil, common.Errorf(common.Invalid, "invalid check statement type task type: %s", taskType)
+	}
+
+	if hasDDL && hasDML {
+		result = append(result, api.TaskCheckResult{
+			Status:    api.TaskCheckStatusWarn,
+			Namespace: api.BBNamespace,
+			Code:      common.TaskTypeMixedDDLAndDML.Int(),
+			Title:     "Mixed DDL and DML",
+			Content:   "The statements mix DDL and DML, please split them into schema change and data change",
+		})
 	}
 
 	return result, nil
 }
+
+// isCreateOrDropDatabaseCheckedStatement returns true if the statement is reported by the CREATE/DROP DATABASE check already.
+func isCreateOrDropDatabaseCheckedStatement(engineType parser.EngineType, text string) bool {
+	switch engineType {
+	case parser.MySQL, parser.TiDB, parser.Postgres:
+		return createOrDropDatabaseRegexp.MatchString(text)
+	}
+	return false
+}
+
+// isTransactionalTask returns true if the task statements are executed in one transaction and expected to be atomic.
+// MySQL DDL always commits implicitly, so only the data change tasks are transactional for MySQL-like engines.
+func isTransactionalTask(engineType parser.EngineType, taskType api.TaskType) bool {
+	switch taskType {
+	case api.TaskDatabaseDataUpdate:
+		return true
+	case api.TaskDatabaseSchemaUpdate, api.TaskDatabaseSchemaUpdateSDL:
+		return engineType == parser.Postgres || engineType == parser.SQLite
+	}
+	return false
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/bytebase/bytebase/backend/common"
 	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/parser"
 
 	// Register pingcap parser driver.
 	_ "github.com/pingcap/tidb/types/parser_driver"
@@ -169,3 +170,109 @@ func TestStatementTypeCheck(t *testing.T) {
 		require.Equal(t, test.want, res)
 	}
 }
+
+func TestStatementCategoryCheck(t *testing.T) {
+	tests := []struct {
+		engineType parser.EngineType
+		stmt       string
+		taskType   api.TaskType
+		want       []api.TaskCheckResult
+	}{
+		{
+			engineType: parser.MySQL,
+			stmt:       "GRANT SELECT ON db.* TO 'u'@'%';",
+			taskType:   api.TaskDatabaseSchemaUpdate,
+			want: []api.TaskCheckResult{
+				{
+					Status:    api.TaskCheckStatusWarn,
+					Namespace: api.BBNamespace,
+					Code:      common.TaskTypeDisallowDCL.Int(),
+					Title:     "Migration should not change privileges",
+					Content:   "\"GRANT SELECT ON db.* TO 'u'@'%';\" changes users or privileges",
+				},
+			},
+		},
+		{
+			engineType: parser.MySQL,
+			stmt:       "UPDATE t SET a = 1;\nLOCK TABLES t WRITE;",
+			taskType:   api.TaskDatabaseDataUpdate,
+			want: []api.TaskCheckResult{
+				{
+					Status:    api.TaskCheckStatusWarn,
+					Namespace: api.BBNamespace,
+					Code:      common.TaskTypeImplicitCommit.Int(),
+					Title:     "Statement breaks the transaction",
+					Content:   "\"LOCK TABLES t WRITE;\" commits the transaction implicitly or cannot run in a transaction",
+				},
+			},
+		},
+		{
+			engineType: parser.Postgres,
+			stmt:       "CREATE INDEX CONCURRENTLY idx ON t(a);",
+			taskType:   api.TaskDatabaseSchemaUpdate,
+			want: []api.TaskCheckResult{
+				{
+					Status:    api.TaskCheckStatusWarn,
+					Namespace: api.BBNamespace,
+					Code:      common.TaskTypeImplicitCommit.Int(),
+					Title:     "Statement breaks the transaction",
+					Content:   "\"CREATE INDEX CONCURRENTLY idx ON t(a);\" commits the transaction implicitly or cannot run in a transaction",
+				},
+			},
+		},
+		{
+			engineType: parser.ClickHouse,
+			stmt:       "CREATE TABLE t(a Int32) ENGINE = Memory;\nINSERT INTO t VALUES (1);",
+			taskType:   api.TaskDatabaseSchemaUpdate,
+			want: []api.TaskCheckResult{
+				{
+					Status:    api.TaskCheckStatusWarn,
+					Namespace: api.BBNamespace,
+					Code:      common.TaskTypeNotDDL.Int(),
+					Title:     "Alter schema can only run DDL",
+					Content:   "\"INSERT INTO t VALUES (1);\" is not DDL",
+				},
+				{
+					Status:    api.TaskCheckStatusWarn,
+					Namespace: api.BBNamespace,
+					Code:      common.TaskTypeMixedDDLAndDML.Int(),
+					Title:     "Mixed DDL and DML",
+					Content:   "The statements mix DDL and DML, please split them into schema change and data change",
+				},
+			},
+		},
+		{
+			engineType: parser.ClickHouse,
+			stmt:       "ALTER TABLE t DELETE WHERE a = 1;",
+			taskType:   api.TaskDatabaseDataUpdate,
+			want:       []api.TaskCheckResult(nil),
+		},
+	}
+
+	for _, test := range tests {
+		res, err := statementCategoryCheck(test.engineType, test.stmt, test.taskType)
+		require.NoError(t, err)
+		require.Equal(t, test.want, res, test.stmt)
+	}
+}
+
+func TestMySQLStatementTypeCheckDelimiter(t *testing.T) {
+	res, err := mysqlStatementTypeCheck("DELIMITER ;;\nUPDATE t SET a = 1;;\nDELIMITER ;\n", "", "", api.TaskDatabaseDataUpdate)
+	require.NoError(t, err)
+	require.Equal(t, []api.TaskCheckResult{
+		{
+			Status:    api.TaskCheckStatusWarn,
+			Namespace: api.BBNamespace,
+			Code:      common.TaskTypeNotDML.Int(),
+			Title:     "Data change can only run DML",
+			Content:   "\"DELIMITER ;;\" is not DML",
+		},
+		{
+			Status:    api.TaskCheckStatusWarn,
+			Namespace: api.BBNamespace,
+			Code:      common.TaskTypeNotDML.Int(),
+			Title:     "Data change can only run DML",
+			Content:   "\"DELIMITER ;\" is not DML",
+		},
+	}, res)
+}
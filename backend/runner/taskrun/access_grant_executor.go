@@ -0,0 +1,85 @@
+package taskrun
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+// NewAccessGrantExecutor creates a database access grant task executor.
+func NewAccessGrantExecutor(store *store.Store) Executor {
+	return &AccessGrantExecutor{
+		store: store,
+	}
+}
+
+// AccessGrantExecutor is the database access grant task executor.
+// The task runs after the access request issue is approved, and grants the issue creator the temporary query access to the database.
+type AccessGrantExecutor struct {
+	store *store.Store
+}
+
+// RunOnce will run the database access grant task executor once.
+func (exec *AccessGrantExecutor) RunOnce(ctx context.Context, task *store.TaskMessage) (terminated bool, result *api.TaskRunResultPayload, err error) {
+	payload := &api.TaskDatabaseAccessGrantPayload{}
+	if err := json.Unmarshal([]byte(task.Payload), payload); err != nil {
+		return true, nil, errors.Wrap(err, "invalid database access grant payload")
+	}
+	if task.DatabaseID == nil {
+		return true, nil, errors.Errorf("missing database in task %d", task.ID)
+	}
+	if payload.DurationSeconds <= 0 {
+		return true, nil, errors.Errorf("invalid access duration %d seconds", payload.DurationSeconds)
+	}
+
+	issue, err := exec.store.GetIssueV2(ctx, &store.FindIssueMessage{PipelineID: &task.PipelineID})
+	if err != nil {
+		return true, nil, errors.Wrapf(err, "failed to get issue of pipeline %d", task.PipelineID)
+	}
+	if issue == nil {
+		return true, nil, errors.Errorf("issue not found for pipeline %d", task.PipelineID)
+	}
+
+	// The grant may be created by a previous run which failed to update the task payload.
+	grant, err := exec.store.GetAccessGrant(ctx, &store.FindAccessGrantMessage{IssueID: &issue.UID, ShowRevoked: true})
+	if err != nil {
+		return true, nil, errors.Wrapf(err, "failed to get access grant of issue %d", issue.UID)
+	}
+	if grant == nil {
+		grant, err = exec.store.CreateAccessGrant(ctx, &store.AccessGrantMessage{
+			IssueID:     issue.UID,
+			PrincipalID: issue.Creator.ID,
+			DatabaseID:  *task.DatabaseID,
+			Tables:      payload.TableList,
+			Reason:      payload.Reason,
+			ExpiresTs:   time.Now().Unix() + payload.DurationSeconds,
+		}, api.SystemBotID)
+		if err != nil {
+			return true, nil, errors.Wrap(err, "failed to create access grant")
+		}
+	}
+
+	payload.AccessGrantID = grant.UID
+	bytes, err := json.Marshal(payload)
+	if err != nil {
+		return true, nil, errors.Wrap(err, "failed to marshal database access grant payload")
+	}
+	payloadString := string(bytes)
+	if _, err := exec.store.UpdateTaskV2(ctx, &api.TaskPatch{
+		ID:        task.ID,
+		UpdaterID: api.SystemBotID,
+		Payload:   &payloadString,
+	}); err != nil {
+		return true, nil, errors.Wrap(err, "failed to update database access grant payload")
+	}
+
+	return true, &api.TaskRunResultPayload{
+		Detail: fmt.Sprintf("Granted %s query access until %s", issue.Creator.Name, time.Unix(grant.ExpiresTs, 0).UTC().Format(time.RFC3339)),
+	}, nil
+}
@@ -0,0 +1,171 @@
+package taskrun
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+type projectInstance struct {
+	projectUID int
+	instanceID int
+}
+
+// concurrencyLimiter enforces the concurrency limits of the workspace and the project deployment configs within a round of scheduling.
+// It counts the running tasks at the beginning of the round, and the tasks started in the round.
+type concurrencyLimiter struct {
+	s *Scheduler
+
+	// maxRunningTasks is the maximum number of running tasks in the workspace, 0 means no limit.
+	maxRunningTasks int
+	// pipelineProjects is the mapping from pipeline ID to project UID, it's 0 if the pipeline doesn't belong to an issue.
+	pipelineProjects map[int]int
+	// projectConcurrency is the mapping from project UID to its concurrency limits, it's nil if the project has no limits.
+	projectConcurrency map[int]*api.DeploymentConcurrency
+
+	running         int
+	stageRunning    map[int]int
+	instanceRunning map[projectInstance]int
+	// queued is the set of tasks queued in the round.
+	queued map[int]bool
+}
+
+func (s *Scheduler) newConcurrencyLimiter(ctx context.Context) (*concurrencyLimiter, error) {
+	maxRunningTasks, err := s.getMaxRunningTasks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	l := &concurrencyLimiter{
+		s:                  s,
+		maxRunningTasks:    maxRunningTasks,
+		pipelineProjects:   make(map[int]int),
+		projectConcurrency: make(map[int]*api.DeploymentConcurrency),
+		stageRunning:       make(map[int]int),
+		instanceRunning:    make(map[projectInstance]int),
+		queued:             make(map[int]bool),
+	}
+	taskStatusList := []api.TaskStatus{api.TaskRunning}
+	tasks, err := s.store.ListTasks(ctx, &api.TaskFind{StatusList: &taskStatusList})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list running tasks")
+	}
+	for _, task := range tasks {
+		if err := l.start(ctx, task); err != nil {
+			return nil, err
+		}
+	}
+	return l, nil
+}
+
+// getMaxRunningTasks returns the maximum number of running tasks in the workspace, 0 means no limit.
+func (s *Scheduler) getMaxRunningTasks(ctx context.Context) (int, error) {
+	settingName := api.SettingWorkspaceMaxRunningTasks
+	setting, err := s.store.GetSettingV2(ctx, &store.FindSettingMessage{Name: &settingName})
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to get setting %q", settingName)
+	}
+	if setting == nil || setting.Value == "" {
+		return 0, nil
+	}
+	maxRunningTasks, err := strconv.Atoi(setting.Value)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid setting value %q for %s", setting.Value, settingName)
+	}
+	return maxRunningTasks, nil
+}
+
+// getQueuedReason returns why the task has to wait for the running tasks, and empty if the task can start now.
+func (l *concurrencyLimiter) getQueuedReason(ctx context.Context, task *store.TaskMessage) (string, error) {
+	if limit := l.maxRunningTasks; limit > 0 && l.running >= limit {
+		return fmt.Sprintf("Waiting for the running tasks in the workspace, the workspace allows %d running tasks.", limit), nil
+	}
+	projectUID, err := l.getProjectUID(ctx, task.PipelineID)
+	if err != nil {
+		return "", err
+	}
+	if projectUID == 0 {
+		return "", nil
+	}
+	concurrency, err := l.getConcurrency(ctx, projectUID)
+	if err != nil {
+		return "", err
+	}
+	if concurrency == nil {
+		return "", nil
+	}
+	if limit := concurrency.MaxRunningTasksPerStage; limit > 0 && l.stageRunning[task.StageID] >= limit {
+		return fmt.Sprintf("Waiting for the running tasks in the stage, the stage allows %d running tasks.", limit), nil
+	}
+	if limit := concurrency.MaxRunningTasksPerInstance; limit > 0 && l.instanceRunning[projectInstance{projectUID: projectUID, instanceID: task.InstanceID}] >= limit {
+		return fmt.Sprintf("Waiting for the running tasks on the instance, the project allows %d running tasks per instance.", limit), nil
+	}
+	return "", nil
+}
+
+// start counts the task as a running task.
+func (l *concurrencyLimiter) start(ctx context.Context, task *store.TaskMessage) error {
+	l.running++
+	projectUID, err := l.getProjectUID(ctx, task.PipelineID)
+	if err != nil {
+		return err
+	}
+	if projectUID == 0 {
+		return nil
+	}
+	l.stageRunning[task.StageID]++
+	l.instanceRunning[projectInstance{projectUID: projectUID, instanceID: task.InstanceID}]++
+	return nil
+}
+
+func (l *concurrencyLimiter) getProjectUID(ctx context.Context, pipelineID int) (int, error) {
+	if projectUID, ok := l.pipelineProjects[pipelineID]; ok {
+		return projectUID, nil
+	}
+	issue, err := l.s.store.GetIssueV2(ctx, &store.FindIssueMessage{PipelineID: &pipelineID})
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to get issue of pipeline %d", pipelineID)
+	}
+	projectUID := 0
+	if issue != nil {
+		projectUID = issue.Project.UID
+	}
+	l.pipelineProjects[pipelineID] = projectUID
+	return projectUID, nil
+}
+
+func (l *concurrencyLimiter) getConcurrency(ctx context.Context, projectUID int) (*api.DeploymentConcurrency, error) {
+	if concurrency, ok := l.projectConcurrency[projectUID]; ok {
+		return concurrency, nil
+	}
+	deploymentConfig, err := l.s.store.GetDeploymentConfigByProjectID(ctx, projectUID)
+	if err != nil {
+		return nil, err
+	}
+	schedule, err := api.ValidateAndGetDeploymentSchedule(deploymentConfig.Payload)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get deployment schedule of project %d", projectUID)
+	}
+	l.projectConcurrency[projectUID] = schedule.Concurrency
+	return schedule.Concurrency, nil
+}
+
+// queue records the task as queued in the round, and exposes the reason to the API.
+func (l *concurrencyLimiter) queue(task *store.TaskMessage, reason string) {
+	l.queued[task.ID] = true
+	l.s.stateCfg.QueuedTasks.Store(task.ID, reason)
+}
+
+// clearDequeued removes the tasks which are no longer queued after the round.
+func (l *concurrencyLimiter) clearDequeued() {
+	l.s.stateCfg.QueuedTasks.Range(func(key, _ interface{}) bool {
+		if taskID := key.(int); !l.queued[taskID] {
+			l.s.stateCfg.QueuedTasks.Delete(taskID)
+		}
+		return true
+	})
+}
@@ -0,0 +1,423 @@
+package taskrun
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/backend/common"
+	"github.com/bytebase/bytebase/backend/common/log"
+	"github.com/bytebase/bytebase/backend/component/dbfactory"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/db"
+	"github.com/bytebase/bytebase/backend/plugin/db/mysql"
+	"github.com/bytebase/bytebase/backend/plugin/db/pg"
+	"github.com/bytebase/bytebase/backend/plugin/db/util"
+	"github.com/bytebase/bytebase/backend/plugin/parser"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+// chunkPollInterval is the interval to check whether the paused or throttled chunked execution can continue.
+const chunkPollInterval = 5 * time.Second
+
+var integerColumnTypeRegexp = regexp.MustCompile(`^(tinyint|smallint|mediumint|int|integer|bigint|int2|int4|int8)(\(|\s|$)`)
+
+// chunkPlan is the plan of the chunked execution of a statement.
+type chunkPlan struct {
+	dml *parser.ChunkDML
+	// table is the quoted table name for the queries.
+	table string
+	// column is the single-column primary key of the table.
+	column    string
+	isInteger bool
+	// estimatedRows is the estimated row count of the table from the last schema sync.
+	estimatedRows int64
+}
+
+// runChunkedMigration runs the data update in chunks ranged by the primary key, and resumes from the checkpoint in the task payload.
+func (exec *DataUpdateExecutor) runChunkedMigration(ctx context.Context, task *store.TaskMessage, payload *api.TaskDatabaseDataUpdatePayload, statement string) (terminated bool, result *api.TaskRunResultPayload, err error) {
+	mi, err := preMigration(ctx, exec.store, exec.profile, task, db.Data, statement, payload.SchemaVersion, payload.VCSPushEvent)
+	if err != nil {
+		return true, nil, err
+	}
+	migrationID, schema, err := exec.executeChunkedMigration(ctx, task, payload, statement, mi)
+	if err != nil {
+		return true, nil, err
+	}
+	return postMigration(ctx, exec.store, exec.activityManager, exec.license, exec.profile, task, payload.VCSPushEvent, mi, migrationID, schema)
+}
+
+func (exec *DataUpdateExecutor) executeChunkedMigration(ctx context.Context, task *store.TaskMessage, payload *api.TaskDatabaseDataUpdatePayload, statement string, mi *db.MigrationInfo) (migrationID string, schema string, resErr error) {
+	instance, err := exec.store.GetInstanceV2(ctx, &store.FindInstanceMessage{UID: &task.InstanceID})
+	if err != nil {
+		return "", "", err
+	}
+	if instance.Engine != db.MySQL && instance.Engine != db.Postgres {
+		return "", "", errors.Errorf("chunked execution is not supported for %s", instance.Engine)
+	}
+	database, err := exec.store.GetDatabaseV2(ctx, &store.FindDatabaseMessage{UID: task.DatabaseID})
+	if err != nil {
+		return "", "", err
+	}
+	plans, err := exec.planChunks(ctx, instance.Engine, database, statement)
+	if err != nil {
+		return "", "", err
+	}
+
+	driver, err := exec.dbFactory.GetAdminDatabaseDriver(ctx, instance, database.DatabaseName)
+	if err != nil {
+		return "", "", errors.Wrapf(err, "failed to get the driver for instance %q", instance.ResourceID)
+	}
+	defer driver.Close(ctx)
+	setup, err := driver.NeedsSetupMigration(ctx)
+	if err != nil {
+		return "", "", errors.Wrapf(err, "failed to check migration setup for instance %q", instance.ResourceID)
+	}
+	if setup {
+		return "", "", common.Errorf(common.MigrationSchemaMissing, "missing migration schema for instance %q", instance.ResourceID)
+	}
+	executor, ok := dbfactory.Unwrap(driver).(util.MigrationExecutor)
+	if !ok {
+		return "", "", errors.Errorf("chunked execution is not supported for %s", instance.Engine)
+	}
+
+	// The migration history is recorded around the chunks like util.ExecuteMigration.
+	// The forced migration reuses the pending or failed history of the previous attempt after restart.
+	var prevSchemaBuf bytes.Buffer
+	if _, err := executor.Dump(ctx, database.DatabaseName, &prevSchemaBuf, true /* schemaOnly */); err != nil {
+		return "", "", util.FormatError(err)
+	}
+	insertedID, err := util.BeginMigration(ctx, executor, mi, prevSchemaBuf.String(), statement, database.DatabaseName)
+	if err != nil {
+		if common.ErrorCode(err) == common.MigrationAlreadyApplied {
+			return insertedID, prevSchemaBuf.String(), nil
+		}
+		return "", "", errors.Wrapf(err, "failed to begin migration for issue %s", mi.IssueID)
+	}
+	startedNs := time.Now().UnixNano()
+	var updatedSchema string
+	defer func() {
+		if err := util.EndMigration(ctx, executor, startedNs, insertedID, updatedSchema, database.DatabaseName, resErr == nil /* isDone */); err != nil {
+			log.Error("Failed to update migration history record",
+				zap.Error(err),
+				zap.String("migration_id", insertedID),
+			)
+		}
+	}()
+
+	sqlDB, err := driver.GetDBConnection(ctx, database.DatabaseName)
+	if err != nil {
+		return "", "", err
+	}
+	checkpoint := payload.ChunkCheckpoint
+	if checkpoint == nil {
+		checkpoint = &api.DataUpdateChunkCheckpoint{}
+	}
+	createdTs := time.Now().Unix()
+	for checkpoint.StatementIndex < len(plans) {
+		config, err := exec.waitForChunk(ctx, sqlDB, task, instance, database, checkpoint, plans, createdTs)
+		if err != nil {
+			return "", "", err
+		}
+		plan := plans[checkpoint.StatementIndex]
+		upper, err := getChunkUpperBound(ctx, sqlDB, instance.Engine, plan, checkpoint.LastKey, config.ChunkSize)
+		if err != nil {
+			return "", "", err
+		}
+		chunkStatement, err := plan.dml.ChunkStatement(plan.column, plan.isInteger, checkpoint.LastKey, upper)
+		if err != nil {
+			return "", "", err
+		}
+		// The chunk and its checkpoint can't be saved in one transaction since the checkpoint is in the Bytebase metadata database.
+		// The chunk interrupted before saving its checkpoint is executed again on resumption, which is safe since ParseChunkDML only accepts the idempotent statements.
+		sqlResult, err := sqlDB.ExecContext(ctx, chunkStatement)
+		if err != nil {
+			return "", "", util.FormatErrorWithQuery(err, chunkStatement)
+		}
+		if rowsAffected, err := sqlResult.RowsAffected(); err == nil {
+			checkpoint.AffectedRows += rowsAffected
+		}
+
+		checkpoint.CompletedChunks++
+		if upper == nil {
+			checkpoint.StatementIndex++
+			checkpoint.LastKey = nil
+		} else {
+			checkpoint.LastKey = upper
+		}
+		if err := exec.saveChunkCheckpoint(ctx, task, checkpoint); err != nil {
+			return "", "", err
+		}
+		storeChunkProgress(ctx, exec.store, task.ID, checkpoint, plans, config.ChunkSize, createdTs, "")
+
+		if config.SleepMs > 0 && checkpoint.StatementIndex < len(plans) {
+			select {
+			case <-time.After(time.Duration(config.SleepMs) * time.Millisecond):
+			case <-ctx.Done():
+				return "", "", ctx.Err()
+			}
+		}
+	}
+
+	var afterSchemaBuf bytes.Buffer
+	if _, err := executor.Dump(ctx, database.DatabaseName, &afterSchemaBuf, true /* schemaOnly */); err != nil {
+		return "", "", util.FormatError(err)
+	}
+	updatedSchema = afterSchemaBuf.String()
+	return insertedID, updatedSchema, nil
+}
+
+// planChunks checks that all statements are single-table UPDATE or DELETE statements on the tables with single-column primary keys.
+func (exec *DataUpdateExecutor) planChunks(ctx context.Context, engine db.Type, database *store.DatabaseMessage, statement string) ([]*chunkPlan, error) {
+	engineType := parser.MySQL
+	if engine == db.Postgres {
+		engineType = parser.Postgres
+	}
+	singleSQLs, err := parser.SplitMultiSQL(engineType, statement)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to split the statement")
+	}
+	dbSchema, err := exec.store.GetDBSchema(ctx, database.UID)
+	if err != nil {
+		return nil, err
+	}
+	if dbSchema == nil || dbSchema.Metadata == nil {
+		return nil, errors.Errorf("schema of database %q is not synced yet", database.DatabaseName)
+	}
+
+	var plans []*chunkPlan
+	for _, singleSQL := range singleSQLs {
+		dml, err := parser.ParseChunkDML(engineType, singleSQL.Text)
+		if err != nil {
+			return nil, err
+		}
+		schemaName := dml.Schema
+		switch engine {
+		case db.MySQL:
+			if schemaName != "" && schemaName != database.DatabaseName {
+				return nil, errors.Errorf("table %s.%s is not in database %q", schemaName, dml.Table, database.DatabaseName)
+			}
+			// The MySQL schema metadata has an empty schema name.
+			schemaName = ""
+		case db.Postgres:
+			if schemaName == "" {
+				schemaName = "public"
+			}
+		}
+		plan := &chunkPlan{dml: dml, table: quoteChunkIdentifier(engine, dml.Table)}
+		if dml.Schema != "" {
+			plan.table = fmt.Sprintf("%s.%s", quoteChunkIdentifier(engine, dml.Schema), plan.table)
+		}
+		if err := setChunkPrimaryKey(plan, dbSchema, schemaName); err != nil {
+			return nil, err
+		}
+		if err := dml.CheckChunkColumn(plan.column); err != nil {
+			return nil, err
+		}
+		plans = append(plans, plan)
+	}
+	if len(plans) == 0 {
+		return nil, errors.Errorf("empty statement")
+	}
+	return plans, nil
+}
+
+func setChunkPrimaryKey(plan *chunkPlan, dbSchema *store.DBSchema, schemaName string) error {
+	for _, schema := range dbSchema.Metadata.Schemas {
+		if schema.Name != schemaName {
+			continue
+		}
+		for _, table := range schema.Tables {
+			if table.Name != plan.dml.Table {
+				continue
+			}
+			for _, index := range table.Indexes {
+				if !index.Primary {
+					continue
+				}
+				if len(index.Expressions) != 1 {
+					return errors.Errorf("chunked execution requires a single-column primary key, but table %q has %d primary key columns", table.Name, len(index.Expressions))
+				}
+				plan.column = index.Expressions[0]
+				plan.estimatedRows = table.RowCount
+				for _, column := range table.Columns {
+					if column.Name == plan.column {
+						plan.isInteger = integerColumnTypeRegexp.MatchString(strings.ToLower(column.Type))
+					}
+				}
+				return nil
+			}
+			return errors.Errorf("chunked execution requires a primary key, but table %q has no primary key", table.Name)
+		}
+	}
+	return errors.Errorf("table %q not found, please sync the database schema and retry", plan.dml.Table)
+}
+
+// getChunkUpperBound returns the primary key upper bound of the chunk after the lower bound, it's nil if the chunk is the last chunk.
+func getChunkUpperBound(ctx context.Context, sqlDB *sql.DB, engine db.Type, plan *chunkPlan, lower *string, chunkSize int) (*string, error) {
+	column := quoteChunkIdentifier(engine, plan.column)
+	var where string
+	var args []interface{}
+	if lower != nil {
+		placeholder := "?"
+		if engine == db.Postgres {
+			placeholder = "$1"
+		}
+		where = fmt.Sprintf("WHERE %s > %s ", column, placeholder)
+		var arg interface{} = *lower
+		if plan.isInteger {
+			v, err := strconv.ParseInt(*lower, 10, 64)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid integer primary key %q", *lower)
+			}
+			arg = v
+		}
+		args = append(args, arg)
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s %sORDER BY %s LIMIT 1 OFFSET %d", column, plan.table, where, column, chunkSize-1)
+	var upper string
+	if err := sqlDB.QueryRowContext(ctx, query, args...).Scan(&upper); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	return &upper, nil
+}
+
+// waitForChunk reloads the chunk configuration from the task payload, and waits while the execution is paused or the replication lag is too high.
+func (exec *DataUpdateExecutor) waitForChunk(ctx context.Context, sqlDB *sql.DB, task *store.TaskMessage, instance *store.InstanceMessage, database *store.DatabaseMessage, checkpoint *api.DataUpdateChunkCheckpoint, plans []*chunkPlan, createdTs int64) (*api.DataUpdateChunkConfig, error) {
+	for {
+		config, err := exec.getChunkConfig(ctx, task.ID)
+		if err != nil {
+			return nil, err
+		}
+		comment := ""
+		if config.Paused {
+			comment = "Paused"
+		} else if config.MaxReplicationLagSeconds > 0 {
+			lag, known, err := exec.getReplicationLag(ctx, sqlDB, instance, database)
+			if err != nil {
+				return nil, err
+			}
+			if !known {
+				comment = "Waiting for the replication which is not running"
+			} else if lag > time.Duration(config.MaxReplicationLagSeconds)*time.Second {
+				comment = fmt.Sprintf("Waiting for the replication lag %v to drop below %ds", lag.Truncate(time.Second), config.MaxReplicationLagSeconds)
+			}
+		}
+		storeChunkProgress(ctx, exec.store, task.ID, checkpoint, plans, config.ChunkSize, createdTs, comment)
+		if comment == "" {
+			return config, nil
+		}
+		select {
+		case <-time.After(chunkPollInterval):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (exec *DataUpdateExecutor) getChunkConfig(ctx context.Context, taskID int) (*api.DataUpdateChunkConfig, error) {
+	task, err := exec.store.GetTaskV2ByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil {
+		return nil, errors.Errorf("task %d not found", taskID)
+	}
+	payload := &api.TaskDatabaseDataUpdatePayload{}
+	if err := json.Unmarshal([]byte(task.Payload), payload); err != nil {
+		return nil, errors.Wrap(err, "invalid database data update payload")
+	}
+	if payload.ChunkConfig == nil {
+		return nil, errors.Errorf("chunk config of task %d not found", taskID)
+	}
+	if err := payload.ChunkConfig.Validate(); err != nil {
+		return nil, err
+	}
+	return payload.ChunkConfig, nil
+}
+
+func (exec *DataUpdateExecutor) saveChunkCheckpoint(ctx context.Context, task *store.TaskMessage, checkpoint *api.DataUpdateChunkCheckpoint) error {
+	bytes, err := json.Marshal(checkpoint)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal chunk checkpoint")
+	}
+	checkpointString := string(bytes)
+	if _, err := exec.store.UpdateTaskV2(ctx, &api.TaskPatch{
+		ID:              task.ID,
+		UpdaterID:       api.SystemBotID,
+		ChunkCheckpoint: &checkpointString,
+	}); err != nil {
+		return errors.Wrapf(err, "failed to save the chunk checkpoint of task %d", task.ID)
+	}
+	return nil
+}
+
+// getReplicationLag returns the replication lag, it returns false if the lag is unknown because the replication is not running.
+// The sqlDB is the connection to the primary server.
+func (exec *DataUpdateExecutor) getReplicationLag(ctx context.Context, sqlDB *sql.DB, instance *store.InstanceMessage, database *store.DatabaseMessage) (time.Duration, bool, error) {
+	switch instance.Engine {
+	case db.MySQL:
+		// MySQL reports the lag on the replica.
+		driver, err := exec.dbFactory.GetReadOnlyDatabaseDriver(ctx, instance, database.DatabaseName)
+		if err != nil {
+			return 0, false, err
+		}
+		defer driver.Close(ctx)
+		replicaDB, err := driver.GetDBConnection(ctx, "")
+		if err != nil {
+			return 0, false, err
+		}
+		return mysql.GetReplicationLag(ctx, replicaDB)
+	case db.Postgres:
+		lag, err := pg.GetReplicationLag(ctx, sqlDB)
+		return lag, err == nil, err
+	default:
+		return 0, true, nil
+	}
+}
+
+// storeChunkProgress reports the progress in chunks, the total is estimated by the table row counts from the last schema sync.
+func storeChunkProgress(ctx context.Context, stores *store.Store, taskID int, checkpoint *api.DataUpdateChunkCheckpoint, plans []*chunkPlan, chunkSize int, createdTs int64, comment string) {
+	var totalChunks int64
+	for _, plan := range plans {
+		totalChunks += plan.estimatedRows/int64(chunkSize) + 1
+	}
+	if totalChunks < checkpoint.CompletedChunks {
+		totalChunks = checkpoint.CompletedChunks
+	}
+	if checkpoint.StatementIndex >= len(plans) {
+		totalChunks = checkpoint.CompletedChunks
+	}
+	progressPayload := ""
+	if comment != "" {
+		if bytes, err := json.Marshal(map[string]string{"comment": comment}); err == nil {
+			progressPayload = string(bytes)
+		}
+	}
+	updateTaskRunProgress(ctx, stores, taskID, &api.Progress{
+		TotalUnit:     totalChunks,
+		CompletedUnit: checkpoint.CompletedChunks,
+		CreatedTs:     createdTs,
+		UpdatedTs:     time.Now().Unix(),
+		Payload:       progressPayload,
+	})
+}
+
+func quoteChunkIdentifier(engine db.Type, name string) string {
+	if engine == db.Postgres {
+		return fmt.Sprintf(`"%s"`, strings.ReplaceAll(name, `"`, `""`))
+	}
+	return fmt.Sprintf("`%s`", strings.ReplaceAll(name, "`", "``"))
+}
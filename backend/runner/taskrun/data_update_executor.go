@@ -56,5 +56,8 @@ func (exec *DataUpdateExecutor) RunOnce(ctx context.Context, task *store.TaskMes
 		}
 		statement = sheet.Statement
 	}
-	return runMigration(ctx, exec.store, exec.dbFactory, exec.activityManager, exec.license, exec.stateCfg, exec.profile, task, db.Data, statement, payload.SchemaVersion, payload.VCSPushEvent)
+	if payload.ChunkConfig != nil {
+		return exec.runChunkedMigration(ctx, task, payload, statement)
+	}
+	return runMigration(ctx, exec.store, exec.dbFactory, exec.activityManager, exec.license, exec.stateCfg, exec.profile, task, db.Data, statement, payload.SchemaVersion, payload.VCSPushEvent, payload.ResumableExecution, payload.Resume)
 }
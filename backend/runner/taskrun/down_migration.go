@@ -0,0 +1,120 @@
+package taskrun
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/component/activity"
+	"github.com/bytebase/bytebase/backend/component/dbfactory"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/db"
+	"github.com/bytebase/bytebase/backend/plugin/parser"
+	"github.com/bytebase/bytebase/backend/plugin/parser/differ"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+// downMigrationEngines is the engines supporting generating the down migrations, and their parser engine types.
+var downMigrationEngines = map[db.Type]parser.EngineType{
+	db.MySQL:    parser.MySQL,
+	db.Postgres: parser.Postgres,
+}
+
+// generateDownMigration generates the down migration reverting the completed schema update task by diffing the schema after the migration to
+// the schema before, both recorded in the migration history. The down migration is saved in the task payload.
+// A warning comment is created on the issue if the down migration is lossy, e.g. it drops the tables or columns created by the task.
+func generateDownMigration(ctx context.Context, stores *store.Store, dbFactory *dbfactory.DBFactory, activityManager *activity.Manager, taskID int, instance *store.InstanceMessage, database *store.DatabaseMessage, migrationID string) error {
+	engineType, ok := downMigrationEngines[instance.Engine]
+	if !ok {
+		return nil
+	}
+
+	driver, err := dbFactory.GetAdminDatabaseDriver(ctx, instance, database.DatabaseName)
+	if err != nil {
+		return err
+	}
+	defer driver.Close(ctx)
+	historyList, err := driver.FindMigrationHistoryList(ctx, &db.MigrationHistoryFind{
+		ID:       &migrationID,
+		Database: &database.DatabaseName,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to find migration history %s", migrationID)
+	}
+	if len(historyList) == 0 {
+		return errors.Errorf("migration history %s not found", migrationID)
+	}
+	history := historyList[0]
+
+	// Read the latest payload since it may be updated during the execution.
+	task, err := stores.GetTaskV2ByID(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	if task == nil {
+		return errors.Errorf("task %d not found", taskID)
+	}
+	payload := &api.TaskDatabaseSchemaUpdatePayload{}
+	if err := json.Unmarshal([]byte(task.Payload), payload); err != nil {
+		return errors.Wrap(err, "invalid database schema update payload")
+	}
+	payload.DownStatement, payload.DownStatementLossyList, payload.DownStatementError = "", nil, ""
+	if downStatement, err := differ.SchemaDiff(engineType, history.Schema, history.SchemaPrev); err != nil {
+		payload.DownStatementError = err.Error()
+	} else if lossyList, err := differ.ListLossyStatements(engineType, downStatement); err != nil {
+		payload.DownStatementError = err.Error()
+	} else {
+		payload.DownStatement = downStatement
+		payload.DownStatementLossyList = lossyList
+	}
+
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal task payload")
+	}
+	payloadString := string(payloadBytes)
+	if _, err := stores.UpdateTaskV2(ctx, &api.TaskPatch{
+		ID:        task.ID,
+		UpdaterID: api.SystemBotID,
+		Payload:   &payloadString,
+	}); err != nil {
+		return errors.Wrapf(err, "failed to patch task %d with the down migration", task.ID)
+	}
+
+	if len(payload.DownStatementLossyList) > 0 {
+		if err := createLossyDownMigrationActivity(ctx, stores, activityManager, task, payload.DownStatementLossyList); err != nil {
+			return errors.Wrap(err, "failed to create the lossy down migration activity")
+		}
+	}
+	return nil
+}
+
+func createLossyDownMigrationActivity(ctx context.Context, stores *store.Store, activityManager *activity.Manager, task *store.TaskMessage, lossyList []string) error {
+	issue, err := stores.GetIssueV2(ctx, &store.FindIssueMessage{PipelineID: &task.PipelineID})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get issue of pipeline %d", task.PipelineID)
+	}
+	if issue == nil {
+		return nil
+	}
+	payload, err := json.Marshal(api.ActivityIssueCommentCreatePayload{
+		IssueName: issue.Title,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal ActivityIssueCommentCreatePayload")
+	}
+	if _, err := activityManager.CreateActivity(ctx, &api.ActivityCreate{
+		CreatorID:   api.SystemBotID,
+		ContainerID: issue.UID,
+		Type:        api.ActivityIssueCommentCreate,
+		Level:       api.ActivityWarn,
+		Comment:     fmt.Sprintf("The down migration of task %q is lossy, rolling back will lose the data of the following statements:\n%s", task.Name, strings.Join(lossyList, "\n")),
+		Payload:     string(payload),
+	}, &activity.Metadata{Issue: issue}); err != nil {
+		return errors.Wrap(err, "failed to create activity")
+	}
+	return nil
+}
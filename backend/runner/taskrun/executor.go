@@ -25,6 +25,7 @@ import (
 	"github.com/bytebase/bytebase/backend/plugin/parser"
 	"github.com/bytebase/bytebase/backend/plugin/parser/transform"
 	vcsPlugin "github.com/bytebase/bytebase/backend/plugin/vcs"
+	"github.com/bytebase/bytebase/backend/runner/anomaly"
 	"github.com/bytebase/bytebase/backend/store"
 	"github.com/bytebase/bytebase/backend/utils"
 )
@@ -200,7 +201,7 @@ func executeMigration(ctx context.Context, stores *store.Store, dbFactory *dbfac
 }
 
 func setThreadIDAndStartBinlogCoordinate(ctx context.Context, driver db.Driver, task *store.TaskMessage, store *store.Store) (*store.TaskMessage, error) {
-	mysqlDriver, ok := driver.(*mysql.Driver)
+	mysqlDriver, ok := dbfactory.Unwrap(driver).(*mysql.Driver)
 	if !ok {
 		return nil, errors.Errorf("failed to cast driver to mysql.Driver")
 	}
@@ -421,7 +422,7 @@ func postMigration(ctx context.Context, stores *store.Store, activityManager *ac
 	}
 
 	// Remove schema drift anomalies.
-	if err := stores.ArchiveAnomaly(ctx, &api.AnomalyArchive{
+	if err := anomaly.ArchiveAnomaly(ctx, stores, activityManager, &api.AnomalyArchive{
 		DatabaseID: task.DatabaseID,
 		Type:       api.AnomalyDatabaseSchemaDrift,
 	}); err != nil && common.ErrorCode(err) != common.NotFound {
@@ -492,11 +493,13 @@ func isWriteBack(ctx context.Context, stores *store.Store, license enterpriseAPI
 	return true, nil
 }
 
-func runMigration(ctx context.Context, store *store.Store, dbFactory *dbfactory.DBFactory, activityManager *activity.Manager, license enterpriseAPI.LicenseService, stateCfg *state.State, profile config.Profile, task *store.TaskMessage, migrationType db.MigrationType, statement, schemaVersion string, vcsPushEvent *vcsPlugin.PushEvent) (terminated bool, result *api.TaskRunResultPayload, err error) {
+func runMigration(ctx context.Context, store *store.Store, dbFactory *dbfactory.DBFactory, activityManager *activity.Manager, license enterpriseAPI.LicenseService, stateCfg *state.State, profile config.Profile, task *store.TaskMessage, migrationType db.MigrationType, statement, schemaVersion string, vcsPushEvent *vcsPlugin.PushEvent, resumableExecution, resume bool) (terminated bool, result *api.TaskRunResultPayload, err error) {
 	mi, err := preMigration(ctx, store, profile, task, migrationType, statement, schemaVersion, vcsPushEvent)
 	if err != nil {
 		return true, nil, err
 	}
+	mi.ResumableExecution = resumableExecution
+	mi.Resume = resume
 	migrationID, schema, err := executeMigration(ctx, store, dbFactory, stateCfg, task, statement, mi)
 	if err != nil {
 		return true, nil, err
@@ -653,3 +656,10 @@ func writeBackLatestSchema(ctx context.Context, store *store.Store, repository *
 	}
 	return schemaFileMeta.LastCommitID, nil
 }
+
+// updateTaskRunProgress saves the progress of the running task. The progress is informational, so the failure is only logged.
+func updateTaskRunProgress(ctx context.Context, stores *store.Store, taskID int, progress *api.Progress) {
+	if err := stores.UpdateTaskRunProgress(ctx, taskID, progress); err != nil {
+		log.Warn("Failed to update the task run progress", zap.Int("task_id", taskID), zap.Error(err))
+	}
+}
@@ -0,0 +1,113 @@
+package taskrun
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/backend/common/log"
+	"github.com/bytebase/bytebase/backend/component/activity"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+// GetMaintenanceWindow returns the first period at or after now when the task on the instance and database is allowed to start
+// by the maintenance window policy of the instance environment.
+// The end is the zero time if the period is unbounded. It returns false if the policy never allows the task to start.
+func (s *Scheduler) GetMaintenanceWindow(ctx context.Context, instanceID int, databaseID *int, now time.Time) (time.Time, time.Time, bool, error) {
+	instance, err := s.store.GetInstanceV2(ctx, &store.FindInstanceMessage{UID: &instanceID})
+	if err != nil {
+		return time.Time{}, time.Time{}, false, err
+	}
+	if instance == nil {
+		return time.Time{}, time.Time{}, false, errors.Errorf("instance %d not found", instanceID)
+	}
+	environment, err := s.store.GetEnvironmentV2(ctx, &store.FindEnvironmentMessage{ResourceID: &instance.EnvironmentID})
+	if err != nil {
+		return time.Time{}, time.Time{}, false, err
+	}
+	if environment == nil {
+		return time.Time{}, time.Time{}, false, errors.Errorf("environment %q not found", instance.EnvironmentID)
+	}
+	policy, err := s.store.GetMaintenanceWindowPolicy(ctx, environment.UID)
+	if err != nil {
+		return time.Time{}, time.Time{}, false, err
+	}
+	if len(policy.WindowList) == 0 && len(policy.BlackoutList) == 0 {
+		return now, time.Time{}, true, nil
+	}
+
+	var labels map[string]string
+	if databaseID != nil {
+		database, err := s.store.GetDatabaseV2(ctx, &store.FindDatabaseMessage{UID: databaseID, ShowDeleted: true})
+		if err != nil {
+			return time.Time{}, time.Time{}, false, err
+		}
+		if database != nil {
+			labels = database.Labels
+		}
+	}
+	return policy.NextEligibleTime(labels, now)
+}
+
+// isInMaintenanceWindow returns whether the task is allowed to start now by the maintenance window policy.
+func (s *Scheduler) isInMaintenanceWindow(ctx context.Context, task *store.TaskMessage) (bool, error) {
+	now := time.Now()
+	start, _, found, err := s.GetMaintenanceWindow(ctx, task.InstanceID, task.DatabaseID, now)
+	if err != nil {
+		return false, err
+	}
+	return found && !start.After(now), nil
+}
+
+// watchMaintenanceWindowOverrun warns in the issue if the running task is still running at the end of the maintenance window.
+// The caller should stop the returned timer once the task finishes, and the timer is nil if the window is unbounded.
+func (s *Scheduler) watchMaintenanceWindowOverrun(ctx context.Context, task *store.TaskMessage) *time.Timer {
+	_, end, found, err := s.GetMaintenanceWindow(ctx, task.InstanceID, task.DatabaseID, time.Now())
+	if err != nil {
+		log.Error("Failed to get the maintenance window of the task", zap.Int("task_id", task.ID), zap.Error(err))
+		return nil
+	}
+	if !found || end.IsZero() {
+		return nil
+	}
+	return time.AfterFunc(time.Until(end), func() {
+		if _, ok := s.stateCfg.RunningTasks.Load(task.ID); !ok {
+			return
+		}
+		if err := s.createMaintenanceWindowOverrunActivity(ctx, task, end); err != nil {
+			log.Error("Failed to create the maintenance window overrun activity", zap.Int("task_id", task.ID), zap.Error(err))
+		}
+	})
+}
+
+func (s *Scheduler) createMaintenanceWindowOverrunActivity(ctx context.Context, task *store.TaskMessage, end time.Time) error {
+	issue, err := s.store.GetIssueV2(ctx, &store.FindIssueMessage{PipelineID: &task.PipelineID})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get issue of pipeline %d", task.PipelineID)
+	}
+	if issue == nil {
+		return nil
+	}
+	payload, err := json.Marshal(api.ActivityIssueCommentCreatePayload{
+		IssueName: issue.Title,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal ActivityIssueCommentCreatePayload")
+	}
+	if _, err := s.activityManager.CreateActivity(ctx, &api.ActivityCreate{
+		CreatorID:   api.SystemBotID,
+		ContainerID: issue.UID,
+		Type:        api.ActivityIssueCommentCreate,
+		Level:       api.ActivityWarn,
+		Comment:     fmt.Sprintf("Task %q is still running after the maintenance window ended at %s.", task.Name, end.UTC().Format(time.RFC3339)),
+		Payload:     string(payload),
+	}, &activity.Metadata{Issue: issue}); err != nil {
+		return errors.Wrap(err, "failed to create activity")
+	}
+	return nil
+}
@@ -221,8 +221,8 @@ func (exec *PITRRestoreExecutor) doPITRRestore(ctx context.Context, dbFactory *d
 	}
 	log.Debug("Found backup list", zap.Array("backups", api.ZapBackupArray(backupList)))
 
-	mysqlSourceDriver, sourceOk := sourceDriver.(*mysql.Driver)
-	mysqlTargetDriver, targetOk := targetDriver.(*mysql.Driver)
+	mysqlSourceDriver, sourceOk := dbfactory.Unwrap(sourceDriver).(*mysql.Driver)
+	mysqlTargetDriver, targetOk := dbfactory.Unwrap(targetDriver).(*mysql.Driver)
 	if (!sourceOk) || (!targetOk) {
 		log.Error("Failed to cast driver to mysql.Driver")
 		return nil, errors.Errorf("[internal] cast driver to mysql.Driver failed")
@@ -375,7 +375,7 @@ func (*PITRRestoreExecutor) doRestoreInPlacePostgres(ctx context.Context, stores
 	}
 	defer driver.Close(ctx)
 
-	pgDriver, ok := driver.(*pg.Driver)
+	pgDriver, ok := dbfactory.Unwrap(driver).(*pg.Driver)
 	if !ok {
 		log.Error("Failed to cast driver to pg.Driver")
 		return nil, errors.Errorf("[internal] cast driver to pg.Driver failed")
@@ -431,7 +431,7 @@ func (exec *PITRRestoreExecutor) updateProgress(ctx context.Context, driver *mys
 		defer ticker.Stop()
 		createdTs := time.Now().Unix()
 		totalUnit := backupFileBytes + totalBinlogBytes
-		exec.stateCfg.TaskProgress.Store(taskID, api.Progress{
+		updateTaskRunProgress(ctx, exec.store, taskID, &api.Progress{
 			TotalUnit:     totalUnit,
 			CompletedUnit: 0,
 			CreatedTs:     createdTs,
@@ -440,7 +440,7 @@ func (exec *PITRRestoreExecutor) updateProgress(ctx context.Context, driver *mys
 		for {
 			select {
 			case <-ticker.C:
-				exec.stateCfg.TaskProgress.Store(taskID, api.Progress{
+				updateTaskRunProgress(ctx, exec.store, taskID, &api.Progress{
 					TotalUnit:     totalUnit,
 					CompletedUnit: driver.GetRestoredBackupBytes() + driver.GetReplayedBinlogBytes(),
 					CreatedTs:     createdTs,
@@ -0,0 +1,177 @@
+package taskrun
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+// promotionGateAnomalyTypes is the anomaly types failing the no open anomaly condition of the promotion gate.
+var promotionGateAnomalyTypes = map[api.AnomalyType]bool{
+	api.AnomalyDatabaseConnection:  true,
+	api.AnomalyDatabaseSchemaDrift: true,
+}
+
+// GetStageGateStatus returns the status of the promotion gate of the stage.
+// It returns nil if the stage has no gate, or it's the first stage of the pipeline.
+func (s *Scheduler) GetStageGateStatus(ctx context.Context, pipelineID int, stageID int) (*api.StageGateStatus, error) {
+	stages, err := s.store.ListStageV2(ctx, pipelineID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list stages of pipeline %d", pipelineID)
+	}
+	index := -1
+	for i, stage := range stages {
+		if stage.ID == stageID {
+			index = i
+			break
+		}
+	}
+	if index <= 0 {
+		return nil, nil
+	}
+	gate := stages[index].Payload.Gate
+	if gate == nil {
+		return nil, nil
+	}
+	previousStage := stages[index-1]
+	previousTasks, err := s.store.ListTasks(ctx, &api.TaskFind{PipelineID: &pipelineID, StageID: &previousStage.ID})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list tasks of stage %d", previousStage.ID)
+	}
+
+	status := &api.StageGateStatus{Passed: true}
+	if gate.SoakMinutes > 0 {
+		doneTime, done, err := s.getStageDoneTime(ctx, previousStage)
+		if err != nil {
+			return nil, err
+		}
+		status.ConditionList = append(status.ConditionList, getSoakTimeCondition(previousStage.Name, doneTime, done, gate.SoakMinutes, time.Now()))
+	}
+	if gate.NoOpenAnomaly {
+		condition, err := s.getNoOpenAnomalyCondition(ctx, previousTasks)
+		if err != nil {
+			return nil, err
+		}
+		status.ConditionList = append(status.ConditionList, condition)
+	}
+	if gate.VerificationSuccess {
+		condition, err := s.getVerificationSuccessCondition(ctx, previousStage, previousTasks)
+		if err != nil {
+			return nil, err
+		}
+		status.ConditionList = append(status.ConditionList, condition)
+	}
+	for _, condition := range status.ConditionList {
+		if !condition.Passed {
+			status.Passed = false
+		}
+	}
+	return status, nil
+}
+
+// isPromotionGateClosed returns whether the promotion gate of the task's stage holds the task from starting.
+func (s *Scheduler) isPromotionGateClosed(ctx context.Context, task *store.TaskMessage) (bool, error) {
+	status, err := s.GetStageGateStatus(ctx, task.PipelineID, task.StageID)
+	if err != nil {
+		return false, err
+	}
+	return status != nil && !status.Passed, nil
+}
+
+func getSoakTimeCondition(previousStageName string, doneTime time.Time, done bool, soakMinutes int, now time.Time) *api.StageGateCondition {
+	condition := &api.StageGateCondition{Type: api.StageGateConditionSoakTime}
+	soakTime := time.Duration(soakMinutes) * time.Minute
+	switch {
+	case !done:
+		condition.Message = fmt.Sprintf("Stage %q isn't done", previousStageName)
+	case now.Before(doneTime.Add(soakTime)):
+		condition.Message = fmt.Sprintf("Stage %q has been done for %s, less than the soak time %s", previousStageName, now.Sub(doneTime).Truncate(time.Minute), soakTime)
+	default:
+		condition.Passed = true
+	}
+	return condition
+}
+
+func (s *Scheduler) getNoOpenAnomalyCondition(ctx context.Context, previousTasks []*store.TaskMessage) (*api.StageGateCondition, error) {
+	condition := &api.StageGateCondition{Type: api.StageGateConditionNoOpenAnomaly, Passed: true}
+	normalRowStatus := api.Normal
+	checked := make(map[int]bool)
+	for _, task := range previousTasks {
+		if task.DatabaseID == nil || checked[*task.DatabaseID] {
+			continue
+		}
+		checked[*task.DatabaseID] = true
+		anomalies, err := s.store.FindAnomaly(ctx, &api.AnomalyFind{
+			RowStatus:  &normalRowStatus,
+			DatabaseID: task.DatabaseID,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to find anomalies of database %d", *task.DatabaseID)
+		}
+		for _, anomaly := range anomalies {
+			if !promotionGateAnomalyTypes[anomaly.Type] {
+				continue
+			}
+			databaseName := strconv.Itoa(*task.DatabaseID)
+			if anomaly.Database != nil {
+				databaseName = anomaly.Database.Name
+			}
+			condition.Passed = false
+			condition.Message = fmt.Sprintf("Database %q has open anomaly %q", databaseName, anomaly.Type)
+			return condition, nil
+		}
+	}
+	return condition, nil
+}
+
+func (s *Scheduler) getVerificationSuccessCondition(ctx context.Context, previousStage *store.StageMessage, previousTasks []*store.TaskMessage) (*api.StageGateCondition, error) {
+	condition := &api.StageGateCondition{Type: api.StageGateConditionVerificationSuccess, Passed: true}
+	verificationType := api.TaskCheckDatabaseVerification
+	taskCheckRuns, err := s.store.FindTaskCheckRun(ctx, &store.TaskCheckRunFind{
+		PipelineID: &previousStage.PipelineID,
+		StageID:    &previousStage.ID,
+		Type:       &verificationType,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list verification task check runs of stage %d", previousStage.ID)
+	}
+	taskCheckRunMap := make(map[int][]*api.TaskCheckRun)
+	for _, run := range taskCheckRuns {
+		taskCheckRunMap[run.TaskID] = append(taskCheckRunMap[run.TaskID], run)
+	}
+
+	for _, task := range previousTasks {
+		var payload struct {
+			Skipped      bool                  `json:"skipped,omitempty"`
+			Verification *api.TaskVerification `json:"verification,omitempty"`
+		}
+		if err := json.Unmarshal([]byte(task.Payload), &payload); err != nil {
+			return nil, errors.Wrapf(err, "invalid payload of task %d", task.ID)
+		}
+		if payload.Skipped || payload.Verification == nil {
+			continue
+		}
+		status, found, err := api.GetVerificationStatus(taskCheckRunMap[task.ID])
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			condition.Passed = false
+			condition.Message = fmt.Sprintf("The verification of task %q isn't done", task.Name)
+			return condition, nil
+		}
+		if status != api.TaskCheckStatusSuccess {
+			condition.Passed = false
+			condition.Message = fmt.Sprintf("The verification of task %q fails", task.Name)
+			return condition, nil
+		}
+	}
+	return condition, nil
+}
@@ -0,0 +1,38 @@
+package taskrun
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+)
+
+func TestGetSoakTimeCondition(t *testing.T) {
+	now := time.Date(2023, 3, 8, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		doneTime time.Time
+		done     bool
+		want     *api.StageGateCondition
+	}{
+		{
+			done: false,
+			want: &api.StageGateCondition{Type: api.StageGateConditionSoakTime, Message: `Stage "staging" isn't done`},
+		},
+		{
+			doneTime: now.Add(-90 * time.Minute),
+			done:     true,
+			want:     &api.StageGateCondition{Type: api.StageGateConditionSoakTime, Message: `Stage "staging" has been done for 1h30m0s, less than the soak time 2h0m0s`},
+		},
+		{
+			doneTime: now.Add(-2 * time.Hour),
+			done:     true,
+			want:     &api.StageGateCondition{Type: api.StageGateConditionSoakTime, Passed: true},
+		},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.want, getSoakTimeCondition("staging", test.doneTime, test.done, 120, now))
+	}
+}
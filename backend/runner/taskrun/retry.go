@@ -0,0 +1,190 @@
+package taskrun
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/common"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/db"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+// retriableTaskTypes is the set of task types which can be retried automatically.
+// The data update tasks are retried only if the task retry policy opts in, because they may not be idempotent.
+var retriableTaskTypes = map[api.TaskType]bool{
+	api.TaskDatabaseSchemaBaseline:  true,
+	api.TaskDatabaseSchemaUpdate:    true,
+	api.TaskDatabaseSchemaUpdateSDL: true,
+	api.TaskDatabaseDataUpdate:      true,
+}
+
+// retryTaskIfNeeded puts the failed task back to pending if the task retry policy of the environment allows to retry the error.
+// The failed run is closed with the error, and the task starts again with a new run after the backoff.
+func (s *Scheduler) retryTaskIfNeeded(ctx context.Context, task *store.TaskMessage, taskErr error) (bool, error) {
+	if !retriableTaskTypes[task.Type] {
+		return false, nil
+	}
+	class, ok := getRetryErrorClass(taskErr)
+	if !ok {
+		return false, nil
+	}
+	instance, err := s.store.GetInstanceV2(ctx, &store.FindInstanceMessage{UID: &task.InstanceID})
+	if err != nil {
+		return false, err
+	}
+	if instance == nil {
+		return false, errors.Errorf("instance %d not found", task.InstanceID)
+	}
+	environment, err := s.store.GetEnvironmentV2(ctx, &store.FindEnvironmentMessage{ResourceID: &instance.EnvironmentID})
+	if err != nil {
+		return false, err
+	}
+	if environment == nil {
+		return false, errors.Errorf("environment %q not found", instance.EnvironmentID)
+	}
+	policy, err := s.store.GetTaskRetryPolicy(ctx, environment.UID)
+	if err != nil {
+		return false, err
+	}
+	if !policy.HasErrorClass(class) {
+		return false, nil
+	}
+	if task.Type == api.TaskDatabaseDataUpdate && !policy.RetryDataUpdate {
+		return false, nil
+	}
+	attempt, err := s.getTaskAttempt(ctx, task)
+	if err != nil {
+		return false, err
+	}
+	if attempt >= policy.MaxAttempts {
+		return false, nil
+	}
+
+	backoff := policy.GetBackoff(attempt)
+	earliestAllowedTs := time.Now().Add(backoff).Unix()
+	if _, err := s.store.UpdateTaskV2(ctx, &api.TaskPatch{
+		ID:                task.ID,
+		UpdaterID:         api.SystemBotID,
+		EarliestAllowedTs: &earliestAllowedTs,
+	}); err != nil {
+		return false, errors.Wrapf(err, "failed to set the retry time of task %d", task.ID)
+	}
+
+	resultPayload := api.TaskRunResultPayload{
+		Detail:       taskErr.Error(),
+		RetryAttempt: attempt,
+	}
+	var statementErr *db.ExecuteStatementError
+	if errors.As(taskErr, &statementErr) {
+		resultPayload.AppliedStatementCount = statementErr.AppliedStatementCount()
+		resultPayload.FailedStatement = statementErr.Statement
+	}
+	bytes, err := json.Marshal(resultPayload)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to marshal task run result")
+	}
+	code := common.ErrorCode(taskErr)
+	result := string(bytes)
+	comment := fmt.Sprintf("Attempt %d of %d failed with a %s error, retrying in %s.", attempt, policy.MaxAttempts, strings.ToLower(strings.ReplaceAll(string(class), "_", " ")), backoff)
+	taskStatusPatch := &api.TaskStatusPatch{
+		ID:        task.ID,
+		UpdaterID: api.SystemBotID,
+		Status:    api.TaskPending,
+		Code:      &code,
+		Result:    &result,
+		Comment:   &comment,
+	}
+	if taskResumptionImplemented[task.Type] {
+		// Retry from the failed statement since the statements before it have been applied.
+		resume := statementErr != nil
+		taskStatusPatch.Resume = &resume
+	}
+	// The task skips the status transition check, since the running task can't go back to pending otherwise.
+	if _, err := s.store.UpdateTaskStatusV2(ctx, taskStatusPatch); err != nil {
+		return false, errors.Wrapf(err, "failed to put task %d back to pending", task.ID)
+	}
+	issue, err := s.store.GetIssueV2(ctx, &store.FindIssueMessage{PipelineID: &task.PipelineID})
+	if err != nil {
+		return true, errors.Wrapf(err, "failed to fetch containing issue of task %d", task.ID)
+	}
+	if err := s.createTaskStatusUpdateActivity(ctx, task, taskStatusPatch, issue); err != nil {
+		return true, err
+	}
+	return true, nil
+}
+
+// getTaskAttempt returns the attempt number of the running task, which continues from the previous run if it was retried automatically.
+func (s *Scheduler) getTaskAttempt(ctx context.Context, task *store.TaskMessage) (int, error) {
+	taskRuns, err := s.store.ListTaskRunsV2(ctx, &store.TaskRunFind{TaskID: &task.ID})
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to list task runs of task %d", task.ID)
+	}
+	sort.Slice(taskRuns, func(i, j int) bool {
+		return taskRuns[i].ID > taskRuns[j].ID
+	})
+	for _, taskRun := range taskRuns {
+		if taskRun.Status == api.TaskRunRunning {
+			continue
+		}
+		if taskRun.Status != api.TaskRunFailed || taskRun.Result == "" {
+			return 1, nil
+		}
+		var result api.TaskRunResultPayload
+		if err := json.Unmarshal([]byte(taskRun.Result), &result); err != nil {
+			return 1, nil
+		}
+		return result.RetryAttempt + 1, nil
+	}
+	return 1, nil
+}
+
+// getRetryErrorClass returns the class of the transient error, and false if the error isn't transient.
+func getRetryErrorClass(err error) (api.TaskRetryErrorClass, bool) {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		switch mysqlErr.Number {
+		case 1205:
+			return api.TaskRetryErrorClassLockTimeout, true
+		case 1213:
+			return api.TaskRetryErrorClassDeadlock, true
+		}
+		return "", false
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "40001":
+			return api.TaskRetryErrorClassSerialization, true
+		case "40P01":
+			return api.TaskRetryErrorClassDeadlock, true
+		}
+		// Class 08 is the connection exceptions.
+		if strings.HasPrefix(pgErr.Code, "08") {
+			return api.TaskRetryErrorClassConnection, true
+		}
+		return "", false
+	}
+	var netErr net.Error
+	if errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, mysql.ErrInvalidConn) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.As(err, &netErr) {
+		return api.TaskRetryErrorClassConnection, true
+	}
+	return "", false
+}
@@ -0,0 +1,38 @@
+package taskrun
+
+import (
+	"database/sql/driver"
+	"syscall"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/backend/common"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+)
+
+func TestGetRetryErrorClass(t *testing.T) {
+	tests := []struct {
+		err       error
+		wantClass api.TaskRetryErrorClass
+		wantOK    bool
+	}{
+		{common.Wrapf(&mysql.MySQLError{Number: 1205, Message: "Lock wait timeout exceeded"}, common.DbExecutionError, "failed to execute"), api.TaskRetryErrorClassLockTimeout, true},
+		{errors.Wrap(&mysql.MySQLError{Number: 1213, Message: "Deadlock found"}, "failed to execute"), api.TaskRetryErrorClassDeadlock, true},
+		{&mysql.MySQLError{Number: 1062, Message: "Duplicate entry"}, "", false},
+		{errors.Wrap(&pgconn.PgError{Code: "40001"}, "failed to execute"), api.TaskRetryErrorClassSerialization, true},
+		{&pgconn.PgError{Code: "08006"}, api.TaskRetryErrorClassConnection, true},
+		{&pgconn.PgError{Code: "42P01"}, "", false},
+		{errors.Wrap(driver.ErrBadConn, "failed to execute"), api.TaskRetryErrorClassConnection, true},
+		{errors.Wrap(syscall.ECONNRESET, "failed to execute"), api.TaskRetryErrorClassConnection, true},
+		{errors.New("syntax error"), "", false},
+	}
+	for _, test := range tests {
+		class, ok := getRetryErrorClass(test.err)
+		require.Equal(t, test.wantOK, ok, test.err.Error())
+		require.Equal(t, test.wantClass, class, test.err.Error())
+	}
+}
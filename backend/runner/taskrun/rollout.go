@@ -0,0 +1,152 @@
+package taskrun
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/component/activity"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+// isRolloutHeld returns whether the rollout holds the task from starting, because the rollout of its stage is paused by the failed tasks,
+// or the previous stage is still baking.
+func (s *Scheduler) isRolloutHeld(ctx context.Context, task *store.TaskMessage) (bool, error) {
+	stages, err := s.store.ListStageV2(ctx, task.PipelineID)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to list stages of pipeline %d", task.PipelineID)
+	}
+	index := -1
+	for i, stage := range stages {
+		if stage.ID == task.StageID {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return false, nil
+	}
+
+	stage := stages[index]
+	if rollout := stage.Payload.Rollout; rollout != nil && rollout.PauseFailurePercent > 0 {
+		paused, err := s.isRolloutPaused(ctx, stage, rollout)
+		if err != nil {
+			return false, err
+		}
+		if paused {
+			return true, nil
+		}
+	}
+
+	if index == 0 {
+		return false, nil
+	}
+	previousStage := stages[index-1]
+	if rollout := previousStage.Payload.Rollout; rollout != nil && rollout.BakeMinutes > 0 {
+		doneTime, done, err := s.getStageDoneTime(ctx, previousStage)
+		if err != nil {
+			return false, err
+		}
+		if !done || time.Now().Before(doneTime.Add(time.Duration(rollout.BakeMinutes)*time.Minute)) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// isRolloutPaused returns whether the failed tasks of the stage reach the pause threshold of the rollout.
+// It comments on the issue when the stage gets paused.
+func (s *Scheduler) isRolloutPaused(ctx context.Context, stage *store.StageMessage, rollout *api.DeploymentRollout) (bool, error) {
+	tasks, err := s.store.ListTasks(ctx, &api.TaskFind{PipelineID: &stage.PipelineID, StageID: &stage.ID})
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to list tasks of stage %d", stage.ID)
+	}
+	failed := 0
+	for _, task := range tasks {
+		if task.Status == api.TaskFailed {
+			failed++
+		}
+	}
+	if failed == 0 || failed*100 < rollout.PauseFailurePercent*len(tasks) {
+		s.stateCfg.RolloutPausedStages.Delete(stage.ID)
+		return false, nil
+	}
+
+	if _, loaded := s.stateCfg.RolloutPausedStages.LoadOrStore(stage.ID, true); !loaded {
+		comment := fmt.Sprintf("The rollout of stage %q is paused because %d of %d tasks failed, reaching the %d%% threshold. Retry or skip the failed tasks to resume.", stage.Name, failed, len(tasks), rollout.PauseFailurePercent)
+		if err := s.createRolloutActivity(ctx, stage.PipelineID, comment); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// getStageDoneTime returns the time when the last task run of the stage ends, and false if the stage isn't done.
+// It uses the end time of the task runs rather than the task updated time, which may change after the task is done.
+func (s *Scheduler) getStageDoneTime(ctx context.Context, stage *store.StageMessage) (time.Time, bool, error) {
+	tasks, err := s.store.ListTasks(ctx, &api.TaskFind{PipelineID: &stage.PipelineID, StageID: &stage.ID})
+	if err != nil {
+		return time.Time{}, false, errors.Wrapf(err, "failed to list tasks of stage %d", stage.ID)
+	}
+	for _, task := range tasks {
+		if task.Status != api.TaskDone {
+			return time.Time{}, false, nil
+		}
+	}
+	taskRuns, err := s.store.ListTaskRunsV2(ctx, &store.TaskRunFind{
+		StageID:    &stage.ID,
+		StatusList: &[]api.TaskRunStatus{api.TaskRunDone},
+	})
+	if err != nil {
+		return time.Time{}, false, errors.Wrapf(err, "failed to list task runs of stage %d", stage.ID)
+	}
+	endTsMap := make(map[int]int64)
+	for _, taskRun := range taskRuns {
+		if taskRun.UpdatedTs > endTsMap[taskRun.TaskID] {
+			endTsMap[taskRun.TaskID] = taskRun.UpdatedTs
+		}
+	}
+	var doneTs int64
+	for _, task := range tasks {
+		endTs, ok := endTsMap[task.ID]
+		if !ok {
+			// The skipped tasks are done without task runs.
+			endTs = task.UpdatedTs
+		}
+		if endTs > doneTs {
+			doneTs = endTs
+		}
+	}
+	return time.Unix(doneTs, 0), true, nil
+}
+
+func (s *Scheduler) createRolloutActivity(ctx context.Context, pipelineID int, comment string) error {
+	issue, err := s.store.GetIssueV2(ctx, &store.FindIssueMessage{PipelineID: &pipelineID})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get issue of pipeline %d", pipelineID)
+	}
+	if issue == nil {
+		return nil
+	}
+	payload, err := json.Marshal(api.ActivityIssueCommentCreatePayload{
+		IssueName: issue.Title,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal ActivityIssueCommentCreatePayload")
+	}
+	if _, err := s.activityManager.CreateActivity(ctx, &api.ActivityCreate{
+		CreatorID:   api.SystemBotID,
+		ContainerID: issue.UID,
+		Type:        api.ActivityIssueCommentCreate,
+		Level:       api.ActivityWarn,
+		Comment:     comment,
+		Payload:     string(payload),
+	}, &activity.Metadata{Issue: issue}); err != nil {
+		return errors.Wrap(err, "failed to create activity")
+	}
+	return nil
+}
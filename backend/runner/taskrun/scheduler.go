@@ -22,6 +22,7 @@ import (
 	"github.com/bytebase/bytebase/backend/component/state"
 	enterpriseAPI "github.com/bytebase/bytebase/backend/enterprise/api"
 	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/db"
 	"github.com/bytebase/bytebase/backend/runner/apprun"
 	"github.com/bytebase/bytebase/backend/runner/schemasync"
 	"github.com/bytebase/bytebase/backend/store"
@@ -36,6 +37,10 @@ var (
 	taskCancellationImplemented = map[api.TaskType]bool{
 		api.TaskDatabaseSchemaUpdateGhostSync: true,
 	}
+	taskResumptionImplemented = map[api.TaskType]bool{
+		api.TaskDatabaseSchemaUpdate: true,
+		api.TaskDatabaseDataUpdate:   true,
+	}
 	applicableTaskStatusTransition = map[api.TaskStatus][]api.TaskStatus{
 		api.TaskPendingApproval: {api.TaskPending, api.TaskDone},
 		api.TaskPending:         {api.TaskCanceled, api.TaskRunning, api.TaskPendingApproval, api.TaskDone},
@@ -137,6 +142,7 @@ func (s *Scheduler) Run(ctx context.Context, wg *sync.WaitGroup) {
 					log.Error("Failed to retrieve running tasks", zap.Error(err))
 					return
 				}
+				s.cancelStoppedTasks(tasks)
 
 				// For each database, we will only execute the earliest running task (minimal task ID) and hold up the rest of the running tasks.
 				// Sort the taskList by ID first.
@@ -199,7 +205,6 @@ func (s *Scheduler) Run(ctx context.Context, wg *sync.WaitGroup) {
 						defer func() {
 							s.stateCfg.RunningTasks.Delete(task.ID)
 							s.stateCfg.RunningTasksCancel.Delete(task.ID)
-							s.stateCfg.TaskProgress.Delete(task.ID)
 							s.stateCfg.Lock()
 							s.stateCfg.InstanceOutstandingConnections[task.InstanceID]--
 							s.stateCfg.Unlock()
@@ -208,6 +213,20 @@ func (s *Scheduler) Run(ctx context.Context, wg *sync.WaitGroup) {
 						executorCtx, cancel := context.WithCancel(ctx)
 						s.stateCfg.RunningTasksCancel.Store(task.ID, cancel)
 
+						if overrunTimer := s.watchMaintenanceWindowOverrun(ctx, task); overrunTimer != nil {
+							defer overrunTimer.Stop()
+						}
+
+						if logger, err := s.newTaskRunLogger(ctx, task); err != nil {
+							log.Error("Failed to create the task run logger",
+								zap.Int("id", task.ID),
+								zap.String("name", task.Name),
+								zap.Error(err),
+							)
+						} else if logger != nil {
+							executorCtx = db.WithExecuteLogger(executorCtx, logger)
+						}
+
 						done, result, err := RunExecutorOnce(executorCtx, executor, task)
 
 						select {
@@ -232,15 +251,32 @@ func (s *Scheduler) Run(ctx context.Context, wg *sync.WaitGroup) {
 							return
 						}
 						if done && err != nil {
+							retried, retryErr := s.retryTaskIfNeeded(ctx, task, err)
+							if retryErr != nil {
+								log.Error("Failed to retry task",
+									zap.Int("id", task.ID),
+									zap.String("name", task.Name),
+									zap.Error(retryErr),
+								)
+							}
+							if retried {
+								return
+							}
 							log.Warn("Failed to run task",
 								zap.Int("id", task.ID),
 								zap.String("name", task.Name),
 								zap.String("type", string(task.Type)),
 								zap.Error(err),
 							)
-							bytes, marshalErr := json.Marshal(api.TaskRunResultPayload{
+							resultPayload := api.TaskRunResultPayload{
 								Detail: err.Error(),
-							})
+							}
+							var statementErr *db.ExecuteStatementError
+							if errors.As(err, &statementErr) {
+								resultPayload.AppliedStatementCount = statementErr.AppliedStatementCount()
+								resultPayload.FailedStatement = statementErr.Statement
+							}
+							bytes, marshalErr := json.Marshal(resultPayload)
 							if marshalErr != nil {
 								log.Error("Failed to marshal task run result",
 									zap.Int("task_id", task.ID),
@@ -294,6 +330,13 @@ func (s *Scheduler) Run(ctx context.Context, wg *sync.WaitGroup) {
 								)
 								return
 							}
+							if err := s.scheduleVerification(ctx, task); err != nil {
+								log.Error("Failed to schedule the verification",
+									zap.Int("id", task.ID),
+									zap.String("name", task.Name),
+									zap.Error(err),
+								)
+							}
 
 							issue, err := s.store.GetIssueV2(ctx, &store.FindIssueMessage{PipelineID: &task.PipelineID})
 							if err != nil {
@@ -350,6 +393,11 @@ func (s *Scheduler) PatchTaskStatement(ctx context.Context, task *store.TaskMess
 			schemaVersion := common.DefaultMigrationVersion()
 			taskPatch.SchemaVersion = &schemaVersion
 		}
+		if task.Type == api.TaskDatabaseDataUpdate {
+			// The chunked execution of the new statement starts over.
+			resetCheckpoint := "null"
+			taskPatch.ChunkCheckpoint = &resetCheckpoint
+		}
 	}
 
 	taskPatched, err := s.store.UpdateTaskV2(ctx, taskPatch)
@@ -450,6 +498,29 @@ func (s *Scheduler) PatchTaskStatement(ctx context.Context, task *store.TaskMess
 				)
 			}
 		}
+
+		if task.Type == api.TaskDatabaseDataUpdate && api.IsStatementAffectedRowsCheckSupported(instance.Engine) {
+			payload, err := json.Marshal(api.TaskCheckDatabaseStatementAffectedRowsPayload{
+				Statement: *taskPatch.Statement,
+				DbType:    instance.Engine,
+			})
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, errors.Wrapf(err, "failed to marshal check statement affected rows payload: %v", task.Name))
+			}
+			if err := s.store.CreateTaskCheckRunIfNeeded(ctx, &store.TaskCheckRunCreate{
+				CreatorID: api.SystemBotID,
+				TaskID:    task.ID,
+				Type:      api.TaskCheckDatabaseStatementAffectedRows,
+				Payload:   string(payload),
+			}); err != nil {
+				// It's OK if we failed to trigger a check, just emit an error log
+				log.Error("Failed to trigger statement affected rows check after changing the task statement",
+					zap.Int("task_id", task.ID),
+					zap.String("task_name", task.Name),
+					zap.Error(err),
+				)
+			}
+		}
 	}
 
 	// Update statement activity.
@@ -631,6 +702,21 @@ func (s *Scheduler) getGroupValueForTask(ctx context.Context, issue *store.Issue
 	return nil, nil
 }
 
+// cancelStoppedTasks cancels the tasks executed by this replica which are no longer running in the database,
+// e.g. the tasks canceled on the other replicas.
+func (s *Scheduler) cancelStoppedTasks(runningTasks []*store.TaskMessage) {
+	running := make(map[int]bool)
+	for _, task := range runningTasks {
+		running[task.ID] = true
+	}
+	s.stateCfg.RunningTasksCancel.Range(func(key, value any) bool {
+		if !running[key.(int)] {
+			value.(context.CancelFunc)()
+		}
+		return true
+	})
+}
+
 // ClearRunningTasks changes all RUNNING tasks to CANCELED.
 // When there are running tasks and Bytebase server is shutdown, these task executors are stopped, but the tasks' status are still RUNNING.
 // When Bytebase is restarted, the task scheduler will re-schedule those RUNNING tasks, which should be CANCELED instead.
@@ -708,10 +794,13 @@ func canUpdateTaskStatement(task *store.TaskMessage) *echo.HTTPError {
 }
 
 // scheduleIfNeeded schedules the task if
-//  2. it has no blocking tasks.
+//  2. it has no blocking tasks, the verification of the previous stages doesn't fail, and the promotion gate of its stage passes.
 //  3. it has passed the earliest allowed time.
-func (s *Scheduler) scheduleIfNeeded(ctx context.Context, task *store.TaskMessage) error {
-	blocked, err := s.isTaskBlocked(ctx, task)
+//  4. it's in the maintenance window.
+//  5. the rollout of its stage isn't paused, and the previous stage has baked.
+//  6. it doesn't exceed the concurrency limits, otherwise it's queued.
+func (s *Scheduler) scheduleIfNeeded(ctx context.Context, task *store.TaskMessage, limiter *concurrencyLimiter, stageStatuses *stageStatusCache) error {
+	blocked, err := s.isTaskBlocked(ctx, task, stageStatuses)
 	if err != nil {
 		return errors.Wrap(err, "failed to check if task is blocked")
 	}
@@ -721,15 +810,40 @@ func (s *Scheduler) scheduleIfNeeded(ctx context.Context, task *store.TaskMessag
 	if task.EarliestAllowedTs != 0 && time.Now().Before(time.Unix(task.EarliestAllowedTs, 0)) {
 		return nil
 	}
+	inWindow, err := s.isInMaintenanceWindow(ctx, task)
+	if err != nil {
+		return errors.Wrap(err, "failed to check the maintenance window")
+	}
+	if !inWindow {
+		return nil
+	}
+	held, err := stageStatuses.isRolloutHeld(ctx, task)
+	if err != nil {
+		return errors.Wrap(err, "failed to check the rollout")
+	}
+	if held {
+		return nil
+	}
+	reason, err := limiter.getQueuedReason(ctx, task)
+	if err != nil {
+		return errors.Wrap(err, "failed to check the concurrency limits")
+	}
+	if reason != "" {
+		limiter.queue(task, reason)
+		return nil
+	}
 
-	return s.PatchTaskStatus(ctx, task, &api.TaskStatusPatch{
+	if err := s.PatchTaskStatus(ctx, task, &api.TaskStatusPatch{
 		ID:        task.ID,
 		UpdaterID: api.SystemBotID,
 		Status:    api.TaskRunning,
-	})
+	}); err != nil {
+		return err
+	}
+	return limiter.start(ctx, task)
 }
 
-func (s *Scheduler) isTaskBlocked(ctx context.Context, task *store.TaskMessage) (bool, error) {
+func (s *Scheduler) isTaskBlocked(ctx context.Context, task *store.TaskMessage, stageStatuses *stageStatusCache) (bool, error) {
 	for _, block := range task.BlockedBy {
 		blockingTask, err := s.store.GetTaskV2ByID(ctx, block)
 		if err != nil {
@@ -739,7 +853,18 @@ func (s *Scheduler) isTaskBlocked(ctx context.Context, task *store.TaskMessage)
 			return true, nil
 		}
 	}
-	return false, nil
+	blocked, err := stageStatuses.isBlockedByVerification(ctx, task)
+	if err != nil {
+		return true, errors.Wrap(err, "failed to check the verification of the previous stages")
+	}
+	if blocked {
+		return true, nil
+	}
+	closed, err := stageStatuses.isPromotionGateClosed(ctx, task)
+	if err != nil {
+		return true, errors.Wrap(err, "failed to check the promotion gate")
+	}
+	return closed, nil
 }
 
 // scheduleAutoApprovedTasks schedules tasks that are approved automatically.
@@ -755,6 +880,10 @@ func (s *Scheduler) scheduleAutoApprovedTasks(ctx context.Context) error {
 	}
 
 	for _, task := range taskList {
+		// The database access requests must always be approved by a human.
+		if task.Type == api.TaskDatabaseAccessGrant {
+			continue
+		}
 		instance, err := s.store.GetInstanceV2(ctx, &store.FindInstanceMessage{UID: &task.InstanceID})
 		if err != nil {
 			return err
@@ -799,9 +928,24 @@ func (s *Scheduler) schedulePendingTasks(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	limiter, err := s.newConcurrencyLimiter(ctx)
+	if err != nil {
+		return err
+	}
+	defer limiter.clearDequeued()
+	stageStatuses := s.newStageStatusCache()
+	// Schedule the earlier tasks first, so that the queued tasks start in order.
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].ID < tasks[j].ID
+	})
 	for _, task := range tasks {
-		if err := s.scheduleIfNeeded(ctx, task); err != nil {
-			return errors.Wrap(err, "failed to schedule task")
+		// One task failing to be scheduled, e.g. failing to check its maintenance window, rollout or concurrency limits, shouldn't block the other tasks.
+		if err := s.scheduleIfNeeded(ctx, task, limiter, stageStatuses); err != nil {
+			log.Error("Failed to schedule task",
+				zap.Int("id", task.ID),
+				zap.String("name", task.Name),
+				zap.Error(err),
+			)
 		}
 	}
 	return nil
@@ -834,16 +978,37 @@ func (s *Scheduler) PatchTaskStatus(ctx context.Context, task *store.TaskMessage
 		}
 	}
 
+	if taskStatusPatch.Resume != nil && *taskStatusPatch.Resume {
+		if task.Status != api.TaskFailed {
+			return common.Errorf(common.Invalid, "cannot resume task whose status is %v", task.Status)
+		}
+		if !taskResumptionImplemented[task.Type] {
+			return common.Errorf(common.NotImplemented, "Resuming task type %s is not supported", task.Type)
+		}
+		resumable, err := isResumableExecution(task)
+		if err != nil {
+			return err
+		}
+		if !resumable {
+			return common.Errorf(common.Invalid, "cannot resume task %d which doesn't opt into resumable execution", task.ID)
+		}
+	}
+	// Rerun the failed task from the start unless it's resumed explicitly.
+	if task.Status == api.TaskFailed && taskResumptionImplemented[task.Type] && taskStatusPatch.Resume == nil {
+		resume := false
+		taskStatusPatch.Resume = &resume
+	}
+
 	if taskStatusPatch.Status == api.TaskCanceled {
 		if !taskCancellationImplemented[task.Type] {
 			return common.Errorf(common.NotImplemented, "Canceling task type %s is not supported", task.Type)
 		}
-		cancelAny, ok := s.stateCfg.RunningTasksCancel.Load(task.ID)
-		cancel := cancelAny.(context.CancelFunc)
-		if !ok {
+		// In HA mode, the task may be running on the leader replica, which cancels it once the task is no longer running in the database.
+		if cancel, ok := s.stateCfg.RunningTasksCancel.Load(task.ID); ok {
+			cancel.(context.CancelFunc)()
+		} else if !s.profile.HA {
 			return errors.New("failed to cancel task")
 		}
-		cancel()
 		result, err := json.Marshal(api.TaskRunResultPayload{
 			Detail: "Task cancellation requested.",
 		})
@@ -897,6 +1062,25 @@ func isTaskStatusTransitionAllowed(fromStatus, toStatus api.TaskStatus) bool {
 	return false
 }
 
+// isResumableExecution returns true if the task opts into resumable execution.
+func isResumableExecution(task *store.TaskMessage) (bool, error) {
+	switch task.Type {
+	case api.TaskDatabaseSchemaUpdate:
+		payload := &api.TaskDatabaseSchemaUpdatePayload{}
+		if err := json.Unmarshal([]byte(task.Payload), payload); err != nil {
+			return false, errors.Wrapf(err, "invalid database schema update payload")
+		}
+		return payload.ResumableExecution, nil
+	case api.TaskDatabaseDataUpdate:
+		payload := &api.TaskDatabaseDataUpdatePayload{}
+		if err := json.Unmarshal([]byte(task.Payload), payload); err != nil {
+			return false, errors.Wrapf(err, "invalid database data update payload")
+		}
+		return payload.ResumableExecution, nil
+	}
+	return false, nil
+}
+
 func (s *Scheduler) createTaskStatusUpdateActivity(ctx context.Context, task *store.TaskMessage, taskStatusPatch *api.TaskStatusPatch, issue *store.IssueMessage) error {
 	var issueName string
 	if issue != nil {
@@ -969,7 +1153,7 @@ func (s *Scheduler) GetDefaultAssignee(ctx context.Context, environmentID int, p
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to GetPipelineApprovalPolicy for environmentID %d", environmentID)
 	}
-	if policy.Value == api.PipelineApprovalValueManualNever {
+	if policy.Value == api.PipelineApprovalValueManualNever && issueType != api.IssueDatabaseAccessRequest {
 		// use SystemBot for auto approval tasks.
 		systemBot, err := s.store.GetUserByID(ctx, api.SystemBotID)
 		if err != nil {
@@ -1082,6 +1266,30 @@ func (s *Scheduler) CanPrincipalBeAssignee(ctx context.Context, principalID int,
 			}
 		}
 	}
+	return s.canPrincipalApproveByCustomRole(ctx, principalID, projectID)
+}
+
+// canPrincipalApproveByCustomRole returns whether the principal is granted the approve permission by the custom roles in the workspace or the project.
+func (s *Scheduler) canPrincipalApproveByCustomRole(ctx context.Context, principalID int, projectID int) (bool, error) {
+	workspacePermissions, err := s.store.GetWorkspacePermissions(ctx, principalID)
+	if err != nil {
+		return false, common.Wrapf(err, common.Internal, "failed to get workspace permissions of principal %d", principalID)
+	}
+	if workspacePermissions[string(api.WorkspacePermissionApproveIssue)] {
+		return true, nil
+	}
+	policy, err := s.store.GetProjectPolicy(ctx, &store.GetProjectPolicyMessage{UID: &projectID})
+	if err != nil {
+		return false, common.Wrapf(err, common.Internal, "failed to get project %d policy", projectID)
+	}
+	for _, binding := range policy.Bindings {
+		for _, member := range binding.Members {
+			if member.ID != principalID {
+				continue
+			}
+			return s.store.HasProjectPermission(ctx, s.licenseService.GetEffectivePlan(), binding.Role, api.ProjectPermissionApproveIssue)
+		}
+	}
 	return false, nil
 }
 
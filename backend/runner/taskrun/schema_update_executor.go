@@ -71,7 +71,7 @@ func (exec *SchemaUpdateExecutor) RunOnce(ctx context.Context, task *store.TaskM
 		statement = sheet.Statement
 	}
 
-	terminated, result, err := runMigration(ctx, exec.store, exec.dbFactory, exec.activityManager, exec.license, exec.stateCfg, exec.profile, task, db.Migrate, statement, payload.SchemaVersion, payload.VCSPushEvent)
+	terminated, result, err := runMigration(ctx, exec.store, exec.dbFactory, exec.activityManager, exec.license, exec.stateCfg, exec.profile, task, db.Migrate, statement, payload.SchemaVersion, payload.VCSPushEvent, payload.ResumableExecution, payload.Resume)
 	if err := exec.schemaSyncer.SyncDatabaseSchema(ctx, database, true /* force */); err != nil {
 		log.Error("failed to sync database schema",
 			zap.String("instanceName", instance.ResourceID),
@@ -79,6 +79,15 @@ func (exec *SchemaUpdateExecutor) RunOnce(ctx context.Context, task *store.TaskM
 			zap.Error(err),
 		)
 	}
+	if err == nil && result != nil && result.MigrationID != "" {
+		if err := generateDownMigration(ctx, exec.store, exec.dbFactory, exec.activityManager, task.ID, instance, database, result.MigrationID); err != nil {
+			log.Error("failed to generate the down migration",
+				zap.Int("task_id", task.ID),
+				zap.String("migration_id", result.MigrationID),
+				zap.Error(err),
+			)
+		}
+	}
 
 	return terminated, result, err
 }
@@ -133,7 +133,7 @@ func cutover(ctx context.Context, stores *store.Store, dbFactory *dbfactory.DBFa
 			return "", "", common.Errorf(common.MigrationSchemaMissing, "missing migration schema for instance %q", instance.ResourceID)
 		}
 
-		executor := driver.(util.MigrationExecutor)
+		executor := dbfactory.Unwrap(driver).(util.MigrationExecutor)
 
 		var prevSchemaBuf bytes.Buffer
 		if _, err := driver.Dump(ctx, mi.Database, &prevSchemaBuf, true); err != nil {
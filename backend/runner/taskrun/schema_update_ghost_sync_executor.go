@@ -14,6 +14,7 @@ import (
 
 	"github.com/bytebase/bytebase/backend/common"
 	"github.com/bytebase/bytebase/backend/common/log"
+	"github.com/bytebase/bytebase/backend/component/dbfactory"
 	"github.com/bytebase/bytebase/backend/component/state"
 	api "github.com/bytebase/bytebase/backend/legacyapi"
 	"github.com/bytebase/bytebase/backend/store"
@@ -21,19 +22,19 @@ import (
 )
 
 // NewSchemaUpdateGhostSyncExecutor creates a schema update (gh-ost) sync task executor.
-func NewSchemaUpdateGhostSyncExecutor(store *store.Store, stateCfg *state.State, secret string) Executor {
+func NewSchemaUpdateGhostSyncExecutor(store *store.Store, dbFactory *dbfactory.DBFactory, stateCfg *state.State) Executor {
 	return &SchemaUpdateGhostSyncExecutor{
-		store:    store,
-		stateCfg: stateCfg,
-		secret:   secret,
+		store:     store,
+		dbFactory: dbFactory,
+		stateCfg:  stateCfg,
 	}
 }
 
 // SchemaUpdateGhostSyncExecutor is the schema update (gh-ost) sync task executor.
 type SchemaUpdateGhostSyncExecutor struct {
-	store    *store.Store
-	stateCfg *state.State
-	secret   string
+	store     *store.Store
+	dbFactory *dbfactory.DBFactory
+	stateCfg  *state.State
 }
 
 // RunOnce will run SchemaUpdateGhostSync task once.
@@ -88,10 +89,11 @@ func (exec *SchemaUpdateGhostSyncExecutor) runGhostMigration(ctx context.Context
 		return true, nil, common.Errorf(common.Internal, "failed to find instance user by instanceID %d", task.InstanceID)
 	}
 
-	config, err := utils.GetGhostConfig(task.ID, database, adminDataSource, exec.secret, instanceUsers, tableName, statement, false, 10000000)
+	username, password, err := exec.dbFactory.GetDataSourcePassword(ctx, adminDataSource)
 	if err != nil {
 		return true, nil, err
 	}
+	config := utils.GetGhostConfig(task.ID, database, adminDataSource, username, password, instanceUsers, tableName, statement, false, 10000000)
 
 	migrationContext, err := utils.NewMigrationContext(config)
 	if err != nil {
@@ -114,7 +116,7 @@ func (exec *SchemaUpdateGhostSyncExecutor) runGhostMigration(ctx context.Context
 					completedUnit = migrationContext.GetTotalRowsCopied()
 					updatedTs     = time.Now().Unix()
 				)
-				exec.stateCfg.TaskProgress.Store(task.ID, api.Progress{
+				updateTaskRunProgress(childCtx, exec.store, task.ID, &api.Progress{
 					TotalUnit:     totalUnit,
 					CompletedUnit: completedUnit,
 					CreatedTs:     createdTs,
@@ -0,0 +1,66 @@
+package taskrun
+
+import (
+	"context"
+
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+// stageStatusCache caches the stage checks of the pending tasks within a round of scheduling.
+// The checks only depend on the stage of the task, so they are done once per stage rather than once per task,
+// which matters for the tenant deployments with thousands of tasks in a stage.
+type stageStatusCache struct {
+	s *Scheduler
+
+	// verificationBlocked is the mapping from stage ID to whether the verification of the previous stages blocks the stage.
+	verificationBlocked map[int]bool
+	// gateClosed is the mapping from stage ID to whether the promotion gate of the stage is closed.
+	gateClosed map[int]bool
+	// rolloutHeld is the mapping from stage ID to whether the rollout holds the stage.
+	rolloutHeld map[int]bool
+}
+
+func (s *Scheduler) newStageStatusCache() *stageStatusCache {
+	return &stageStatusCache{
+		s:                   s,
+		verificationBlocked: make(map[int]bool),
+		gateClosed:          make(map[int]bool),
+		rolloutHeld:         make(map[int]bool),
+	}
+}
+
+func (c *stageStatusCache) isBlockedByVerification(ctx context.Context, task *store.TaskMessage) (bool, error) {
+	if blocked, ok := c.verificationBlocked[task.StageID]; ok {
+		return blocked, nil
+	}
+	blocked, err := c.s.isBlockedByVerification(ctx, task)
+	if err != nil {
+		return false, err
+	}
+	c.verificationBlocked[task.StageID] = blocked
+	return blocked, nil
+}
+
+func (c *stageStatusCache) isPromotionGateClosed(ctx context.Context, task *store.TaskMessage) (bool, error) {
+	if closed, ok := c.gateClosed[task.StageID]; ok {
+		return closed, nil
+	}
+	closed, err := c.s.isPromotionGateClosed(ctx, task)
+	if err != nil {
+		return false, err
+	}
+	c.gateClosed[task.StageID] = closed
+	return closed, nil
+}
+
+func (c *stageStatusCache) isRolloutHeld(ctx context.Context, task *store.TaskMessage) (bool, error) {
+	if held, ok := c.rolloutHeld[task.StageID]; ok {
+		return held, nil
+	}
+	held, err := c.s.isRolloutHeld(ctx, task)
+	if err != nil {
+		return false, err
+	}
+	c.rolloutHeld[task.StageID] = held
+	return held, nil
+}
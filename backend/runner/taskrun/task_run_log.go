@@ -0,0 +1,41 @@
+package taskrun
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/backend/common/log"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/db"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+// newTaskRunLogger returns the execute logger persisting the execute logs to the running task run of the task.
+// It returns nil if the task has no running task run.
+func (s *Scheduler) newTaskRunLogger(ctx context.Context, task *store.TaskMessage) (db.ExecuteLogger, error) {
+	taskRuns, err := s.store.ListTaskRunsV2(ctx, &store.TaskRunFind{
+		TaskID:     &task.ID,
+		StatusList: &[]api.TaskRunStatus{api.TaskRunRunning},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(taskRuns) == 0 {
+		return nil, nil
+	}
+	taskRunID := taskRuns[0].ID
+	return func(executeLog *db.ExecuteLog) {
+		// Use the scheduler context instead of the executor context so that the logs are still persisted after the task is canceled.
+		if err := s.store.CreateTaskRunLog(ctx, &store.TaskRunLogMessage{
+			TaskRunID: taskRunID,
+			Payload:   executeLog,
+		}); err != nil {
+			log.Warn("Failed to create task run log",
+				zap.Int("task_id", task.ID),
+				zap.Int("task_run_id", taskRunID),
+				zap.Error(err),
+			)
+		}
+	}, nil
+}
@@ -0,0 +1,83 @@
+package taskrun
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/store"
+	"github.com/bytebase/bytebase/backend/utils"
+)
+
+// scheduleVerification schedules the post-deployment verification task check of the done task if it has one.
+func (s *Scheduler) scheduleVerification(ctx context.Context, task *store.TaskMessage) error {
+	doneTask, err := s.store.GetTaskV2ByID(ctx, task.ID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get task %d", task.ID)
+	}
+	if doneTask == nil {
+		return errors.Errorf("task %d not found", task.ID)
+	}
+	create, err := utils.GetVerificationTaskCheckRunCreate(doneTask, api.SystemBotID)
+	if err != nil {
+		return err
+	}
+	if create == nil {
+		return nil
+	}
+	return s.store.CreateTaskCheckRunIfNeeded(ctx, create)
+}
+
+// isBlockedByVerification returns whether the task is blocked from the promotion,
+// because the post-deployment verification of any task in the previous stages isn't finished or fails.
+func (s *Scheduler) isBlockedByVerification(ctx context.Context, task *store.TaskMessage) (bool, error) {
+	stages, err := s.store.ListStageV2(ctx, task.PipelineID)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to list stages of pipeline %d", task.PipelineID)
+	}
+	verificationType := api.TaskCheckDatabaseVerification
+	for _, stage := range stages {
+		if stage.ID == task.StageID {
+			break
+		}
+		stageTasks, err := s.store.ListTasks(ctx, &api.TaskFind{PipelineID: &task.PipelineID, StageID: &stage.ID})
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to list tasks of stage %d", stage.ID)
+		}
+		taskCheckRuns, err := s.store.FindTaskCheckRun(ctx, &store.TaskCheckRunFind{
+			PipelineID: &task.PipelineID,
+			StageID:    &stage.ID,
+			Type:       &verificationType,
+		})
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to list verification task check runs of stage %d", stage.ID)
+		}
+		taskCheckRunMap := make(map[int][]*api.TaskCheckRun)
+		for _, run := range taskCheckRuns {
+			taskCheckRunMap[run.TaskID] = append(taskCheckRunMap[run.TaskID], run)
+		}
+		for _, stageTask := range stageTasks {
+			var payload struct {
+				Skipped      bool                  `json:"skipped,omitempty"`
+				Verification *api.TaskVerification `json:"verification,omitempty"`
+			}
+			if err := json.Unmarshal([]byte(stageTask.Payload), &payload); err != nil {
+				return false, errors.Wrapf(err, "invalid payload of task %d", stageTask.ID)
+			}
+			if payload.Skipped || payload.Verification == nil {
+				continue
+			}
+			// The verification which is pending, running or not created yet blocks the task as well.
+			status, found, err := api.GetVerificationStatus(taskCheckRunMap[stageTask.ID])
+			if err != nil {
+				return false, err
+			}
+			if !found || status == api.TaskCheckStatusError {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,86 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/plugin/db"
+	"github.com/bytebase/bytebase/backend/plugin/parser"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+// maxAccessRequestDurationSeconds is the max duration of the temporary database access, which is 30 days.
+const maxAccessRequestDurationSeconds = 30 * 24 * 60 * 60
+
+// isTableAccessGrantSupported returns whether the access grants can be limited to tables for the engine,
+// which requires extracting the accessed tables from the statements.
+func isTableAccessGrantSupported(engine db.Type) bool {
+	return engine == db.MySQL || engine == db.TiDB || engine == db.Postgres
+}
+
+// getQueryAccessGrant returns the active access grant which allows the principal to run the statement on the database.
+// It returns nil if there is none.
+func (s *Server) getQueryAccessGrant(ctx context.Context, principalID int, database *store.DatabaseMessage, engine db.Type, statement string) (*store.AccessGrantMessage, error) {
+	now := time.Now().Unix()
+	grants, err := s.store.ListAccessGrants(ctx, &store.FindAccessGrantMessage{
+		PrincipalID:  &principalID,
+		DatabaseID:   &database.UID,
+		ExpiresAfter: &now,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var tableList []string
+	tableListExtracted := false
+	for _, grant := range grants {
+		if len(grant.Tables) == 0 {
+			return grant, nil
+		}
+		if !isTableAccessGrantSupported(engine) {
+			continue
+		}
+		if !tableListExtracted {
+			tableList, err = parser.ExtractTableList(parser.EngineType(engine), statement)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to extract tables from statement %q", statement)
+			}
+			tableListExtracted = true
+		}
+		if isTableListGranted(engine, database.DatabaseName, grant.Tables, tableList) {
+			return grant, nil
+		}
+	}
+	return nil, nil
+}
+
+// isTableListGranted returns whether all the tables accessed by the statement are in the granted tables.
+func isTableListGranted(engine db.Type, databaseName string, grantedTables []string, tableList []string) bool {
+	granted := make(map[string]bool)
+	for _, table := range grantedTables {
+		granted[normalizeGrantedTableName(engine, databaseName, table)] = true
+	}
+	for _, table := range tableList {
+		if !granted[normalizeGrantedTableName(engine, databaseName, table)] {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeGrantedTableName normalizes the table name for comparison,
+// the MySQL tables are qualified by the database name and the PostgreSQL tables are qualified by the schema name.
+func normalizeGrantedTableName(engine db.Type, databaseName string, table string) string {
+	if strings.Contains(table, ".") {
+		return table
+	}
+	switch engine {
+	case db.Postgres:
+		return "public." + table
+	default:
+		return databaseName + "." + table
+	}
+}
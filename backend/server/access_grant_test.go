@@ -0,0 +1,60 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/backend/plugin/db"
+)
+
+func TestIsTableListGranted(t *testing.T) {
+	tests := []struct {
+		engine        db.Type
+		grantedTables []string
+		tableList     []string
+		want          bool
+	}{
+		{
+			engine:        db.MySQL,
+			grantedTables: []string{"orders"},
+			tableList:     []string{"orders", "db1.orders"},
+			want:          true,
+		},
+		{
+			engine:        db.MySQL,
+			grantedTables: []string{"orders"},
+			tableList:     []string{"orders", "users"},
+			want:          false,
+		},
+		{
+			engine:        db.MySQL,
+			grantedTables: []string{"orders"},
+			tableList:     []string{"db2.orders"},
+			want:          false,
+		},
+		{
+			engine:        db.Postgres,
+			grantedTables: []string{"orders", "s1.users"},
+			tableList:     []string{"public.orders", "s1.users"},
+			want:          true,
+		},
+		{
+			engine:        db.Postgres,
+			grantedTables: []string{"orders"},
+			tableList:     []string{"s1.orders"},
+			want:          false,
+		},
+		{
+			engine:        db.Postgres,
+			grantedTables: []string{"orders"},
+			tableList:     nil,
+			want:          true,
+		},
+	}
+
+	for _, test := range tests {
+		got := isTableListGranted(test.engine, "db1", test.grantedTables, test.tableList)
+		require.Equal(t, test.want, got, "granted %v, tables %v", test.grantedTables, test.tableList)
+	}
+}
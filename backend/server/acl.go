@@ -31,7 +31,7 @@ var projectGeneralRouteRegex = regexp.MustCompile(`^/project/(?P<projectID>\d+)`
 var projectMemberRouteRegex = regexp.MustCompile(`^/project/(?P<projectID>\d+)/member`)
 var projectSyncSheetRouteRegex = regexp.MustCompile(`^/project/(?P<projectID>\d+)/sync-sheet`)
 
-func enforceWorkspaceDeveloperProjectRouteACL(plan api.PlanType, path string, method string, quaryParams url.Values, principalID int, roleFinder func(projectID int, principalID int) (common.ProjectRole, error)) *echo.HTTPError {
+func enforceWorkspaceDeveloperProjectRouteACL(plan api.PlanType, path string, method string, quaryParams url.Values, principalID int, roleFinder func(projectID int, principalID int) (common.ProjectRole, error), customRoleFinder func(role common.ProjectRole) (*store.RoleMessage, error)) *echo.HTTPError {
 	var projectID int
 	var permission api.ProjectPermissionType
 	var permissionErrMsg string
@@ -72,7 +72,11 @@ func enforceWorkspaceDeveloperProjectRouteACL(plan api.PlanType, path string, me
 			return echo.NewHTTPError(http.StatusUnauthorized, "is not a member of the project")
 		}
 
-		if !api.ProjectPermission(permission, plan, role) {
+		granted, err := hasProjectPermission(plan, role, permission, customRoleFinder)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process authorize request.").SetInternal(err)
+		}
+		if !granted {
 			return echo.NewHTTPError(http.StatusUnauthorized, permissionErrMsg)
 		}
 	}
@@ -83,7 +87,7 @@ func enforceWorkspaceDeveloperProjectRouteACL(plan api.PlanType, path string, me
 var sheetRouteRegex = regexp.MustCompile(`^/sheet/(?P<sheetID>\d+)`)
 var sheetOrganizeRouteRegex = regexp.MustCompile(`^/sheet/(?P<projectID>\d+)/organize`)
 
-func enforceWorkspaceDeveloperSheetRouteACL(plan api.PlanType, path string, method string, principalID int, roleFinder func(projectID int, principalID int) (common.ProjectRole, error), sheetFinder func(sheetID int) (*api.Sheet, error)) *echo.HTTPError {
+func enforceWorkspaceDeveloperSheetRouteACL(plan api.PlanType, path string, method string, principalID int, roleFinder func(projectID int, principalID int) (common.ProjectRole, error), customRoleFinder func(role common.ProjectRole) (*store.RoleMessage, error), sheetFinder func(sheetID int) (*api.Sheet, error)) *echo.HTTPError {
 	if matches := sheetOrganizeRouteRegex.FindStringSubmatch(path); matches != nil {
 		sheetID, _ := strconv.Atoi(matches[1])
 		sheet, err := sheetFinder(sheetID)
@@ -112,7 +116,11 @@ func enforceWorkspaceDeveloperSheetRouteACL(plan api.PlanType, path string, meth
 				return echo.NewHTTPError(http.StatusUnauthorized, "is not a member of the project containing the sheet")
 			}
 
-			if !api.ProjectPermission(api.ProjectPermissionOrganizeSheet, plan, role) {
+			granted, err := hasProjectPermission(plan, role, api.ProjectPermissionOrganizeSheet, customRoleFinder)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process authorize request.").SetInternal(err)
+			}
+			if !granted {
 				return echo.NewHTTPError(http.StatusUnauthorized, "not have permission to organize the project sheet")
 			}
 		}
@@ -151,7 +159,11 @@ func enforceWorkspaceDeveloperSheetRouteACL(plan api.PlanType, path string, meth
 				return nil
 			}
 
-			if !api.ProjectPermission(api.ProjectPermissionAdminSheet, plan, role) {
+			granted, err := hasProjectPermission(plan, role, api.ProjectPermissionAdminSheet, customRoleFinder)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process authorize request.").SetInternal(err)
+			}
+			if !granted {
 				return echo.NewHTTPError(http.StatusUnauthorized, "not have permission to change the project sheet")
 			}
 		}
@@ -160,6 +172,45 @@ func enforceWorkspaceDeveloperSheetRouteACL(plan api.PlanType, path string, meth
 	return nil
 }
 
+var anomalySchemaDriftReportRouteRegex = regexp.MustCompile(`^/anomaly/(?P<anomalyID>\d+)/schema-drift-report`)
+
+// enforceWorkspaceDeveloperAnomalyRouteACL only allows the project members to access the anomalies of the databases in the project.
+// anomalyProjectFinder returns 0 if the anomaly doesn't belong to a database, and the route handler rejects such anomalies.
+func enforceWorkspaceDeveloperAnomalyRouteACL(path string, principalID int, roleFinder func(projectID int, principalID int) (common.ProjectRole, error), anomalyProjectFinder func(anomalyID int) (int, error)) *echo.HTTPError {
+	if matches := anomalySchemaDriftReportRouteRegex.FindStringSubmatch(path); matches != nil {
+		anomalyID, _ := strconv.Atoi(matches[1])
+		projectID, err := anomalyProjectFinder(anomalyID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process authorize request.").SetInternal(err)
+		}
+		if projectID == 0 {
+			return nil
+		}
+		role, err := roleFinder(projectID, principalID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process authorize request.").SetInternal(err)
+		}
+		if role == "" {
+			return echo.NewHTTPError(http.StatusUnauthorized, "is not a member of the project containing the anomaly")
+		}
+	}
+
+	return nil
+}
+
+// hasProjectPermission returns whether the project role is granted the permission.
+// Besides the built-in OWNER and DEVELOPER, the project role can be a custom role which is granted the permissions it's made of.
+func hasProjectPermission(plan api.PlanType, role common.ProjectRole, permission api.ProjectPermissionType, customRoleFinder func(role common.ProjectRole) (*store.RoleMessage, error)) (bool, error) {
+	if role == common.ProjectOwner || role == common.ProjectDeveloper {
+		return api.ProjectPermission(permission, plan, role), nil
+	}
+	customRole, err := customRoleFinder(role)
+	if err != nil {
+		return false, err
+	}
+	return customRole != nil && customRole.HasPermission(string(permission)), nil
+}
+
 func aclMiddleware(s *Server, pathPrefix string, ce *casbin.Enforcer, next echo.HandlerFunc, readonly bool) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		ctx := c.Request().Context()
@@ -236,6 +287,23 @@ func aclMiddleware(s *Server, pathPrefix string, ce *casbin.Enforcer, next echo.
 			}
 		}
 
+		if !pass {
+			// The custom roles bound in the workspace IAM policy grant the routes of their permissions in addition to the workspace role.
+			permissions, err := s.store.GetWorkspacePermissions(ctx, principalID)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process authorize request.").SetInternal(err)
+			}
+			for permission := range permissions {
+				pass, err = ce.Enforce(permission, path, c.Request().Method)
+				if err != nil {
+					return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process authorize request.").SetInternal(err)
+				}
+				if pass {
+					break
+				}
+			}
+		}
+
 		if !pass {
 			return echo.NewHTTPError(http.StatusUnauthorized).SetInternal(
 				errors.Errorf("rejected by the ACL policy; %s %s u%d/%s", method, path, principalID, role))
@@ -260,6 +328,10 @@ func aclMiddleware(s *Server, pathPrefix string, ce *casbin.Enforcer, next echo.
 				return "", nil
 			}
 
+			customRoleFinder := func(role common.ProjectRole) (*store.RoleMessage, error) {
+				return s.store.GetRole(ctx, string(role))
+			}
+
 			sheetFinder := func(sheetID int) (*api.Sheet, error) {
 				sheetFind := &api.SheetFind{
 					ID: &sheetID,
@@ -267,10 +339,23 @@ func aclMiddleware(s *Server, pathPrefix string, ce *casbin.Enforcer, next echo.
 				return s.store.GetSheet(ctx, sheetFind, principalID)
 			}
 
+			anomalyProjectFinder := func(anomalyID int) (int, error) {
+				anomalyList, err := s.store.FindAnomaly(ctx, &api.AnomalyFind{ID: &anomalyID})
+				if err != nil {
+					return 0, err
+				}
+				if len(anomalyList) == 0 || anomalyList[0].Database == nil {
+					return 0, nil
+				}
+				return anomalyList[0].Database.ProjectID, nil
+			}
+
 			if strings.HasPrefix(path, "/project") {
-				aclErr = enforceWorkspaceDeveloperProjectRouteACL(s.licenseService.GetEffectivePlan(), path, method, c.QueryParams(), principalID, roleFinder)
+				aclErr = enforceWorkspaceDeveloperProjectRouteACL(s.licenseService.GetEffectivePlan(), path, method, c.QueryParams(), principalID, roleFinder, customRoleFinder)
 			} else if strings.HasPrefix(path, "/sheet") {
-				aclErr = enforceWorkspaceDeveloperSheetRouteACL(s.licenseService.GetEffectivePlan(), path, method, principalID, roleFinder, sheetFinder)
+				aclErr = enforceWorkspaceDeveloperSheetRouteACL(s.licenseService.GetEffectivePlan(), path, method, principalID, roleFinder, customRoleFinder, sheetFinder)
+			} else if strings.HasPrefix(path, "/anomaly") {
+				aclErr = enforceWorkspaceDeveloperAnomalyRouteACL(path, principalID, roleFinder, anomalyProjectFinder)
 			}
 
 			if aclErr != nil {
@@ -0,0 +1,56 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/bytebase/bytebase/backend/common"
+)
+
+func TestEnforceWorkspaceDeveloperAnomalyRouteACL(t *testing.T) {
+	tests := []struct {
+		desc        string
+		path        string
+		principalID int
+		errMsg      string
+	}{
+		{
+			desc:        "List anomalies",
+			path:        "/anomaly",
+			principalID: testFindPrincipalIDFromProject(101, common.ProjectOwner),
+			errMsg:      "",
+		},
+		{
+			desc:        "Get the schema drift report as the project developer",
+			path:        "/anomaly/2000/schema-drift-report",
+			principalID: testFindPrincipalIDFromProject(100, common.ProjectDeveloper),
+			errMsg:      "",
+		},
+		{
+			desc:        "Get the schema drift report as the non-member",
+			path:        "/anomaly/2000/schema-drift-report",
+			principalID: testFindPrincipalIDFromProject(101, common.ProjectOwner),
+			errMsg:      "is not a member of the project containing the anomaly",
+		},
+		{
+			desc:        "Get the schema drift report of the anomaly without database",
+			path:        "/anomaly/2001/schema-drift-report",
+			principalID: testFindPrincipalIDFromProject(101, common.ProjectOwner),
+			errMsg:      "",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := enforceWorkspaceDeveloperAnomalyRouteACL(tc.path, tc.principalID, roleFinder, anomalyProjectFinder)
+			if err != nil {
+				if tc.errMsg == "" {
+					t.Errorf("expect no error, got %s", err.Message)
+				} else if tc.errMsg != err.Message {
+					t.Errorf("expect error %s, got %s", tc.errMsg, err.Message)
+				}
+			} else if tc.errMsg != "" {
+				t.Errorf("expect error %s, got no error", tc.errMsg)
+			}
+		})
+	}
+}
@@ -271,11 +271,27 @@ func TestEnforceWorkspaceDeveloperProjectRouteACL(t *testing.T) {
 			principalID: testFindPrincipalIDFromProject(100, ""),
 			errMsg:      "is not a member of the project",
 		},
+		{
+			desc:        "Patch a single project as a custom role member granted the permission",
+			plan:        api.ENTERPRISE,
+			path:        "/project/100",
+			method:      "PATCH",
+			principalID: testFindPrincipalIDFromProject(100, testProjectMaintainerRole),
+			errMsg:      "",
+		},
+		{
+			desc:        "Create member from a single project as a custom role member not granted the permission",
+			plan:        api.ENTERPRISE,
+			path:        "/project/100/member",
+			method:      "POST",
+			principalID: testFindPrincipalIDFromProject(100, testProjectMaintainerRole),
+			errMsg:      "not have permission to manage the project member",
+		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.desc, func(t *testing.T) {
-			err := enforceWorkspaceDeveloperProjectRouteACL(tc.plan, tc.path, tc.method, tc.queryParams, tc.principalID, roleFinder)
+			err := enforceWorkspaceDeveloperProjectRouteACL(tc.plan, tc.path, tc.method, tc.queryParams, tc.principalID, roleFinder, customRoleFinder)
 			if err != nil {
 				if tc.errMsg == "" {
 					t.Errorf("expect no error, got %s", err.Message)
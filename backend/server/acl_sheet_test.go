@@ -215,6 +215,22 @@ func TestEnforceWorkspaceDeveloperSheetRouteACL(t *testing.T) {
 			principalID: testFindPrincipalIDFromSheet(1001, "DEVELOPER"),
 			errMsg:      "not have permission to change the project sheet",
 		},
+		{
+			desc:        "Change project sheet as a custom role member granted the permission",
+			plan:        api.ENTERPRISE,
+			path:        "/sheet/1001",
+			method:      "PATCH",
+			principalID: testFindPrincipalIDFromSheet(1001, "project-maintainer"),
+			errMsg:      "",
+		},
+		{
+			desc:        "Organize project sheet as a custom role member not granted the permission",
+			plan:        api.ENTERPRISE,
+			path:        "/sheet/1001/organize",
+			method:      "PATCH",
+			principalID: testFindPrincipalIDFromSheet(1001, "project-maintainer"),
+			errMsg:      "not have permission to organize the project sheet",
+		},
 		{
 			desc:        "Change project sheet as a non-member",
 			plan:        api.ENTERPRISE,
@@ -314,7 +330,7 @@ func TestEnforceWorkspaceDeveloperSheetRouteACL(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.desc, func(t *testing.T) {
-			err := enforceWorkspaceDeveloperSheetRouteACL(tc.plan, tc.path, tc.method, tc.principalID, roleFinder, sheetFinder)
+			err := enforceWorkspaceDeveloperSheetRouteACL(tc.plan, tc.path, tc.method, tc.principalID, roleFinder, customRoleFinder, sheetFinder)
 			if err != nil {
 				if tc.errMsg == "" {
 					t.Errorf("expect no error, got %s", err.Message)
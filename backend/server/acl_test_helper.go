@@ -3,13 +3,18 @@ package server
 import (
 	"github.com/bytebase/bytebase/backend/common"
 	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/store"
 )
 
+// testProjectMaintainerRole is a custom project role which can manage the project general settings and the sheets but not the members.
+const testProjectMaintainerRole common.ProjectRole = "project-maintainer"
+
 // map from project ID to the map of <role, principal ID>.
 var testProjectMemberMap = map[int]map[common.ProjectRole]int{
 	100: {
-		common.ProjectOwner:     200,
-		common.ProjectDeveloper: 201,
+		common.ProjectOwner:       200,
+		common.ProjectDeveloper:   201,
+		testProjectMaintainerRole: 203,
 	},
 	101: {
 		common.ProjectOwner: 202,
@@ -27,22 +32,34 @@ var testSheetProjectMap = map[int]int{
 // string can be one of the project roles or "CREATOR" which represents the sheet creator.
 var testSheetMemberMap = map[int]map[string]int{
 	1000: {
-		"OWNER":     200,
-		"DEVELOPER": 201,
-		"CREATOR":   202,
+		"OWNER":              200,
+		"DEVELOPER":          201,
+		"CREATOR":            202,
+		"project-maintainer": 203,
 	},
 	1001: {
-		"OWNER":     200,
-		"DEVELOPER": 201,
-		"CREATOR":   202,
+		"OWNER":              200,
+		"DEVELOPER":          201,
+		"CREATOR":            202,
+		"project-maintainer": 203,
 	},
 	1002: {
-		"OWNER":     200,
-		"DEVELOPER": 201,
-		"CREATOR":   202,
+		"OWNER":              200,
+		"DEVELOPER":          201,
+		"CREATOR":            202,
+		"project-maintainer": 203,
 	},
 }
 
+// map from anomaly ID to the project ID of its database, the anomalies not in the map don't belong to a database.
+var testAnomalyProjectMap = map[int]int{
+	2000: 100,
+}
+
+var anomalyProjectFinder = func(anomalyID int) (int, error) {
+	return testAnomalyProjectMap[anomalyID], nil
+}
+
 func testFindPrincipalIDFromProject(projectID int, role common.ProjectRole) int {
 	m, ok := testProjectMemberMap[projectID]
 	if !ok {
@@ -83,6 +100,20 @@ var roleFinder = func(projectID int, principalID int) (common.ProjectRole, error
 	return "", nil
 }
 
+var customRoleFinder = func(role common.ProjectRole) (*store.RoleMessage, error) {
+	if role != testProjectMaintainerRole {
+		return nil, nil
+	}
+	return &store.RoleMessage{
+		ResourceID: string(testProjectMaintainerRole),
+		Name:       "Project Maintainer",
+		Permissions: []string{
+			string(api.ProjectPermissionManageGeneral),
+			string(api.ProjectPermissionAdminSheet),
+		},
+	}, nil
+}
+
 var sheetFinder = func(sheetID int) (*api.Sheet, error) {
 	switch sheetID {
 	case 1000:
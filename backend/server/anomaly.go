@@ -1,14 +1,20 @@
 package server
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/google/jsonapi"
 	"github.com/labstack/echo/v4"
 
 	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/db"
+	"github.com/bytebase/bytebase/backend/plugin/parser"
+	"github.com/bytebase/bytebase/backend/plugin/parser/differ"
 )
 
 func (s *Server) registerAnomalyRoutes(g *echo.Group) {
@@ -51,4 +57,153 @@ func (s *Server) registerAnomalyRoutes(g *echo.Group) {
 		}
 		return nil
 	})
+	g.GET("/anomaly/:anomalyID/schema-drift-report", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("anomalyID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("anomalyID"))).SetInternal(err)
+		}
+
+		_, report, err := s.getSchemaDriftReport(ctx, id)
+		if err != nil {
+			return err
+		}
+		return c.JSON(http.StatusOK, report)
+	})
+
+	g.POST("/anomaly/:anomalyID/schema-drift-remediation", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("anomalyID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("anomalyID"))).SetInternal(err)
+		}
+
+		remediationCreate := &api.SchemaDriftRemediationCreate{}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, remediationCreate); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed create schema drift remediation request").SetInternal(err)
+		}
+
+		anomaly, report, err := s.getSchemaDriftReport(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		var issueName string
+		var detail *api.MigrationDetail
+		switch remediationCreate.Action {
+		case api.SchemaDriftRemediationBaseline:
+			issueName = fmt.Sprintf("[%s] Accept schema drift", anomaly.Database.Name)
+			detail = &api.MigrationDetail{
+				MigrationType: db.Baseline,
+				DatabaseID:    anomaly.Database.ID,
+			}
+		case api.SchemaDriftRemediationRevert:
+			if report.RevertStatement == "" {
+				return echo.NewHTTPError(http.StatusBadRequest, "No reverse DDL generated for the schema drift")
+			}
+			issueName = fmt.Sprintf("[%s] Revert schema drift", anomaly.Database.Name)
+			detail = &api.MigrationDetail{
+				MigrationType: db.Migrate,
+				DatabaseID:    anomaly.Database.ID,
+				Statement:     report.RevertStatement,
+			}
+		default:
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid schema drift remediation action %q", remediationCreate.Action))
+		}
+
+		createContext, err := json.Marshal(&api.MigrationContext{
+			DetailList: []*api.MigrationDetail{detail},
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal schema drift remediation context").SetInternal(err)
+		}
+		issueCreate := &api.IssueCreate{
+			ProjectID:             anomaly.Database.ProjectID,
+			Name:                  issueName,
+			Type:                  api.IssueDatabaseSchemaUpdate,
+			Description:           getSchemaDriftDescription(report),
+			AssigneeID:            api.SystemBotID,
+			AssigneeNeedAttention: true,
+			CreateContext:         string(createContext),
+		}
+		issue, err := s.createIssue(ctx, issueCreate, c.Get(getPrincipalIDContextKey()).(int))
+		if err != nil {
+			return err
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, issue); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal create schema drift remediation response").SetInternal(err)
+		}
+		return nil
+	})
+}
+
+// getSchemaDriftReport returns the active schema drift anomaly and its drift report.
+func (s *Server) getSchemaDriftReport(ctx context.Context, anomalyID int) (*api.Anomaly, *api.SchemaDriftReport, error) {
+	normalRowStatus := api.Normal
+	anomalyList, err := s.store.FindAnomaly(ctx, &api.AnomalyFind{
+		ID:        &anomalyID,
+		RowStatus: &normalRowStatus,
+	})
+	if err != nil {
+		return nil, nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch anomaly %d", anomalyID)).SetInternal(err)
+	}
+	if len(anomalyList) == 0 {
+		return nil, nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Active anomaly not found with ID %d", anomalyID))
+	}
+	anomaly := anomalyList[0]
+	if anomaly.Type != api.AnomalyDatabaseSchemaDrift || anomaly.Database == nil {
+		return nil, nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Anomaly %d is not a database schema drift", anomalyID))
+	}
+
+	var engineType parser.EngineType
+	switch anomaly.Instance.Engine {
+	case db.MySQL:
+		engineType = parser.MySQL
+	case db.TiDB:
+		engineType = parser.TiDB
+	case db.Postgres:
+		engineType = parser.Postgres
+	default:
+		return nil, nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Schema drift report is not supported for %s", anomaly.Instance.Engine))
+	}
+
+	payload := &api.AnomalyDatabaseSchemaDriftPayload{}
+	if err := json.Unmarshal([]byte(anomaly.Payload), payload); err != nil {
+		return nil, nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to unmarshal schema drift payload").SetInternal(err)
+	}
+	driftStatement, err := differ.SchemaDiff(engineType, payload.Expect, payload.Actual)
+	if err != nil {
+		return nil, nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to compute diff between expected and actual schemas").SetInternal(err)
+	}
+	revertStatement, err := differ.SchemaDiff(engineType, payload.Actual, payload.Expect)
+	if err != nil {
+		return nil, nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to compute diff between actual and expected schemas").SetInternal(err)
+	}
+	changeList, err := differ.SummarizeSchemaDiff(engineType, driftStatement)
+	if err != nil {
+		return nil, nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to summarize schema drift").SetInternal(err)
+	}
+
+	return anomaly, &api.SchemaDriftReport{
+		AnomalyID:       anomaly.ID,
+		Version:         payload.Version,
+		ChangeList:      changeList,
+		DriftStatement:  driftStatement,
+		RevertStatement: revertStatement,
+	}, nil
+}
+
+func getSchemaDriftDescription(report *api.SchemaDriftReport) string {
+	var lines []string
+	lines = append(lines, fmt.Sprintf("Schema drift detected against schema version %q.", report.Version))
+	for _, change := range report.ChangeList {
+		if change.ObjectName == "" {
+			lines = append(lines, fmt.Sprintf("- %s %s", change.Action, change.ObjectType))
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("- %s %s %s", change.Action, change.ObjectType, change.ObjectName))
+	}
+	return strings.Join(lines, "\n")
 }
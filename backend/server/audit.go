@@ -0,0 +1,103 @@
+package server
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/bytebase/bytebase/backend/component/audit"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+// maxAuditResponseSize is the max size of the response body captured for the audit log diff.
+const maxAuditResponseSize = 64 * 1024
+
+// auditResponseWriter captures the response body for the audit log diff.
+type auditResponseWriter struct {
+	http.ResponseWriter
+	body      bytes.Buffer
+	truncated bool
+}
+
+func (w *auditResponseWriter) Write(b []byte) (int, error) {
+	if !w.truncated {
+		if w.body.Len()+len(b) > maxAuditResponseSize {
+			w.truncated = true
+			w.body.Reset()
+		} else {
+			w.body.Write(b)
+		}
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// auditMiddleware records the mutating calls and the failed authentications of the echo routes in the audit log.
+// It must be the outermost middleware of the routes to see the failed authentications.
+// The call is recorded from its request and response after it has been handled by the JWT and ACL middlewares.
+func auditMiddleware(s *Server, next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		method := c.Request().Method
+		readOnly := method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+		if readOnly {
+			err := next(c)
+			if getHTTPStatus(c, err) == http.StatusUnauthorized {
+				s.recordAudit(c, err, nil, nil)
+			}
+			return err
+		}
+
+		var request []byte
+		if c.Request().Body != nil {
+			body, err := io.ReadAll(c.Request().Body)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "Failed to read request body").SetInternal(err)
+			}
+			request = body
+			c.Request().Body = io.NopCloser(bytes.NewReader(body))
+		}
+		writer := &auditResponseWriter{ResponseWriter: c.Response().Writer}
+		c.Response().Writer = writer
+
+		err := next(c)
+
+		var after []byte
+		if err == nil && method != http.MethodDelete && !writer.truncated {
+			after = writer.body.Bytes()
+		}
+		s.recordAudit(c, err, request, after)
+		return err
+	}
+}
+
+func (s *Server) recordAudit(c echo.Context, err error, request, after []byte) {
+	auditLog := &store.AuditLogMessage{
+		Method:    c.Request().Method + " " + c.Path(),
+		Resource:  c.Request().URL.Path,
+		Status:    strconv.Itoa(getHTTPStatus(c, err)),
+		Request:   audit.Redact(request),
+		Diff:      audit.Diff(nil, after),
+		ClientIP:  c.RealIP(),
+		UserAgent: c.Request().UserAgent(),
+	}
+	if principalID, ok := c.Get(getPrincipalIDContextKey()).(int); ok {
+		auditLog.ActorID = principalID
+	}
+	if err != nil {
+		auditLog.Error = err.Error()
+	}
+	s.auditManager.Record(c.Request().Context(), auditLog)
+}
+
+// getHTTPStatus returns the HTTP status of the handled request, the error response is not written yet at this point.
+func getHTTPStatus(c echo.Context, err error) int {
+	if err == nil {
+		return c.Response().Status
+	}
+	if he, ok := err.(*echo.HTTPError); ok {
+		return he.Code
+	}
+	return http.StatusInternalServerError
+}
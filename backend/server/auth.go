@@ -5,11 +5,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/google/jsonapi"
 	"github.com/labstack/echo/v4"
 	"golang.org/x/crypto/bcrypt"
 
+	"github.com/bytebase/bytebase/backend/api/auth"
 	"github.com/bytebase/bytebase/backend/common"
 	"github.com/bytebase/bytebase/backend/component/activity"
 	api "github.com/bytebase/bytebase/backend/legacyapi"
@@ -69,11 +71,36 @@ func (s *Server) registerAuthRoutes(g *echo.Group) {
 					return echo.NewHTTPError(http.StatusUnauthorized, fmt.Sprintf("User not found: %s", login.Email))
 				}
 
+				lockedUntil, err := auth.GetLoginLockout(ctx, s.store, user.ID)
+				if err != nil {
+					return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get login lockout").SetInternal(err)
+				}
+				if !lockedUntil.IsZero() {
+					return echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("Too many failed login attempts, please retry after %s", lockedUntil.UTC().Format(time.RFC3339)))
+				}
 				// Compare the stored hashed password, with the hashed version of the password that was received.
 				if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(login.Password)); err != nil {
+					if httpError := s.recordLoginFailure(ctx, user); httpError != nil {
+						return httpError
+					}
 					// If the two passwords don't match, return a 401 status.
 					return echo.NewHTTPError(http.StatusUnauthorized, "Incorrect password").SetInternal(err)
 				}
+				if auth.IsMFAEnabled(user) {
+					if login.OTPCode == "" && login.RecoveryCode == "" {
+						return echo.NewHTTPError(http.StatusUnauthorized, "OTP code or recovery code is required")
+					}
+					verified, err := auth.VerifyMFA(ctx, s.store, user, login.OTPCode, login.RecoveryCode)
+					if err != nil {
+						return echo.NewHTTPError(http.StatusInternalServerError, "Failed to verify MFA").SetInternal(err)
+					}
+					if !verified {
+						if httpError := s.recordLoginFailure(ctx, user); httpError != nil {
+							return httpError
+						}
+						return echo.NewHTTPError(http.StatusUnauthorized, "Invalid OTP code or recovery code")
+					}
+				}
 			}
 		case api.PrincipalAuthProviderGitlabSelfHost, api.PrincipalAuthProviderGitHubCom:
 			{
@@ -168,6 +195,13 @@ func (s *Server) registerAuthRoutes(g *echo.Group) {
 		if user.MemberDeleted {
 			return echo.NewHTTPError(http.StatusUnauthorized, "This user has been deactivated by the admin")
 		}
+		// The VCS login has no second factor, so the users with MFA enabled must sign in with the password.
+		if authProvider != api.PrincipalAuthProviderBytebase && auth.IsMFAEnabled(user) {
+			return echo.NewHTTPError(http.StatusUnauthorized, "MFA is enabled for this user, please sign in with email and password")
+		}
+		if err := s.store.ResetFailedLogins(ctx, user.ID); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to reset failed logins").SetInternal(err)
+		}
 
 		// If password is correct, generate tokens and set cookies.
 		if err := GenerateTokensAndSetCookies(c, user, s.profile.Mode, s.secret); err != nil {
@@ -231,3 +265,14 @@ func trySignUp(ctx context.Context, s *Server, signUp *api.SignUp, creatorID int
 
 	return user, nil
 }
+
+func (s *Server) recordLoginFailure(ctx context.Context, user *store.UserMessage) *echo.HTTPError {
+	locked, err := auth.RecordLoginFailure(ctx, s.store, user.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to record login failure").SetInternal(err)
+	}
+	if locked {
+		return echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("Too many failed login attempts, login is locked for %v", auth.LoginLockoutDuration))
+	}
+	return nil
+}
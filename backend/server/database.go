@@ -19,6 +19,7 @@ import (
 	"github.com/bytebase/bytebase/backend/plugin/db"
 	"github.com/bytebase/bytebase/backend/plugin/parser"
 	"github.com/bytebase/bytebase/backend/plugin/parser/edit"
+	"github.com/bytebase/bytebase/backend/plugin/secret"
 	"github.com/bytebase/bytebase/backend/store"
 )
 
@@ -502,6 +503,13 @@ func (s *Server) registerDatabaseRoutes(g *echo.Group) {
 			}
 		}
 
+		if err := secret.ValidateReference(dataSourceCreate.PasswordSecretRef, dataSourceCreate.Password, "password"); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		if err := secret.ValidateReference(dataSourceCreate.SslKeySecretRef, dataSourceCreate.SslKey, "SSL key"); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
 		creatorID := c.Get(getPrincipalIDContextKey()).(int)
 		title := api.AdminDataSourceName
 		if dataSourceCreate.Type == api.RO {
@@ -520,6 +528,15 @@ func (s *Server) registerDatabaseRoutes(g *echo.Group) {
 			Database:               dataSourceCreate.Database,
 			SRV:                    dataSourceCreate.Options.SRV,
 			AuthenticationDatabase: dataSourceCreate.Options.AuthenticationDatabase,
+
+			SSHHost:                 dataSourceCreate.SSHHost,
+			SSHPort:                 dataSourceCreate.SSHPort,
+			SSHUser:                 dataSourceCreate.SSHUser,
+			SSHObfuscatedPassword:   common.Obfuscate(dataSourceCreate.SSHPassword, s.secret),
+			SSHObfuscatedPrivateKey: common.Obfuscate(dataSourceCreate.SSHPrivateKey, s.secret),
+			SSHHostKey:              dataSourceCreate.SSHHostKey,
+			PasswordSecretRef:       dataSourceCreate.PasswordSecretRef,
+			SslKeySecretRef:         dataSourceCreate.SslKeySecretRef,
 		}
 		if err := s.store.AddDataSourceToInstanceV2(ctx, instance.UID, creatorID, instance.EnvironmentID, instance.ResourceID, dataSourceMessage); err != nil {
 			return err
@@ -604,6 +621,9 @@ func (s *Server) registerDatabaseRoutes(g *echo.Group) {
 			Username:      dataSourcePatch.Username,
 			Host:          dataSourcePatch.Host,
 			Port:          dataSourcePatch.Port,
+			SSHHost:       dataSourcePatch.SSHHost,
+			SSHPort:       dataSourcePatch.SSHPort,
+			SSHUser:       dataSourcePatch.SSHUser,
 		}
 		if dataSourcePatch.Password != nil {
 			obfuscated := common.Obfuscate(*dataSourcePatch.Password, s.secret)
@@ -621,10 +641,50 @@ func (s *Server) registerDatabaseRoutes(g *echo.Group) {
 			obfuscated := common.Obfuscate(*dataSourcePatch.SslKey, s.secret)
 			updateMessage.ObfuscatedSslKey = &obfuscated
 		}
+		if dataSourcePatch.SSHPassword != nil {
+			obfuscated := common.Obfuscate(*dataSourcePatch.SSHPassword, s.secret)
+			updateMessage.SSHObfuscatedPassword = &obfuscated
+		}
+		if dataSourcePatch.SSHPrivateKey != nil {
+			obfuscated := common.Obfuscate(*dataSourcePatch.SSHPrivateKey, s.secret)
+			updateMessage.SSHObfuscatedPrivateKey = &obfuscated
+		}
+		if v := dataSourcePatch.SSHHostKey; v != nil {
+			updateMessage.SSHHostKey = v
+		}
 		if dataSourcePatch.UseEmptyPassword != nil && *dataSourcePatch.UseEmptyPassword {
 			obfuscated := common.Obfuscate("", s.secret)
 			updateMessage.ObfuscatedPassword = &obfuscated
 		}
+		if v := dataSourcePatch.PasswordSecretRef; v != nil {
+			password := ""
+			if dataSourcePatch.Password != nil {
+				password = *dataSourcePatch.Password
+			}
+			if err := secret.ValidateReference(*v, password, "password"); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			}
+			updateMessage.PasswordSecretRef = v
+			if *v != "" {
+				// Drop the stored password because it's resolved from the external secret provider from now on.
+				obfuscated := common.Obfuscate("", s.secret)
+				updateMessage.ObfuscatedPassword = &obfuscated
+			}
+		}
+		if v := dataSourcePatch.SslKeySecretRef; v != nil {
+			sslKey := ""
+			if dataSourcePatch.SslKey != nil {
+				sslKey = *dataSourcePatch.SslKey
+			}
+			if err := secret.ValidateReference(*v, sslKey, "SSL key"); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			}
+			updateMessage.SslKeySecretRef = v
+			if *v != "" {
+				obfuscated := common.Obfuscate("", s.secret)
+				updateMessage.ObfuscatedSslKey = &obfuscated
+			}
+		}
 
 		if dataSourcePatch.Options != nil {
 			updateMessage.SRV = &dataSourcePatch.Options.SRV
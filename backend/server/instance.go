@@ -18,6 +18,7 @@ import (
 	"github.com/bytebase/bytebase/backend/common/log"
 	api "github.com/bytebase/bytebase/backend/legacyapi"
 	"github.com/bytebase/bytebase/backend/plugin/db"
+	"github.com/bytebase/bytebase/backend/plugin/secret"
 	"github.com/bytebase/bytebase/backend/resources/postgres"
 	"github.com/bytebase/bytebase/backend/store"
 )
@@ -56,6 +57,12 @@ func (s *Server) registerInstanceRoutes(g *echo.Group) {
 		if environment == nil {
 			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("environment %v not found", instanceCreate.EnvironmentID))
 		}
+		if err := secret.ValidateReference(instanceCreate.PasswordSecretRef, instanceCreate.Password, "password"); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		if err := secret.ValidateReference(instanceCreate.SslKeySecretRef, instanceCreate.SslKey, "SSL key"); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
 		creator := c.Get(getPrincipalIDContextKey()).(int)
 		instance, err := s.store.CreateInstanceV2(ctx, environment.ResourceID, &store.InstanceMessage{
 			ResourceID:   fmt.Sprintf("instance-%s", uuid.New().String()[:8]),
@@ -76,6 +83,15 @@ func (s *Server) registerInstanceRoutes(g *echo.Group) {
 					Database:               instanceCreate.Database,
 					SRV:                    instanceCreate.SRV,
 					AuthenticationDatabase: instanceCreate.AuthenticationDatabase,
+
+					SSHHost:                 instanceCreate.SSHHost,
+					SSHPort:                 instanceCreate.SSHPort,
+					SSHUser:                 instanceCreate.SSHUser,
+					SSHObfuscatedPassword:   common.Obfuscate(instanceCreate.SSHPassword, s.secret),
+					SSHObfuscatedPrivateKey: common.Obfuscate(instanceCreate.SSHPrivateKey, s.secret),
+					SSHHostKey:              instanceCreate.SSHHostKey,
+					PasswordSecretRef:       instanceCreate.PasswordSecretRef,
+					SslKeySecretRef:         instanceCreate.SslKeySecretRef,
 				},
 			},
 		}, creator)
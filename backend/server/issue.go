@@ -10,6 +10,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/google/jsonapi"
 	"github.com/labstack/echo/v4"
@@ -39,6 +40,8 @@ func (s *Server) registerIssueRoutes(g *echo.Group) {
 		if err != nil {
 			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create issue").SetInternal(err)
 		}
+		s.setMaintenanceWindowForIssue(ctx, issue)
+		s.setGateStatusForIssue(ctx, issue)
 
 		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
 		if err := jsonapi.MarshalPayload(c.Response().Writer, issue); err != nil {
@@ -120,7 +123,7 @@ func (s *Server) registerIssueRoutes(g *echo.Group) {
 		}
 
 		for _, issue := range issueList {
-			s.setTaskProgressForIssue(issue)
+			s.setTaskProgressForIssue(ctx, issue)
 		}
 
 		issueResponse := &api.IssueResponse{}
@@ -157,7 +160,9 @@ func (s *Server) registerIssueRoutes(g *echo.Group) {
 			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Issue ID not found: %d", id))
 		}
 
-		s.setTaskProgressForIssue(issue)
+		s.setTaskProgressForIssue(ctx, issue)
+		s.setMaintenanceWindowForIssue(ctx, issue)
+		s.setGateStatusForIssue(ctx, issue)
 
 		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
 		if err := jsonapi.MarshalPayload(c.Response().Writer, issue); err != nil {
@@ -493,6 +498,7 @@ func (s *Server) createPipeline(ctx context.Context, creatorID int, pipelineCrea
 			Name:          stage.Name,
 			EnvironmentID: stage.EnvironmentID,
 			PipelineID:    pipelineCreated.ID,
+			Payload:       stage.Payload,
 		})
 	}
 	createdStages, err := s.store.CreateStageV2(ctx, stageCreates, creatorID)
@@ -543,6 +549,8 @@ func (s *Server) getPipelineCreate(ctx context.Context, issueCreate *api.IssueCr
 		return s.getPipelineCreateForDatabaseSchemaAndDataUpdate(ctx, issueCreate)
 	case api.IssueDatabaseRollback:
 		return s.getPipelineCreateForDatabaseRollback(ctx, issueCreate)
+	case api.IssueDatabaseAccessRequest:
+		return s.getPipelineCreateForDatabaseAccessRequest(ctx, issueCreate)
 	default:
 		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid issue type %q", issueCreate.Type))
 	}
@@ -573,42 +581,67 @@ func (s *Server) getPipelineCreateForDatabaseRollback(ctx context.Context, issue
 	if task == nil {
 		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Task not found with ID %d", taskID))
 	}
-	if task.Type != api.TaskDatabaseDataUpdate {
-		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Task type must be %s, but got %s", api.TaskDatabaseDataUpdate, task.Type))
-	}
 	instance, err := s.store.GetInstanceV2(ctx, &store.FindInstanceMessage{UID: &task.InstanceID})
 	if err != nil {
 		return nil, err
 	}
-	if instance.Engine != db.MySQL {
-		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Only support rollback for MySQL now, but got %s", instance.Engine))
-	}
 	if task.PipelineID != issue.PipelineUID {
 		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Task %d is not in issue %d", taskID, issue.UID))
 	}
-	if task.Status != api.TaskDone && task.Status != api.TaskFailed {
-		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Task %d has status %s, must be %s or %s", taskID, task.Status, api.TaskDone, api.TaskFailed))
-	}
 
-	taskPayload := &api.TaskDatabaseDataUpdatePayload{}
-	if err := json.Unmarshal([]byte(task.Payload), taskPayload); err != nil {
-		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to unmarshal the task payload with ID %d", taskID)).SetInternal(err)
-	}
-	switch {
-	case taskPayload.RollbackStatement == "" && taskPayload.RollbackError == "":
-		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Rollback SQL generation for task %d is still in progress", taskID))
-	case taskPayload.RollbackStatement == "" && taskPayload.RollbackError != "":
-		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Rollback SQL generation for task %d has already failed: %s", taskID, taskPayload.RollbackError))
-	case taskPayload.RollbackStatement != "" && taskPayload.RollbackError != "":
-		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Invalid task payload: RollbackStatement=%q, RollbackError=%q", taskPayload.RollbackStatement, taskPayload.RollbackError))
+	var migrationType db.MigrationType
+	var statement string
+	switch task.Type {
+	case api.TaskDatabaseDataUpdate:
+		if instance.Engine != db.MySQL {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Only support rollback for MySQL now, but got %s", instance.Engine))
+		}
+		if task.Status != api.TaskDone && task.Status != api.TaskFailed {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Task %d has status %s, must be %s or %s", taskID, task.Status, api.TaskDone, api.TaskFailed))
+		}
+		taskPayload := &api.TaskDatabaseDataUpdatePayload{}
+		if err := json.Unmarshal([]byte(task.Payload), taskPayload); err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to unmarshal the task payload with ID %d", taskID)).SetInternal(err)
+		}
+		switch {
+		case taskPayload.RollbackStatement == "" && taskPayload.RollbackError == "":
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Rollback SQL generation for task %d is still in progress", taskID))
+		case taskPayload.RollbackStatement == "" && taskPayload.RollbackError != "":
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Rollback SQL generation for task %d has already failed: %s", taskID, taskPayload.RollbackError))
+		case taskPayload.RollbackStatement != "" && taskPayload.RollbackError != "":
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Invalid task payload: RollbackStatement=%q, RollbackError=%q", taskPayload.RollbackStatement, taskPayload.RollbackError))
+		}
+		migrationType, statement = db.Data, taskPayload.RollbackStatement
+		issueCreate.Type = api.IssueDatabaseDataUpdate
+	case api.TaskDatabaseSchemaUpdate:
+		if instance.Engine != db.MySQL && instance.Engine != db.Postgres {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Only support schema rollback for MySQL and PostgreSQL now, but got %s", instance.Engine))
+		}
+		if task.Status != api.TaskDone {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Task %d has status %s, must be %s", taskID, task.Status, api.TaskDone))
+		}
+		taskPayload := &api.TaskDatabaseSchemaUpdatePayload{}
+		if err := json.Unmarshal([]byte(task.Payload), taskPayload); err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to unmarshal the task payload with ID %d", taskID)).SetInternal(err)
+		}
+		switch {
+		case taskPayload.DownStatement == "" && taskPayload.DownStatementError != "":
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Down migration generation for task %d has failed: %s", taskID, taskPayload.DownStatementError))
+		case taskPayload.DownStatement == "":
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Task %d has no down migration, the schema is unchanged or the down migration is not generated", taskID))
+		}
+		migrationType, statement = db.Migrate, taskPayload.DownStatement
+		issueCreate.Type = api.IssueDatabaseSchemaUpdate
+	default:
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Task type must be %s or %s, but got %s", api.TaskDatabaseDataUpdate, api.TaskDatabaseSchemaUpdate, task.Type))
 	}
 
 	issueCreateContext := &api.MigrationContext{
 		DetailList: []*api.MigrationDetail{
 			{
-				MigrationType: db.Data,
+				MigrationType: migrationType,
 				DatabaseID:    *task.DatabaseID,
-				Statement:     taskPayload.RollbackStatement,
+				Statement:     statement,
 			},
 		},
 	}
@@ -617,7 +650,6 @@ func (s *Server) getPipelineCreateForDatabaseRollback(ctx context.Context, issue
 		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal issue create context for rollback issue")
 	}
 	issueCreate.CreateContext = string(bytes)
-	issueCreate.Type = api.IssueDatabaseDataUpdate
 	pipelineCreate, err := s.getPipelineCreateForDatabaseSchemaAndDataUpdate(ctx, issueCreate)
 	if err != nil {
 		return nil, err
@@ -629,17 +661,31 @@ func (s *Server) getPipelineCreateForDatabaseRollback(ctx context.Context, issue
 	if len(pipelineCreate.StageList[0].TaskList) != 1 {
 		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Must have one task for a rollback task")
 	}
-	rollbackTaskPayload := &api.TaskDatabaseDataUpdatePayload{}
-	if err := json.Unmarshal([]byte(pipelineCreate.StageList[0].TaskList[0].Payload), rollbackTaskPayload); err != nil {
-		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to unmarshal the rollback task create payload").SetInternal(err)
+	rollbackTaskCreate := &pipelineCreate.StageList[0].TaskList[0]
+	var rollbackTaskPayload interface{}
+	switch task.Type {
+	case api.TaskDatabaseDataUpdate:
+		payload := &api.TaskDatabaseDataUpdatePayload{}
+		if err := json.Unmarshal([]byte(rollbackTaskCreate.Payload), payload); err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to unmarshal the rollback task create payload").SetInternal(err)
+		}
+		payload.RollbackFromIssueID = issueID
+		payload.RollbackFromTaskID = taskID
+		rollbackTaskPayload = payload
+	case api.TaskDatabaseSchemaUpdate:
+		payload := &api.TaskDatabaseSchemaUpdatePayload{}
+		if err := json.Unmarshal([]byte(rollbackTaskCreate.Payload), payload); err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to unmarshal the rollback task create payload").SetInternal(err)
+		}
+		payload.RollbackFromIssueID = issueID
+		payload.RollbackFromTaskID = taskID
+		rollbackTaskPayload = payload
 	}
-	rollbackTaskPayload.RollbackFromIssueID = issueID
-	rollbackTaskPayload.RollbackFromTaskID = taskID
 	buf, err := json.Marshal(rollbackTaskPayload)
 	if err != nil {
 		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal rollback task payload").SetInternal(err)
 	}
-	pipelineCreate.StageList[0].TaskList[0].Payload = string(buf)
+	rollbackTaskCreate.Payload = string(buf)
 
 	return pipelineCreate, nil
 }
@@ -746,6 +792,91 @@ func (s *Server) getPipelineCreateForDatabaseCreate(ctx context.Context, issueCr
 	}, nil
 }
 
+func (s *Server) getPipelineCreateForDatabaseAccessRequest(ctx context.Context, issueCreate *api.IssueCreate) (*api.PipelineCreate, error) {
+	c := api.AccessRequestContext{}
+	if err := json.Unmarshal([]byte(issueCreate.CreateContext), &c); err != nil {
+		return nil, err
+	}
+	if c.DurationSeconds <= 0 || c.DurationSeconds > maxAccessRequestDurationSeconds {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("The access duration must be between 1 and %d seconds", maxAccessRequestDurationSeconds))
+	}
+	if strings.TrimSpace(c.Reason) == "" {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Failed to create issue, reason missing")
+	}
+
+	project, err := s.store.GetProjectV2(ctx, &store.FindProjectMessage{UID: &issueCreate.ProjectID})
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch project with ID %d", issueCreate.ProjectID)).SetInternal(err)
+	}
+	if project == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("project %d not found", issueCreate.ProjectID))
+	}
+	database, err := s.store.GetDatabaseV2(ctx, &store.FindDatabaseMessage{UID: &c.DatabaseID})
+	if err != nil {
+		return nil, err
+	}
+	if database == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("database %d not found", c.DatabaseID))
+	}
+	if database.ProjectID != project.ResourceID {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("The issue project %d must be the same as the database project %q.", issueCreate.ProjectID, database.ProjectID))
+	}
+	instance, err := s.store.GetInstanceV2(ctx, &store.FindInstanceMessage{EnvironmentID: &database.EnvironmentID, ResourceID: &database.InstanceID})
+	if err != nil {
+		return nil, err
+	}
+	if instance == nil {
+		return nil, errors.Errorf("instance %q not found", database.InstanceID)
+	}
+	if len(c.TableList) > 0 && !isTableAccessGrantSupported(instance.Engine) {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Limiting the access to tables is not supported for %s", instance.Engine))
+	}
+	environment, err := s.store.GetEnvironmentV2(ctx, &store.FindEnvironmentMessage{ResourceID: &database.EnvironmentID})
+	if err != nil {
+		return nil, err
+	}
+	if environment == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("environment %q not found", database.EnvironmentID))
+	}
+
+	var tableList []string
+	for _, table := range c.TableList {
+		if table = strings.TrimSpace(table); table != "" {
+			tableList = append(tableList, table)
+		}
+	}
+	payload := api.TaskDatabaseAccessGrantPayload{
+		TableList:       tableList,
+		DurationSeconds: c.DurationSeconds,
+		Reason:          c.Reason,
+	}
+	bytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal database access grant payload").SetInternal(err)
+	}
+
+	return &api.PipelineCreate{
+		Name: fmt.Sprintf("Pipeline - Request access to database %s", database.DatabaseName),
+		StageList: []api.StageCreate{
+			{
+				Name:          environment.Title,
+				EnvironmentID: environment.UID,
+				TaskList: []api.TaskCreate{
+					{
+						InstanceID:   instance.UID,
+						DatabaseID:   &database.UID,
+						Name:         fmt.Sprintf("Grant query access to database %s", database.DatabaseName),
+						Status:       api.TaskPendingApproval,
+						Type:         api.TaskDatabaseAccessGrant,
+						DatabaseName: database.DatabaseName,
+						Payload:      string(bytes),
+					},
+				},
+			},
+		},
+	}, nil
+}
+
 func (s *Server) getPipelineCreateForDatabasePITR(ctx context.Context, issueCreate *api.IssueCreate) (*api.PipelineCreate, error) {
 	c := api.PITRContext{}
 	if err := json.Unmarshal([]byte(issueCreate.CreateContext), &c); err != nil {
@@ -839,6 +970,25 @@ func (s *Server) getPipelineCreateForDatabaseSchemaAndDataUpdate(ctx context.Con
 		if detail.MigrationType != db.Baseline && detail.MigrationType != db.Migrate && detail.MigrationType != db.MigrateSDL && detail.MigrationType != db.Data {
 			return nil, echo.NewHTTPError(http.StatusBadRequest, "support migrate, migrateSDL and data type migration only")
 		}
+		// The verification of the issue applies to the schema and data updates without their own verification.
+		verificationSupported := (detail.MigrationType == db.Migrate || detail.MigrationType == db.Data) && issueCreate.Type != api.IssueDatabaseSchemaUpdateGhost
+		if detail.Verification == nil && verificationSupported {
+			detail.Verification = c.Verification
+		}
+		if detail.Verification != nil {
+			if !verificationSupported {
+				return nil, echo.NewHTTPError(http.StatusBadRequest, "Verification is only supported for schema and data updates")
+			}
+			if strings.TrimSpace(detail.Verification.Statement) == "" {
+				return nil, echo.NewHTTPError(http.StatusBadRequest, "Verification statement must not be empty")
+			}
+			if !util.ValidateSQLSelectStatement(detail.Verification.Statement) {
+				return nil, echo.NewHTTPError(http.StatusBadRequest, "Verification statement must be a single SELECT statement")
+			}
+		}
+		if detail.ResumableExecution && ((detail.MigrationType != db.Migrate && detail.MigrationType != db.Data) || issueCreate.Type == api.IssueDatabaseSchemaUpdateGhost) {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, "Resumable execution is only supported for schema and data updates")
+		}
 		if detail.MigrationType != db.Baseline && (detail.Statement == "" && detail.SheetID == 0) {
 			return nil, echo.NewHTTPError(http.StatusBadRequest, "require sql statement or sheet ID to create an issue")
 		}
@@ -895,6 +1045,7 @@ func (s *Server) getPipelineCreateForDatabaseSchemaAndDataUpdate(ctx context.Con
 			}
 		}
 	} else {
+		var detailDatabaseList []*store.DatabaseMessage
 		for _, d := range c.DetailList {
 			database, ok := databaseMap[d.DatabaseID]
 			if !ok {
@@ -911,30 +1062,17 @@ func (s *Server) getPipelineCreateForDatabaseSchemaAndDataUpdate(ctx context.Con
 				// We disallow user to create non-data migration for MongoDB.
 				return nil, echo.NewHTTPError(http.StatusBadRequest, "Cannot create non-data migration for MongoDB, consider using data migration(DML) instead.")
 			}
-			matrix, err := utils.GetDatabaseMatrixFromDeploymentSchedule(deploySchedule, []*store.DatabaseMessage{database})
-			if err != nil {
-				return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to build deployment pipeline").SetInternal(err)
-			}
-			for i, databaseList := range matrix {
-				if len(databaseList) == 0 {
-					continue
-				} else if len(databaseList) > 1 {
-					return nil, echo.NewHTTPError(http.StatusInternalServerError, "should be at most one database in the matrix stage")
-				}
-				database := databaseList[0]
-				found := false
-				for _, v := range aggregatedMatrix[i] {
-					if v == database {
-						found = true
-						break
-					}
-				}
-				if !found {
-					aggregatedMatrix[i] = append(aggregatedMatrix[i], database)
-				}
+			if _, ok := databaseToMigrationList[database.UID]; !ok {
+				detailDatabaseList = append(detailDatabaseList, database)
 			}
 			databaseToMigrationList[database.UID] = append(databaseToMigrationList[database.UID], d)
 		}
+		// Build the matrix with all databases at once, so that the rollout limits of the stages apply to the databases as a whole.
+		matrix, err := utils.GetDatabaseMatrixFromDeploymentSchedule(deploySchedule, detailDatabaseList)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to build deployment pipeline").SetInternal(err)
+		}
+		aggregatedMatrix = matrix
 	}
 
 	if issueCreate.Type == api.IssueDatabaseSchemaUpdateGhost {
@@ -984,6 +1122,7 @@ func (s *Server) getPipelineCreateForDatabaseSchemaAndDataUpdate(ctx context.Con
 				EnvironmentID:    environment.UID,
 				TaskList:         taskCreateList,
 				TaskIndexDAGList: taskIndexDAGList,
+				Payload:          &api.StagePayload{Rollout: deploySchedule.Deployments[i].Spec.Rollout, Gate: deploySchedule.Deployments[i].Spec.Gate},
 			})
 		}
 		return create, nil
@@ -1037,6 +1176,7 @@ func (s *Server) getPipelineCreateForDatabaseSchemaAndDataUpdate(ctx context.Con
 			EnvironmentID:    environment.UID,
 			TaskList:         taskCreateList,
 			TaskIndexDAGList: taskIndexDAGList,
+			Payload:          &api.StagePayload{Rollout: deploySchedule.Deployments[i].Spec.Rollout, Gate: deploySchedule.Deployments[i].Spec.Gate},
 		})
 	}
 	return create, nil
@@ -1074,6 +1214,9 @@ func getUpdateTask(database *store.DatabaseMessage, instance *store.InstanceMess
 			SheetID:       d.SheetID,
 			SchemaVersion: schemaVersion,
 			VCSPushEvent:  vcsPushEvent,
+			Verification:  d.Verification,
+
+			ResumableExecution: d.ResumableExecution,
 		}
 		bytes, err := json.Marshal(payload)
 		if err != nil {
@@ -1097,11 +1240,23 @@ func getUpdateTask(database *store.DatabaseMessage, instance *store.InstanceMess
 	case db.Data:
 		taskName = fmt.Sprintf("DML(data) for database %q", database.DatabaseName)
 		taskType = api.TaskDatabaseDataUpdate
+		if d.ChunkConfig != nil {
+			if instance.Engine != db.MySQL && instance.Engine != db.Postgres {
+				return api.TaskCreate{}, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Chunked execution is not supported for %s", instance.Engine))
+			}
+			if err := d.ChunkConfig.Validate(); err != nil {
+				return api.TaskCreate{}, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			}
+		}
 		payload := api.TaskDatabaseDataUpdatePayload{
 			Statement:     d.Statement,
 			SheetID:       d.SheetID,
 			SchemaVersion: schemaVersion,
 			VCSPushEvent:  vcsPushEvent,
+			ChunkConfig:   d.ChunkConfig,
+			Verification:  d.Verification,
+
+			ResumableExecution: d.ResumableExecution,
 		}
 		bytes, err := json.Marshal(payload)
 		if err != nil {
@@ -1415,18 +1570,109 @@ func checkCharacterSetCollationOwner(dbType db.Type, characterSet, collation, ow
 	return nil
 }
 
-func (s *Server) setTaskProgressForIssue(issue *api.Issue) {
+// setTaskProgressForIssue sets the progress of the running tasks and the queued reason of the pending tasks.
+// The progress is loaded from the running task runs, so that it's available on all the replicas.
+func (s *Server) setTaskProgressForIssue(ctx context.Context, issue *api.Issue) {
 	if s.TaskScheduler == nil {
 		// readonly server doesn't have a TaskScheduler.
 		return
 	}
+	progressMap, err := s.getTaskProgressMap(ctx, issue)
+	if err != nil {
+		log.Warn("Failed to get the task progress of the issue", zap.Int("issue_id", issue.ID), zap.Error(err))
+	}
+	for _, stage := range issue.Pipeline.StageList {
+		for _, task := range stage.TaskList {
+			if progress, ok := progressMap[task.ID]; ok {
+				task.Progress = progress
+			}
+			if reason, ok := s.stateCfg.QueuedTasks.Load(task.ID); ok && task.Status == api.TaskPending {
+				task.QueuedReason = reason.(string)
+			}
+		}
+	}
+}
+
+// getTaskProgressMap returns the mapping from task ID to the progress of the running tasks in the issue.
+func (s *Server) getTaskProgressMap(ctx context.Context, issue *api.Issue) (map[int]api.Progress, error) {
+	hasRunningTask := false
 	for _, stage := range issue.Pipeline.StageList {
 		for _, task := range stage.TaskList {
-			if progress, ok := s.stateCfg.TaskProgress.Load(task.ID); ok {
-				task.Progress = progress.(api.Progress)
+			if task.Status == api.TaskRunning {
+				hasRunningTask = true
 			}
 		}
 	}
+	if !hasRunningTask {
+		return nil, nil
+	}
+	taskRuns, err := s.store.ListTaskRunsV2(ctx, &store.TaskRunFind{
+		PipelineID: &issue.Pipeline.ID,
+		StatusList: &[]api.TaskRunStatus{api.TaskRunRunning},
+	})
+	if err != nil {
+		return nil, err
+	}
+	progressMap := make(map[int]api.Progress)
+	for _, taskRun := range taskRuns {
+		var progress api.Progress
+		if err := json.Unmarshal([]byte(taskRun.Progress), &progress); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal the progress of task run %d", taskRun.ID)
+		}
+		progressMap[taskRun.TaskID] = progress
+	}
+	return progressMap, nil
+}
+
+// setMaintenanceWindowForIssue sets the next start time allowed by the maintenance window policy for the tasks not started yet.
+func (s *Server) setMaintenanceWindowForIssue(ctx context.Context, issue *api.Issue) {
+	if s.TaskScheduler == nil || issue.Pipeline == nil {
+		return
+	}
+	now := time.Now()
+	for _, stage := range issue.Pipeline.StageList {
+		for _, task := range stage.TaskList {
+			if task.Status != api.TaskPendingApproval && task.Status != api.TaskPending {
+				continue
+			}
+			start, _, found, err := s.TaskScheduler.GetMaintenanceWindow(ctx, task.InstanceID, task.DatabaseID, now)
+			if err != nil {
+				log.Warn("Failed to get the maintenance window of the task", zap.Int("task_id", task.ID), zap.Error(err))
+				continue
+			}
+			if found && start.After(now) {
+				task.MaintenanceWindowStartTs = start.Unix()
+			}
+		}
+	}
+}
+
+// setGateStatusForIssue sets the promotion gate status of the stages.
+func (s *Server) setGateStatusForIssue(ctx context.Context, issue *api.Issue) {
+	if s.TaskScheduler == nil || issue.Pipeline == nil {
+		return
+	}
+	for _, stage := range issue.Pipeline.StageList {
+		if !isStageStarted(stage) {
+			continue
+		}
+		status, err := s.TaskScheduler.GetStageGateStatus(ctx, stage.PipelineID, stage.ID)
+		if err != nil {
+			log.Warn("Failed to get the promotion gate status of the stage", zap.Int("stage_id", stage.ID), zap.Error(err))
+			continue
+		}
+		stage.GateStatus = status
+	}
+}
+
+// isStageStarted returns whether any task of the stage has been approved to run.
+func isStageStarted(stage *api.Stage) bool {
+	for _, task := range stage.TaskList {
+		if task.Status != api.TaskPendingApproval {
+			return true
+		}
+	}
+	return false
 }
 
 func marshalPageToken(id int) (string, error) {
@@ -5,6 +5,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	api "github.com/bytebase/bytebase/backend/legacyapi"
 	"github.com/bytebase/bytebase/backend/plugin/db"
 )
 
@@ -103,3 +104,31 @@ func TestCheckCharacterSetCollationOwner(t *testing.T) {
 		}
 	}
 }
+
+func TestIsStageStarted(t *testing.T) {
+	tests := []struct {
+		statusList []api.TaskStatus
+		want       bool
+	}{
+		{
+			statusList: []api.TaskStatus{api.TaskPendingApproval, api.TaskPendingApproval},
+			want:       false,
+		},
+		{
+			statusList: []api.TaskStatus{api.TaskPendingApproval, api.TaskPending},
+			want:       true,
+		},
+		{
+			statusList: []api.TaskStatus{api.TaskDone},
+			want:       true,
+		},
+	}
+
+	for _, test := range tests {
+		stage := &api.Stage{}
+		for _, status := range test.statusList {
+			stage.TaskList = append(stage.TaskList, &api.Task{Status: status})
+		}
+		require.Equal(t, test.want, isStageStarted(stage))
+	}
+}
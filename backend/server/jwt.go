@@ -1,6 +1,7 @@
 package server
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -105,12 +106,12 @@ func findAccessToken(c echo.Context) (string, error) {
 
 	cookie, err := c.Cookie(auth.AccessTokenCookieName)
 	if err != nil {
-		// TODO(ed): support trigger the schema sync via terraform in a quick but dirty way.
-		// We should support trigger the sync via OpenAPI later.
-		if c.Path() == "/api/sql/sync-schema" {
-			return extractTokenFromHeader(c)
+		// The API tokens used by the CI jobs and the schema sync triggered by terraform are sent in the Authorization header.
+		token, headerErr := extractTokenFromHeader(c)
+		if headerErr != nil || token == "" {
+			return "", err
 		}
-		return "", err
+		return token, nil
 	}
 
 	return cookie.Value, nil
@@ -162,6 +163,30 @@ func JWTMiddleware(pathPrefix string, principalStore *store.Store, next echo.Han
 			return nil, pkgerrors.Errorf("unexpected access token kid=%v", t.Header["kid"])
 		})
 
+		if audienceContains(claims.Audience, fmt.Sprintf(auth.APITokenAudienceFmt, mode)) {
+			if err != nil {
+				return echo.NewHTTPError(http.StatusUnauthorized, "Invalid or expired API token").SetInternal(err)
+			}
+			apiToken, err := auth.ValidateAPIToken(c.Request().Context(), principalStore, claims.RegisteredClaims)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+			}
+			if !auth.IsAPITokenRequestAllowed(method, c.Request().URL.Path, apiToken) {
+				return echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("API token %d is not granted the scope to %s %s", apiToken.UID, method, c.Request().URL.Path))
+			}
+			user, err := principalStore.GetUserByID(c.Request().Context(), apiToken.PrincipalID)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Server error to find user ID: %d", apiToken.PrincipalID)).SetInternal(err)
+			}
+			if user == nil {
+				return echo.NewHTTPError(http.StatusUnauthorized, fmt.Sprintf("Failed to find user ID: %d", apiToken.PrincipalID))
+			}
+			if err := checkMFAEnrollment(c.Request().Context(), principalStore, user, method, true /* isAPIToken */); err != nil {
+				return err
+			}
+			c.Set(getPrincipalIDContextKey(), apiToken.PrincipalID)
+			return next(c)
+		}
 		if !audienceContains(claims.Audience, fmt.Sprintf(auth.AccessTokenAudienceFmt, mode)) {
 			return echo.NewHTTPError(http.StatusUnauthorized,
 				fmt.Sprintf("Invalid access token, audience mismatch, got %q, expected %q. you may send request to the wrong environment",
@@ -254,6 +279,10 @@ func JWTMiddleware(pathPrefix string, principalStore *store.Store, next echo.Han
 				}
 			}
 
+			if err := checkMFAEnrollment(ctx, principalStore, user, method, false /* isAPIToken */); err != nil {
+				return err
+			}
+
 			// Stores principalID into context.
 			c.Set(getPrincipalIDContextKey(), principalID)
 			return next(c)
@@ -267,6 +296,28 @@ func JWTMiddleware(pathPrefix string, principalStore *store.Store, next echo.Han
 	}
 }
 
+// checkMFAEnrollment denies the request of the user who is required to enroll MFA but hasn't yet.
+func checkMFAEnrollment(ctx context.Context, principalStore *store.Store, user *store.UserMessage, method string, isAPIToken bool) error {
+	if isMFAEnrollmentPendingRequestAllowed(method, isAPIToken) {
+		return nil
+	}
+	pending, err := auth.IsMFAEnrollmentPending(ctx, principalStore, user)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to check MFA enrollment").SetInternal(err)
+	}
+	if pending {
+		return echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("MFA is required by the workspace, please enable MFA for user %q first", user.Email))
+	}
+	return nil
+}
+
+// isMFAEnrollmentPendingRequestAllowed returns whether the request is allowed before the user enrolls the required MFA.
+// The users can only read in the console until they enable MFA in the v1 API. The API tokens are denied as the v1 API
+// interceptor does, since MFA can't be enrolled with them.
+func isMFAEnrollmentPendingRequestAllowed(method string, isAPIToken bool) bool {
+	return !isAPIToken && method == http.MethodGet
+}
+
 func audienceContains(audience jwt.ClaimStrings, token string) bool {
 	for _, v := range audience {
 		if v == token {
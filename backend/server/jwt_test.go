@@ -0,0 +1,42 @@
+package server
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsMFAEnrollmentPendingRequestAllowed(t *testing.T) {
+	tests := []struct {
+		method     string
+		isAPIToken bool
+		want       bool
+	}{
+		{
+			method:     http.MethodGet,
+			isAPIToken: false,
+			want:       true,
+		},
+		{
+			method:     http.MethodPost,
+			isAPIToken: false,
+			want:       false,
+		},
+		// The API tokens are denied before the MFA enrollment, the same as the v1 API.
+		{
+			method:     http.MethodGet,
+			isAPIToken: true,
+			want:       false,
+		},
+		{
+			method:     http.MethodPatch,
+			isAPIToken: true,
+			want:       false,
+		},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.want, isMFAEnrollmentPendingRequestAllowed(test.method, test.isAPIToken), "%s %v", test.method, test.isAPIToken)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/labstack/echo/v4"
 
 	metricAPI "github.com/bytebase/bytebase/backend/metric"
+	"github.com/bytebase/bytebase/backend/plugin/advisor"
 	"github.com/bytebase/bytebase/backend/plugin/advisor/catalog"
 	advisorDB "github.com/bytebase/bytebase/backend/plugin/advisor/db"
 	"github.com/bytebase/bytebase/backend/plugin/db"
@@ -46,8 +47,15 @@ type sqlCheckRequestBody struct {
 	EnvironmentName string `json:"environmentName"`
 	Host            string `json:"host"`
 	Port            string `json:"port"`
+	// Format is the optional report format, could be "SARIF" or "JUNIT".
+	Format advisor.ReportFormat `json:"format"`
+	// FilePath is the statement file path used as the location in the report.
+	FilePath string `json:"filePath"`
 }
 
+// defaultSQLCheckFilePath is the statement file path in the report if the file path is not specified.
+const defaultSQLCheckFilePath = "statement.sql"
+
 // sqlCheckController godoc
 // @Summary  Check the SQL statement.
 // @Description  Parse and check the SQL statement according to the SQL review policy.
@@ -60,6 +68,8 @@ type sqlCheckRequestBody struct {
 // @Param  host             body  string  false  "The instance host."
 // @Param  port             body  string  false  "The instance port."
 // @Param  databaseName     body  string  false  "The database name in the instance."
+// @Param  format           body  string  false  "The report format. Return the advice list if it's not specified."  Enums(SARIF, JUNIT)
+// @Param  filePath         body  string  false  "The statement file path used as the location in the report."
 // @Success  200  {array}   advisor.Advice
 // @Failure  400  {object}  echo.HTTPError
 // @Failure  500  {object}  echo.HTTPError
@@ -83,6 +93,12 @@ func (s *Server) sqlCheckController(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "Missing required SQL statement")
 	}
 
+	if request.Format != "" {
+		if err := request.Format.Validate(); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+	}
+
 	ctx := c.Request().Context()
 	var databaseType string
 	var catalog catalog.Catalog
@@ -184,6 +200,21 @@ func (s *Server) sqlCheckController(c echo.Context) error {
 		})
 	}
 
+	if request.Format != "" {
+		filePath := request.FilePath
+		if filePath == "" {
+			filePath = defaultSQLCheckFilePath
+		}
+		report, err := advisor.GenerateReport(request.Format, map[string][]advisor.Advice{filePath: adviceList}, advisor.ReportContext{DocURL: sqlReviewDocs})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate SQL review report").SetInternal(err)
+		}
+		if request.Format == advisor.ReportFormatJUnit {
+			return c.Blob(http.StatusOK, echo.MIMEApplicationXMLCharsetUTF8, report)
+		}
+		return c.Blob(http.StatusOK, "application/sarif+json", report)
+	}
+
 	return c.JSON(http.StatusOK, adviceList)
 }
 
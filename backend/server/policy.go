@@ -426,6 +426,10 @@ func (s *Server) hasAccessToUpsertPolicy(pType api.PolicyType, policyUpsert *api
 		if !s.licenseService.IsFeatureEnabled(api.FeatureSensitiveData) {
 			return errors.Errorf(api.FeatureSensitiveData.AccessErrorMessage())
 		}
+	case api.PolicyTypeMaintenanceWindow:
+		if !s.licenseService.IsFeatureEnabled(api.FeatureTaskScheduleTime) {
+			return errors.Errorf(api.FeatureTaskScheduleTime.AccessErrorMessage())
+		}
 	}
 	return nil
 }
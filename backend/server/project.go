@@ -1268,3 +1268,15 @@ func hasActiveProjectMembership(principalID int, projectPolicy *store.IAMPolicyM
 	}
 	return false
 }
+
+// getProjectRole returns the role of a principal in a project, and returns empty if the principal is not a member.
+func getProjectRole(principalID int, projectPolicy *store.IAMPolicyMessage) api.Role {
+	for _, binding := range projectPolicy.Bindings {
+		for _, member := range binding.Members {
+			if member.ID == principalID {
+				return binding.Role
+			}
+		}
+	}
+	return ""
+}
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -26,6 +27,9 @@ func (s *Server) registerProjectMemberRoutes(g *echo.Group) {
 			return echo.NewHTTPError(http.StatusBadRequest, "Malformed create project membership request").SetInternal(err)
 		}
 		creatorID := c.Get(getPrincipalIDContextKey()).(int)
+		if err := s.validateProjectRole(ctx, api.Role(projectMemberCreate.Role)); err != nil {
+			return err
+		}
 
 		project, err := s.store.GetProjectV2(ctx, &store.FindProjectMessage{UID: &projectID})
 		if err != nil {
@@ -117,6 +121,9 @@ func (s *Server) registerProjectMemberRoutes(g *echo.Group) {
 		}
 		newRole := *projectMemberPatch.Role
 		updaterID := c.Get(getPrincipalIDContextKey()).(int)
+		if err := s.validateProjectRole(ctx, api.Role(newRole)); err != nil {
+			return err
+		}
 
 		project, err := s.store.GetProjectV2(ctx, &store.FindProjectMessage{UID: &projectID})
 		if err != nil {
@@ -260,3 +267,18 @@ func removeMember(policy *store.IAMPolicyMessage, user *store.UserMessage) *stor
 	}
 	return newPolicy
 }
+
+// validateProjectRole validates that the project role is either a built-in role or an existing custom role.
+func (s *Server) validateProjectRole(ctx context.Context, role api.Role) error {
+	if api.IsBuiltinProjectRole(role) {
+		return nil
+	}
+	customRole, err := s.store.GetRole(ctx, string(role))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to find role %q", role)).SetInternal(err)
+	}
+	if customRole == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid project role %q", role))
+	}
+	return nil
+}
@@ -43,6 +43,7 @@ import (
 	"github.com/bytebase/bytebase/backend/common"
 	"github.com/bytebase/bytebase/backend/common/log"
 	"github.com/bytebase/bytebase/backend/component/activity"
+	"github.com/bytebase/bytebase/backend/component/audit"
 	"github.com/bytebase/bytebase/backend/component/config"
 	"github.com/bytebase/bytebase/backend/component/dbfactory"
 	"github.com/bytebase/bytebase/backend/component/state"
@@ -53,10 +54,12 @@ import (
 	metricCollector "github.com/bytebase/bytebase/backend/metric/collector"
 	"github.com/bytebase/bytebase/backend/plugin/app/feishu"
 	"github.com/bytebase/bytebase/backend/plugin/db"
+	"github.com/bytebase/bytebase/backend/plugin/secret"
 	bbs3 "github.com/bytebase/bytebase/backend/plugin/storage/s3"
 	"github.com/bytebase/bytebase/backend/resources/mongoutil"
 	"github.com/bytebase/bytebase/backend/resources/mysqlutil"
 	"github.com/bytebase/bytebase/backend/resources/postgres"
+	"github.com/bytebase/bytebase/backend/runner/accessrun"
 	"github.com/bytebase/bytebase/backend/runner/anomaly"
 	"github.com/bytebase/bytebase/backend/runner/apprun"
 	"github.com/bytebase/bytebase/backend/runner/backuprun"
@@ -126,6 +129,7 @@ type Server struct {
 	AnomalyScanner     *anomaly.Scanner
 	ApplicationRunner  *apprun.Runner
 	RollbackRunner     *rollbackrun.Runner
+	AccessRunner       *accessrun.Runner
 	runnerWG           sync.WaitGroup
 
 	ActivityManager *activity.Manager
@@ -137,6 +141,7 @@ type Server struct {
 
 	profile         config.Profile
 	e               *echo.Echo
+	auditManager    *audit.Manager
 	grpcServer      *grpc.Server
 	metaDB          *store.MetadataDB
 	store           *store.Store
@@ -175,6 +180,11 @@ var casbinDBAPolicy string
 //go:embed acl_casbin_policy_developer.csv
 var casbinDeveloperPolicy string
 
+// casbinPermissionPolicy maps the workspace permissions granted by the custom roles to the routes.
+//
+//go:embed acl_casbin_policy_permission.csv
+var casbinPermissionPolicy string
+
 // Use following cmd to generate swagger doc
 // swag init -g ./backend/server.go -d ./backend/server --output docs/openapi --parseDependency
 
@@ -287,6 +297,9 @@ func NewServer(ctx context.Context, profile config.Profile) (*Server, error) {
 		InstanceOutstandingConnections: make(map[int]int),
 	}
 	storeInstance := store.New(storeDB)
+	if profile.HA {
+		storeInstance.EnableHA(profile.ReplicaID)
+	}
 	s.store = storeInstance
 	s.licenseService, err = enterpriseService.NewLicenseService(profile.Mode, storeInstance)
 	if err != nil {
@@ -303,7 +316,17 @@ func NewServer(ctx context.Context, profile config.Profile) (*Server, error) {
 	s.workspaceID = config.workspaceID
 
 	s.ActivityManager = activity.NewManager(storeInstance, profile)
-	s.dbFactory = dbfactory.New(s.mysqlBinDir, s.mongoBinDir, s.pgBinDir, profile.DataDir, s.secret)
+	auditManager, err := audit.NewManager(storeInstance, profile)
+	if err != nil {
+		return nil, err
+	}
+	s.auditManager = auditManager
+	secretResolver := secret.NewDefaultResolver(secret.Config{
+		VaultAddress: profile.SecretVaultAddress,
+		VaultToken:   profile.SecretVaultToken,
+		FileDir:      profile.SecretFileDir,
+	})
+	s.dbFactory = dbfactory.New(s.mysqlBinDir, s.mongoBinDir, s.pgBinDir, profile.DataDir, s.secret, secretResolver)
 	e := echo.New()
 	e.Debug = profile.Debug
 	e.HideBanner = true
@@ -336,6 +359,7 @@ func NewServer(ctx context.Context, profile config.Profile) (*Server, error) {
 		s.ApplicationRunner = apprun.NewRunner(storeInstance, s.ActivityManager, s.feishuProvider, profile)
 		s.BackupRunner = backuprun.NewRunner(storeInstance, s.dbFactory, s.s3Client, s.stateCfg, &profile)
 		s.RollbackRunner = rollbackrun.NewRunner(storeInstance, s.dbFactory, s.stateCfg)
+		s.AccessRunner = accessrun.NewRunner(storeInstance, s.ActivityManager)
 
 		s.TaskScheduler = taskrun.NewScheduler(storeInstance, s.ApplicationRunner, s.SchemaSyncer, s.ActivityManager, s.licenseService, s.stateCfg, profile)
 		s.TaskScheduler.Register(api.TaskGeneral, taskrun.NewDefaultExecutor())
@@ -345,10 +369,11 @@ func NewServer(ctx context.Context, profile config.Profile) (*Server, error) {
 		s.TaskScheduler.Register(api.TaskDatabaseSchemaUpdateSDL, taskrun.NewSchemaUpdateSDLExecutor(storeInstance, s.dbFactory, s.ActivityManager, s.licenseService, s.stateCfg, s.SchemaSyncer, profile))
 		s.TaskScheduler.Register(api.TaskDatabaseDataUpdate, taskrun.NewDataUpdateExecutor(storeInstance, s.dbFactory, s.ActivityManager, s.licenseService, s.stateCfg, profile))
 		s.TaskScheduler.Register(api.TaskDatabaseBackup, taskrun.NewDatabaseBackupExecutor(storeInstance, s.dbFactory, s.s3Client, profile))
-		s.TaskScheduler.Register(api.TaskDatabaseSchemaUpdateGhostSync, taskrun.NewSchemaUpdateGhostSyncExecutor(storeInstance, s.stateCfg, s.secret))
+		s.TaskScheduler.Register(api.TaskDatabaseSchemaUpdateGhostSync, taskrun.NewSchemaUpdateGhostSyncExecutor(storeInstance, s.dbFactory, s.stateCfg))
 		s.TaskScheduler.Register(api.TaskDatabaseSchemaUpdateGhostCutover, taskrun.NewSchemaUpdateGhostCutoverExecutor(storeInstance, s.dbFactory, s.ActivityManager, s.licenseService, s.stateCfg, s.SchemaSyncer, profile))
 		s.TaskScheduler.Register(api.TaskDatabaseRestorePITRRestore, taskrun.NewPITRRestoreExecutor(storeInstance, s.dbFactory, s.s3Client, s.SchemaSyncer, s.stateCfg, profile))
 		s.TaskScheduler.Register(api.TaskDatabaseRestorePITRCutover, taskrun.NewPITRCutoverExecutor(storeInstance, s.dbFactory, s.SchemaSyncer, s.BackupRunner, s.ActivityManager, profile))
+		s.TaskScheduler.Register(api.TaskDatabaseAccessGrant, taskrun.NewAccessGrantExecutor(storeInstance))
 
 		s.TaskCheckScheduler = taskcheck.NewScheduler(storeInstance, s.licenseService, s.stateCfg)
 		statementSimpleExecutor := taskcheck.NewStatementAdvisorSimpleExecutor()
@@ -358,19 +383,23 @@ func NewServer(ctx context.Context, profile config.Profile) (*Server, error) {
 		s.TaskCheckScheduler.Register(api.TaskCheckDatabaseStatementAdvise, statementCompositeExecutor)
 		statementTypeExecutor := taskcheck.NewStatementTypeExecutor(storeInstance)
 		s.TaskCheckScheduler.Register(api.TaskCheckDatabaseStatementType, statementTypeExecutor)
+		statementAffectedRowsExecutor := taskcheck.NewStatementAffectedRowsExecutor(storeInstance, s.dbFactory)
+		s.TaskCheckScheduler.Register(api.TaskCheckDatabaseStatementAffectedRows, statementAffectedRowsExecutor)
 		databaseConnectExecutor := taskcheck.NewDatabaseConnectExecutor(storeInstance, s.dbFactory)
 		s.TaskCheckScheduler.Register(api.TaskCheckDatabaseConnect, databaseConnectExecutor)
 		migrationSchemaExecutor := taskcheck.NewMigrationSchemaExecutor(storeInstance, s.dbFactory)
 		s.TaskCheckScheduler.Register(api.TaskCheckInstanceMigrationSchema, migrationSchemaExecutor)
-		ghostSyncExecutor := taskcheck.NewGhostSyncExecutor(storeInstance, s.secret)
+		ghostSyncExecutor := taskcheck.NewGhostSyncExecutor(storeInstance, s.dbFactory)
 		s.TaskCheckScheduler.Register(api.TaskCheckGhostSync, ghostSyncExecutor)
 		checkLGTMExecutor := taskcheck.NewLGTMExecutor(storeInstance)
 		s.TaskCheckScheduler.Register(api.TaskCheckIssueLGTM, checkLGTMExecutor)
 		pitrMySQLExecutor := taskcheck.NewPITRMySQLExecutor(storeInstance, s.dbFactory)
 		s.TaskCheckScheduler.Register(api.TaskCheckPITRMySQL, pitrMySQLExecutor)
+		databaseVerificationExecutor := taskcheck.NewDatabaseVerificationExecutor(storeInstance, s.dbFactory, s.ActivityManager)
+		s.TaskCheckScheduler.Register(api.TaskCheckDatabaseVerification, databaseVerificationExecutor)
 
 		// Anomaly scanner
-		s.AnomalyScanner = anomaly.NewScanner(storeInstance, s.dbFactory, s.licenseService)
+		s.AnomalyScanner = anomaly.NewScanner(storeInstance, s.dbFactory, s.ActivityManager, s.licenseService)
 
 		// Metric reporter
 		s.initMetricReporter(config.workspaceID)
@@ -399,6 +428,9 @@ func NewServer(ctx context.Context, profile config.Profile) (*Server, error) {
 	s.registerWebhookRoutes(webhookGroup)
 
 	apiGroup := e.Group(internalAPIPrefix)
+	apiGroup.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return auditMiddleware(s, next)
+	})
 	apiGroup.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
 		return JWTMiddleware(internalAPIPrefix, s.store, next, profile.Mode, config.secret)
 	})
@@ -407,7 +439,7 @@ func NewServer(ctx context.Context, profile config.Profile) (*Server, error) {
 	if err != nil {
 		return nil, err
 	}
-	sa := scas.NewAdapter(strings.Join([]string{casbinOwnerPolicy, casbinDBAPolicy, casbinDeveloperPolicy}, "\n"))
+	sa := scas.NewAdapter(strings.Join([]string{casbinOwnerPolicy, casbinDBAPolicy, casbinDeveloperPolicy, casbinPermissionPolicy}, "\n"))
 	ce, err := casbin.NewEnforcer(m, sa)
 	if err != nil {
 		return nil, err
@@ -452,8 +484,9 @@ func NewServer(ctx context.Context, profile config.Profile) (*Server, error) {
 	// Setup the gRPC and grpc-gateway.
 	authProvider := auth.New(s.store, s.secret, s.licenseService, profile.Mode)
 	aclProvider := v1.NewACLInterceptor(s.store, s.secret, s.licenseService, profile.Mode)
+	auditProvider := v1.NewAuditInterceptor(s.auditManager)
 	s.grpcServer = grpc.NewServer(
-		grpc.ChainUnaryInterceptor(authProvider.AuthenticationInterceptor, aclProvider.ACLInterceptor),
+		grpc.ChainUnaryInterceptor(auditProvider.AuditInterceptor, authProvider.AuthenticationInterceptor, aclProvider.ACLInterceptor),
 	)
 	v1pb.RegisterAuthServiceServer(s.grpcServer, v1.NewAuthService(s.store, s.secret, s.MetricReporter, &profile,
 		func(ctx context.Context, user *store.UserMessage, firstEndUser bool) error {
@@ -473,6 +506,10 @@ func NewServer(ctx context.Context, profile config.Profile) (*Server, error) {
 	v1pb.RegisterOrgPolicyServiceServer(s.grpcServer, v1.NewOrgPolicyService(s.store, s.licenseService))
 	v1pb.RegisterIdentityProviderServiceServer(s.grpcServer, v1.NewIdentityProviderService(s.store, s.licenseService, &profile))
 	v1pb.RegisterSettingServiceServer(s.grpcServer, v1.NewSettingService(s.store))
+	v1pb.RegisterAnomalyServiceServer(s.grpcServer, v1.NewAnomalyService(s.store))
+	v1pb.RegisterCustomRoleServiceServer(s.grpcServer, v1.NewCustomRoleService(s.store, s.licenseService))
+	v1pb.RegisterApiTokenServiceServer(s.grpcServer, v1.NewAPITokenService(s.store, s.secret, profile.Mode))
+	v1pb.RegisterAuditLogServiceServer(s.grpcServer, v1.NewAuditLogService(s.store))
 	reflection.Register(s.grpcServer)
 
 	// REST gateway proxy.
@@ -509,6 +546,18 @@ func NewServer(ctx context.Context, profile config.Profile) (*Server, error) {
 	if err := v1pb.RegisterSettingServiceHandler(ctx, mux, grpcConn); err != nil {
 		return nil, err
 	}
+	if err := v1pb.RegisterAnomalyServiceHandler(ctx, mux, grpcConn); err != nil {
+		return nil, err
+	}
+	if err := v1pb.RegisterCustomRoleServiceHandler(ctx, mux, grpcConn); err != nil {
+		return nil, err
+	}
+	if err := v1pb.RegisterApiTokenServiceHandler(ctx, mux, grpcConn); err != nil {
+		return nil, err
+	}
+	if err := v1pb.RegisterAuditLogServiceHandler(ctx, mux, grpcConn); err != nil {
+		return nil, err
+	}
 	e.Any("/v1/*", echo.WrapHandler(mux))
 	// GRPC web proxy.
 	options := []grpcweb.Option{
@@ -522,6 +571,8 @@ func NewServer(ctx context.Context, profile config.Profile) (*Server, error) {
 
 	// Register open API routes
 	s.registerOpenAPIRoutes(e, ce, profile)
+	// Register SCIM routes.
+	s.registerSCIMRoutes(e)
 
 	// Register pprof endpoints.
 	pprof.Register(e)
@@ -534,6 +585,9 @@ func NewServer(ctx context.Context, profile config.Profile) (*Server, error) {
 }
 
 func (s *Server) registerOpenAPIRoutes(e *echo.Echo, ce *casbin.Enforcer, prof config.Profile) {
+	auditMiddlewareFunc := func(next echo.HandlerFunc) echo.HandlerFunc {
+		return auditMiddleware(s, next)
+	}
 	jwtMiddlewareFunc := func(next echo.HandlerFunc) echo.HandlerFunc {
 		return JWTMiddleware(openAPIPrefix, s.store, next, prof.Mode, s.secret)
 	}
@@ -545,8 +599,8 @@ func (s *Server) registerOpenAPIRoutes(e *echo.Echo, ce *casbin.Enforcer, prof c
 	}
 	e.POST("/v1/sql/advise", s.sqlCheckController)
 	e.POST("/v1/sql/schema/diff", schemaDiff)
-	e.PATCH("/v1/instances/:instanceName/databases/:database", s.updateInstanceDatabase, jwtMiddlewareFunc, aclMiddlewareFunc, metricMiddlewareFunc)
-	e.POST("/v1/issues", s.createIssueByOpenAPI, jwtMiddlewareFunc, aclMiddlewareFunc, metricMiddlewareFunc)
+	e.PATCH("/v1/instances/:instanceName/databases/:database", s.updateInstanceDatabase, auditMiddlewareFunc, jwtMiddlewareFunc, aclMiddlewareFunc, metricMiddlewareFunc)
+	e.POST("/v1/issues", s.createIssueByOpenAPI, auditMiddlewareFunc, jwtMiddlewareFunc, aclMiddlewareFunc, metricMiddlewareFunc)
 }
 
 // initMetricReporter will initial the metric scheduler.
@@ -642,6 +696,24 @@ func getInitSetting(ctx context.Context, datastore *store.Store) (*workspaceConf
 		return nil, err
 	}
 
+	// initial require MFA setting
+	if _, _, err := datastore.CreateSettingIfNotExistV2(ctx, &store.SettingMessage{
+		Name:        api.SettingWorkspaceRequireMFA,
+		Value:       "0",
+		Description: "Require the Owners and DBAs to enable MFA",
+	}, api.SystemBotID); err != nil {
+		return nil, err
+	}
+
+	// initial max running tasks setting
+	if _, _, err := datastore.CreateSettingIfNotExistV2(ctx, &store.SettingMessage{
+		Name:        api.SettingWorkspaceMaxRunningTasks,
+		Value:       "0",
+		Description: "The maximum number of running tasks in the workspace, 0 means no limit",
+	}, api.SystemBotID); err != nil {
+		return nil, err
+	}
+
 	return conf, nil
 }
 
@@ -649,31 +721,29 @@ func getInitSetting(ctx context.Context, datastore *store.Store) (*workspaceConf
 func (s *Server) Run(ctx context.Context, port int) error {
 	ctx, cancel := context.WithCancel(ctx)
 	s.cancel = cancel
-	if !s.profile.Readonly {
-		if err := s.TaskScheduler.ClearRunningTasks(ctx); err != nil {
-			return errors.Wrap(err, "failed to clear existing RUNNING tasks before starting the task scheduler")
-		}
-		// runnerWG waits for all goroutines to complete.
-		s.runnerWG.Add(1)
-		go s.TaskScheduler.Run(ctx, &s.runnerWG)
+	// The database driver pool is shared by the runners and the API, so it runs in read-only mode as well.
+	s.runnerWG.Add(1)
+	go s.dbFactory.Run(ctx, &s.runnerWG)
+	if s.profile.HA {
 		s.runnerWG.Add(1)
-		go s.TaskCheckScheduler.Run(ctx, &s.runnerWG)
-		s.runnerWG.Add(1)
-		go s.SchemaSyncer.Run(ctx, &s.runnerWG)
-		s.runnerWG.Add(1)
-		go s.BackupRunner.Run(ctx, &s.runnerWG)
-		s.runnerWG.Add(1)
-		go s.AnomalyScanner.Run(ctx, &s.runnerWG)
-		s.runnerWG.Add(1)
-		go s.ApplicationRunner.Run(ctx, &s.runnerWG)
-		if s.profile.Mode == common.ReleaseModeDev {
+		go s.store.RunCacheInvalidationListener(ctx, &s.runnerWG)
+	}
+	if !s.profile.Readonly {
+		if s.profile.HA {
+			// The API requests on any replica may ask for syncing the instance schema.
 			s.runnerWG.Add(1)
-			go s.RollbackRunner.Run(ctx, &s.runnerWG)
-		}
-
-		if s.MetricReporter != nil {
+			go s.SchemaSyncer.RunSyncRequests(ctx, &s.runnerWG)
+			// Only the leader replica runs the background runners, so that the tasks are not run twice.
 			s.runnerWG.Add(1)
-			go s.MetricReporter.Run(ctx, &s.runnerWG)
+			go s.store.RunLeaderElection(ctx, &s.runnerWG, func(ctx context.Context) {
+				var wg sync.WaitGroup
+				if err := s.runRunners(ctx, &wg); err != nil {
+					log.Error("Failed to start the runners", zap.Error(err))
+				}
+				wg.Wait()
+			})
+		} else if err := s.runRunners(ctx, &s.runnerWG); err != nil {
+			return err
 		}
 	}
 
@@ -689,6 +759,38 @@ func (s *Server) Run(ctx context.Context, port int) error {
 	return s.e.Start(fmt.Sprintf(":%d", port))
 }
 
+// runRunners starts the background runners, and they are stopped after ctx is canceled.
+func (s *Server) runRunners(ctx context.Context, wg *sync.WaitGroup) error {
+	if err := s.TaskScheduler.ClearRunningTasks(ctx); err != nil {
+		return errors.Wrap(err, "failed to clear existing RUNNING tasks before starting the task scheduler")
+	}
+	// wg waits for all goroutines to complete.
+	wg.Add(1)
+	go s.TaskScheduler.Run(ctx, wg)
+	wg.Add(1)
+	go s.TaskCheckScheduler.Run(ctx, wg)
+	wg.Add(1)
+	go s.SchemaSyncer.Run(ctx, wg)
+	wg.Add(1)
+	go s.BackupRunner.Run(ctx, wg)
+	wg.Add(1)
+	go s.AnomalyScanner.Run(ctx, wg)
+	wg.Add(1)
+	go s.ApplicationRunner.Run(ctx, wg)
+	wg.Add(1)
+	go s.AccessRunner.Run(ctx, wg)
+	if s.profile.Mode == common.ReleaseModeDev {
+		wg.Add(1)
+		go s.RollbackRunner.Run(ctx, wg)
+	}
+
+	if s.MetricReporter != nil {
+		wg.Add(1)
+		go s.MetricReporter.Run(ctx, wg)
+	}
+	return nil
+}
+
 // Shutdown will shut down the server.
 func (s *Server) Shutdown(ctx context.Context) error {
 	log.Info("Stopping Bytebase...")
@@ -698,6 +800,10 @@ func (s *Server) Shutdown(ctx context.Context) error {
 	if s.MetricReporter != nil {
 		s.MetricReporter.Close()
 	}
+	// Close the audit log exporters.
+	if s.auditManager != nil {
+		defer s.auditManager.Close()
+	}
 
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/google/jsonapi"
 	"github.com/labstack/echo/v4"
@@ -18,6 +19,8 @@ var whitelistSettings = []api.SettingName{
 	api.SettingBrandingLogo,
 	api.SettingAppIM,
 	api.SettingWatermark,
+	api.SettingWorkspaceRequireMFA,
+	api.SettingWorkspaceMaxRunningTasks,
 }
 
 func (s *Server) registerSettingRoutes(g *echo.Group) {
@@ -61,6 +64,16 @@ func (s *Server) registerSettingRoutes(g *echo.Group) {
 			return echo.NewHTTPError(http.StatusBadRequest, "Malformed update setting request").SetInternal(err)
 		}
 
+		if settingPatch.Name == api.SettingWorkspaceRequireMFA && settingPatch.Value != "0" && settingPatch.Value != "1" {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid setting value %q for %s, expect \"0\" or \"1\"", settingPatch.Value, settingPatch.Name))
+		}
+
+		if settingPatch.Name == api.SettingWorkspaceMaxRunningTasks {
+			if v, err := strconv.Atoi(settingPatch.Value); err != nil || v < 0 {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid setting value %q for %s, expect a non-negative integer", settingPatch.Value, settingPatch.Name))
+			}
+		}
+
 		if settingPatch.Name == api.SettingAppIM {
 			var value api.SettingAppIMValue
 			if err := json.Unmarshal([]byte(settingPatch.Value), &value); err != nil {
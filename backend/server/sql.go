@@ -6,7 +6,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"regexp"
 	"strings"
 	"time"
 
@@ -18,6 +17,7 @@ import (
 	"github.com/bytebase/bytebase/backend/common"
 	"github.com/bytebase/bytebase/backend/common/log"
 	"github.com/bytebase/bytebase/backend/component/activity"
+	"github.com/bytebase/bytebase/backend/component/dbfactory"
 	api "github.com/bytebase/bytebase/backend/legacyapi"
 	"github.com/bytebase/bytebase/backend/plugin/advisor"
 	"github.com/bytebase/bytebase/backend/plugin/advisor/catalog"
@@ -40,12 +40,21 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
 		}
 
-		password := connectionInfo.Password
-		// Instance detail page has a Test Connection button, if user doesn't input new password and doesn't specify
-		// to use empty password, we want the connection to use the existing password to test the connection, however,
-		// we do not transfer the password back to client, thus the client will pass the instanceID to let server
-		// retrieve the password.
+		// The secret references are only resolved from the stored data sources, otherwise the caller could send the resolved credentials to any host.
+		if connectionInfo.PasswordSecretRef != "" || connectionInfo.SslKeySecretRef != "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "Secret references are only resolved from the stored data source, save the instance before testing the connection")
+		}
+		role := c.Get(getRoleContextKey()).(api.Role)
+		canResolveSecret := role == api.Owner || role == api.DBA
+
+		// The errors of resolving the credentials from the external secret provider are returned as the ping result.
+		var resolveErr error
+		username, password := connectionInfo.Username, connectionInfo.Password
 		if password == "" && !connectionInfo.UseEmptyPassword && connectionInfo.InstanceID != nil {
+			// Instance detail page has a Test Connection button, if user doesn't input new password and doesn't specify
+			// to use empty password, we want the connection to use the existing password to test the connection, however,
+			// we do not transfer the password back to client, thus the client will pass the instanceID to let server
+			// retrieve the password.
 			instance, err := s.store.GetInstanceV2(ctx, &store.FindInstanceMessage{UID: connectionInfo.InstanceID})
 			if err != nil {
 				return err
@@ -55,10 +64,14 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 			}
 			for _, ds := range instance.DataSources {
 				if ds.Type == api.Admin {
-					password, err = common.Unobfuscate(ds.ObfuscatedPassword, s.secret)
-					if err != nil {
-						return err
+					if ds.PasswordSecretRef != "" && !canResolveSecret {
+						return echo.NewHTTPError(http.StatusForbidden, "Only workspace owners and DBAs can test the connection with the password in the external secret provider")
 					}
+					username, password, resolveErr = s.dbFactory.GetDataSourcePassword(ctx, &store.DataSourceMessage{
+						Username:           connectionInfo.Username,
+						ObfuscatedPassword: ds.ObfuscatedPassword,
+						PasswordSecretRef:  ds.PasswordSecretRef,
+					})
 					break
 				}
 			}
@@ -77,14 +90,17 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 					return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("instance %d not found", *connectionInfo.InstanceID))
 				}
 				for _, ds := range instance.DataSources {
-					if ds.ObfuscatedSslCa != "" || ds.ObfuscatedSslCert != "" || ds.ObfuscatedSslKey != "" {
+					if ds.ObfuscatedSslCa != "" || ds.ObfuscatedSslCert != "" || ds.ObfuscatedSslKey != "" || ds.SslKeySecretRef != "" {
+						if ds.SslKeySecretRef != "" && !canResolveSecret {
+							return echo.NewHTTPError(http.StatusForbidden, "Only workspace owners and DBAs can test the connection with the SSL key in the external secret provider")
+						}
 						sslCa, err := common.Unobfuscate(ds.ObfuscatedSslCa, s.secret)
 						if err != nil {
 							return err
 						}
-						sslKey, err := common.Unobfuscate(ds.ObfuscatedSslKey, s.secret)
+						sslKey, err := s.dbFactory.GetDataSourceSslKey(ctx, ds)
 						if err != nil {
-							return err
+							resolveErr = err
 						}
 						sslCert, err := common.Unobfuscate(ds.ObfuscatedSslCert, s.secret)
 						if err != nil {
@@ -111,35 +127,28 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 			}
 		}
 
-		db, err := db.Open(
-			ctx,
-			connectionInfo.Engine,
-			db.DriverConfig{},
-			db.ConnectionConfig{
-				Username:               connectionInfo.Username,
+		sshConfig, err := s.getConnectionSSHConfig(ctx, connectionInfo, canResolveSecret)
+		if err != nil {
+			return err
+		}
+		resultSet := &api.SQLResultSet{}
+		host, port := connectionInfo.Host, connectionInfo.Port
+		if resolveErr == nil && sshConfig != nil {
+			host, port, resolveErr = s.dbFactory.GetTunnelHostPort(*sshConfig, host, port)
+		}
+		if resolveErr != nil {
+			resultSet.Error = resolveErr.Error()
+		} else {
+			resultSet.Error = s.pingConnection(ctx, connectionInfo, db.ConnectionConfig{
+				Username:               username,
 				Password:               password,
-				Host:                   connectionInfo.Host,
-				Port:                   connectionInfo.Port,
+				Host:                   host,
+				Port:                   port,
 				TLSConfig:              tlsConfig,
 				SRV:                    connectionInfo.SRV,
 				AuthenticationDatabase: connectionInfo.AuthenticationDatabase,
 				Database:               connectionInfo.Database,
-			},
-			db.ConnectionContext{},
-		)
-
-		resultSet := &api.SQLResultSet{}
-		if err != nil {
-			hostPort := connectionInfo.Host
-			if connectionInfo.Port != "" {
-				hostPort += ":" + connectionInfo.Port
-			}
-			resultSet.Error = errors.Wrapf(err, "failed to connect %q for user %q", hostPort, connectionInfo.Username).Error()
-		} else {
-			defer db.Close(ctx)
-			if err := db.Ping(ctx); err != nil {
-				resultSet.Error = err.Error()
-			}
+			})
 		}
 
 		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
@@ -214,7 +223,7 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 		if !exec.Readonly {
 			return echo.NewHTTPError(http.StatusBadRequest, "Malformed sql execute request, only support readonly sql statement")
 		}
-		if !validateSQLSelectStatement(exec.Statement) {
+		if !util.ValidateSQLSelectStatement(exec.Statement) {
 			return echo.NewHTTPError(http.StatusBadRequest, "Malformed sql execute request, only support SELECT sql statement")
 		}
 
@@ -235,6 +244,8 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 		principalID := c.Get(getPrincipalIDContextKey()).(int)
 		role := c.Get(getRoleContextKey()).(api.Role)
 		var database *store.DatabaseMessage
+		// The queries allowed by the temporary access grant are linked to the database access request.
+		var accessGrantID, accessRequestIssueID int
 		if exec.DatabaseName != "" {
 			database, err = s.store.GetDatabaseV2(ctx, &store.FindDatabaseMessage{EnvironmentID: &instance.EnvironmentID, InstanceID: &instance.ResourceID, DatabaseName: &exec.DatabaseName})
 			if err != nil {
@@ -249,7 +260,14 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to check access control for database: %q", exec.DatabaseName)).SetInternal(err)
 			}
 			if !hasAccessRights {
-				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformed sql execute request, no permission to access database %q", exec.DatabaseName))
+				accessGrant, err := s.getQueryAccessGrant(ctx, principalID, database, instance.Engine, exec.Statement)
+				if err != nil {
+					return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to check access grants for database: %q", exec.DatabaseName)).SetInternal(err)
+				}
+				if accessGrant == nil {
+					return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformed sql execute request, no permission to access database %q", exec.DatabaseName))
+				}
+				accessGrantID, accessRequestIssueID = accessGrant.UID, accessGrant.IssueID
 			}
 		}
 
@@ -356,6 +374,8 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 					DatabaseName:           exec.DatabaseName,
 					Error:                  "",
 					AdviceList:             adviceList,
+					AccessGrantID:          accessGrantID,
+					AccessRequestIssueID:   accessRequestIssueID,
 				}); err != nil {
 					return err
 				}
@@ -460,6 +480,8 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 			DatabaseName:           exec.DatabaseName,
 			Error:                  errMessage,
 			AdviceList:             adviceList,
+			AccessGrantID:          accessGrantID,
+			AccessRequestIssueID:   accessRequestIssueID,
 		}); err != nil {
 			return err
 		}
@@ -599,31 +621,6 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 	})
 }
 
-func validateSQLSelectStatement(sqlStatement string) bool {
-	// Check if the query has only one statement.
-	count := 0
-	if err := util.ApplyMultiStatements(strings.NewReader(sqlStatement), func(_ string) error {
-		count++
-		return nil
-	}); err != nil {
-		return false
-	}
-	if count != 1 {
-		return false
-	}
-
-	// Allow SELECT and EXPLAIN queries only.
-	whiteListRegs := []string{`^SELECT\s+?`, `^EXPLAIN\s+?`, `^WITH\s+?`}
-	formattedStr := strings.ToUpper(strings.TrimSpace(sqlStatement))
-	for _, reg := range whiteListRegs {
-		matchResult, _ := regexp.MatchString(reg, formattedStr)
-		if matchResult {
-			return true
-		}
-	}
-	return false
-}
-
 func (s *Server) createSQLEditorQueryActivity(ctx context.Context, c echo.Context, level api.ActivityLevel, containerID int, payload api.ActivitySQLEditorQueryPayload) error {
 	activityBytes, err := json.Marshal(payload)
 	if err != nil {
@@ -848,7 +845,6 @@ func (s *Server) hasDatabaseAccessRights(ctx context.Context, principalID int, r
 	if role == api.Owner || role == api.DBA {
 		return true, nil
 	}
-
 	project, err := s.store.GetProjectV2(ctx, &store.FindProjectMessage{ResourceID: &database.ProjectID})
 	if err != nil {
 		return false, err
@@ -863,9 +859,18 @@ func (s *Server) hasDatabaseAccessRights(ctx context.Context, principalID int, r
 	if err != nil {
 		return false, err
 	}
-	if !hasActiveProjectMembership(principalID, projectPolicy) {
+	projectRole := getProjectRole(principalID, projectPolicy)
+	if projectRole == "" {
 		return false, nil
 	}
+	// The custom project roles granted the query permission can access the databases in the project regardless of the access control policies.
+	canQuery, err := s.store.HasProjectPermission(ctx, s.licenseService.GetEffectivePlan(), projectRole, api.ProjectPermissionQueryDatabase)
+	if err != nil {
+		return false, err
+	}
+	if canQuery {
+		return true, nil
+	}
 
 	// calculate the effective policy.
 	databasePolicy, inheritFromEnvironment, err := s.store.GetAccessControlPolicy(ctx, api.PolicyResourceTypeDatabase, database.UID)
@@ -899,3 +904,83 @@ func (s *Server) hasDatabaseAccessRights(ctx context.Context, principalID int, r
 	}
 	return hasAccessRights, nil
 }
+
+// pingConnection pings the database with the connection config, and returns the error message if it fails.
+func (*Server) pingConnection(ctx context.Context, connectionInfo *api.ConnectionInfo, connectionConfig db.ConnectionConfig) string {
+	driver, err := db.Open(ctx, connectionInfo.Engine, db.DriverConfig{}, connectionConfig, db.ConnectionContext{})
+	if err != nil {
+		hostPort := connectionInfo.Host
+		if connectionInfo.Port != "" {
+			hostPort += ":" + connectionInfo.Port
+		}
+		return errors.Wrapf(err, "failed to connect %q for user %q", hostPort, connectionInfo.Username).Error()
+	}
+	defer driver.Close(ctx)
+	if err := driver.Ping(ctx); err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+// getConnectionSSHConfig returns the SSH tunnel config for testing the connection, or nil if the connection is not tunneled.
+// Similar to the password, we use the SSH credentials and the host key of the instance admin data source if the user doesn't input new ones.
+func (s *Server) getConnectionSSHConfig(ctx context.Context, connectionInfo *api.ConnectionInfo, canUseStoredCredential bool) (*dbfactory.SSHConfig, error) {
+	if connectionInfo.SSHHost == "" {
+		return nil, nil
+	}
+	sshConfig := &dbfactory.SSHConfig{
+		Host:       connectionInfo.SSHHost,
+		Port:       connectionInfo.SSHPort,
+		User:       connectionInfo.SSHUser,
+		Password:   connectionInfo.SSHPassword,
+		PrivateKey: connectionInfo.SSHPrivateKey,
+		HostKey:    connectionInfo.SSHHostKey,
+	}
+	hasCredential := sshConfig.Password != "" || sshConfig.PrivateKey != ""
+	if (hasCredential && sshConfig.HostKey != "") || connectionInfo.InstanceID == nil {
+		return sshConfig, nil
+	}
+	instance, err := s.store.GetInstanceV2(ctx, &store.FindInstanceMessage{UID: connectionInfo.InstanceID})
+	if err != nil {
+		return nil, err
+	}
+	if instance == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("instance %d not found", *connectionInfo.InstanceID))
+	}
+	for _, ds := range instance.DataSources {
+		if ds.Type == api.Admin {
+			if err := fillStoredSSHConfig(sshConfig, ds, s.secret, canUseStoredCredential); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+	return sshConfig, nil
+}
+
+// fillStoredSSHConfig fills the SSH credentials and the host key of the stored data source into the SSH config if the user doesn't input new ones.
+// The stored credentials are only sent to the stored SSH server with the stored host key, otherwise the caller could harvest them with an SSH server
+// of their own. Only workspace owners and DBAs can test the connection to another SSH server with the stored credentials.
+func fillStoredSSHConfig(sshConfig *dbfactory.SSHConfig, ds *store.DataSourceMessage, secret string, canUseStoredCredential bool) error {
+	if sshConfig.Password != "" || sshConfig.PrivateKey != "" {
+		if sshConfig.HostKey == "" {
+			sshConfig.HostKey = ds.SSHHostKey
+		}
+		return nil
+	}
+	if sshConfig.Host == ds.SSHHost && sshConfig.Port == ds.SSHPort && sshConfig.User == ds.SSHUser {
+		sshConfig.HostKey = ds.SSHHostKey
+	} else if !canUseStoredCredential {
+		return echo.NewHTTPError(http.StatusForbidden, "Only workspace owners and DBAs can test the connection to another SSH server with the stored SSH credentials")
+	} else if sshConfig.HostKey == "" {
+		sshConfig.HostKey = ds.SSHHostKey
+	}
+	var err error
+	if sshConfig.Password, err = common.Unobfuscate(ds.SSHObfuscatedPassword, secret); err != nil {
+		return err
+	}
+	if sshConfig.PrivateKey, err = common.Unobfuscate(ds.SSHObfuscatedPrivateKey, secret); err != nil {
+		return err
+	}
+	return nil
+}
@@ -2,95 +2,69 @@ package server
 
 import (
 	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/backend/common"
+	"github.com/bytebase/bytebase/backend/component/dbfactory"
+	"github.com/bytebase/bytebase/backend/store"
 )
 
-func TestValidateSQLSelectStatement(t *testing.T) {
+func TestFillStoredSSHConfig(t *testing.T) {
+	const secret = "secret"
+	ds := &store.DataSourceMessage{
+		SSHHost:                 "bastion.example.com",
+		SSHPort:                 "22",
+		SSHUser:                 "bytebase",
+		SSHObfuscatedPassword:   common.Obfuscate("stored-password", secret),
+		SSHObfuscatedPrivateKey: common.Obfuscate("stored-key", secret),
+		SSHHostKey:              "stored-host-key",
+	}
 	tests := []struct {
-		sqlStatement string
-		want         bool
+		sshConfig              dbfactory.SSHConfig
+		canUseStoredCredential bool
+		want                   dbfactory.SSHConfig
+		wantErr                bool
 	}{
+		// The stored credentials are sent to the stored SSH server with the stored host key.
 		{
-			sqlStatement: "  seLeCT * FROM test",
-			want:         true,
-		},
-		{
-			sqlStatement: "  \n \r SELEct * from test ",
-			want:         true,
-		},
-		{
-			sqlStatement: "SELECT\n*\nFROM\ntest",
-			want:         true,
-		},
-		{
-			sqlStatement: "SELECT * FROM test",
-			want:         true,
-		},
-		{
-			sqlStatement: "select *",
-			want:         true,
-		},
-		{
-			sqlStatement: "select ",
-			want:         false,
-		},
-		{
-			sqlStatement: "select",
-			want:         false,
-		},
-		{
-			sqlStatement: "explain select",
-			want:         true,
-		},
-		{
-			sqlStatement: "explain \n select",
-			want:         true,
-		},
-		{
-			sqlStatement: "\n explain \n \r  select",
-			want:         true,
-		},
-		{
-			sqlStatement: "explain select *",
-			want:         true,
-		},
-		{
-			sqlStatement: "  explain select ",
-			want:         true,
-		},
-		{
-			sqlStatement: "asd  explain selectasd ",
-			want:         false,
-		},
-		{
-			sqlStatement: "SELECTfoo",
-			want:         false,
-		},
-		{
-			sqlStatement: "insert into asd",
-			want:         false,
+			sshConfig:              dbfactory.SSHConfig{Host: "bastion.example.com", Port: "22", User: "bytebase", HostKey: "caller-host-key"},
+			canUseStoredCredential: false,
+			want:                   dbfactory.SSHConfig{Host: "bastion.example.com", Port: "22", User: "bytebase", Password: "stored-password", PrivateKey: "stored-key", HostKey: "stored-host-key"},
 		},
+		// The stored credentials are not sent to another SSH server for the developers.
 		{
-			sqlStatement: "SETEST * FROM test",
-			want:         false,
+			sshConfig:              dbfactory.SSHConfig{Host: "evil.example.com", Port: "22", User: "bytebase", HostKey: "evil-host-key"},
+			canUseStoredCredential: false,
+			wantErr:                true,
 		},
 		{
-			sqlStatement: " asdexplain selectasd ",
-			want:         false,
+			sshConfig:              dbfactory.SSHConfig{Host: "bastion.example.com", Port: "2222", User: "bytebase"},
+			canUseStoredCredential: false,
+			wantErr:                true,
 		},
+		// The owners and DBAs can test the connection to another SSH server with the stored credentials.
 		{
-			sqlStatement: "",
-			want:         false,
+			sshConfig:              dbfactory.SSHConfig{Host: "bastion2.example.com", Port: "22", User: "bytebase", HostKey: "new-host-key"},
+			canUseStoredCredential: true,
+			want:                   dbfactory.SSHConfig{Host: "bastion2.example.com", Port: "22", User: "bytebase", Password: "stored-password", PrivateKey: "stored-key", HostKey: "new-host-key"},
 		},
+		// The credentials input by the caller are used as is.
 		{
-			sqlStatement: "SETEST 1; INSERT INTO tbl(num) VALUES(113);",
-			want:         false,
+			sshConfig:              dbfactory.SSHConfig{Host: "evil.example.com", Port: "22", User: "bytebase", Password: "caller-password"},
+			canUseStoredCredential: false,
+			want:                   dbfactory.SSHConfig{Host: "evil.example.com", Port: "22", User: "bytebase", Password: "caller-password", HostKey: "stored-host-key"},
 		},
 	}
 
 	for _, test := range tests {
-		result := validateSQLSelectStatement(test.sqlStatement)
-		if result != test.want {
-			t.Errorf("Validate SQLStatement %q: got result %v, want %v.", test.sqlStatement, result, test.want)
+		sshConfig := test.sshConfig
+		err := fillStoredSSHConfig(&sshConfig, ds, secret, test.canUseStoredCredential)
+		if test.wantErr {
+			require.Error(t, err)
+			continue
 		}
+		require.NoError(t, err)
+		require.Equal(t, test.want, sshConfig)
 	}
 }
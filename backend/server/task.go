@@ -1,15 +1,18 @@
 package server
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/google/jsonapi"
 	"github.com/labstack/echo/v4"
 
 	"github.com/bytebase/bytebase/backend/common"
 	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/db"
 	"github.com/bytebase/bytebase/backend/store"
 	"github.com/bytebase/bytebase/backend/utils"
 )
@@ -118,6 +121,101 @@ func (s *Server) registerTaskRoutes(g *echo.Group) {
 		return nil
 	})
 
+	g.PATCH("/pipeline/:pipelineID/task/:taskID/chunk-config", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		taskID, err := strconv.Atoi(c.Param("taskID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Task ID is not a number: %s", c.Param("taskID"))).SetInternal(err)
+		}
+
+		currentPrincipalID := c.Get(getPrincipalIDContextKey()).(int)
+		chunkConfigPatch := &api.TaskChunkConfigPatch{
+			ID:        taskID,
+			UpdaterID: currentPrincipalID,
+		}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, chunkConfigPatch); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed update task chunk config request").SetInternal(err)
+		}
+
+		task, err := s.store.GetTaskV2ByID(ctx, taskID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to update task chunk config").SetInternal(err)
+		}
+		if task == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Task not found with ID %d", taskID))
+		}
+		if task.Type != api.TaskDatabaseDataUpdate {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Chunked execution is only supported for %s task", api.TaskDatabaseDataUpdate))
+		}
+		if task.Status == api.TaskDone || task.Status == api.TaskCanceled {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Cannot update the chunk config of task with status %s", task.Status))
+		}
+		// The principal who can run the task can pause and resume it.
+		ok, err := s.TaskScheduler.CanPrincipalChangeTaskStatus(ctx, currentPrincipalID, task, api.TaskRunning)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to validate if the principal can change task status").SetInternal(err)
+		}
+		if !ok {
+			return echo.NewHTTPError(http.StatusUnauthorized, "Not allowed to update the task chunk config")
+		}
+		instance, err := s.store.GetInstanceV2(ctx, &store.FindInstanceMessage{UID: &task.InstanceID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch instance").SetInternal(err)
+		}
+		if instance.Engine != db.MySQL && instance.Engine != db.Postgres {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Chunked execution is not supported for %s", instance.Engine))
+		}
+
+		payload := &api.TaskDatabaseDataUpdatePayload{}
+		if err := json.Unmarshal([]byte(task.Payload), payload); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Invalid database data update payload").SetInternal(err)
+		}
+		chunkConfig := payload.ChunkConfig
+		if chunkConfig == nil {
+			if task.Status == api.TaskRunning {
+				return echo.NewHTTPError(http.StatusBadRequest, "Cannot enable the chunked execution for the running task")
+			}
+			chunkConfig = &api.DataUpdateChunkConfig{ChunkSize: api.DefaultDataUpdateChunkSize}
+		}
+		if v := chunkConfigPatch.ChunkSize; v != nil {
+			chunkConfig.ChunkSize = *v
+		}
+		if v := chunkConfigPatch.SleepMs; v != nil {
+			chunkConfig.SleepMs = *v
+		}
+		if v := chunkConfigPatch.MaxReplicationLagSeconds; v != nil {
+			chunkConfig.MaxReplicationLagSeconds = *v
+		}
+		if v := chunkConfigPatch.Paused; v != nil {
+			chunkConfig.Paused = *v
+		}
+		if err := chunkConfig.Validate(); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		chunkConfigBytes, err := json.Marshal(chunkConfig)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal task chunk config").SetInternal(err)
+		}
+		chunkConfigString := string(chunkConfigBytes)
+		if _, err := s.store.UpdateTaskV2(ctx, &api.TaskPatch{
+			ID:          task.ID,
+			UpdaterID:   currentPrincipalID,
+			ChunkConfig: &chunkConfigString,
+		}); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to update task chunk config").SetInternal(err)
+		}
+		composedTask, err := s.store.GetTaskByID(ctx, task.ID)
+		if err != nil {
+			return err
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, composedTask); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal update task \"%v\" chunk config response", task.Name)).SetInternal(err)
+		}
+		return nil
+	})
+
 	g.PATCH("/pipeline/:pipelineID/task/:taskID/status", func(c echo.Context) error {
 		ctx := c.Request().Context()
 		taskID, err := strconv.Atoi(c.Param("taskID"))
@@ -241,4 +339,126 @@ func (s *Server) registerTaskRoutes(g *echo.Group) {
 		}
 		return nil
 	})
+
+	g.GET("/pipeline/:pipelineID/task/:taskID/task-run/:taskRunID/log", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		taskRun, err := s.getTaskRunByParam(c)
+		if err != nil {
+			return err
+		}
+
+		logs, err := s.store.ListTaskRunLogs(ctx, &store.FindTaskRunLogMessage{TaskRunID: taskRun.ID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to list logs of task run %d", taskRun.ID)).SetInternal(err)
+		}
+		taskRunLogList := []*api.TaskRunLog{}
+		for _, log := range logs {
+			taskRunLogList = append(taskRunLogList, convertToTaskRunLog(log))
+		}
+		return c.JSON(http.StatusOK, taskRunLogList)
+	})
+
+	// Streams the logs of the task run as server-sent events until the task run finishes.
+	// Clients reconnecting with the Last-Event-ID header only receive the logs after the last received one.
+	g.GET("/pipeline/:pipelineID/task/:taskID/task-run/:taskRunID/log/stream", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		taskRun, err := s.getTaskRunByParam(c)
+		if err != nil {
+			return err
+		}
+		var afterID *int64
+		if lastEventID := c.Request().Header.Get("Last-Event-ID"); lastEventID != "" {
+			id, err := strconv.ParseInt(lastEventID, 10, 64)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Last-Event-ID is not a number: %s", lastEventID)).SetInternal(err)
+			}
+			afterID = &id
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, "text/event-stream")
+		c.Response().Header().Set(echo.HeaderCacheControl, "no-cache")
+		c.Response().WriteHeader(http.StatusOK)
+
+		ticker := time.NewTicker(taskRunLogStreamInterval)
+		defer ticker.Stop()
+		for {
+			// Check the status before listing the logs so that no log is missed after the task run finishes.
+			taskRuns, err := s.store.ListTaskRunsV2(ctx, &store.TaskRunFind{TaskID: &taskRun.TaskID})
+			if err != nil {
+				return err
+			}
+			running := false
+			for _, run := range taskRuns {
+				if run.ID == taskRun.ID {
+					running = run.Status == api.TaskRunRunning
+					break
+				}
+			}
+
+			logs, err := s.store.ListTaskRunLogs(ctx, &store.FindTaskRunLogMessage{TaskRunID: taskRun.ID, AfterID: afterID})
+			if err != nil {
+				return err
+			}
+			for _, log := range logs {
+				data, err := json.Marshal(convertToTaskRunLog(log))
+				if err != nil {
+					return err
+				}
+				if _, err := fmt.Fprintf(c.Response(), "id: %d\nevent: log\ndata: %s\n\n", log.ID, data); err != nil {
+					return err
+				}
+				id := log.ID
+				afterID = &id
+			}
+			if !running {
+				if _, err := fmt.Fprint(c.Response(), "event: end\ndata: {}\n\n"); err != nil {
+					return err
+				}
+				c.Response().Flush()
+				return nil
+			}
+			c.Response().Flush()
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+			}
+		}
+	})
+}
+
+// taskRunLogStreamInterval is the interval to poll the new logs of a running task run.
+const taskRunLogStreamInterval = 1 * time.Second
+
+// getTaskRunByParam gets the task run by the taskID and taskRunID path parameters.
+func (s *Server) getTaskRunByParam(c echo.Context) (*store.TaskRunMessage, error) {
+	ctx := c.Request().Context()
+	taskID, err := strconv.Atoi(c.Param("taskID"))
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Task ID is not a number: %s", c.Param("taskID"))).SetInternal(err)
+	}
+	taskRunID, err := strconv.Atoi(c.Param("taskRunID"))
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Task run ID is not a number: %s", c.Param("taskRunID"))).SetInternal(err)
+	}
+	taskRuns, err := s.store.ListTaskRunsV2(ctx, &store.TaskRunFind{TaskID: &taskID})
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to list task runs of task %d", taskID)).SetInternal(err)
+	}
+	for _, taskRun := range taskRuns {
+		if taskRun.ID == taskRunID {
+			return taskRun, nil
+		}
+	}
+	return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Task run %d not found in task %d", taskRunID, taskID))
+}
+
+func convertToTaskRunLog(log *store.TaskRunLogMessage) *api.TaskRunLog {
+	return &api.TaskRunLog{
+		ID:        log.ID,
+		TaskRunID: log.TaskRunID,
+		CreatedTs: log.CreatedTs,
+		Payload:   log.Payload,
+	}
 }
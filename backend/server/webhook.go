@@ -161,6 +161,11 @@ func (s *Server) registerWebhookRoutes(g *echo.Group) {
 		if err := json.Unmarshal(body, &request); err != nil {
 			return echo.NewHTTPError(http.StatusBadRequest, "Malformed SQL review request").SetInternal(err)
 		}
+		if request.Format != "" {
+			if err := request.Format.Validate(); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			}
+		}
 
 		filter := func(repo *api.Repository) (bool, error) {
 			if !repo.EnableSQLReviewCI {
@@ -258,11 +263,24 @@ func (s *Server) registerWebhookRoutes(g *echo.Group) {
 		wg.Wait()
 
 		response := &api.VCSSQLReviewResult{}
-		switch repo.VCS.Type {
-		case vcs.GitHubCom:
+		switch {
+		case request.Format != "":
+			report, err := advisor.GenerateReport(request.Format, sqlCheckAdvice, advisor.ReportContext{DocURL: sqlReviewDocs})
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate SQL review report").SetInternal(err)
+			}
+			response = &api.VCSSQLReviewResult{
+				Status:  advisor.GetReportStatus(sqlCheckAdvice),
+				Content: []string{string(report)},
+			}
+		case repo.VCS.Type == vcs.GitHubCom:
 			response = convertSQLAdviceToGitHubActionResult(sqlCheckAdvice)
-		case vcs.GitLabSelfHost:
-			response = convertSQLAdviceToGitLabCIResult(sqlCheckAdvice)
+		case repo.VCS.Type == vcs.GitLabSelfHost:
+			result, err := convertSQLAdviceToGitLabCIResult(sqlCheckAdvice)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate SQL review report").SetInternal(err)
+			}
+			response = result
 		}
 
 		log.Debug("SQL review finished",
@@ -1214,71 +1232,15 @@ func (s *Server) tryUpdateTasksFromModifiedFile(ctx context.Context, databases [
 // convertSQLAdviceToGitLabCIResult will convert SQL advice map to GitLab test output format.
 // GitLab test report: https://docs.gitlab.com/ee/ci/testing/unit_test_reports.html
 // junit XML format: https://llg.cubic.org/docs/junit/
-// nolint:unused
-func convertSQLAdviceToGitLabCIResult(adviceMap map[string][]advisor.Advice) *api.VCSSQLReviewResult {
-	testsuiteList := []string{}
-	status := advisor.Success
-
-	fileList := []string{}
-	for filePath := range adviceMap {
-		fileList = append(fileList, filePath)
-	}
-	sort.Strings(fileList)
-
-	for _, filePath := range fileList {
-		adviceList := adviceMap[filePath]
-		testcaseList := []string{}
-		for _, advice := range adviceList {
-			if advice.Code == 0 {
-				continue
-			}
-
-			line := advice.Line
-			if line <= 0 {
-				line = 1
-			}
-
-			if advice.Status == advisor.Error {
-				status = advice.Status
-			} else if advice.Status == advisor.Warn && status != advisor.Error {
-				status = advice.Status
-			}
-
-			content := fmt.Sprintf("Error: %s.\nYou can check the docs at %s#%d",
-				advice.Content,
-				sqlReviewDocs,
-				advice.Code,
-			)
-
-			testcase := fmt.Sprintf(
-				"<testcase name=\"%s\" classname=\"%s\" file=\"%s#L%d\">\n<failure>\n%s\n</failure>\n</testcase>",
-				advice.Title,
-				filePath,
-				filePath,
-				line,
-				content,
-			)
-
-			testcaseList = append(testcaseList, testcase)
-		}
-
-		if len(testcaseList) > 0 {
-			testsuiteList = append(
-				testsuiteList,
-				fmt.Sprintf("<testsuite name=\"%s\">\n%s\n</testsuite>", filePath, strings.Join(testcaseList, "\n")),
-			)
-		}
+func convertSQLAdviceToGitLabCIResult(adviceMap map[string][]advisor.Advice) (*api.VCSSQLReviewResult, error) {
+	report, err := advisor.GenerateReport(advisor.ReportFormatJUnit, adviceMap, advisor.ReportContext{DocURL: sqlReviewDocs})
+	if err != nil {
+		return nil, err
 	}
-
 	return &api.VCSSQLReviewResult{
-		Status: status,
-		Content: []string{
-			fmt.Sprintf(
-				"<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<testsuites name=\"SQL Review\">\n%s\n</testsuites>",
-				strings.Join(testsuiteList, "\n"),
-			),
-		},
-	}
+		Status:  advisor.GetReportStatus(adviceMap),
+		Content: []string{string(report)},
+	}, nil
 }
 
 // convertSQLAdviceToGitHubActionResult will convert SQL advice map to GitHub action output format.
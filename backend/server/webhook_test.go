@@ -135,37 +135,26 @@ var mockSQLAdviceMap = map[string][]advisor.Advice{
 func TestVCSSQLReview_ConvertSQLAdviceToGitLabCIResult(t *testing.T) {
 	expect :=
 		`<?xml version="1.0" encoding="UTF-8"?>
-<testsuites name="SQL Review">
-<testsuite name="file1.sql">
-<testcase name="column.no-null" classname="file1.sql" file="file1.sql#L1">
-<failure>
-Error: Column "id" in "public"."book" cannot have NULL value.
-You can check the docs at https://www.bytebase.com/docs/reference/error-code/advisor#402
-</failure>
-</testcase>
-<testcase name="naming.index.idx" classname="file1.sql" file="file1.sql#L2">
-<failure>
-Error: Index in table "tech_book" mismatches the naming convention, expect "^$|^idx_tech_book_id_name$" but found "tech_book_id_name".
-You can check the docs at https://www.bytebase.com/docs/reference/error-code/advisor#303
-</failure>
-</testcase>
-</testsuite>
-<testsuite name="file2.sql">
-<testcase name="naming.table" classname="file2.sql" file="file2.sql#L1">
-<failure>
-Error: "techBook" mismatches table naming convention, naming format should be "^[a-z]+(_[a-z]+)*$".
-You can check the docs at https://www.bytebase.com/docs/reference/error-code/advisor#301
-</failure>
-</testcase>
-<testcase name="naming.index.uk" classname="file2.sql" file="file2.sql#L4">
-<failure>
-Error: Unique key in table "tech_book" mismatches the naming convention, expect "^$|^uk_tech_book_id_name$" but found "tech_book_id_name".
-You can check the docs at https://www.bytebase.com/docs/reference/error-code/advisor#304
-</failure>
-</testcase>
-</testsuite>
+<testsuites name="Bytebase SQL Review" tests="4" failures="4">
+  <testsuite name="file1.sql" tests="2" failures="2">
+    <testcase name="column.no-null" classname="file1.sql" file="file1.sql" line="1">
+      <failure message="Column &#34;id&#34; in &#34;public&#34;.&#34;book&#34; cannot have NULL value" type="WARN">WARN: Column &#34;id&#34; in &#34;public&#34;.&#34;book&#34; cannot have NULL value.&#xA;You can check the docs at https://www.bytebase.com/docs/reference/error-code/advisor#402</failure>
+    </testcase>
+    <testcase name="naming.index.idx" classname="file1.sql" file="file1.sql" line="2">
+      <failure message="Index in table &#34;tech_book&#34; mismatches the naming convention, expect &#34;^$|^idx_tech_book_id_name$&#34; but found &#34;tech_book_id_name&#34;" type="ERROR">ERROR: Index in table &#34;tech_book&#34; mismatches the naming convention, expect &#34;^$|^idx_tech_book_id_name$&#34; but found &#34;tech_book_id_name&#34;.&#xA;You can check the docs at https://www.bytebase.com/docs/reference/error-code/advisor#303</failure>
+    </testcase>
+  </testsuite>
+  <testsuite name="file2.sql" tests="2" failures="2">
+    <testcase name="naming.table" classname="file2.sql" file="file2.sql" line="1">
+      <failure message="&#34;techBook&#34; mismatches table naming convention, naming format should be &#34;^[a-z]+(_[a-z]+)*$&#34;" type="WARN">WARN: &#34;techBook&#34; mismatches table naming convention, naming format should be &#34;^[a-z]+(_[a-z]+)*$&#34;.&#xA;You can check the docs at https://www.bytebase.com/docs/reference/error-code/advisor#301</failure>
+    </testcase>
+    <testcase name="naming.index.uk" classname="file2.sql" file="file2.sql" line="4">
+      <failure message="Unique key in table &#34;tech_book&#34; mismatches the naming convention, expect &#34;^$|^uk_tech_book_id_name$&#34; but found &#34;tech_book_id_name&#34;" type="ERROR">ERROR: Unique key in table &#34;tech_book&#34; mismatches the naming convention, expect &#34;^$|^uk_tech_book_id_name$&#34; but found &#34;tech_book_id_name&#34;.&#xA;You can check the docs at https://www.bytebase.com/docs/reference/error-code/advisor#304</failure>
+    </testcase>
+  </testsuite>
 </testsuites>`
-	res := convertSQLAdviceToGitLabCIResult(mockSQLAdviceMap)
+	res, err := convertSQLAdviceToGitLabCIResult(mockSQLAdviceMap)
+	require.NoError(t, err)
 	assert.Equal(t, advisor.Error, res.Status)
 	assert.Equal(t, 1, len(res.Content))
 	assert.Equal(t, expect, res.Content[0])
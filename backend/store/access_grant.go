@@ -0,0 +1,231 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+)
+
+// AccessGrantMessage is the message for the temporary query access to a database granted by a database access request issue.
+type AccessGrantMessage struct {
+	IssueID     int
+	PrincipalID int
+	DatabaseID  int
+	// Tables is empty if all tables of the database are granted.
+	Tables    []string
+	Reason    string
+	ExpiresTs int64
+
+	// Output only.
+	UID       int
+	CreatorID int
+	CreatedTs int64
+	Revoked   bool
+}
+
+// FindAccessGrantMessage is the message for finding access grants.
+type FindAccessGrantMessage struct {
+	UID         *int
+	IssueID     *int
+	PrincipalID *int
+	DatabaseID  *int
+	// ExpiresBefore finds the grants expiring before the timestamp.
+	ExpiresBefore *int64
+	// ExpiresAfter finds the grants expiring after the timestamp.
+	ExpiresAfter *int64
+	ShowRevoked  bool
+}
+
+// GetAccessGrant gets an access grant.
+func (s *Store) GetAccessGrant(ctx context.Context, find *FindAccessGrantMessage) (*AccessGrantMessage, error) {
+	grants, err := s.ListAccessGrants(ctx, find)
+	if err != nil {
+		return nil, err
+	}
+	if len(grants) == 0 {
+		return nil, nil
+	}
+	if len(grants) > 1 {
+		return nil, errors.Errorf("found %d access grants with filter %+v, expect 1", len(grants), find)
+	}
+	return grants[0], nil
+}
+
+// ListAccessGrants lists the access grants.
+// The grants are not cached so that the revocation takes effect on all the replicas immediately.
+func (s *Store) ListAccessGrants(ctx context.Context, find *FindAccessGrantMessage) ([]*AccessGrantMessage, error) {
+	where, args := []string{"TRUE"}, []interface{}{}
+	if v := find.UID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.IssueID; v != nil {
+		where, args = append(where, fmt.Sprintf("issue_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.PrincipalID; v != nil {
+		where, args = append(where, fmt.Sprintf("principal_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.DatabaseID; v != nil {
+		where, args = append(where, fmt.Sprintf("database_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.ExpiresBefore; v != nil {
+		where, args = append(where, fmt.Sprintf("expires_ts <= $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.ExpiresAfter; v != nil {
+		where, args = append(where, fmt.Sprintf("expires_ts > $%d", len(args)+1)), append(args, *v)
+	}
+	if !find.ShowRevoked {
+		where, args = append(where, fmt.Sprintf("row_status = $%d", len(args)+1)), append(args, api.Normal)
+	}
+
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			row_status,
+			creator_id,
+			created_ts,
+			issue_id,
+			principal_id,
+			database_id,
+			tables,
+			reason,
+			expires_ts
+		FROM access_grant
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY id ASC`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	var grants []*AccessGrantMessage
+	for rows.Next() {
+		grant := &AccessGrantMessage{}
+		var rowStatus string
+		var tables []byte
+		if err := rows.Scan(
+			&grant.UID,
+			&rowStatus,
+			&grant.CreatorID,
+			&grant.CreatedTs,
+			&grant.IssueID,
+			&grant.PrincipalID,
+			&grant.DatabaseID,
+			&tables,
+			&grant.Reason,
+			&grant.ExpiresTs,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+		if err := json.Unmarshal(tables, &grant.Tables); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal tables")
+		}
+		grant.Revoked = api.RowStatus(rowStatus) == api.Archived
+		grants = append(grants, grant)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+	return grants, nil
+}
+
+// CreateAccessGrant creates an access grant.
+func (s *Store) CreateAccessGrant(ctx context.Context, create *AccessGrantMessage, creatorID int) (*AccessGrantMessage, error) {
+	tableList := create.Tables
+	if tableList == nil {
+		tableList = []string{}
+	}
+	tables, err := json.Marshal(tableList)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal tables")
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.Rollback()
+
+	grant := &AccessGrantMessage{
+		IssueID:     create.IssueID,
+		PrincipalID: create.PrincipalID,
+		DatabaseID:  create.DatabaseID,
+		Tables:      create.Tables,
+		Reason:      create.Reason,
+		ExpiresTs:   create.ExpiresTs,
+		CreatorID:   creatorID,
+	}
+	if err := tx.QueryRowContext(ctx, `
+		INSERT INTO access_grant (
+			creator_id,
+			updater_id,
+			issue_id,
+			principal_id,
+			database_id,
+			tables,
+			reason,
+			expires_ts
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_ts
+	`,
+		creatorID,
+		creatorID,
+		create.IssueID,
+		create.PrincipalID,
+		create.DatabaseID,
+		tables,
+		create.Reason,
+		create.ExpiresTs,
+	).Scan(&grant.UID, &grant.CreatedTs); err != nil {
+		return nil, FormatError(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+	return grant, nil
+}
+
+// RevokeAccessGrant revokes an access grant, it returns false if the grant is already revoked.
+func (s *Store) RevokeAccessGrant(ctx context.Context, uid int, updaterID int) (bool, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, FormatError(err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE access_grant
+		SET row_status = $1, updater_id = $2
+		WHERE id = $3 AND row_status = $4
+	`, api.Archived, updaterID, uid, api.Normal)
+	if err != nil {
+		return false, FormatError(err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, FormatError(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, FormatError(err)
+	}
+	return rows > 0, nil
+}
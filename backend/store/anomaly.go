@@ -266,6 +266,9 @@ func createAnomalyImpl(ctx context.Context, tx *Tx, upsert *api.AnomalyUpsert) (
 func findAnomalyListImpl(ctx context.Context, tx *Tx, find *api.AnomalyFind) ([]*anomalyRaw, error) {
 	// Build WHERE clause.
 	where, args := []string{"TRUE"}, []interface{}{}
+	if v := find.ID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
 	if v := find.InstanceID; v != nil {
 		where, args = append(where, fmt.Sprintf("instance_id = $%d", len(args)+1)), append(args, *v)
 		if find.InstanceOnly {
@@ -283,6 +286,14 @@ func findAnomalyListImpl(ctx context.Context, tx *Tx, find *api.AnomalyFind) ([]
 	if v := find.Type; v != nil {
 		where, args = append(where, fmt.Sprintf("type = $%d", len(args)+1)), append(args, *v)
 	}
+	if v := find.ProjectMemberID; v != nil {
+		where, args = append(where, fmt.Sprintf(`database_id IN (
+			SELECT db.id
+			FROM db
+			JOIN project_member ON project_member.project_id = db.project_id
+			WHERE project_member.principal_id = $%d AND project_member.row_status = 'NORMAL'
+		)`, len(args)+1)), append(args, *v)
+	}
 
 	rows, err := tx.QueryContext(ctx, `
 		SELECT
@@ -0,0 +1,251 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/common"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+)
+
+// apiTokenLastUsedInterval is the interval to record the last used time of a token,
+// so that the busy tokens don't write the metadata database on every request.
+const apiTokenLastUsedInterval = time.Minute
+
+// APITokenMessage is the message for a personal access token or a service account key.
+type APITokenMessage struct {
+	PrincipalID int
+	Name        string
+	Scopes      []string
+	// ExpiresTs is 0 if the token never expires.
+	ExpiresTs int64
+
+	// Output only.
+	UID        int
+	CreatorID  int
+	CreatedTs  int64
+	LastUsedTs int64
+	Revoked    bool
+}
+
+// HasScope returns whether the token is granted the scope.
+func (t *APITokenMessage) HasScope(scope api.APITokenScope) bool {
+	for _, s := range t.Scopes {
+		if s == string(scope) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsExpired returns whether the token is expired at the time.
+func (t *APITokenMessage) IsExpired(now time.Time) bool {
+	return t.ExpiresTs > 0 && now.Unix() >= t.ExpiresTs
+}
+
+// FindAPITokenMessage is the message for finding API tokens.
+type FindAPITokenMessage struct {
+	UID         *int
+	PrincipalID *int
+	ShowRevoked bool
+}
+
+// GetAPIToken gets an API token by ID, the revoked tokens are returned as well.
+// The tokens are not cached so that the revocation takes effect on all the replicas immediately.
+func (s *Store) GetAPIToken(ctx context.Context, uid int) (*APITokenMessage, error) {
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.Rollback()
+
+	tokens, err := listAPITokenImpl(ctx, tx, &FindAPITokenMessage{UID: &uid, ShowRevoked: true})
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+	return tokens[0], nil
+}
+
+// ListAPITokens lists the API tokens.
+func (s *Store) ListAPITokens(ctx context.Context, find *FindAPITokenMessage) ([]*APITokenMessage, error) {
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.Rollback()
+
+	tokens, err := listAPITokenImpl(ctx, tx, find)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+	return tokens, nil
+}
+
+// CreateAPIToken creates an API token.
+func (s *Store) CreateAPIToken(ctx context.Context, create *APITokenMessage, creatorID int) (*APITokenMessage, error) {
+	scopes, err := json.Marshal(create.Scopes)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal scopes")
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.Rollback()
+
+	token := &APITokenMessage{
+		PrincipalID: create.PrincipalID,
+		Name:        create.Name,
+		Scopes:      create.Scopes,
+		ExpiresTs:   create.ExpiresTs,
+		CreatorID:   creatorID,
+	}
+	if err := tx.QueryRowContext(ctx, `
+		INSERT INTO api_token (
+			creator_id,
+			updater_id,
+			principal_id,
+			name,
+			scopes,
+			expires_ts
+		)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_ts
+	`,
+		creatorID,
+		creatorID,
+		create.PrincipalID,
+		create.Name,
+		scopes,
+		create.ExpiresTs,
+	).Scan(&token.UID, &token.CreatedTs); err != nil {
+		return nil, FormatError(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+	return token, nil
+}
+
+// RevokeAPIToken revokes an API token, the revoked token can no longer be used to authenticate.
+func (s *Store) RevokeAPIToken(ctx context.Context, uid int, updaterID int) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormatError(err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE api_token
+		SET row_status = $1, updater_id = $2
+		WHERE id = $3
+	`, api.Archived, updaterID, uid)
+	if err != nil {
+		return FormatError(err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return &common.Error{Code: common.NotFound, Err: errors.Errorf("API token not found: %d", uid)}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return FormatError(err)
+	}
+	return nil
+}
+
+// TouchAPIToken records the last used time of an API token.
+func (s *Store) TouchAPIToken(ctx context.Context, token *APITokenMessage) error {
+	now := time.Now()
+	if now.Sub(time.Unix(token.LastUsedTs, 0)) < apiTokenLastUsedInterval {
+		return nil
+	}
+	if _, err := s.db.db.ExecContext(ctx, `
+		UPDATE api_token
+		SET last_used_ts = $1
+		WHERE id = $2
+	`, now.Unix(), token.UID); err != nil {
+		return FormatError(err)
+	}
+	token.LastUsedTs = now.Unix()
+	return nil
+}
+
+func listAPITokenImpl(ctx context.Context, tx *Tx, find *FindAPITokenMessage) ([]*APITokenMessage, error) {
+	where, args := []string{"TRUE"}, []interface{}{}
+	if v := find.UID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.PrincipalID; v != nil {
+		where, args = append(where, fmt.Sprintf("principal_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if !find.ShowRevoked {
+		where, args = append(where, fmt.Sprintf("row_status = $%d", len(args)+1)), append(args, api.Normal)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			row_status,
+			creator_id,
+			created_ts,
+			principal_id,
+			name,
+			scopes,
+			expires_ts,
+			last_used_ts
+		FROM api_token
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY id ASC`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	var tokens []*APITokenMessage
+	for rows.Next() {
+		token := &APITokenMessage{}
+		var rowStatus string
+		var scopes []byte
+		if err := rows.Scan(
+			&token.UID,
+			&rowStatus,
+			&token.CreatorID,
+			&token.CreatedTs,
+			&token.PrincipalID,
+			&token.Name,
+			&scopes,
+			&token.ExpiresTs,
+			&token.LastUsedTs,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+		if err := json.Unmarshal(scopes, &token.Scopes); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal scopes")
+		}
+		token.Revoked = api.RowStatus(rowStatus) == api.Archived
+		tokens = append(tokens, token)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+	return tokens, nil
+}
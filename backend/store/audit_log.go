@@ -0,0 +1,269 @@
+package store
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/pkg/errors"
+)
+
+const (
+	// maxAuditLogAppendAttempts is the max attempts to append an audit log entry when the concurrent appends chain to the same entry.
+	maxAuditLogAppendAttempts = 10
+	// auditLogPrevHashIndex is the unique index on prev_hash, which rejects the entries chained to the same entry.
+	auditLogPrevHashIndex = "idx_audit_log_unique_prev_hash"
+)
+
+// AuditLogMessage is the message for an audit log entry.
+type AuditLogMessage struct {
+	CreatedTs int64
+	// ActorID is 0 if the call is not authenticated.
+	ActorID   int
+	Method    string
+	Resource  string
+	Status    string
+	Error     string
+	Request   string
+	Diff      string
+	ClientIP  string
+	UserAgent string
+
+	// Output only.
+	ID       int64
+	PrevHash string
+	Hash     string
+}
+
+// FindAuditLogMessage is the message for finding audit logs.
+type FindAuditLogMessage struct {
+	ActorID *int
+	Method  *string
+	// ResourcePrefix matches the audit logs whose resource starts with it.
+	ResourcePrefix  *string
+	CreatedTsAfter  *int64
+	CreatedTsBefore *int64
+	// AfterID lists the audit logs after the ID for pagination.
+	AfterID *int64
+	Limit   *int
+}
+
+// ComputeAuditLogHash computes the hash of the audit log chained to the hash of the previous entry.
+func ComputeAuditLogHash(prevHash string, log *AuditLogMessage) string {
+	// The JSON encoding of a struct has a stable field order.
+	content, _ := json.Marshal(struct {
+		PrevHash  string `json:"prevHash"`
+		CreatedTs int64  `json:"createdTs"`
+		ActorID   int    `json:"actorId"`
+		Method    string `json:"method"`
+		Resource  string `json:"resource"`
+		Status    string `json:"status"`
+		Error     string `json:"error"`
+		Request   string `json:"request"`
+		Diff      string `json:"diff"`
+		ClientIP  string `json:"clientIp"`
+		UserAgent string `json:"userAgent"`
+	}{
+		PrevHash:  prevHash,
+		CreatedTs: log.CreatedTs,
+		ActorID:   log.ActorID,
+		Method:    log.Method,
+		Resource:  log.Resource,
+		Status:    log.Status,
+		Error:     log.Error,
+		Request:   log.Request,
+		Diff:      log.Diff,
+		ClientIP:  log.ClientIP,
+		UserAgent: log.UserAgent,
+	})
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// VerifyAuditLogChain verifies the consecutive audit logs in ascending ID order are not tampered,
+// and returns the ID of the first broken entry, or 0 if the chain is intact.
+func VerifyAuditLogChain(logs []*AuditLogMessage) int64 {
+	for i, log := range logs {
+		if i > 0 && log.PrevHash != logs[i-1].Hash {
+			return log.ID
+		}
+		if ComputeAuditLogHash(log.PrevHash, log) != log.Hash {
+			return log.ID
+		}
+	}
+	return 0
+}
+
+// CreateAuditLog appends an audit log entry to the hash chain.
+// The appends are not serialized by a lock. Instead, the unique index on prev_hash rejects the entries chained to the same entry
+// by the concurrent appends across the replicas, and the rejected ones are chained to the new last entry again.
+func (s *Store) CreateAuditLog(ctx context.Context, create *AuditLogMessage) (*AuditLogMessage, error) {
+	for attempt := 1; ; attempt++ {
+		log, err := s.appendAuditLog(ctx, create)
+		if err == nil {
+			return log, nil
+		}
+		if attempt >= maxAuditLogAppendAttempts || !isAuditLogChainConflict(err) {
+			return nil, err
+		}
+	}
+}
+
+// isAuditLogChainConflict returns whether the error is the unique violation of prev_hash caused by the concurrent appends.
+func isAuditLogChainConflict(err error) bool {
+	var pgErr *pgconn.PgError
+	// 23505 is the unique_violation error code.
+	return errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == auditLogPrevHashIndex
+}
+
+func (s *Store) appendAuditLog(ctx context.Context, create *AuditLogMessage) (*AuditLogMessage, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.Rollback()
+
+	var prevHash string
+	if err := tx.QueryRowContext(ctx, `SELECT hash FROM audit_log ORDER BY id DESC LIMIT 1`).Scan(&prevHash); err != nil && err != sql.ErrNoRows {
+		return nil, FormatError(err)
+	}
+
+	log := *create
+	log.PrevHash = prevHash
+	log.Hash = ComputeAuditLogHash(prevHash, &log)
+	var actorID sql.NullInt32
+	if log.ActorID > 0 {
+		actorID = sql.NullInt32{Int32: int32(log.ActorID), Valid: true}
+	}
+	if err := tx.QueryRowContext(ctx, `
+		INSERT INTO audit_log (
+			created_ts,
+			actor_id,
+			method,
+			resource,
+			status,
+			error,
+			request,
+			diff,
+			client_ip,
+			user_agent,
+			prev_hash,
+			hash
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING id
+	`,
+		log.CreatedTs,
+		actorID,
+		log.Method,
+		log.Resource,
+		log.Status,
+		log.Error,
+		log.Request,
+		log.Diff,
+		log.ClientIP,
+		log.UserAgent,
+		log.PrevHash,
+		log.Hash,
+	).Scan(&log.ID); err != nil {
+		return nil, FormatError(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+	return &log, nil
+}
+
+// ListAuditLogs lists the audit logs in ascending ID order.
+func (s *Store) ListAuditLogs(ctx context.Context, find *FindAuditLogMessage) ([]*AuditLogMessage, error) {
+	where, args := []string{"TRUE"}, []interface{}{}
+	if v := find.ActorID; v != nil {
+		where, args = append(where, fmt.Sprintf("actor_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.Method; v != nil {
+		where, args = append(where, fmt.Sprintf("method = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.ResourcePrefix; v != nil {
+		where, args = append(where, fmt.Sprintf("starts_with(resource, $%d)", len(args)+1)), append(args, *v)
+	}
+	if v := find.CreatedTsAfter; v != nil {
+		where, args = append(where, fmt.Sprintf("created_ts >= $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.CreatedTsBefore; v != nil {
+		where, args = append(where, fmt.Sprintf("created_ts < $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.AfterID; v != nil {
+		where, args = append(where, fmt.Sprintf("id > $%d", len(args)+1)), append(args, *v)
+	}
+	query := `
+		SELECT
+			id,
+			created_ts,
+			actor_id,
+			method,
+			resource,
+			status,
+			error,
+			request,
+			diff,
+			client_ip,
+			user_agent,
+			prev_hash,
+			hash
+		FROM audit_log
+		WHERE ` + strings.Join(where, " AND ") + `
+		ORDER BY id ASC`
+	if v := find.Limit; v != nil {
+		query += fmt.Sprintf(" LIMIT %d", *v)
+	}
+
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	var logs []*AuditLogMessage
+	for rows.Next() {
+		log := &AuditLogMessage{}
+		var actorID sql.NullInt32
+		if err := rows.Scan(
+			&log.ID,
+			&log.CreatedTs,
+			&actorID,
+			&log.Method,
+			&log.Resource,
+			&log.Status,
+			&log.Error,
+			&log.Request,
+			&log.Diff,
+			&log.ClientIP,
+			&log.UserAgent,
+			&log.PrevHash,
+			&log.Hash,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+		log.ActorID = int(actorID.Int32)
+		logs = append(logs, log)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+	return logs, nil
+}
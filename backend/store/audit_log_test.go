@@ -0,0 +1,101 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/backend/common"
+	dbdriver "github.com/bytebase/bytebase/backend/plugin/db"
+	"github.com/bytebase/bytebase/backend/resources/postgres"
+)
+
+func TestVerifyAuditLogChain(t *testing.T) {
+	a := require.New(t)
+
+	var logs []*AuditLogMessage
+	prevHash := ""
+	for i, method := range []string{"/bytebase.v1.AuthService/Login", "PATCH /api/instance/101", "/bytebase.v1.SettingService/SetSetting"} {
+		log := &AuditLogMessage{
+			ID:        int64(101 + i),
+			CreatedTs: int64(1677600000 + i),
+			ActorID:   101,
+			Method:    method,
+			Status:    "OK",
+			PrevHash:  prevHash,
+		}
+		log.Hash = ComputeAuditLogHash(prevHash, log)
+		prevHash = log.Hash
+		logs = append(logs, log)
+	}
+	a.Equal(int64(0), VerifyAuditLogChain(logs))
+
+	// Tampering an entry breaks its own hash.
+	logs[1].ActorID = 102
+	a.Equal(int64(102), VerifyAuditLogChain(logs))
+	logs[1].ActorID = 101
+
+	// Deleting an entry breaks the chain of the next one.
+	a.Equal(int64(103), VerifyAuditLogChain([]*AuditLogMessage{logs[0], logs[2]}))
+}
+
+func TestCreateAuditLogConcurrently(t *testing.T) {
+	port := pgPort + 1
+	pgDir := t.TempDir()
+	pgBinDir, err := postgres.Install(path.Join(pgDir, "resource"))
+	require.NoError(t, err)
+	pgDataDir := path.Join(pgDir, "data")
+	err = postgres.InitDB(pgBinDir, pgDataDir, pgUser)
+	require.NoError(t, err)
+	err = postgres.Start(port, pgBinDir, pgDataDir, false /* serverLog */)
+	require.NoError(t, err)
+	defer func() {
+		_ = postgres.Stop(pgBinDir, pgDataDir)
+	}()
+
+	ctx := context.Background()
+	db := NewDB(dbdriver.ConnectionConfig{
+		Username: pgUser,
+		Host:     common.GetPostgresSocketDir(),
+		Port:     fmt.Sprintf("%d", port),
+	}, pgBinDir, "" /* demoName */, false /* readonly */, serverVersion, common.ReleaseModeProd)
+	_, err = db.Open(ctx)
+	require.NoError(t, err)
+	s := New(db)
+	defer s.Close()
+
+	// The concurrent appends are chained one after another without a global lock.
+	const workers, appendsPerWorker = 5, 4
+	var wg sync.WaitGroup
+	errs := make(chan error, workers*appendsPerWorker)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for j := 0; j < appendsPerWorker; j++ {
+				if _, err := s.CreateAuditLog(ctx, &AuditLogMessage{
+					CreatedTs: int64(1677600000 + j),
+					Method:    fmt.Sprintf("PATCH /api/instance/%d", worker),
+					Status:    "OK",
+				}); err != nil {
+					errs <- err
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	logs, err := s.ListAuditLogs(ctx, &FindAuditLogMessage{})
+	require.NoError(t, err)
+	require.Len(t, logs, workers*appendsPerWorker)
+	require.Equal(t, "", logs[0].PrevHash)
+	require.Equal(t, int64(0), VerifyAuditLogChain(logs))
+}
@@ -23,8 +23,16 @@ type DataSourceMessage struct {
 	Port               string
 	Database           string
 	// Flatten data source options.
-	SRV                    bool
-	AuthenticationDatabase string
+	SRV                     bool
+	AuthenticationDatabase  string
+	SSHHost                 string
+	SSHPort                 string
+	SSHUser                 string
+	SSHObfuscatedPassword   string
+	SSHObfuscatedPrivateKey string
+	SSHHostKey              string
+	PasswordSecretRef       string
+	SslKeySecretRef         string
 	// (deprecated) Output only.
 	UID        int
 	DatabaseID int
@@ -47,8 +55,16 @@ type UpdateDataSourceMessage struct {
 	Host               *string
 	Port               *string
 	// Flatten data source options.
-	SRV                    *bool
-	AuthenticationDatabase *string
+	SRV                     *bool
+	AuthenticationDatabase  *string
+	SSHHost                 *string
+	SSHPort                 *string
+	SSHUser                 *string
+	SSHObfuscatedPassword   *string
+	SSHObfuscatedPrivateKey *string
+	SSHHostKey              *string
+	PasswordSecretRef       *string
+	SslKeySecretRef         *string
 }
 
 func (*Store) listDataSourceV2(ctx context.Context, tx *Tx, instanceID string) ([]*DataSourceMessage, error) {
@@ -77,9 +93,9 @@ func (*Store) listDataSourceV2(ctx context.Context, tx *Tx, instanceID string) (
 		return nil, FormatError(err)
 	}
 	defer rows.Close()
-	var dataSourceOptions api.DataSourceOptions
 	for rows.Next() {
 		var dataSourceMessage DataSourceMessage
+		var dataSourceOptions api.DataSourceOptions
 		if err := rows.Scan(
 			&dataSourceMessage.UID,
 			&dataSourceMessage.DatabaseID,
@@ -99,6 +115,14 @@ func (*Store) listDataSourceV2(ctx context.Context, tx *Tx, instanceID string) (
 		}
 		dataSourceMessage.SRV = dataSourceOptions.SRV
 		dataSourceMessage.AuthenticationDatabase = dataSourceOptions.AuthenticationDatabase
+		dataSourceMessage.SSHHost = dataSourceOptions.SSHHost
+		dataSourceMessage.SSHPort = dataSourceOptions.SSHPort
+		dataSourceMessage.SSHUser = dataSourceOptions.SSHUser
+		dataSourceMessage.SSHObfuscatedPassword = dataSourceOptions.SSHObfuscatedPassword
+		dataSourceMessage.SSHObfuscatedPrivateKey = dataSourceOptions.SSHObfuscatedPrivateKey
+		dataSourceMessage.SSHHostKey = dataSourceOptions.SSHHostKey
+		dataSourceMessage.PasswordSecretRef = dataSourceOptions.PasswordSecretRef
+		dataSourceMessage.SslKeySecretRef = dataSourceOptions.SslKeySecretRef
 
 		dataSourceMessages = append(dataSourceMessages, &dataSourceMessage)
 	}
@@ -134,6 +158,7 @@ func (s *Store) AddDataSourceToInstanceV2(ctx context.Context, instanceUID, crea
 
 	s.instanceCache.Delete(getInstanceCacheKey(environmentID, instanceID))
 	s.instanceIDCache.Delete(instanceUID)
+	s.notifyCacheInvalidation(ctx, cacheTypeInstance)
 	return nil
 }
 
@@ -166,6 +191,7 @@ func (s *Store) RemoveDataSourceV2(ctx context.Context, instanceUID int, environ
 
 	s.instanceCache.Delete(getInstanceCacheKey(environmentID, instanceID))
 	s.instanceIDCache.Delete(instanceUID)
+	s.notifyCacheInvalidation(ctx, cacheTypeInstance)
 	return nil
 }
 
@@ -203,6 +229,30 @@ func (s *Store) UpdateDataSourceV2(ctx context.Context, patch *UpdateDataSourceM
 	if v := patch.AuthenticationDatabase; v != nil {
 		optionSet, args = append(optionSet, fmt.Sprintf("jsonb_build_object('authenticationDatabase', to_jsonb($%d::TEXT))", len(args)+1)), append(args, *v)
 	}
+	if v := patch.SSHHost; v != nil {
+		optionSet, args = append(optionSet, fmt.Sprintf("jsonb_build_object('sshHost', to_jsonb($%d::TEXT))", len(args)+1)), append(args, *v)
+	}
+	if v := patch.SSHPort; v != nil {
+		optionSet, args = append(optionSet, fmt.Sprintf("jsonb_build_object('sshPort', to_jsonb($%d::TEXT))", len(args)+1)), append(args, *v)
+	}
+	if v := patch.SSHUser; v != nil {
+		optionSet, args = append(optionSet, fmt.Sprintf("jsonb_build_object('sshUser', to_jsonb($%d::TEXT))", len(args)+1)), append(args, *v)
+	}
+	if v := patch.SSHObfuscatedPassword; v != nil {
+		optionSet, args = append(optionSet, fmt.Sprintf("jsonb_build_object('sshObfuscatedPassword', to_jsonb($%d::TEXT))", len(args)+1)), append(args, *v)
+	}
+	if v := patch.SSHObfuscatedPrivateKey; v != nil {
+		optionSet, args = append(optionSet, fmt.Sprintf("jsonb_build_object('sshObfuscatedPrivateKey', to_jsonb($%d::TEXT))", len(args)+1)), append(args, *v)
+	}
+	if v := patch.SSHHostKey; v != nil {
+		optionSet, args = append(optionSet, fmt.Sprintf("jsonb_build_object('sshHostKey', to_jsonb($%d::TEXT))", len(args)+1)), append(args, *v)
+	}
+	if v := patch.PasswordSecretRef; v != nil {
+		optionSet, args = append(optionSet, fmt.Sprintf("jsonb_build_object('passwordSecretRef', to_jsonb($%d::TEXT))", len(args)+1)), append(args, *v)
+	}
+	if v := patch.SslKeySecretRef; v != nil {
+		optionSet, args = append(optionSet, fmt.Sprintf("jsonb_build_object('sslKeySecretRef', to_jsonb($%d::TEXT))", len(args)+1)), append(args, *v)
+	}
 	if len(optionSet) != 0 {
 		set = append(set, fmt.Sprintf(`options = options || %s`, strings.Join(optionSet, "||")))
 	}
@@ -236,14 +286,23 @@ func (s *Store) UpdateDataSourceV2(ctx context.Context, patch *UpdateDataSourceM
 
 	s.instanceCache.Delete(getInstanceCacheKey(patch.EnvironmentID, patch.InstanceID))
 	s.instanceIDCache.Delete(patch.InstanceUID)
+	s.notifyCacheInvalidation(ctx, cacheTypeInstance)
 	return nil
 }
 
 func (*Store) addDataSourceToInstanceImplV2(ctx context.Context, tx *Tx, instanceUID, databaseUID, creatorID int, dataSource *DataSourceMessage) error {
 	// We flatten the data source fields in DataSourceMessage, so we need to compose them in store layer before INSERT.
 	dataSourceOptions := api.DataSourceOptions{
-		SRV:                    dataSource.SRV,
-		AuthenticationDatabase: dataSource.AuthenticationDatabase,
+		SRV:                     dataSource.SRV,
+		AuthenticationDatabase:  dataSource.AuthenticationDatabase,
+		SSHHost:                 dataSource.SSHHost,
+		SSHPort:                 dataSource.SSHPort,
+		SSHUser:                 dataSource.SSHUser,
+		SSHObfuscatedPassword:   dataSource.SSHObfuscatedPassword,
+		SSHObfuscatedPrivateKey: dataSource.SSHObfuscatedPrivateKey,
+		SSHHostKey:              dataSource.SSHHostKey,
+		PasswordSecretRef:       dataSource.PasswordSecretRef,
+		SslKeySecretRef:         dataSource.SslKeySecretRef,
 	}
 
 	if _, err := tx.QueryContext(ctx, `
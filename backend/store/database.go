@@ -281,6 +281,7 @@ func (s *Store) CreateDatabaseDefault(ctx context.Context, create *DatabaseMessa
 	// Invalidate an update the cache.
 	s.databaseCache.Delete(getDatabaseCacheKey(instance.EnvironmentID, instance.ResourceID, create.DatabaseName))
 	s.databaseIDCache.Delete(databaseUID)
+	s.notifyCacheInvalidation(ctx, cacheTypeDatabase)
 	if _, err = s.GetDatabaseV2(ctx, &FindDatabaseMessage{UID: &databaseUID}); err != nil {
 		return err
 	}
@@ -389,6 +390,7 @@ func (s *Store) UpsertDatabase(ctx context.Context, create *DatabaseMessage) (*D
 	// Invalidate and update the cache.
 	s.databaseCache.Delete(getDatabaseCacheKey(instance.EnvironmentID, instance.ResourceID, create.DatabaseName))
 	s.databaseIDCache.Delete(databaseUID)
+	s.notifyCacheInvalidation(ctx, cacheTypeDatabase)
 	return s.GetDatabaseV2(ctx, &FindDatabaseMessage{UID: &databaseUID})
 }
 
@@ -448,6 +450,7 @@ func (s *Store) UpdateDatabase(ctx context.Context, patch *UpdateDatabaseMessage
 	// Invalidate and update the cache.
 	s.databaseCache.Delete(getDatabaseCacheKey(patch.EnvironmentID, patch.InstanceID, patch.DatabaseName))
 	s.databaseIDCache.Delete(databaseUID)
+	s.notifyCacheInvalidation(ctx, cacheTypeDatabase)
 	return s.GetDatabaseV2(ctx, &FindDatabaseMessage{UID: &databaseUID})
 }
 
@@ -492,6 +495,7 @@ func (s *Store) BatchUpdateDatabaseProject(ctx context.Context, databases []*Dat
 		s.databaseIDCache.Store(database.UID, &updatedDatabase)
 		updatedDatabases = append(updatedDatabases, &updatedDatabase)
 	}
+	s.notifyCacheInvalidation(ctx, cacheTypeDatabase)
 	return updatedDatabases, nil
 }
 
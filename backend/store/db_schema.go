@@ -108,5 +108,6 @@ func (s *Store) UpsertDBSchema(ctx context.Context, databaseID int, dbSchema *DB
 	}
 
 	s.dbSchemaCache.Store(databaseID, dbSchema)
+	s.notifyCacheInvalidation(ctx, cacheTypeDBSchema)
 	return nil
 }
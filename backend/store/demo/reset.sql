@@ -34,6 +34,9 @@ DELETE FROM
 DELETE FROM
     sheet;
 
+DELETE FROM
+    access_grant;
+
 DELETE FROM
     issue_subscriber;
 
@@ -43,6 +46,9 @@ DELETE FROM
 DELETE FROM
     task_check_run;
 
+DELETE FROM
+    task_run_log;
+
 DELETE FROM
     task_run;
 
@@ -98,6 +104,9 @@ DELETE FROM
 DELETE FROM
     project_member;
 
+DELETE FROM
+    role;
+
 DELETE FROM
     deployment_config;
 
@@ -110,6 +119,12 @@ WHERE
 DELETE FROM
     member;
 
+DELETE FROM
+    api_token;
+
+DELETE FROM
+    audit_log;
+
 -- Principal 1 refers to bytebase system account which is considered as part of schema
 DELETE FROM
     principal
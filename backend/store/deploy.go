@@ -259,6 +259,13 @@ type DeploymentConfigMessage struct {
 // Schedule is the message for deployment schedule.
 type Schedule struct {
 	Deployments []*Deployment `json:"deployments"`
+	Concurrency *Concurrency  `json:"concurrency,omitempty"`
+}
+
+// Concurrency is the message for the concurrency limits of the running tasks.
+type Concurrency struct {
+	MaxRunningTasksPerStage    int `json:"maxRunningTasksPerStage,omitempty"`
+	MaxRunningTasksPerInstance int `json:"maxRunningTasksPerInstance,omitempty"`
 }
 
 // Deployment is the message for deployment.
@@ -269,7 +276,16 @@ type Deployment struct {
 
 // DeploymentSpec is the message for deployment specification.
 type DeploymentSpec struct {
-	Selector *LabelSelector `json:"selector"`
+	Selector *LabelSelector     `json:"selector"`
+	Rollout  *DeploymentRollout `json:"rollout,omitempty"`
+}
+
+// DeploymentRollout is the message for the gradual rollout of a deployment.
+type DeploymentRollout struct {
+	Count               int `json:"count,omitempty"`
+	Percent             int `json:"percent,omitempty"`
+	PauseFailurePercent int `json:"pauseFailurePercent,omitempty"`
+	BakeMinutes         int `json:"bakeMinutes,omitempty"`
 }
 
 // LabelSelector is the message for label selector.
@@ -279,15 +295,18 @@ type LabelSelector struct {
 }
 
 // OperatorType is the type of label selector requirement operator.
-// Valid operators are In, Exists.
-// Note: NotIn and DoesNotExist are not supported initially.
+// Valid operators are In, NotIn, Exists and DoesNotExist.
 type OperatorType string
 
 const (
 	// InOperatorType is the operator type for In.
 	InOperatorType OperatorType = "In"
+	// NotInOperatorType is the operator type for NotIn.
+	NotInOperatorType OperatorType = "NotIn"
 	// ExistsOperatorType is the operator type for Exists.
 	ExistsOperatorType OperatorType = "Exists"
+	// DoesNotExistOperatorType is the operator type for DoesNotExist.
+	DoesNotExistOperatorType OperatorType = "DoesNotExist"
 )
 
 // LabelSelectorRequirement is the message for label selector.
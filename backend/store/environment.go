@@ -299,6 +299,7 @@ func (s *Store) CreateEnvironmentV2(ctx context.Context, create *EnvironmentMess
 	}
 	s.environmentCache.Store(environment.ResourceID, environment)
 	s.environmentIDCache.Store(environment.UID, environment)
+	s.notifyCacheInvalidation(ctx, cacheTypeEnvironment)
 	return environment, nil
 }
 
@@ -371,6 +372,7 @@ func (s *Store) UpdateEnvironmentV2(ctx context.Context, environmentID string, p
 	// Invalid the cache and read the value again.
 	s.environmentCache.Delete(environmentID)
 	s.environmentIDCache.Delete(environmentUID)
+	s.notifyCacheInvalidation(ctx, cacheTypeEnvironment)
 
 	return s.GetEnvironmentV2(ctx, &FindEnvironmentMessage{
 		ResourceID: &environmentID,
@@ -106,6 +106,7 @@ func (s *Store) CreateIdentityProvider(ctx context.Context, create *IdentityProv
 	}
 
 	s.idpCache.Store(identityProvider.ResourceID, identityProvider)
+	s.notifyCacheInvalidation(ctx, cacheTypeIDP)
 	return identityProvider, nil
 }
 
@@ -183,6 +184,7 @@ func (s *Store) UpdateIdentityProvider(ctx context.Context, patch *UpdateIdentit
 	}
 
 	s.idpCache.Store(identityProvider.ResourceID, identityProvider)
+	s.notifyCacheInvalidation(ctx, cacheTypeIDP)
 	return identityProvider, nil
 }
 
@@ -89,8 +89,16 @@ func (s *Store) composeInstance(ctx context.Context, instance *InstanceMessage)
 			Username:   ds.Username,
 			Host:       ds.Host,
 			Port:       ds.Port,
-			Options:    api.DataSourceOptions{SRV: ds.SRV, AuthenticationDatabase: ds.AuthenticationDatabase},
-			Database:   ds.Database,
+			Options: api.DataSourceOptions{
+				SRV:                    ds.SRV,
+				AuthenticationDatabase: ds.AuthenticationDatabase,
+				SSHHost:                ds.SSHHost,
+				SSHPort:                ds.SSHPort,
+				SSHUser:                ds.SSHUser,
+				PasswordSecretRef:      ds.PasswordSecretRef,
+				SslKeySecretRef:        ds.SslKeySecretRef,
+			},
+			Database: ds.Database,
 		})
 		if ds.Type == api.Admin {
 			composedInstance.Host = ds.Host
@@ -281,6 +289,7 @@ func (s *Store) CreateInstanceV2(ctx context.Context, environmentID string, inst
 	}
 	s.instanceCache.Store(getInstanceCacheKey(instance.EnvironmentID, instance.ResourceID), instance)
 	s.instanceIDCache.Store(instance.UID, instance)
+	s.notifyCacheInvalidation(ctx, cacheTypeInstance)
 	return instance, nil
 }
 
@@ -394,6 +403,7 @@ func (s *Store) UpdateInstanceV2(ctx context.Context, patch *UpdateInstanceMessa
 
 	s.instanceCache.Store(getInstanceCacheKey(instance.EnvironmentID, instance.ResourceID), instance)
 	s.instanceIDCache.Store(instance.UID, instance)
+	s.notifyCacheInvalidation(ctx, cacheTypeInstance)
 	return instance, nil
 }
 
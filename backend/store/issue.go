@@ -436,6 +436,13 @@ func (s *Store) composePipeline(ctx context.Context, pipeline *PipelineMessage)
 		for _, composedTask := range composedTasks {
 			if composedTask.StageID == stage.ID {
 				composedStage.TaskList = append(composedStage.TaskList, composedTask)
+				status, found, err := api.GetVerificationStatus(composedTask.TaskCheckRunList)
+				if err != nil {
+					return nil, errors.Wrapf(err, "failed to get the verification status of task %d", composedTask.ID)
+				}
+				if found && status == api.TaskCheckStatusError {
+					composedStage.Unhealthy = true
+				}
 			}
 		}
 
@@ -645,6 +652,7 @@ func (s *Store) CreateIssueV2(ctx context.Context, create *IssueMessage, creator
 
 	s.issueCache.Store(create.UID, create)
 	s.issueByPipelineCache.Store(create.PipelineUID, create)
+	s.notifyCacheInvalidation(ctx, cacheTypeIssue)
 	return create, nil
 }
 
@@ -704,6 +712,7 @@ func (s *Store) UpdateIssueV2(ctx context.Context, uid int, patch *UpdateIssueMe
 	// Invalid the cache and read the value again.
 	s.issueCache.Delete(uid)
 	s.issueByPipelineCache.Delete(oldIssue.PipelineUID)
+	s.notifyCacheInvalidation(ctx, cacheTypeIssue)
 	return s.GetIssueV2(ctx, &FindIssueMessage{UID: &uid})
 }
 
@@ -0,0 +1,7 @@
+-- mfa_config is the multi-factor authentication config of the user, see store.MFAConfig.
+ALTER TABLE principal ADD COLUMN mfa_config JSONB NOT NULL DEFAULT '{}';
+
+-- failed_login_count and locked_until_ts lock the user out after repeated login failures.
+ALTER TABLE principal ADD COLUMN failed_login_count INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE principal ADD COLUMN locked_until_ts BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,27 @@
+-- role stores the custom roles made of fine-grained permissions.
+CREATE TABLE role (
+    id SERIAL PRIMARY KEY,
+    row_status row_status NOT NULL DEFAULT 'NORMAL',
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    resource_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    -- permissions is the list of the permission names granted by the role.
+    permissions JSONB NOT NULL DEFAULT '[]'
+);
+
+CREATE UNIQUE INDEX idx_role_unique_resource_id ON role(resource_id);
+
+ALTER SEQUENCE role_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_role_updated_ts
+BEFORE
+UPDATE
+    ON role FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- Project members can be bound to the custom roles besides the built-in OWNER and DEVELOPER roles.
+ALTER TABLE project_member DROP CONSTRAINT project_member_role_check;
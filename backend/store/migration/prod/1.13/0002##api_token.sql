@@ -0,0 +1,29 @@
+-- api_token stores the long-lived personal access tokens and the service account keys.
+-- The token itself is a JWT whose ID is the id of the row, so it's never stored.
+CREATE TABLE api_token (
+    id SERIAL PRIMARY KEY,
+    -- ARCHIVED means the token is revoked.
+    row_status row_status NOT NULL DEFAULT 'NORMAL',
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    -- principal_id is the principal the token acts as, it's either an end user or a service account.
+    principal_id INTEGER NOT NULL REFERENCES principal (id),
+    name TEXT NOT NULL,
+    -- scopes is the list of the scopes granted to the token.
+    scopes JSONB NOT NULL DEFAULT '[]',
+    -- expires_ts is 0 if the token never expires.
+    expires_ts BIGINT NOT NULL DEFAULT 0,
+    last_used_ts BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_api_token_principal_id ON api_token(principal_id);
+
+ALTER SEQUENCE api_token_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_api_token_updated_ts
+BEFORE
+UPDATE
+    ON api_token FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
@@ -0,0 +1,27 @@
+-- audit_log stores the mutating API calls and the authentication attempts.
+-- Each entry is chained to the previous one by hash, so that the tampering can be detected.
+CREATE TABLE audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    created_ts BIGINT NOT NULL,
+    -- actor_id is NULL if the call is not authenticated, e.g. the failed logins.
+    actor_id INTEGER REFERENCES principal (id),
+    -- method is the gRPC full method, or the HTTP method and path of the echo route.
+    method TEXT NOT NULL,
+    resource TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL,
+    error TEXT NOT NULL DEFAULT '',
+    -- request is the request payload with the secrets redacted.
+    request TEXT NOT NULL DEFAULT '',
+    -- diff is the fields of the resource changed by the call, with the values after the call.
+    diff TEXT NOT NULL DEFAULT '',
+    client_ip TEXT NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    prev_hash TEXT NOT NULL,
+    hash TEXT NOT NULL
+);
+
+CREATE INDEX idx_audit_log_created_ts ON audit_log(created_ts);
+
+CREATE INDEX idx_audit_log_actor_id ON audit_log(actor_id);
+
+ALTER SEQUENCE audit_log_id_seq RESTART WITH 101;
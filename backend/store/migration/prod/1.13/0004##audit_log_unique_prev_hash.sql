@@ -0,0 +1,2 @@
+-- Each entry is chained to a distinct previous entry, which prevents the concurrent appends from forking the chain.
+CREATE UNIQUE INDEX idx_audit_log_unique_prev_hash ON audit_log(prev_hash);
@@ -0,0 +1,2 @@
+-- progress saves the progress of the running task run in json format, so that it's visible to all the replicas.
+ALTER TABLE task_run ADD COLUMN progress JSONB NOT NULL DEFAULT '{}';
@@ -0,0 +1,2 @@
+-- payload is the stage config copied from the deployment when the pipeline is created, see api.StagePayload.
+ALTER TABLE stage ADD COLUMN payload JSONB NOT NULL DEFAULT '{}';
@@ -0,0 +1,29 @@
+-- access_grant stores the temporary query access to a database granted by the approved database access request issues.
+CREATE TABLE access_grant (
+    id SERIAL PRIMARY KEY,
+    -- ARCHIVED means the grant is expired or revoked.
+    row_status row_status NOT NULL DEFAULT 'NORMAL',
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    issue_id INTEGER NOT NULL REFERENCES issue (id),
+    principal_id INTEGER NOT NULL REFERENCES principal (id),
+    database_id INTEGER NOT NULL REFERENCES db (id),
+    -- tables is the list of the tables the grant is limited to, it's empty if all tables are granted.
+    tables JSONB NOT NULL DEFAULT '[]',
+    reason TEXT NOT NULL DEFAULT '',
+    expires_ts BIGINT NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_access_grant_unique_issue_id ON access_grant(issue_id);
+
+CREATE INDEX idx_access_grant_principal_id_database_id ON access_grant(principal_id, database_id);
+
+ALTER SEQUENCE access_grant_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_access_grant_updated_ts
+BEFORE
+UPDATE
+    ON access_grant FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
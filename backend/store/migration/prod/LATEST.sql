@@ -47,7 +47,12 @@ CREATE TABLE principal (
     email TEXT NOT NULL,
     password_hash TEXT NOT NULL,
     idp_id INTEGER REFERENCES idp (id),
-    idp_user_info JSONB NOT NULL DEFAULT '{}'
+    idp_user_info JSONB NOT NULL DEFAULT '{}',
+    -- mfa_config is the multi-factor authentication config of the user, see store.MFAConfig.
+    mfa_config JSONB NOT NULL DEFAULT '{}',
+    -- failed_login_count and locked_until_ts lock the user out after repeated login failures.
+    failed_login_count INTEGER NOT NULL DEFAULT 0,
+    locked_until_ts BIGINT NOT NULL DEFAULT 0
 );
 
 CREATE TRIGGER update_principal_updated_ts
@@ -250,7 +255,8 @@ CREATE TABLE project_member (
     updater_id INTEGER NOT NULL REFERENCES principal (id),
     updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
     project_id INTEGER NOT NULL REFERENCES project (id),
-    role TEXT NOT NULL CHECK (role IN ('OWNER', 'DEVELOPER')),
+    -- role is either the built-in OWNER and DEVELOPER, or the resource ID of a custom role.
+    role TEXT NOT NULL,
     principal_id INTEGER NOT NULL REFERENCES principal (id),
     payload JSONB NOT NULL DEFAULT '{}'
 );
@@ -525,7 +531,9 @@ CREATE TABLE stage (
     updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
     pipeline_id INTEGER NOT NULL REFERENCES pipeline (id),
     environment_id INTEGER NOT NULL REFERENCES environment (id),
-    name TEXT NOT NULL
+    name TEXT NOT NULL,
+    -- payload is the stage config copied from the deployment when the pipeline is created, see api.StagePayload.
+    payload JSONB NOT NULL DEFAULT '{}'
 );
 
 CREATE INDEX idx_stage_pipeline_id ON stage(pipeline_id);
@@ -610,7 +618,9 @@ CREATE TABLE task_run (
     comment TEXT NOT NULL DEFAULT '',
     -- result saves the task run result in json format
     result  JSONB NOT NULL DEFAULT '{}',
-    payload JSONB NOT NULL DEFAULT '{}'
+    payload JSONB NOT NULL DEFAULT '{}',
+    -- progress saves the progress of the running task run in json format, so that it's visible to all the replicas.
+    progress JSONB NOT NULL DEFAULT '{}'
 );
 
 CREATE INDEX idx_task_run_task_id ON task_run(task_id);
@@ -623,6 +633,18 @@ UPDATE
     ON task_run FOR EACH ROW
 EXECUTE FUNCTION trigger_update_updated_ts();
 
+-- task_run_log stores the structured logs emitted while executing a task run, e.g. the start and end of each statement and the server notices.
+CREATE TABLE task_run_log (
+    id BIGSERIAL PRIMARY KEY,
+    task_run_id INTEGER NOT NULL REFERENCES task_run (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    payload JSONB NOT NULL DEFAULT '{}'
+);
+
+CREATE INDEX idx_task_run_log_task_run_id ON task_run_log(task_run_id);
+
+ALTER SEQUENCE task_run_log_id_seq RESTART WITH 101;
+
 -- task check run table stores the task check run
 CREATE TABLE task_check_run (
     id SERIAL PRIMARY KEY,
@@ -1049,3 +1071,119 @@ BEFORE
 UPDATE
     ON external_approval FOR EACH ROW
 EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- role stores the custom roles made of fine-grained permissions.
+CREATE TABLE role (
+    id SERIAL PRIMARY KEY,
+    row_status row_status NOT NULL DEFAULT 'NORMAL',
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    resource_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    -- permissions is the list of the permission names granted by the role.
+    permissions JSONB NOT NULL DEFAULT '[]'
+);
+
+CREATE UNIQUE INDEX idx_role_unique_resource_id ON role(resource_id);
+
+ALTER SEQUENCE role_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_role_updated_ts
+BEFORE
+UPDATE
+    ON role FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- api_token stores the long-lived personal access tokens and the service account keys.
+-- The token itself is a JWT whose ID is the id of the row, so it's never stored.
+CREATE TABLE api_token (
+    id SERIAL PRIMARY KEY,
+    -- ARCHIVED means the token is revoked.
+    row_status row_status NOT NULL DEFAULT 'NORMAL',
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    -- principal_id is the principal the token acts as, it's either an end user or a service account.
+    principal_id INTEGER NOT NULL REFERENCES principal (id),
+    name TEXT NOT NULL,
+    -- scopes is the list of the scopes granted to the token.
+    scopes JSONB NOT NULL DEFAULT '[]',
+    -- expires_ts is 0 if the token never expires.
+    expires_ts BIGINT NOT NULL DEFAULT 0,
+    last_used_ts BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_api_token_principal_id ON api_token(principal_id);
+
+ALTER SEQUENCE api_token_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_api_token_updated_ts
+BEFORE
+UPDATE
+    ON api_token FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- audit_log stores the mutating API calls and the authentication attempts.
+-- Each entry is chained to the previous one by hash, so that the tampering can be detected.
+CREATE TABLE audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    created_ts BIGINT NOT NULL,
+    -- actor_id is NULL if the call is not authenticated, e.g. the failed logins.
+    actor_id INTEGER REFERENCES principal (id),
+    -- method is the gRPC full method, or the HTTP method and path of the echo route.
+    method TEXT NOT NULL,
+    resource TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL,
+    error TEXT NOT NULL DEFAULT '',
+    -- request is the request payload with the secrets redacted.
+    request TEXT NOT NULL DEFAULT '',
+    -- diff is the fields of the resource changed by the call, with the values after the call.
+    diff TEXT NOT NULL DEFAULT '',
+    client_ip TEXT NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    prev_hash TEXT NOT NULL,
+    hash TEXT NOT NULL
+);
+
+CREATE INDEX idx_audit_log_created_ts ON audit_log(created_ts);
+
+CREATE INDEX idx_audit_log_actor_id ON audit_log(actor_id);
+
+-- Each entry is chained to a distinct previous entry, which prevents the concurrent appends from forking the chain.
+CREATE UNIQUE INDEX idx_audit_log_unique_prev_hash ON audit_log(prev_hash);
+
+ALTER SEQUENCE audit_log_id_seq RESTART WITH 101;
+
+-- access_grant stores the temporary query access to a database granted by the approved database access request issues.
+CREATE TABLE access_grant (
+    id SERIAL PRIMARY KEY,
+    -- ARCHIVED means the grant is expired or revoked.
+    row_status row_status NOT NULL DEFAULT 'NORMAL',
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    issue_id INTEGER NOT NULL REFERENCES issue (id),
+    principal_id INTEGER NOT NULL REFERENCES principal (id),
+    database_id INTEGER NOT NULL REFERENCES db (id),
+    -- tables is the list of the tables the grant is limited to, it's empty if all tables are granted.
+    tables JSONB NOT NULL DEFAULT '[]',
+    reason TEXT NOT NULL DEFAULT '',
+    expires_ts BIGINT NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_access_grant_unique_issue_id ON access_grant(issue_id);
+
+CREATE INDEX idx_access_grant_principal_id_database_id ON access_grant(principal_id, database_id);
+
+ALTER SEQUENCE access_grant_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_access_grant_updated_ts
+BEFORE
+UPDATE
+    ON access_grant FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
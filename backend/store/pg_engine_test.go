@@ -220,5 +220,5 @@ func TestMigrationCompatibility(t *testing.T) {
 func TestGetCutoffVersion(t *testing.T) {
 	releaseVersion, err := getProdCutoffVersion()
 	require.NoError(t, err)
-	require.Equal(t, semver.MustParse("1.12.6"), releaseVersion)
+	require.Equal(t, semver.MustParse("1.13.8"), releaseVersion)
 }
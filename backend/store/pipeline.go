@@ -60,6 +60,7 @@ func (s *Store) CreatePipelineV2(ctx context.Context, create *PipelineMessage, c
 	}
 
 	s.pipelineCache.Store(pipeline.ID, pipeline)
+	s.notifyCacheInvalidation(ctx, cacheTypePipeline)
 	return pipeline, nil
 }
 
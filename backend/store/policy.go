@@ -75,6 +75,44 @@ func (s *Store) GetSQLReviewPolicy(ctx context.Context, environmentID int) (*adv
 	return api.UnmarshalSQLReviewPolicy(policy.Payload)
 }
 
+// GetMaintenanceWindowPolicy will get the maintenance window policy for an environment.
+func (s *Store) GetMaintenanceWindowPolicy(ctx context.Context, environmentID int) (*api.MaintenanceWindowPolicy, error) {
+	resourceType := api.PolicyResourceTypeEnvironment
+	pType := api.PolicyTypeMaintenanceWindow
+	policy, err := s.GetPolicyV2(ctx, &FindPolicyMessage{
+		ResourceType: &resourceType,
+		ResourceUID:  &environmentID,
+		Type:         &pType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if policy == nil {
+		return &api.MaintenanceWindowPolicy{}, nil
+	}
+
+	return api.UnmarshalMaintenanceWindowPolicy(policy.Payload)
+}
+
+// GetTaskRetryPolicy will get the task retry policy for an environment.
+func (s *Store) GetTaskRetryPolicy(ctx context.Context, environmentID int) (*api.TaskRetryPolicy, error) {
+	resourceType := api.PolicyResourceTypeEnvironment
+	pType := api.PolicyTypeTaskRetry
+	policy, err := s.GetPolicyV2(ctx, &FindPolicyMessage{
+		ResourceType: &resourceType,
+		ResourceUID:  &environmentID,
+		Type:         &pType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if policy == nil {
+		return &api.TaskRetryPolicy{}, nil
+	}
+
+	return api.UnmarshalTaskRetryPolicy(policy.Payload)
+}
+
 // GetSensitiveDataPolicy will get the sensitive data policy for database ID.
 func (s *Store) GetSensitiveDataPolicy(ctx context.Context, databaseID int) (*api.SensitiveDataPolicy, error) {
 	resourceType := api.PolicyResourceTypeDatabase
@@ -234,6 +272,7 @@ func (s *Store) CreatePolicyV2(ctx context.Context, create *PolicyMessage, creat
 	}
 
 	s.policyCache.Store(getPolicyCacheKey(policy.ResourceType, policy.ResourceUID, policy.Type), policy)
+	s.notifyCacheInvalidation(ctx, cacheTypePolicy)
 
 	return policy, nil
 }
@@ -297,6 +336,7 @@ func (s *Store) UpdatePolicyV2(ctx context.Context, patch *UpdatePolicyMessage)
 	}
 
 	s.policyCache.Store(getPolicyCacheKey(policy.ResourceType, policy.ResourceUID, policy.Type), policy)
+	s.notifyCacheInvalidation(ctx, cacheTypePolicy)
 
 	return policy, nil
 }
@@ -323,6 +363,7 @@ func (s *Store) DeletePolicyV2(ctx context.Context, policy *PolicyMessage) error
 	}
 
 	s.policyCache.Delete(getPolicyCacheKey(policy.ResourceType, policy.ResourceUID, policy.Type))
+	s.notifyCacheInvalidation(ctx, cacheTypePolicy)
 	return nil
 }
 
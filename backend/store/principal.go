@@ -90,6 +90,7 @@ type UpdateUserMessage struct {
 	PasswordHash *string
 	Role         *api.Role
 	Delete       *bool
+	MFAConfig    *storepb.MFAConfig
 }
 
 // UserMessage is the message for an user.
@@ -103,6 +104,7 @@ type UserMessage struct {
 	IdentityProviderUserInfo   *storepb.IdentityProviderUserInfo
 	Role                       api.Role
 	MemberDeleted              bool
+	MFAConfig                  *storepb.MFAConfig
 }
 
 // GetUser gets an user.
@@ -261,6 +263,7 @@ func (s *Store) listUserImpl(ctx context.Context, tx *Tx, find *FindUserMessage)
 				principal.type,
 				principal.password_hash,
 				principal.idp_user_info,
+				principal.mfa_config,
 				member.role,
 				member.row_status AS row_status,
 				idp.resource_id AS idp_resource_id
@@ -277,7 +280,7 @@ func (s *Store) listUserImpl(ctx context.Context, tx *Tx, find *FindUserMessage)
 	for rows.Next() {
 		var userMessage UserMessage
 		var role, rowStatus, idpResourceID sql.NullString
-		var idpUserInfo string
+		var idpUserInfo, mfaConfig string
 		if err := rows.Scan(
 			&userMessage.ID,
 			&userMessage.Email,
@@ -285,6 +288,7 @@ func (s *Store) listUserImpl(ctx context.Context, tx *Tx, find *FindUserMessage)
 			&userMessage.Type,
 			&userMessage.PasswordHash,
 			&idpUserInfo,
+			&mfaConfig,
 			&role,
 			&rowStatus,
 			&idpResourceID,
@@ -311,6 +315,10 @@ func (s *Store) listUserImpl(ctx context.Context, tx *Tx, find *FindUserMessage)
 			}
 			userMessage.IdentityProviderUserInfo = &identityProviderUserInfo
 		}
+		userMessage.MFAConfig = &storepb.MFAConfig{}
+		if err := protojson.Unmarshal([]byte(mfaConfig), userMessage.MFAConfig); err != nil {
+			return nil, err
+		}
 		userMessages = append(userMessages, &userMessage)
 	}
 	return userMessages, nil
@@ -407,9 +415,11 @@ func (s *Store) CreateUser(ctx context.Context, create *UserMessage, creatorID i
 		Role:                       role,
 		IdentityProviderResourceID: create.IdentityProviderResourceID,
 		IdentityProviderUserInfo:   create.IdentityProviderUserInfo,
+		MFAConfig:                  &storepb.MFAConfig{},
 	}
 	s.userIDCache.Store(user.ID, user)
 	s.userEmailCache.Store(user.Email, user)
+	s.notifyCacheInvalidation(ctx, cacheTypeUser)
 	return user, nil
 }
 
@@ -433,6 +443,13 @@ func (s *Store) UpdateUser(ctx context.Context, userID int, patch *UpdateUserMes
 	if v := patch.PasswordHash; v != nil {
 		principalSet, principalArgs = append(principalSet, fmt.Sprintf("password_hash = $%d", len(principalArgs)+1)), append(principalArgs, *v)
 	}
+	if v := patch.MFAConfig; v != nil {
+		mfaConfigBytes, err := protojson.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		principalSet, principalArgs = append(principalSet, fmt.Sprintf("mfa_config = $%d", len(principalArgs)+1)), append(principalArgs, string(mfaConfigBytes))
+	}
 	principalArgs = append(principalArgs, userID)
 
 	memberSet, memberArgs := []string{"updater_id = $1"}, []interface{}{fmt.Sprintf("%d", updaterID)}
@@ -455,11 +472,12 @@ func (s *Store) UpdateUser(ctx context.Context, userID int, patch *UpdateUserMes
 	defer tx.Rollback()
 
 	user := &UserMessage{}
+	var mfaConfig string
 	if err := tx.QueryRowContext(ctx, fmt.Sprintf(`
 			UPDATE principal
 			SET `+strings.Join(principalSet, ", ")+`
 			WHERE id = $%d
-			RETURNING id, email, name, type, password_hash
+			RETURNING id, email, name, type, password_hash, mfa_config
 		`, len(principalArgs)),
 		principalArgs...,
 	).Scan(
@@ -468,6 +486,7 @@ func (s *Store) UpdateUser(ctx context.Context, userID int, patch *UpdateUserMes
 		&user.Name,
 		&user.Type,
 		&user.PasswordHash,
+		&mfaConfig,
 	); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -492,6 +511,10 @@ func (s *Store) UpdateUser(ctx context.Context, userID int, patch *UpdateUserMes
 		return nil, FormatError(err)
 	}
 	user.MemberDeleted = convertRowStatusToDeleted(rowStatus)
+	user.MFAConfig = &storepb.MFAConfig{}
+	if err := protojson.Unmarshal([]byte(mfaConfig), user.MFAConfig); err != nil {
+		return nil, err
+	}
 
 	if err := tx.Commit(); err != nil {
 		return nil, FormatError(err)
@@ -499,5 +522,49 @@ func (s *Store) UpdateUser(ctx context.Context, userID int, patch *UpdateUserMes
 	s.userEmailCache.Delete(oldUser.Email)
 	s.userIDCache.Store(user.ID, user)
 	s.userEmailCache.Store(user.Email, user)
+	s.notifyCacheInvalidation(ctx, cacheTypeUser)
 	return user, nil
 }
+
+// GetUserLockedUntil returns the time until which the user is locked out of login, it's 0 if the user has never been locked out.
+// The lockout isn't cached so that it takes effect on all the replicas immediately.
+func (s *Store) GetUserLockedUntil(ctx context.Context, userID int) (int64, error) {
+	var lockedUntilTs int64
+	if err := s.db.db.QueryRowContext(ctx, `
+		SELECT locked_until_ts
+		FROM principal
+		WHERE id = $1
+	`, userID).Scan(&lockedUntilTs); err != nil {
+		return 0, FormatError(err)
+	}
+	return lockedUntilTs, nil
+}
+
+// RecordFailedLogin counts a failed login of the user. The user is locked out until lockedUntilTs
+// after maxFailures consecutive failures, and the count starts over.
+func (s *Store) RecordFailedLogin(ctx context.Context, userID int, maxFailures int, lockedUntilTs int64) (bool, error) {
+	var locked bool
+	if err := s.db.db.QueryRowContext(ctx, `
+		UPDATE principal
+		SET
+			failed_login_count = CASE WHEN failed_login_count + 1 >= $1 THEN 0 ELSE failed_login_count + 1 END,
+			locked_until_ts = CASE WHEN failed_login_count + 1 >= $1 THEN $2 ELSE locked_until_ts END
+		WHERE id = $3
+		RETURNING locked_until_ts = $2
+	`, maxFailures, lockedUntilTs, userID).Scan(&locked); err != nil {
+		return false, FormatError(err)
+	}
+	return locked, nil
+}
+
+// ResetFailedLogins resets the count of the consecutive failed logins after a successful login.
+func (s *Store) ResetFailedLogins(ctx context.Context, userID int) error {
+	if _, err := s.db.db.ExecContext(ctx, `
+		UPDATE principal
+		SET failed_login_count = 0
+		WHERE id = $1 AND failed_login_count > 0
+	`, userID); err != nil {
+		return FormatError(err)
+	}
+	return nil
+}
@@ -328,6 +328,7 @@ func (s *Store) CreateProjectV2(ctx context.Context, create *ProjectMessage, cre
 
 	s.projectCache.Store(project.ResourceID, project)
 	s.projectIDCache.Store(project.UID, project)
+	s.notifyCacheInvalidation(ctx, cacheTypeProject)
 	return project, nil
 }
 
@@ -350,6 +351,7 @@ func (s *Store) UpdateProjectV2(ctx context.Context, patch *UpdateProjectMessage
 
 	s.projectCache.Store(project.ResourceID, project)
 	s.projectIDCache.Store(project.UID, project)
+	s.notifyCacheInvalidation(ctx, cacheTypeProject)
 	return project, nil
 }
 
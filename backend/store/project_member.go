@@ -105,6 +105,7 @@ func (s *Store) SetProjectIAMPolicy(ctx context.Context, set *IAMPolicyMessage,
 
 	s.projectPolicyCache.Delete(project.ResourceID)
 	s.projectIDPolicyCache.Delete(project.UID)
+	s.notifyCacheInvalidation(ctx, cacheTypeProjectPolicy)
 	return s.GetProjectPolicy(ctx, &GetProjectPolicyMessage{UID: &projectUID})
 }
 
@@ -0,0 +1,276 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/backend/common/log"
+)
+
+const (
+	// cacheInvalidationChannel is the Postgres notification channel to invalidate the caches of the other replicas.
+	cacheInvalidationChannel = "bytebase_cache_invalidation"
+	// leaderLockKey is the key of the Postgres advisory lock held by the leader replica, it's "bytebase" in ASCII.
+	leaderLockKey int64 = 0x6279746562617365
+
+	replicaRetryInterval       = 5 * time.Second
+	leaderHealthCheckInterval  = 5 * time.Second
+	leaderHealthCheckTimeout   = 3 * time.Second
+	cacheInvalidationNotifyTTL = 3 * time.Second
+)
+
+// cacheType is the group of the caches which are invalidated together.
+type cacheType string
+
+const (
+	cacheTypeUser          cacheType = "user"
+	cacheTypeEnvironment   cacheType = "environment"
+	cacheTypeInstance      cacheType = "instance"
+	cacheTypeDatabase      cacheType = "database"
+	cacheTypeProject       cacheType = "project"
+	cacheTypeProjectPolicy cacheType = "project-policy"
+	cacheTypePolicy        cacheType = "policy"
+	cacheTypeIssue         cacheType = "issue"
+	cacheTypePipeline      cacheType = "pipeline"
+	cacheTypeDBSchema      cacheType = "db-schema"
+	cacheTypeSetting       cacheType = "setting"
+	cacheTypeIDP           cacheType = "idp"
+	cacheTypeRole          cacheType = "role"
+)
+
+// cacheInvalidation is the payload of the cache invalidation notification.
+type cacheInvalidation struct {
+	Replica string    `json:"replica"`
+	Cache   cacheType `json:"cache"`
+}
+
+// EnableHA enables the high availability mode, in which multiple replicas share the same metadata database.
+// The replicas notify each other to invalidate the caches after the cached objects are changed.
+func (s *Store) EnableHA(replicaID string) {
+	s.replicaID = replicaID
+}
+
+func (s *Store) getCaches(tp cacheType) []*sync.Map {
+	switch tp {
+	case cacheTypeUser:
+		return []*sync.Map{&s.userIDCache, &s.userEmailCache}
+	case cacheTypeEnvironment:
+		return []*sync.Map{&s.environmentCache, &s.environmentIDCache}
+	case cacheTypeInstance:
+		return []*sync.Map{&s.instanceCache, &s.instanceIDCache}
+	case cacheTypeDatabase:
+		return []*sync.Map{&s.databaseCache, &s.databaseIDCache}
+	case cacheTypeProject:
+		return []*sync.Map{&s.projectCache, &s.projectIDCache}
+	case cacheTypeProjectPolicy:
+		return []*sync.Map{&s.projectPolicyCache, &s.projectIDPolicyCache}
+	case cacheTypePolicy:
+		return []*sync.Map{&s.policyCache}
+	case cacheTypeIssue:
+		return []*sync.Map{&s.issueCache, &s.issueByPipelineCache}
+	case cacheTypePipeline:
+		return []*sync.Map{&s.pipelineCache}
+	case cacheTypeDBSchema:
+		return []*sync.Map{&s.dbSchemaCache}
+	case cacheTypeSetting:
+		return []*sync.Map{&s.settingCache}
+	case cacheTypeIDP:
+		return []*sync.Map{&s.idpCache}
+	case cacheTypeRole:
+		return []*sync.Map{&s.roleCache}
+	}
+	return nil
+}
+
+// invalidateCache drops all the entries of the cache type, they are loaded from the database on the next read.
+func (s *Store) invalidateCache(tp cacheType) {
+	for _, cache := range s.getCaches(tp) {
+		cache.Range(func(key, _ any) bool {
+			cache.Delete(key)
+			return true
+		})
+	}
+}
+
+func (s *Store) invalidateAllCaches() {
+	for _, tp := range []cacheType{
+		cacheTypeUser,
+		cacheTypeEnvironment,
+		cacheTypeInstance,
+		cacheTypeDatabase,
+		cacheTypeProject,
+		cacheTypeProjectPolicy,
+		cacheTypePolicy,
+		cacheTypeIssue,
+		cacheTypePipeline,
+		cacheTypeDBSchema,
+		cacheTypeSetting,
+		cacheTypeIDP,
+		cacheTypeRole,
+	} {
+		s.invalidateCache(tp)
+	}
+}
+
+// notifyCacheInvalidation notifies the other replicas to invalidate the cache type after the cached objects are changed.
+// It's a no-op if the HA mode is disabled. The change is already committed, so the failure is only logged.
+func (s *Store) notifyCacheInvalidation(ctx context.Context, tp cacheType) {
+	if s.replicaID == "" {
+		return
+	}
+	payload, err := json.Marshal(&cacheInvalidation{Replica: s.replicaID, Cache: tp})
+	if err != nil {
+		log.Warn("Failed to marshal cache invalidation", zap.String("cache", string(tp)), zap.Error(err))
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, cacheInvalidationNotifyTTL)
+	defer cancel()
+	if _, err := s.db.db.ExecContext(ctx, `SELECT pg_notify($1, $2)`, cacheInvalidationChannel, string(payload)); err != nil {
+		log.Warn("Failed to notify cache invalidation", zap.String("cache", string(tp)), zap.Error(err))
+	}
+}
+
+func (s *Store) handleCacheInvalidation(payload string) {
+	var invalidation cacheInvalidation
+	if err := json.Unmarshal([]byte(payload), &invalidation); err != nil {
+		log.Warn("Failed to unmarshal cache invalidation", zap.String("payload", payload), zap.Error(err))
+		return
+	}
+	if invalidation.Replica == s.replicaID {
+		return
+	}
+	s.invalidateCache(invalidation.Cache)
+}
+
+// RunCacheInvalidationListener listens to the cache invalidation notifications from the other replicas.
+func (s *Store) RunCacheInvalidationListener(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+	log.Debug("Cache invalidation listener started", zap.String("replica", s.replicaID))
+	for {
+		err := s.listenCacheInvalidation(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Warn("Cache invalidation listener disconnected, retrying", zap.Error(err))
+		select {
+		case <-time.After(replicaRetryInterval):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *Store) listenCacheInvalidation(ctx context.Context) error {
+	conn, err := s.db.db.Conn(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get connection")
+	}
+	defer discardConn(conn)
+
+	return conn.Raw(func(driverConn any) error {
+		c, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return errors.Errorf("unexpected connection type %T", driverConn)
+		}
+		pgxConn := c.Conn()
+		if _, err := pgxConn.Exec(ctx, "LISTEN "+cacheInvalidationChannel); err != nil {
+			return errors.Wrap(err, "failed to listen to cache invalidation")
+		}
+		// The notifications sent before listening or while disconnected are missed.
+		s.invalidateAllCaches()
+		for {
+			notification, err := pgxConn.WaitForNotification(ctx)
+			if err != nil {
+				return errors.Wrap(err, "failed to wait for cache invalidation")
+			}
+			s.handleCacheInvalidation(notification.Payload)
+		}
+	})
+}
+
+// RunLeaderElection elects the leader among the replicas by the Postgres advisory lock.
+// lead is called with a context canceled after the replica loses the leadership, and it should return after the context is canceled.
+// The lock is held by the session, so it's released by Postgres if the leader is gone.
+func (s *Store) RunLeaderElection(ctx context.Context, wg *sync.WaitGroup, lead func(ctx context.Context)) {
+	defer wg.Done()
+	log.Debug("Leader election started", zap.String("replica", s.replicaID))
+	for {
+		if err := s.tryLead(ctx, lead); err != nil && ctx.Err() == nil {
+			log.Warn("Leader election failed, retrying", zap.String("replica", s.replicaID), zap.Error(err))
+		}
+		select {
+		case <-time.After(replicaRetryInterval):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// tryLead tries to acquire the leader lock, and calls lead until the lock session is broken or ctx is canceled.
+func (s *Store) tryLead(ctx context.Context, lead func(ctx context.Context)) error {
+	conn, err := s.db.db.Conn(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get connection")
+	}
+	// Discard the connection instead of returning it to the pool to release the lock.
+	defer discardConn(conn)
+
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1)`, leaderLockKey).Scan(&acquired); err != nil {
+		return errors.Wrap(err, "failed to acquire leader lock")
+	}
+	if !acquired {
+		return nil
+	}
+
+	log.Info("Became the leader replica", zap.String("replica", s.replicaID))
+	leaderCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		lead(leaderCtx)
+	}()
+	defer func() {
+		cancel()
+		<-done
+		log.Info("Stepped down from the leader replica", zap.String("replica", s.replicaID))
+	}()
+
+	ticker := time.NewTicker(leaderHealthCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := pingConn(ctx, conn); err != nil {
+				return errors.Wrap(err, "failed to keep the leader lock")
+			}
+		case <-done:
+			// Give up the leadership so that another replica can take over.
+			return errors.New("leader exited unexpectedly")
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func pingConn(ctx context.Context, conn *sql.Conn) error {
+	ctx, cancel := context.WithTimeout(ctx, leaderHealthCheckTimeout)
+	defer cancel()
+	return conn.PingContext(ctx)
+}
+
+// discardConn closes the underlying session of the connection, so that the session states such as LISTEN and advisory locks don't leak to the pool.
+func discardConn(conn *sql.Conn) {
+	_ = conn.Raw(func(any) error {
+		return driver.ErrBadConn
+	})
+	_ = conn.Close()
+}
@@ -0,0 +1,39 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleCacheInvalidation(t *testing.T) {
+	a := require.New(t)
+	s := New(nil)
+	s.EnableHA("replica-1")
+	s.instanceCache.Store("prod/mysql", &InstanceMessage{})
+	s.instanceIDCache.Store(101, &InstanceMessage{})
+	s.databaseIDCache.Store(101, &DatabaseMessage{})
+
+	// The notifications from the replica itself are ignored.
+	s.handleCacheInvalidation(`{"replica":"replica-1","cache":"instance"}`)
+	_, ok := s.instanceIDCache.Load(101)
+	a.True(ok)
+
+	s.handleCacheInvalidation(`{"replica":"replica-2","cache":"instance"}`)
+	_, ok = s.instanceCache.Load("prod/mysql")
+	a.False(ok)
+	_, ok = s.instanceIDCache.Load(101)
+	a.False(ok)
+	// The other cache types are kept.
+	_, ok = s.databaseIDCache.Load(101)
+	a.True(ok)
+
+	// Malformed notifications are ignored.
+	s.handleCacheInvalidation(`not json`)
+	_, ok = s.databaseIDCache.Load(101)
+	a.True(ok)
+
+	s.invalidateAllCaches()
+	_, ok = s.databaseIDCache.Load(101)
+	a.False(ok)
+}
@@ -144,6 +144,10 @@ func (s *Store) DeleteRepository(ctx context.Context, delete *api.RepositoryDele
 	if err := tx.Commit(); err != nil {
 		return FormatError(err)
 	}
+	// The project workflow type is updated along with the repository.
+	s.projectCache.Delete(delete.ProjectResourceID)
+	s.projectIDCache.Delete(delete.ProjectID)
+	s.notifyCacheInvalidation(ctx, cacheTypeProject)
 
 	return nil
 }
@@ -190,6 +194,7 @@ func (s *Store) createRepositoryRaw(ctx context.Context, create *api.RepositoryC
 	if err := tx.Commit(); err != nil {
 		return nil, FormatError(err)
 	}
+	s.notifyCacheInvalidation(ctx, cacheTypeProject)
 
 	return repository, nil
 }
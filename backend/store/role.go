@@ -0,0 +1,414 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/common"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+)
+
+// RoleMessage is the message for a custom role made of fine-grained permissions.
+type RoleMessage struct {
+	ResourceID  string
+	Name        string
+	Description string
+	Permissions []string
+
+	// Output only.
+	UID int
+}
+
+// HasPermission returns whether the role grants the permission.
+func (r *RoleMessage) HasPermission(permission string) bool {
+	for _, p := range r.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// FindRoleMessage is the message for finding custom roles.
+type FindRoleMessage struct {
+	ResourceID *string
+}
+
+// UpdateRoleMessage is the message for updating a custom role.
+type UpdateRoleMessage struct {
+	UpdaterID  int
+	ResourceID string
+
+	Name        *string
+	Description *string
+	Permissions *[]string
+}
+
+// workspaceIAMPolicy is the value of the workspace IAM policy setting.
+type workspaceIAMPolicy struct {
+	Bindings []*workspaceIAMPolicyBinding `json:"bindings"`
+}
+
+type workspaceIAMPolicyBinding struct {
+	Role    api.Role `json:"role"`
+	Members []int    `json:"members"`
+}
+
+// GetRole gets a custom role by resource ID.
+func (s *Store) GetRole(ctx context.Context, resourceID string) (*RoleMessage, error) {
+	if role, ok := s.roleCache.Load(resourceID); ok {
+		return role.(*RoleMessage), nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.Rollback()
+
+	roles, err := listRoleImpl(ctx, tx, &FindRoleMessage{ResourceID: &resourceID})
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+	if len(roles) == 0 {
+		return nil, nil
+	}
+
+	s.roleCache.Store(roles[0].ResourceID, roles[0])
+	return roles[0], nil
+}
+
+// ListRoles lists the custom roles.
+func (s *Store) ListRoles(ctx context.Context, find *FindRoleMessage) ([]*RoleMessage, error) {
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.Rollback()
+
+	roles, err := listRoleImpl(ctx, tx, find)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	for _, role := range roles {
+		s.roleCache.Store(role.ResourceID, role)
+	}
+	return roles, nil
+}
+
+// CreateRole creates a custom role.
+func (s *Store) CreateRole(ctx context.Context, create *RoleMessage, creatorID int) (*RoleMessage, error) {
+	permissions, err := json.Marshal(create.Permissions)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal permissions")
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.Rollback()
+
+	role := &RoleMessage{
+		ResourceID:  create.ResourceID,
+		Name:        create.Name,
+		Description: create.Description,
+		Permissions: create.Permissions,
+	}
+	if err := tx.QueryRowContext(ctx, `
+		INSERT INTO role (
+			creator_id,
+			updater_id,
+			resource_id,
+			name,
+			description,
+			permissions
+		)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`,
+		creatorID,
+		creatorID,
+		create.ResourceID,
+		create.Name,
+		create.Description,
+		permissions,
+	).Scan(&role.UID); err != nil {
+		return nil, FormatError(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	s.roleCache.Store(role.ResourceID, role)
+	s.notifyCacheInvalidation(ctx, cacheTypeRole)
+	return role, nil
+}
+
+// UpdateRole updates a custom role.
+func (s *Store) UpdateRole(ctx context.Context, patch *UpdateRoleMessage) (*RoleMessage, error) {
+	set, args := []string{"updater_id = $1"}, []interface{}{patch.UpdaterID}
+	if v := patch.Name; v != nil {
+		set, args = append(set, fmt.Sprintf("name = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.Description; v != nil {
+		set, args = append(set, fmt.Sprintf("description = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.Permissions; v != nil {
+		permissions, err := json.Marshal(*v)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal permissions")
+		}
+		set, args = append(set, fmt.Sprintf("permissions = $%d", len(args)+1)), append(args, permissions)
+	}
+	args = append(args, patch.ResourceID)
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.Rollback()
+
+	role := &RoleMessage{}
+	var permissions []byte
+	if err := tx.QueryRowContext(ctx, fmt.Sprintf(`
+		UPDATE role
+		SET `+strings.Join(set, ", ")+`
+		WHERE resource_id = $%d
+		RETURNING id, resource_id, name, description, permissions
+	`, len(args)),
+		args...,
+	).Scan(
+		&role.UID,
+		&role.ResourceID,
+		&role.Name,
+		&role.Description,
+		&permissions,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &common.Error{Code: common.NotFound, Err: errors.Errorf("role not found: %s", patch.ResourceID)}
+		}
+		return nil, FormatError(err)
+	}
+	if err := json.Unmarshal(permissions, &role.Permissions); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal permissions")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	s.roleCache.Store(role.ResourceID, role)
+	s.notifyCacheInvalidation(ctx, cacheTypeRole)
+	return role, nil
+}
+
+// DeleteRole deletes a custom role, the role must not be bound in the workspace or any project.
+func (s *Store) DeleteRole(ctx context.Context, resourceID string) error {
+	workspacePolicy, err := s.GetWorkspaceIAMPolicy(ctx)
+	if err != nil {
+		return err
+	}
+	for _, binding := range workspacePolicy.Bindings {
+		if string(binding.Role) == resourceID && len(binding.Members) > 0 {
+			return &common.Error{Code: common.Conflict, Err: errors.Errorf("role %q is bound in the workspace IAM policy", resourceID)}
+		}
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormatError(err)
+	}
+	defer tx.Rollback()
+
+	var memberCount int
+	if err := tx.QueryRowContext(ctx, `
+		SELECT COUNT(1) FROM project_member WHERE role = $1 AND row_status = $2
+	`, resourceID, api.Normal).Scan(&memberCount); err != nil {
+		return FormatError(err)
+	}
+	if memberCount > 0 {
+		return &common.Error{Code: common.Conflict, Err: errors.Errorf("role %q is bound to %d project members", resourceID, memberCount)}
+	}
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM role WHERE resource_id = $1`, resourceID)
+	if err != nil {
+		return FormatError(err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return &common.Error{Code: common.NotFound, Err: errors.Errorf("role not found: %s", resourceID)}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return FormatError(err)
+	}
+
+	s.roleCache.Delete(resourceID)
+	s.notifyCacheInvalidation(ctx, cacheTypeRole)
+	return nil
+}
+
+func listRoleImpl(ctx context.Context, tx *Tx, find *FindRoleMessage) ([]*RoleMessage, error) {
+	where, args := []string{"TRUE"}, []interface{}{}
+	if v := find.ResourceID; v != nil {
+		where, args = append(where, fmt.Sprintf("resource_id = $%d", len(args)+1)), append(args, *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			resource_id,
+			name,
+			description,
+			permissions
+		FROM role
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY id ASC`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	var roles []*RoleMessage
+	for rows.Next() {
+		role := &RoleMessage{}
+		var permissions []byte
+		if err := rows.Scan(
+			&role.UID,
+			&role.ResourceID,
+			&role.Name,
+			&role.Description,
+			&permissions,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+		if err := json.Unmarshal(permissions, &role.Permissions); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal permissions")
+		}
+		roles = append(roles, role)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+	return roles, nil
+}
+
+// GetWorkspaceIAMPolicy gets the custom role bindings of the workspace.
+// The workspace roles of the members are not included, they are granted in addition to the custom roles.
+func (s *Store) GetWorkspaceIAMPolicy(ctx context.Context) (*IAMPolicyMessage, error) {
+	settingName := api.SettingWorkspaceIAMPolicy
+	setting, err := s.GetSettingV2(ctx, &FindSettingMessage{Name: &settingName})
+	if err != nil {
+		return nil, err
+	}
+	policy := &IAMPolicyMessage{}
+	if setting == nil || setting.Value == "" {
+		return policy, nil
+	}
+
+	var value workspaceIAMPolicy
+	if err := json.Unmarshal([]byte(setting.Value), &value); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal workspace IAM policy")
+	}
+	for _, b := range value.Bindings {
+		binding := &PolicyBinding{Role: b.Role}
+		for _, memberID := range b.Members {
+			user, err := s.GetUserByID(ctx, memberID)
+			if err != nil {
+				return nil, err
+			}
+			if user == nil {
+				continue
+			}
+			binding.Members = append(binding.Members, user)
+		}
+		policy.Bindings = append(policy.Bindings, binding)
+	}
+	return policy, nil
+}
+
+// SetWorkspaceIAMPolicy sets the custom role bindings of the workspace.
+func (s *Store) SetWorkspaceIAMPolicy(ctx context.Context, set *IAMPolicyMessage, updaterID int) (*IAMPolicyMessage, error) {
+	value := &workspaceIAMPolicy{}
+	for _, binding := range set.Bindings {
+		b := &workspaceIAMPolicyBinding{Role: binding.Role}
+		for _, member := range binding.Members {
+			b.Members = append(b.Members, member.ID)
+		}
+		sort.Ints(b.Members)
+		value.Bindings = append(value.Bindings, b)
+	}
+	sort.Slice(value.Bindings, func(i, j int) bool {
+		return value.Bindings[i].Role < value.Bindings[j].Role
+	})
+	bytes, err := json.Marshal(value)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal workspace IAM policy")
+	}
+	if _, err := s.UpsertSettingV2(ctx, &SetSettingMessage{
+		Name:  api.SettingWorkspaceIAMPolicy,
+		Value: string(bytes),
+	}, updaterID); err != nil {
+		return nil, err
+	}
+	return s.GetWorkspaceIAMPolicy(ctx)
+}
+
+// GetWorkspacePermissions returns the permissions granted to the principal by the custom roles bound in the workspace IAM policy.
+func (s *Store) GetWorkspacePermissions(ctx context.Context, principalID int) (map[string]bool, error) {
+	policy, err := s.GetWorkspaceIAMPolicy(ctx)
+	if err != nil {
+		return nil, err
+	}
+	permissions := make(map[string]bool)
+	for _, binding := range policy.Bindings {
+		for _, member := range binding.Members {
+			if member.ID != principalID {
+				continue
+			}
+			role, err := s.GetRole(ctx, string(binding.Role))
+			if err != nil {
+				return nil, err
+			}
+			if role == nil {
+				continue
+			}
+			for _, permission := range role.Permissions {
+				permissions[permission] = true
+			}
+		}
+	}
+	return permissions, nil
+}
+
+// HasProjectPermission returns whether the project role is granted the project permission.
+// The built-in roles follow api.ProjectPermission, and the custom roles are granted the permissions they are made of.
+func (s *Store) HasProjectPermission(ctx context.Context, plan api.PlanType, role api.Role, permission api.ProjectPermissionType) (bool, error) {
+	if role == "" || role == api.UnknownRole {
+		return false, nil
+	}
+	if api.IsBuiltinProjectRole(role) {
+		return api.ProjectPermission(permission, plan, common.ProjectRole(role)), nil
+	}
+	customRole, err := s.GetRole(ctx, string(role))
+	if err != nil {
+		return false, err
+	}
+	return customRole != nil && customRole.HasPermission(string(permission)), nil
+}
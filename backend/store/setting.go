@@ -167,6 +167,7 @@ func (s *Store) UpsertSettingV2(ctx context.Context, update *SetSettingMessage,
 		return nil, errors.Wrap(err, "failed to commit transaction")
 	}
 	s.settingCache.Store(setting.Name, &setting)
+	s.notifyCacheInvalidation(ctx, cacheTypeSetting)
 	return &setting, nil
 }
 
@@ -212,6 +213,7 @@ func (s *Store) CreateSettingIfNotExistV2(ctx context.Context, create *SettingMe
 		return nil, false, errors.Wrap(err, "failed to commit transaction")
 	}
 	s.settingCache.Store(setting.Name, &setting)
+	s.notifyCacheInvalidation(ctx, cacheTypeSetting)
 	return &setting, true, nil
 }
 
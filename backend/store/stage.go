@@ -3,8 +3,13 @@ package store
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"strings"
+
+	"github.com/pkg/errors"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
 )
 
 // StageMessage is the message for stage.
@@ -12,6 +17,7 @@ type StageMessage struct {
 	Name          string
 	EnvironmentID int
 	PipelineID    int
+	Payload       *api.StagePayload
 	// The earliest stage with incompleted tasks.
 	Active bool
 	// Output only.
@@ -29,15 +35,24 @@ func (s *Store) CreateStageV2(ctx context.Context, stagesCreate []*StageMessage,
 	var valueStr []string
 	var values []interface{}
 	for i, create := range stagesCreate {
+		payload := create.Payload
+		if payload == nil {
+			payload = &api.StagePayload{}
+		}
+		payloadBytes, err := json.Marshal(payload)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal stage payload")
+		}
 		values = append(values,
 			creatorID,
 			creatorID,
 			create.PipelineID,
 			create.EnvironmentID,
 			create.Name,
+			string(payloadBytes),
 		)
-		const count = 5
-		valueStr = append(valueStr, fmt.Sprintf("($%d,$%d,$%d,$%d,$%d)", i*count+1, i*count+2, i*count+3, i*count+4, i*count+5))
+		const count = 6
+		valueStr = append(valueStr, fmt.Sprintf("($%d,$%d,$%d,$%d,$%d,$%d)", i*count+1, i*count+2, i*count+3, i*count+4, i*count+5, i*count+6))
 	}
 
 	query := fmt.Sprintf(`
@@ -47,9 +62,10 @@ func (s *Store) CreateStageV2(ctx context.Context, stagesCreate []*StageMessage,
 	  		updater_id,
 	  		pipeline_id,
 	  		environment_id,
-	  		name
+	  		name,
+	  		payload
 	  	) VALUES %s
-	  	RETURNING id, pipeline_id, environment_id, name
+	  	RETURNING id, pipeline_id, environment_id, name, payload
     ) SELECT * FROM inserted ORDER BY id ASC
     `, strings.Join(valueStr, ","))
 	rows, err := tx.QueryContext(ctx, query, values...)
@@ -61,14 +77,20 @@ func (s *Store) CreateStageV2(ctx context.Context, stagesCreate []*StageMessage,
 	var stages []*StageMessage
 	for rows.Next() {
 		var stage StageMessage
+		var payload string
 		if err := rows.Scan(
 			&stage.ID,
 			&stage.PipelineID,
 			&stage.EnvironmentID,
 			&stage.Name,
+			&payload,
 		); err != nil {
 			return nil, FormatError(err)
 		}
+		stage.Payload = &api.StagePayload{}
+		if err := json.Unmarshal([]byte(payload), stage.Payload); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal stage payload")
+		}
 		stages = append(stages, &stage)
 	}
 	if err := rows.Err(); err != nil {
@@ -99,6 +121,7 @@ func (s *Store) ListStageV2(ctx context.Context, pipelineUID int) ([]*StageMessa
 			pipeline_id,
 			environment_id,
 			name,
+			payload,
 			(SELECT COUNT(1) > 0 FROM task as other_task WHERE other_task.pipeline_id = stage.pipeline_id AND other_task.stage_id <= stage.id AND other_task.status != 'DONE')
 		FROM stage
 		WHERE %s ORDER BY id ASC`, strings.Join(where, " AND ")),
@@ -112,15 +135,21 @@ func (s *Store) ListStageV2(ctx context.Context, pipelineUID int) ([]*StageMessa
 	var stages []*StageMessage
 	for rows.Next() {
 		var stage StageMessage
+		var payload string
 		if err := rows.Scan(
 			&stage.ID,
 			&stage.PipelineID,
 			&stage.EnvironmentID,
 			&stage.Name,
+			&payload,
 			&stage.Active,
 		); err != nil {
 			return nil, FormatError(err)
 		}
+		stage.Payload = &api.StagePayload{}
+		if err := json.Unmarshal([]byte(payload), stage.Payload); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal stage payload")
+		}
 
 		stages = append(stages, &stage)
 	}
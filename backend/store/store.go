@@ -11,6 +11,8 @@ import (
 // Store provides database access to all raw objects.
 type Store struct {
 	db *DB
+	// replicaID is the ID of this replica in the HA mode, and it's empty if the HA mode is disabled.
+	replicaID string
 
 	userIDCache          sync.Map // map[int]*UserMessage
 	userEmailCache       sync.Map // map[string]*UserMessage
@@ -31,6 +33,7 @@ type Store struct {
 	dbSchemaCache        sync.Map // map[int]*DBSchema
 	settingCache         sync.Map // map[string]*SettingMessage
 	idpCache             sync.Map // map[string]*IdentityProvider
+	roleCache            sync.Map // map[string]*RoleMessage
 }
 
 // New creates a new instance of Store.
@@ -9,8 +9,10 @@ import (
 
 	"github.com/lib/pq"
 	"github.com/pkg/errors"
+	"go.uber.org/zap"
 
 	"github.com/bytebase/bytebase/backend/common"
+	"github.com/bytebase/bytebase/backend/common/log"
 	api "github.com/bytebase/bytebase/backend/legacyapi"
 )
 
@@ -160,6 +162,13 @@ func (s *Store) composeTask(ctx context.Context, task *TaskMessage) (*api.Task,
 		taskCheckRun.Updater = updater
 		composedTask.TaskCheckRunList = append(composedTask.TaskCheckRunList, taskCheckRun)
 	}
+	// The estimated affected rows are informational, so a malformed check result shouldn't fail composing the pipeline.
+	affectedRows, ok, err := api.GetEstimatedAffectedRows(composedTask.TaskCheckRunList)
+	if err != nil {
+		log.Warn("Failed to get estimated affected rows", zap.Int("task_id", composedTask.ID), zap.Error(err))
+	} else if ok {
+		composedTask.EstimatedAffectedRows = affectedRows
+	}
 
 	instance, err := s.GetInstanceByID(ctx, composedTask.InstanceID)
 	if err != nil {
@@ -417,8 +426,8 @@ func (s *Store) UpdateTaskV2(ctx context.Context, patch *api.TaskPatch) (*TaskMe
 	if v := patch.DatabaseID; v != nil {
 		set, args = append(set, fmt.Sprintf("database_id = $%d", len(args)+1)), append(args, *v)
 	}
-	if (patch.Statement != nil || patch.SchemaVersion != nil) && patch.Payload != nil {
-		return nil, errors.Errorf("cannot set both statement/schemaVersion and payload for TaskPatch")
+	if (patch.Statement != nil || patch.SchemaVersion != nil || patch.ChunkConfig != nil || patch.ChunkCheckpoint != nil) && patch.Payload != nil {
+		return nil, errors.Errorf("cannot set both statement/schemaVersion/chunkConfig/chunkCheckpoint and payload for TaskPatch")
 	}
 	var payloadSet []string
 	if v := patch.Statement; v != nil {
@@ -427,6 +436,12 @@ func (s *Store) UpdateTaskV2(ctx context.Context, patch *api.TaskPatch) (*TaskMe
 	if v := patch.SchemaVersion; v != nil {
 		payloadSet, args = append(payloadSet, fmt.Sprintf(`jsonb_build_object('schemaVersion', to_jsonb($%d::TEXT))`, len(args)+1)), append(args, *v)
 	}
+	if v := patch.ChunkConfig; v != nil {
+		payloadSet, args = append(payloadSet, fmt.Sprintf(`jsonb_build_object('chunkConfig', $%d::JSONB)`, len(args)+1)), append(args, *v)
+	}
+	if v := patch.ChunkCheckpoint; v != nil {
+		payloadSet, args = append(payloadSet, fmt.Sprintf(`jsonb_build_object('chunkCheckpoint', $%d::JSONB)`, len(args)+1)), append(args, *v)
+	}
 	if len(payloadSet) != 0 {
 		set = append(set, fmt.Sprintf(`payload = payload || %s`, strings.Join(payloadSet, "||")))
 	}
@@ -539,6 +554,8 @@ func (s *Store) UpdateTaskStatusV2(ctx context.Context, patch *api.TaskStatusPat
 		case api.TaskFailed:
 			taskRunStatusPatch.Status = api.TaskRunFailed
 		case api.TaskPending:
+			// The running task goes back to pending only when the failed run is retried automatically.
+			taskRunStatusPatch.Status = api.TaskRunFailed
 		case api.TaskPendingApproval:
 		case api.TaskCanceled:
 			taskRunStatusPatch.Status = api.TaskRunCanceled
@@ -559,6 +576,9 @@ func (s *Store) UpdateTaskStatusV2(ctx context.Context, patch *api.TaskStatusPat
 	if v := patch.SkippedReason; v != nil {
 		payloadSet, args = append(payloadSet, fmt.Sprintf(`jsonb_build_object('skippedReason', to_jsonb($%d::TEXT))`, len(args)+1)), append(args, *v)
 	}
+	if v := patch.Resume; v != nil {
+		payloadSet, args = append(payloadSet, fmt.Sprintf(`jsonb_build_object('resume', to_jsonb($%d::BOOLEAN))`, len(args)+1)), append(args, *v)
+	}
 	if len(payloadSet) != 0 {
 		set = append(set, fmt.Sprintf(`payload = payload || %s`, strings.Join(payloadSet, "||")))
 	}
@@ -3,6 +3,7 @@ package store
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -22,6 +23,8 @@ type TaskRunMessage struct {
 	Comment string
 	Result  string
 	Payload string
+	// Progress is the progress of the running task run in json format.
+	Progress string
 
 	// Output only.
 	ID        int
@@ -188,6 +191,28 @@ func (*Store) patchTaskRunStatusImpl(ctx context.Context, tx *Tx, patch *TaskRun
 	return &taskRun, nil
 }
 
+// UpdateTaskRunProgress updates the progress of the running task run of the task.
+// The progress is saved in the database instead of the memory, so that all the replicas can read it.
+func (s *Store) UpdateTaskRunProgress(ctx context.Context, taskID int, progress *api.Progress) error {
+	bytes, err := json.Marshal(progress)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal progress")
+	}
+	if _, err := s.db.db.ExecContext(ctx, `
+		UPDATE task_run
+		SET progress = $1
+		WHERE task_id = $2 AND status = $3
+	`, string(bytes), taskID, api.TaskRunRunning); err != nil {
+		return FormatError(err)
+	}
+	return nil
+}
+
+// ListTaskRunsV2 lists the task runs.
+func (s *Store) ListTaskRunsV2(ctx context.Context, find *TaskRunFind) ([]*TaskRunMessage, error) {
+	return s.listTaskRun(ctx, find)
+}
+
 func (s *Store) listTaskRun(ctx context.Context, find *TaskRunFind) ([]*TaskRunMessage, error) {
 	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
 	if err != nil {
@@ -246,7 +271,8 @@ func (*Store) findTaskRunImpl(ctx context.Context, tx *Tx, find *TaskRunFind) ([
 			task_run.code,
 			task_run.comment,
 			task_run.result,
-			task_run.payload
+			task_run.payload,
+			task_run.progress
 		FROM task_run
 		%s
 		WHERE %s`, joinClause, strings.Join(where, " AND ")),
@@ -274,6 +300,7 @@ func (*Store) findTaskRunImpl(ctx context.Context, tx *Tx, find *TaskRunFind) ([
 			&taskRun.Comment,
 			&taskRun.Result,
 			&taskRun.Payload,
+			&taskRun.Progress,
 		); err != nil {
 			return nil, FormatError(err)
 		}
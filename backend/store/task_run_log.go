@@ -0,0 +1,119 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/plugin/db"
+)
+
+// TaskRunLogMessage is the message for a structured log line of a task run.
+type TaskRunLogMessage struct {
+	TaskRunID int
+	Payload   *db.ExecuteLog
+
+	// Output only.
+	ID        int64
+	CreatedTs int64
+}
+
+// FindTaskRunLogMessage is the message for finding task run logs.
+type FindTaskRunLogMessage struct {
+	TaskRunID int
+	// AfterID finds the logs created after the log with the ID, it's used for tailing the logs.
+	AfterID *int64
+}
+
+// CreateTaskRunLog creates a task run log.
+func (s *Store) CreateTaskRunLog(ctx context.Context, create *TaskRunLogMessage) error {
+	payload, err := json.Marshal(create.Payload)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal payload")
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormatError(err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO task_run_log (
+			task_run_id,
+			payload
+		)
+		VALUES ($1, $2)
+	`,
+		create.TaskRunID,
+		payload,
+	); err != nil {
+		return FormatError(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return FormatError(err)
+	}
+	return nil
+}
+
+// ListTaskRunLogs lists the task run logs in the order of creation.
+func (s *Store) ListTaskRunLogs(ctx context.Context, find *FindTaskRunLogMessage) ([]*TaskRunLogMessage, error) {
+	where, args := []string{"task_run_id = $1"}, []interface{}{find.TaskRunID}
+	if v := find.AfterID; v != nil {
+		where, args = append(where, fmt.Sprintf("id > $%d", len(args)+1)), append(args, *v)
+	}
+
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			task_run_id,
+			created_ts,
+			payload
+		FROM task_run_log
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY id ASC`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	var logs []*TaskRunLogMessage
+	for rows.Next() {
+		log := &TaskRunLogMessage{
+			Payload: &db.ExecuteLog{},
+		}
+		var payload []byte
+		if err := rows.Scan(
+			&log.ID,
+			&log.TaskRunID,
+			&log.CreatedTs,
+			&payload,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+		if err := json.Unmarshal(payload, log.Payload); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal payload")
+		}
+		logs = append(logs, log)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+	return logs, nil
+}
@@ -0,0 +1,73 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/tests/fake"
+)
+
+func TestHighAvailability(t *testing.T) {
+	t.Parallel()
+	a := require.New(t)
+	ctx := context.Background()
+	primary := &controller{}
+	err := primary.StartServerWithExternalPg(ctx, &config{
+		dataDir:                 t.TempDir(),
+		vcsProviderCreator:      fake.NewGitLab,
+		feishuProverdierCreator: fake.NewFeishu,
+		ha:                      true,
+	})
+	a.NoError(err)
+	defer primary.Close(ctx)
+
+	replica := &controller{}
+	err = replica.StartReplica(ctx, primary, &config{
+		dataDir:                 t.TempDir(),
+		vcsProviderCreator:      fake.NewGitLab,
+		feishuProverdierCreator: fake.NewFeishu,
+	})
+	a.NoError(err)
+	defer replica.Close(ctx)
+
+	// Only one replica holds the leader lock.
+	metaDB, err := sql.Open("pgx", primary.profile.PgURL)
+	a.NoError(err)
+	defer metaDB.Close()
+	a.Eventually(func() bool {
+		var count int
+		err := metaDB.QueryRowContext(ctx, `
+			SELECT COUNT(*) FROM pg_locks
+			WHERE locktype = 'advisory' AND granted AND database = (SELECT oid FROM pg_database WHERE datname = current_database())
+		`).Scan(&count)
+		return err == nil && count == 1
+	}, 30*time.Second, time.Second)
+
+	project, err := primary.createProject(api.ProjectCreate{
+		Name:       "HAProject",
+		Key:        "HAP",
+		TenantMode: api.TenantModeDisabled,
+	})
+	a.NoError(err)
+	// Load the project into the cache of the replica.
+	replicaProject, err := replica.getProject(project.ID)
+	a.NoError(err)
+	a.Equal("HAProject", replicaProject.Name)
+
+	// The cache of the replica is invalidated after the project is updated on the primary.
+	name := "HAProjectUpdated"
+	err = primary.patchProject(api.ProjectPatch{
+		ID:   project.ID,
+		Name: &name,
+	})
+	a.NoError(err)
+	a.Eventually(func() bool {
+		replicaProject, err := replica.getProject(project.ID)
+		return err == nil && replicaProject.Name == name
+	}, 10*time.Second, 100*time.Millisecond)
+}
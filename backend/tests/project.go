@@ -53,6 +53,20 @@ func (ctl *controller) getProjects() ([]*api.Project, error) {
 	return projects, nil
 }
 
+// getProject gets the project by ID.
+func (ctl *controller) getProject(id int) (*api.Project, error) {
+	body, err := ctl.get(fmt.Sprintf("/project/%d", id), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	project := new(api.Project)
+	if err = jsonapi.UnmarshalPayload(body, project); err != nil {
+		return nil, errors.Wrap(err, "fail to unmarshal get project response")
+	}
+	return project, nil
+}
+
 func (ctl *controller) patchProject(projectPatch api.ProjectPatch) error {
 	buf := new(bytes.Buffer)
 	if err := jsonapi.MarshalPayload(buf, &projectPatch); err != nil {
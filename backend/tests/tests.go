@@ -149,6 +149,8 @@ type config struct {
 	vcsProviderCreator      fake.VCSProviderCreator
 	feishuProverdierCreator fake.FeishuProviderCreator
 	readOnly                bool
+	// ha runs the server in the HA mode, only applicable to the server with external Postgres.
+	ha bool
 }
 
 var (
@@ -214,6 +216,10 @@ func (ctl *controller) StartServerWithExternalPg(ctx context.Context, config *co
 	pgURL := fmt.Sprintf("postgresql://%s@:%d/%s?host=%s", externalPgUser, externalPgPort, databaseName, common.GetPostgresSocketDir())
 	serverPort := getTestPort()
 	profile := getTestProfileWithExternalPg(config.dataDir, resourceDir, serverPort, externalPgUser, pgURL, ctl.feishuProvider.APIURL(ctl.feishuURL))
+	if config.ha {
+		profile.HA = true
+		profile.ReplicaID = fmt.Sprintf("replica-%d", serverPort)
+	}
 	server, err := server.NewServer(ctx, profile)
 	if err != nil {
 		return err
@@ -230,6 +236,30 @@ func (ctl *controller) StartServerWithExternalPg(ctx context.Context, config *co
 	return ctl.Login()
 }
 
+// StartReplica starts another replica in the HA mode, sharing the external Postgres with the primary server.
+func (ctl *controller) StartReplica(ctx context.Context, primary *controller, config *config) error {
+	log.SetLevel(zap.DebugLevel)
+	if err := ctl.startMockServers(config.vcsProviderCreator, config.feishuProverdierCreator); err != nil {
+		return err
+	}
+
+	serverPort := getTestPort()
+	profile := getTestProfileWithExternalPg(config.dataDir, resourceDir, serverPort, externalPgUser, primary.profile.PgURL, ctl.feishuProvider.APIURL(ctl.feishuURL))
+	profile.HA = true
+	profile.ReplicaID = fmt.Sprintf("replica-%d", serverPort)
+	server, err := server.NewServer(ctx, profile)
+	if err != nil {
+		return err
+	}
+	ctl.server = server
+	ctl.profile = profile
+
+	if err := ctl.start(ctx, serverPort); err != nil {
+		return err
+	}
+	return ctl.Login()
+}
+
 // StartServer starts the main server with embed Postgres.
 func (ctl *controller) StartServer(ctx context.Context, config *config) error {
 	log.SetLevel(zap.DebugLevel)
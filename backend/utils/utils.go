@@ -79,7 +79,8 @@ type GhostConfig struct {
 }
 
 // GetGhostConfig returns a gh-ost configuration for migration.
-func GetGhostConfig(taskID int, database *store.DatabaseMessage, dataSource *store.DataSourceMessage, secret string, instanceUsers []*store.InstanceUserMessage, tableName string, statement string, noop bool, serverIDOffset uint) (GhostConfig, error) {
+// The username and the password are resolved by the caller because they may come from the external secret provider.
+func GetGhostConfig(taskID int, database *store.DatabaseMessage, dataSource *store.DataSourceMessage, username, password string, instanceUsers []*store.InstanceUserMessage, tableName string, statement string, noop bool, serverIDOffset uint) GhostConfig {
 	var isAWS bool
 	for _, user := range instanceUsers {
 		if user.Name == "'rdsadmin'@'localhost'" && strings.Contains(user.Grant, "SUPER") {
@@ -87,14 +88,10 @@ func GetGhostConfig(taskID int, database *store.DatabaseMessage, dataSource *sto
 			break
 		}
 	}
-	password, err := common.Unobfuscate(dataSource.ObfuscatedPassword, secret)
-	if err != nil {
-		return GhostConfig{}, err
-	}
 	return GhostConfig{
 		host:                 dataSource.Host,
 		port:                 dataSource.Port,
-		user:                 dataSource.Username,
+		user:                 username,
 		password:             password,
 		database:             database.DatabaseName,
 		table:                tableName,
@@ -108,7 +105,7 @@ func GetGhostConfig(taskID int, database *store.DatabaseMessage, dataSource *sto
 		serverID: serverIDOffset + uint(taskID),
 		// https://github.com/github/gh-ost/blob/master/doc/rds.md
 		isAWS: isAWS,
-	}, nil
+	}
 }
 
 func getSocketFilename(taskID int, databaseID int, databaseName string, tableName string) string {
@@ -230,9 +227,23 @@ func isMatchExpression(labels map[string]string, expression *api.LabelSelectorRe
 			}
 		}
 		return false
+	case api.NotInOperatorType:
+		value, ok := labels[expression.Key]
+		if !ok {
+			return true
+		}
+		for _, exprValue := range expression.Values {
+			if exprValue == value {
+				return false
+			}
+		}
+		return true
 	case api.ExistsOperatorType:
 		_, ok := labels[expression.Key]
 		return ok
+	case api.DoesNotExistOperatorType:
+		_, ok := labels[expression.Key]
+		return !ok
 	default:
 		return false
 	}
@@ -254,49 +265,40 @@ func isMatchExpressions(labels map[string]string, expressionList []*api.LabelSel
 
 // GetDatabaseMatrixFromDeploymentSchedule gets a pipeline based on deployment schedule.
 // The matrix will include the stage even if the stage has no database.
+// A stage with the rollout limit takes the first databases in the order of database ID, and leaves the rest to the following stages.
 func GetDatabaseMatrixFromDeploymentSchedule(schedule *api.DeploymentSchedule, databaseList []*store.DatabaseMessage) ([][]*store.DatabaseMessage, error) {
 	var matrix [][]*store.DatabaseMessage
 
-	// idToLabels maps databaseID -> label key -> label value
-	idToLabels := make(map[int]map[string]string)
-	databaseMap := make(map[int]*store.DatabaseMessage)
-	for _, database := range databaseList {
-		databaseMap[database.UID] = database
-		idToLabels[database.UID] = database.Labels
-	}
-
 	// idsSeen records database id which is already in a stage.
 	idsSeen := make(map[int]bool)
 
 	// For each stage, we loop over all databases to see if it is a match.
 	for _, deployment := range schedule.Deployments {
 		// For each stage, we will get a list of matched databases.
-		var matchedDatabaseList []int
-		// Loop over databaseList instead of idToLabels to get determinant results.
+		var matchedDatabaseList []*store.DatabaseMessage
+		// Loop over databaseList to get determinant results.
 		for _, database := range databaseList {
 			// Skip if the database is already in a stage.
 			if _, ok := idsSeen[database.UID]; ok {
 				continue
 			}
 
-			if isMatchExpressions(idToLabels[database.UID], deployment.Spec.Selector.MatchExpressions) {
-				matchedDatabaseList = append(matchedDatabaseList, database.UID)
-				idsSeen[database.UID] = true
+			if isMatchExpressions(database.Labels, deployment.Spec.Selector.MatchExpressions) {
+				matchedDatabaseList = append(matchedDatabaseList, database)
 			}
 		}
-
-		var databaseList []*store.DatabaseMessage
-		for _, id := range matchedDatabaseList {
-			databaseList = append(databaseList, databaseMap[id])
-		}
 		// sort databases in stage based on IDs.
-		if len(databaseList) > 0 {
-			sort.Slice(databaseList, func(i, j int) bool {
-				return databaseList[i].UID < databaseList[j].UID
-			})
+		sort.Slice(matchedDatabaseList, func(i, j int) bool {
+			return matchedDatabaseList[i].UID < matchedDatabaseList[j].UID
+		})
+		if rollout := deployment.Spec.Rollout; rollout != nil {
+			matchedDatabaseList = matchedDatabaseList[:rollout.GetDatabaseLimit(len(matchedDatabaseList))]
+		}
+		for _, database := range matchedDatabaseList {
+			idsSeen[database.UID] = true
 		}
 
-		matrix = append(matrix, databaseList)
+		matrix = append(matrix, matchedDatabaseList)
 	}
 
 	return matrix, nil
@@ -327,6 +329,37 @@ func GetTaskStatement(taskPayload string) (string, error) {
 	return taskStatement.Statement, nil
 }
 
+// GetVerificationTaskCheckRunCreate returns the post-deployment verification task check of the done task,
+// or nil if the task has no verification or it's skipped.
+func GetVerificationTaskCheckRunCreate(task *store.TaskMessage, creatorID int) (*store.TaskCheckRunCreate, error) {
+	if task.Status != api.TaskDone {
+		return nil, nil
+	}
+	var taskPayload struct {
+		Skipped      bool                  `json:"skipped,omitempty"`
+		Verification *api.TaskVerification `json:"verification,omitempty"`
+	}
+	if err := json.Unmarshal([]byte(task.Payload), &taskPayload); err != nil {
+		return nil, err
+	}
+	if taskPayload.Skipped || taskPayload.Verification == nil {
+		return nil, nil
+	}
+	payload, err := json.Marshal(api.TaskCheckDatabaseVerificationPayload{
+		Statement:      taskPayload.Verification.Statement,
+		ExpectedResult: taskPayload.Verification.ExpectedResult,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal database verification payload: %v", task.Name)
+	}
+	return &store.TaskCheckRunCreate{
+		CreatorID: creatorID,
+		TaskID:    task.ID,
+		Type:      api.TaskCheckDatabaseVerification,
+		Payload:   string(payload),
+	}, nil
+}
+
 // GetTaskSkippedAndReason gets skipped and skippedReason from a task.
 func GetTaskSkippedAndReason(task *api.Task) (bool, string, error) {
 	var payload struct {
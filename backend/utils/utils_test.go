@@ -253,6 +253,101 @@ func TestGetDatabaseMatrixFromDeploymentSchedule(t *testing.T) {
 				{dbs[5], dbs[6]},
 			},
 		},
+		{
+			"notInAndDoesNotExistOperators",
+			&api.DeploymentSchedule{
+				Deployments: []*api.Deployment{
+					{
+						Spec: &api.DeploymentSpec{
+							Selector: &api.LabelSelector{
+								MatchExpressions: []*api.LabelSelectorRequirement{
+									{
+										Key:      "bb.location",
+										Operator: "NotIn",
+										Values:   []string{"earth", "us"},
+									},
+								},
+							},
+						},
+					},
+					{
+						Spec: &api.DeploymentSpec{
+							Selector: &api.LabelSelector{
+								MatchExpressions: []*api.LabelSelectorRequirement{
+									{
+										Key:      "bb.tenant",
+										Operator: "DoesNotExist",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			[]*store.DatabaseMessage{
+				dbs[0], dbs[1], dbs[3], dbs[5], dbs[6],
+			},
+			[][]*store.DatabaseMessage{
+				{dbs[0], dbs[6]},
+				{dbs[3], dbs[5]},
+			},
+		},
+		{
+			"canaryRollout",
+			&api.DeploymentSchedule{
+				Deployments: []*api.Deployment{
+					{
+						Spec: &api.DeploymentSpec{
+							Selector: &api.LabelSelector{
+								MatchExpressions: []*api.LabelSelectorRequirement{
+									{
+										Key:      "bb.environment",
+										Operator: "In",
+										Values:   []string{"dev"},
+									},
+								},
+							},
+							Rollout: &api.DeploymentRollout{Count: 2},
+						},
+					},
+					{
+						Spec: &api.DeploymentSpec{
+							Selector: &api.LabelSelector{
+								MatchExpressions: []*api.LabelSelectorRequirement{
+									{
+										Key:      "bb.environment",
+										Operator: "In",
+										Values:   []string{"dev"},
+									},
+								},
+							},
+							Rollout: &api.DeploymentRollout{Percent: 50},
+						},
+					},
+					{
+						Spec: &api.DeploymentSpec{
+							Selector: &api.LabelSelector{
+								MatchExpressions: []*api.LabelSelectorRequirement{
+									{
+										Key:      "bb.environment",
+										Operator: "In",
+										Values:   []string{"dev"},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			[]*store.DatabaseMessage{
+				dbs[6], dbs[5], dbs[4], dbs[3], dbs[2], dbs[1], dbs[0],
+			},
+			[][]*store.DatabaseMessage{
+				{dbs[0], dbs[1]},
+				{dbs[2], dbs[3], dbs[4]},
+				{dbs[5], dbs[6]},
+			},
+		},
 	}
 
 	for _, test := range tests {
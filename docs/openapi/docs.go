@@ -93,6 +93,26 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    {
+                        "enum": [
+                            "SARIF",
+                            "JUNIT"
+                        ],
+                        "description": "The report format. Return the advice list if it's not specified.",
+                        "name": "format",
+                        "in": "body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "The statement file path used as the location in the report.",
+                        "name": "filePath",
+                        "in": "body",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 ],
                 "responses": {
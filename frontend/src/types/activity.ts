@@ -45,12 +45,15 @@ export type DatabaseActivityType = "bb.database.recovery.pitr.done";
 
 export type SQLEditorActivityType = "bb.sql-editor.query";
 
+export type AnomalyActivityType = "bb.anomaly.create" | "bb.anomaly.resolve";
+
 export type ActivityType =
   | IssueActivityType
   | MemberActivityType
   | ProjectActivityType
   | DatabaseActivityType
-  | SQLEditorActivityType;
+  | SQLEditorActivityType
+  | AnomalyActivityType;
 
 export function activityName(type: ActivityType): string {
   switch (type) {
@@ -92,6 +95,10 @@ export function activityName(type: ActivityType): string {
       return t("activity.type.project-member-role-update");
     case "bb.database.recovery.pitr.done":
       return t("activity.type.database-recovery-pitr-done");
+    case "bb.anomaly.create":
+      return t("activity.type.anomaly-create");
+    case "bb.anomaly.resolve":
+      return t("activity.type.anomaly-resolve");
   }
   console.assert(false, `undefined text for activity type "${type}"`);
   return "";
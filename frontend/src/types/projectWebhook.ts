@@ -86,6 +86,16 @@ export const PROJECT_HOOK_ACTIVITY_ITEM_LIST: () => ProjectWebhookActivityItem[]
       label: t("project.webhook.activity-item.issue-comment-creation.label"),
       activity: "bb.issue.comment.create",
     },
+    {
+      title: t("project.webhook.activity-item.anomaly-creation.title"),
+      label: t("project.webhook.activity-item.anomaly-creation.label"),
+      activity: "bb.anomaly.create",
+    },
+    {
+      title: t("project.webhook.activity-item.anomaly-resolution.title"),
+      label: t("project.webhook.activity-item.anomaly-resolution.label"),
+      activity: "bb.anomaly.resolve",
+    },
   ];
 
 // Project Member
@@ -0,0 +1,292 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             (unknown)
+// source: v1/custom_role_service.proto
+
+package v1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// CustomRoleServiceClient is the client API for CustomRoleService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type CustomRoleServiceClient interface {
+	ListCustomRoles(ctx context.Context, in *ListCustomRolesRequest, opts ...grpc.CallOption) (*ListCustomRolesResponse, error)
+	CreateCustomRole(ctx context.Context, in *CreateCustomRoleRequest, opts ...grpc.CallOption) (*CustomRole, error)
+	UpdateCustomRole(ctx context.Context, in *UpdateCustomRoleRequest, opts ...grpc.CallOption) (*CustomRole, error)
+	DeleteCustomRole(ctx context.Context, in *DeleteCustomRoleRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// GetWorkspaceIamPolicy returns the custom role bindings of the workspace.
+	GetWorkspaceIamPolicy(ctx context.Context, in *GetWorkspaceIamPolicyRequest, opts ...grpc.CallOption) (*IamPolicy, error)
+	// SetWorkspaceIamPolicy sets the custom role bindings of the workspace.
+	// The custom roles are granted to the members in addition to their workspace roles.
+	SetWorkspaceIamPolicy(ctx context.Context, in *SetWorkspaceIamPolicyRequest, opts ...grpc.CallOption) (*IamPolicy, error)
+}
+
+type customRoleServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCustomRoleServiceClient(cc grpc.ClientConnInterface) CustomRoleServiceClient {
+	return &customRoleServiceClient{cc}
+}
+
+func (c *customRoleServiceClient) ListCustomRoles(ctx context.Context, in *ListCustomRolesRequest, opts ...grpc.CallOption) (*ListCustomRolesResponse, error) {
+	out := new(ListCustomRolesResponse)
+	err := c.cc.Invoke(ctx, "/bytebase.v1.CustomRoleService/ListCustomRoles", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *customRoleServiceClient) CreateCustomRole(ctx context.Context, in *CreateCustomRoleRequest, opts ...grpc.CallOption) (*CustomRole, error) {
+	out := new(CustomRole)
+	err := c.cc.Invoke(ctx, "/bytebase.v1.CustomRoleService/CreateCustomRole", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *customRoleServiceClient) UpdateCustomRole(ctx context.Context, in *UpdateCustomRoleRequest, opts ...grpc.CallOption) (*CustomRole, error) {
+	out := new(CustomRole)
+	err := c.cc.Invoke(ctx, "/bytebase.v1.CustomRoleService/UpdateCustomRole", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *customRoleServiceClient) DeleteCustomRole(ctx context.Context, in *DeleteCustomRoleRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/bytebase.v1.CustomRoleService/DeleteCustomRole", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *customRoleServiceClient) GetWorkspaceIamPolicy(ctx context.Context, in *GetWorkspaceIamPolicyRequest, opts ...grpc.CallOption) (*IamPolicy, error) {
+	out := new(IamPolicy)
+	err := c.cc.Invoke(ctx, "/bytebase.v1.CustomRoleService/GetWorkspaceIamPolicy", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *customRoleServiceClient) SetWorkspaceIamPolicy(ctx context.Context, in *SetWorkspaceIamPolicyRequest, opts ...grpc.CallOption) (*IamPolicy, error) {
+	out := new(IamPolicy)
+	err := c.cc.Invoke(ctx, "/bytebase.v1.CustomRoleService/SetWorkspaceIamPolicy", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CustomRoleServiceServer is the server API for CustomRoleService service.
+// All implementations must embed UnimplementedCustomRoleServiceServer
+// for forward compatibility
+type CustomRoleServiceServer interface {
+	ListCustomRoles(context.Context, *ListCustomRolesRequest) (*ListCustomRolesResponse, error)
+	CreateCustomRole(context.Context, *CreateCustomRoleRequest) (*CustomRole, error)
+	UpdateCustomRole(context.Context, *UpdateCustomRoleRequest) (*CustomRole, error)
+	DeleteCustomRole(context.Context, *DeleteCustomRoleRequest) (*emptypb.Empty, error)
+	// GetWorkspaceIamPolicy returns the custom role bindings of the workspace.
+	GetWorkspaceIamPolicy(context.Context, *GetWorkspaceIamPolicyRequest) (*IamPolicy, error)
+	// SetWorkspaceIamPolicy sets the custom role bindings of the workspace.
+	// The custom roles are granted to the members in addition to their workspace roles.
+	SetWorkspaceIamPolicy(context.Context, *SetWorkspaceIamPolicyRequest) (*IamPolicy, error)
+	mustEmbedUnimplementedCustomRoleServiceServer()
+}
+
+// UnimplementedCustomRoleServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedCustomRoleServiceServer struct {
+}
+
+func (UnimplementedCustomRoleServiceServer) ListCustomRoles(context.Context, *ListCustomRolesRequest) (*ListCustomRolesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCustomRoles not implemented")
+}
+func (UnimplementedCustomRoleServiceServer) CreateCustomRole(context.Context, *CreateCustomRoleRequest) (*CustomRole, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateCustomRole not implemented")
+}
+func (UnimplementedCustomRoleServiceServer) UpdateCustomRole(context.Context, *UpdateCustomRoleRequest) (*CustomRole, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateCustomRole not implemented")
+}
+func (UnimplementedCustomRoleServiceServer) DeleteCustomRole(context.Context, *DeleteCustomRoleRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteCustomRole not implemented")
+}
+func (UnimplementedCustomRoleServiceServer) GetWorkspaceIamPolicy(context.Context, *GetWorkspaceIamPolicyRequest) (*IamPolicy, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWorkspaceIamPolicy not implemented")
+}
+func (UnimplementedCustomRoleServiceServer) SetWorkspaceIamPolicy(context.Context, *SetWorkspaceIamPolicyRequest) (*IamPolicy, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetWorkspaceIamPolicy not implemented")
+}
+func (UnimplementedCustomRoleServiceServer) mustEmbedUnimplementedCustomRoleServiceServer() {}
+
+// UnsafeCustomRoleServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CustomRoleServiceServer will
+// result in compilation errors.
+type UnsafeCustomRoleServiceServer interface {
+	mustEmbedUnimplementedCustomRoleServiceServer()
+}
+
+func RegisterCustomRoleServiceServer(s grpc.ServiceRegistrar, srv CustomRoleServiceServer) {
+	s.RegisterService(&CustomRoleService_ServiceDesc, srv)
+}
+
+func _CustomRoleService_ListCustomRoles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCustomRolesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CustomRoleServiceServer).ListCustomRoles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bytebase.v1.CustomRoleService/ListCustomRoles",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CustomRoleServiceServer).ListCustomRoles(ctx, req.(*ListCustomRolesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CustomRoleService_CreateCustomRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateCustomRoleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CustomRoleServiceServer).CreateCustomRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bytebase.v1.CustomRoleService/CreateCustomRole",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CustomRoleServiceServer).CreateCustomRole(ctx, req.(*CreateCustomRoleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CustomRoleService_UpdateCustomRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateCustomRoleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CustomRoleServiceServer).UpdateCustomRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bytebase.v1.CustomRoleService/UpdateCustomRole",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CustomRoleServiceServer).UpdateCustomRole(ctx, req.(*UpdateCustomRoleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CustomRoleService_DeleteCustomRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteCustomRoleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CustomRoleServiceServer).DeleteCustomRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bytebase.v1.CustomRoleService/DeleteCustomRole",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CustomRoleServiceServer).DeleteCustomRole(ctx, req.(*DeleteCustomRoleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CustomRoleService_GetWorkspaceIamPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetWorkspaceIamPolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CustomRoleServiceServer).GetWorkspaceIamPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bytebase.v1.CustomRoleService/GetWorkspaceIamPolicy",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CustomRoleServiceServer).GetWorkspaceIamPolicy(ctx, req.(*GetWorkspaceIamPolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CustomRoleService_SetWorkspaceIamPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetWorkspaceIamPolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CustomRoleServiceServer).SetWorkspaceIamPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bytebase.v1.CustomRoleService/SetWorkspaceIamPolicy",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CustomRoleServiceServer).SetWorkspaceIamPolicy(ctx, req.(*SetWorkspaceIamPolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// CustomRoleService_ServiceDesc is the grpc.ServiceDesc for CustomRoleService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var CustomRoleService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "bytebase.v1.CustomRoleService",
+	HandlerType: (*CustomRoleServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListCustomRoles",
+			Handler:    _CustomRoleService_ListCustomRoles_Handler,
+		},
+		{
+			MethodName: "CreateCustomRole",
+			Handler:    _CustomRoleService_CreateCustomRole_Handler,
+		},
+		{
+			MethodName: "UpdateCustomRole",
+			Handler:    _CustomRoleService_UpdateCustomRole_Handler,
+		},
+		{
+			MethodName: "DeleteCustomRole",
+			Handler:    _CustomRoleService_DeleteCustomRole_Handler,
+		},
+		{
+			MethodName: "GetWorkspaceIamPolicy",
+			Handler:    _CustomRoleService_GetWorkspaceIamPolicy_Handler,
+		},
+		{
+			MethodName: "SetWorkspaceIamPolicy",
+			Handler:    _CustomRoleService_SetWorkspaceIamPolicy_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "v1/custom_role_service.proto",
+}